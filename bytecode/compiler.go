@@ -0,0 +1,434 @@
+package bytecode
+
+import (
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// Chunk 一条语句编译后的字节码
+type Chunk struct {
+	code   []Instr
+	consts []data.GetValue
+	nodes  []data.GetValue // EVAL/EXEC 交给树遍历执行的节点
+	vars   []*node.VariableExpression
+	exprs  []binaryFunc // 二元运算的慢路径
+	froms  []data.From
+	loops  []loop
+	nregs  int
+}
+
+// loop chunk 内一层循环的跳转信息
+type loop struct {
+	parent int32 // 外层循环下标，-1 表示 chunk 内没有外层循环
+	brk    int32 // break 跳转目标
+	cont   int32 // continue 跳转目标
+}
+
+// binaryFunc 用已求值的左右操作数重新执行原二元运算
+type binaryFunc func(ctx data.Context, l, r data.GetValue) (data.GetValue, data.Control)
+
+// patch 等待循环结束后回填的跳转
+type patch struct {
+	pc   int
+	loop int32
+	cont bool
+}
+
+type compiler struct {
+	chunk    *Chunk
+	reg      int
+	loop     int32 // 当前循环下标，-1 表示不在循环内
+	patches  []patch
+	fallback []data.GetValue // 交给树遍历执行的节点，其中的语句块仍需继续编译
+}
+
+// compileStatement 把 if/while/for/do-while 语句编译为 chunk
+func compileStatement(stmt data.GetValue) (*Chunk, []data.GetValue) {
+	c := &compiler{chunk: &Chunk{}, loop: -1}
+	c.stmt(stmt)
+	return c.chunk, c.fallback
+}
+
+func (c *compiler) emit(op Op, a, b, cc int32) int {
+	c.chunk.code = append(c.chunk.code, Instr{Op: op, A: a, B: b, C: cc})
+	return len(c.chunk.code) - 1
+}
+
+func (c *compiler) pc() int32 {
+	return int32(len(c.chunk.code))
+}
+
+func (c *compiler) alloc() int32 {
+	r := c.reg
+	c.reg++
+	if c.reg > c.chunk.nregs {
+		c.chunk.nregs = c.reg
+	}
+	return int32(r)
+}
+
+func (c *compiler) free(n int) {
+	c.reg -= n
+}
+
+func (c *compiler) constant(v data.GetValue) int32 {
+	c.chunk.consts = append(c.chunk.consts, v)
+	return int32(len(c.chunk.consts) - 1)
+}
+
+func (c *compiler) node(n data.GetValue) int32 {
+	c.chunk.nodes = append(c.chunk.nodes, n)
+	c.fallback = append(c.fallback, n)
+	return int32(len(c.chunk.nodes) - 1)
+}
+
+func (c *compiler) variable(v *node.VariableExpression) int32 {
+	c.chunk.vars = append(c.chunk.vars, v)
+	return int32(len(c.chunk.vars) - 1)
+}
+
+func (c *compiler) from(n data.GetValue) int32 {
+	var from data.From
+	if gf, ok := n.(node.GetFrom); ok {
+		from = gf.GetFrom()
+	}
+	c.chunk.froms = append(c.chunk.froms, from)
+	return int32(len(c.chunk.froms) - 1)
+}
+
+func (c *compiler) stmts(list []data.GetValue) {
+	for _, stmt := range list {
+		c.stmt(stmt)
+	}
+}
+
+func (c *compiler) stmt(n data.GetValue) {
+	switch s := n.(type) {
+	case *node.IfStatement:
+		c.ifStmt(s)
+	case *node.WhileStatement:
+		c.whileStmt(s)
+	case *node.ForStatement:
+		c.forStmt(s)
+	case *node.DoWhileStatement:
+		c.doWhileStmt(s)
+	case *node.BlockStatement:
+		c.stmts(s.Statements)
+	case *node.BreakStatement:
+		c.breakStmt(s)
+	case *node.ContinueStatement:
+		if c.loop < 0 {
+			c.exec(s)
+			return
+		}
+		c.patches = append(c.patches, patch{pc: c.emit(OpJmp, 0, 0, 0), loop: c.loop, cont: true})
+	case *node.BinaryAssignVariable:
+		if v, ok := s.Left.(*node.VariableExpression); ok {
+			r := c.expr(s.Right)
+			c.emit(OpStoreVar, r, c.variable(v), 0)
+			c.free(1)
+			return
+		}
+		c.exec(n)
+	case *node.VarPostIncr:
+		c.incr(OpIncVar, s.Var, n)
+	case *node.VarPostDecr:
+		c.incr(OpDecVar, s.Var, n)
+	case *node.UnaryIncr:
+		c.incr(OpIncVar, s.Right, n)
+	case *node.UnaryDecr:
+		c.incr(OpDecVar, s.Right, n)
+	default:
+		c.exec(n)
+	}
+}
+
+// incr 作为语句的 $i++ / ++$i / $i-- / --$i：变量为整数时直接加减，否则执行原节点
+func (c *compiler) incr(op Op, target data.GetValue, n data.GetValue) {
+	v, ok := target.(*node.VariableExpression)
+	if !ok {
+		c.exec(n)
+		return
+	}
+	c.emit(op, c.variable(v), c.node(n), c.loop+1)
+}
+
+func (c *compiler) exec(n data.GetValue) {
+	c.emit(OpExec, 0, c.node(n), c.loop+1)
+}
+
+// cond 计算条件，为假（jumpIf 为 false）或为真时跳转，返回待回填目标的跳转指令；
+// && 与 || 按短路规则直接编译为跳转
+func (c *compiler) cond(n data.GetValue, owner data.GetValue, jumpIf bool) []int {
+	switch e := n.(type) {
+	case *node.BinaryLand:
+		if !jumpIf {
+			return append(c.cond(e.Left, owner, false), c.cond(e.Right, owner, false)...)
+		}
+		skip := c.cond(e.Left, owner, false)
+		jumps := c.cond(e.Right, owner, true)
+		c.patch(skip, c.pc())
+		return jumps
+	case *node.BinaryLor:
+		if jumpIf {
+			return append(c.cond(e.Left, owner, true), c.cond(e.Right, owner, true)...)
+		}
+		skip := c.cond(e.Left, owner, true)
+		jumps := c.cond(e.Right, owner, false)
+		c.patch(skip, c.pc())
+		return jumps
+	}
+	r := c.expr(n)
+	op := OpJmpFalse
+	if jumpIf {
+		op = OpJmpTrue
+	}
+	pc := c.emit(op, r, 0, c.from(owner))
+	c.free(1)
+	return []int{pc}
+}
+
+// patch 回填跳转目标
+func (c *compiler) patch(jumps []int, target int32) {
+	for _, pc := range jumps {
+		c.chunk.code[pc].B = target
+	}
+}
+
+func (c *compiler) ifStmt(s *node.IfStatement) {
+	var ends []int
+	next := c.cond(s.Condition, s, false)
+	c.stmts(s.ThenBranch)
+	for _, branch := range s.ElseIf {
+		ends = append(ends, c.emit(OpJmp, 0, 0, 0))
+		c.patch(next, c.pc())
+		next = c.cond(branch.Condition, s, false)
+		c.stmts(branch.ThenBranch)
+	}
+	if len(s.ElseBranch) > 0 {
+		ends = append(ends, c.emit(OpJmp, 0, 0, 0))
+		c.patch(next, c.pc())
+		c.stmts(s.ElseBranch)
+	} else {
+		c.patch(next, c.pc())
+	}
+	c.patch(ends, c.pc())
+}
+
+// enter 开始一层循环，返回外层循环下标
+func (c *compiler) enter() int32 {
+	outer := c.loop
+	c.chunk.loops = append(c.chunk.loops, loop{parent: outer})
+	c.loop = int32(len(c.chunk.loops) - 1)
+	return outer
+}
+
+// leave 结束当前循环并回填其中的 break/continue
+func (c *compiler) leave(outer int32, cont int32) {
+	l := &c.chunk.loops[c.loop]
+	l.brk, l.cont = c.pc(), cont
+	rest := c.patches[:0]
+	for _, p := range c.patches {
+		switch {
+		case p.loop != c.loop:
+			rest = append(rest, p)
+		case p.cont:
+			c.chunk.code[p.pc].B = l.cont
+		default:
+			c.chunk.code[p.pc].B = l.brk
+		}
+	}
+	c.patches = rest
+	c.loop = outer
+}
+
+func (c *compiler) whileStmt(s *node.WhileStatement) {
+	outer := c.enter()
+	top := c.pc()
+	c.emit(OpTick, 0, c.from(s), 0)
+	var exit []int
+	if s.Condition != nil {
+		exit = c.cond(s.Condition, s, false)
+	}
+	c.stmts(s.Body)
+	c.emit(OpJmp, 0, top, 0)
+	c.patch(exit, c.pc())
+	c.leave(outer, top)
+}
+
+func (c *compiler) forStmt(s *node.ForStatement) {
+	for _, init := range s.Initializers {
+		if init != nil {
+			c.stmt(init)
+		}
+	}
+	outer := c.enter()
+	top := c.pc()
+	var exit []int
+	if s.Condition != nil {
+		exit = c.cond(s.Condition, s, false)
+	}
+	c.stmts(s.Body)
+	next := c.pc()
+	loopIdx := c.loop
+	c.loop = outer
+	for _, inc := range s.Increments {
+		if inc != nil {
+			c.stmt(inc)
+		}
+	}
+	c.loop = loopIdx
+	c.emit(OpJmp, 0, top, 0)
+	c.patch(exit, c.pc())
+	c.leave(outer, next)
+}
+
+func (c *compiler) doWhileStmt(s *node.DoWhileStatement) {
+	outer := c.enter()
+	top := c.pc()
+	c.stmts(s.Body)
+	next := c.pc()
+	if s.Condition != nil {
+		c.patch(c.cond(s.Condition, s, true), top)
+	}
+	c.leave(outer, next)
+}
+
+// breakStmt break N：目标循环在 chunk 内时直接跳转，否则把剩余层数交给外层执行
+func (c *compiler) breakStmt(s *node.BreakStatement) {
+	level := s.Level
+	if level < 1 {
+		level = 1
+	}
+	target := c.loop
+	for ; level > 1 && target >= 0; level-- {
+		target = c.chunk.loops[target].parent
+	}
+	if target < 0 {
+		c.emit(OpExec, 0, c.node(&node.BreakStatement{Node: s.Node, Level: level}), 0)
+		return
+	}
+	c.patches = append(c.patches, patch{pc: c.emit(OpJmp, 0, 0, 0), loop: target})
+}
+
+// expr 把表达式的值算到新分配的寄存器中
+func (c *compiler) expr(n data.GetValue) int32 {
+	r := c.alloc()
+	switch e := n.(type) {
+	case *node.IntLiteral:
+		c.emit(OpLoadK, r, c.constant(e.V), 0)
+	case *node.FloatLiteral:
+		c.emit(OpLoadK, r, c.constant(e.V), 0)
+	case *node.VariableExpression:
+		c.emit(OpLoadVar, r, c.variable(e), 0)
+	case *node.BinaryAdd:
+		c.binary(OpAdd, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryAdd{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinarySub:
+		c.binary(OpSub, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinarySub{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryMul:
+		c.binary(OpMul, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryMul{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryRem:
+		c.binary(OpRem, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryRem{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryEq:
+		c.binary(OpEq, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryEq{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryNe:
+		c.binary(OpNe, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryNe{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryLt:
+		c.binary(OpLt, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryLt{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryLe:
+		c.binary(OpLe, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryLe{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryGt:
+		c.binary(OpGt, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryGt{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	case *node.BinaryGe:
+		c.binary(OpGe, r, e.Left, e.Right, func(ctx data.Context, l, rv data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryGe{Node: e.Node, Left: hold(l), Right: hold(rv)}).GetValue(ctx)
+		})
+	default:
+		if fn := binaryOf(n); fn != nil {
+			l, rv := operands(n)
+			c.binary(OpBinary, r, l, rv, fn)
+			break
+		}
+		c.emit(OpEval, r, c.node(n), c.loop+1)
+	}
+	return r
+}
+
+func (c *compiler) binary(op Op, r int32, left, right data.GetValue, fn binaryFunc) {
+	a := c.expr(left)
+	c.expr(right)
+	c.chunk.exprs = append(c.chunk.exprs, fn)
+	c.emit(op, r, a, int32(len(c.chunk.exprs)-1))
+	c.free(2)
+}
+
+// binaryOf 其余左右操作数都会求值的二元运算（&&、|| 短路，仍走树遍历）
+func binaryOf(n data.GetValue) binaryFunc {
+	switch e := n.(type) {
+	case *node.BinaryQuo:
+		return func(ctx data.Context, l, r data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryQuo{Node: e.Node, Left: hold(l), Right: hold(r)}).GetValue(ctx)
+		}
+	case *node.BinaryDot:
+		return func(ctx data.Context, l, r data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryDot{Node: e.Node, Left: hold(l), Right: hold(r)}).GetValue(ctx)
+		}
+	case *node.BinaryEqStrict:
+		return func(ctx data.Context, l, r data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryEqStrict{Node: e.Node, Left: hold(l), Right: hold(r)}).GetValue(ctx)
+		}
+	case *node.BinaryNeStrict:
+		return func(ctx data.Context, l, r data.GetValue) (data.GetValue, data.Control) {
+			return (&node.BinaryNeStrict{Node: e.Node, Left: hold(l), Right: hold(r)}).GetValue(ctx)
+		}
+	}
+	return nil
+}
+
+func operands(n data.GetValue) (data.GetValue, data.GetValue) {
+	switch e := n.(type) {
+	case *node.BinaryQuo:
+		return e.Left, e.Right
+	case *node.BinaryDot:
+		return e.Left, e.Right
+	case *node.BinaryEqStrict:
+		return e.Left, e.Right
+	case *node.BinaryNeStrict:
+		return e.Left, e.Right
+	}
+	return nil, nil
+}
+
+// held 已求值的操作数，让原二元运算节点在慢路径中直接取用
+type held struct {
+	v data.GetValue
+}
+
+func (h held) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return h.v, nil
+}
+
+func hold(v data.GetValue) data.GetValue {
+	if v == nil {
+		return held{}
+	}
+	return v
+}
@@ -0,0 +1,153 @@
+package bytecode
+
+import (
+	"reflect"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+var (
+	nodePkgPath     = reflect.TypeOf(node.Node{}).PkgPath()
+	getValueIfaceTy = reflect.TypeOf((*data.GetValue)(nil)).Elem()
+	getValueSliceTy = reflect.TypeOf([]data.GetValue(nil))
+)
+
+// Statement 编译后的语句，替换原 if/while/for/do-while 节点；
+// 位置信息沿用原节点，报错与调用栈不受影响
+type Statement struct {
+	*node.Node `pp:"-"`
+	Chunk      *Chunk
+	Orig       data.GetValue
+}
+
+func (s *Statement) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return s.Chunk.Run(ctx)
+}
+
+// Compile 把程序及其中声明的函数、类方法（含生成器）里的控制流语句编译为字节码，原地替换语法树中的节点。
+// 编译范围：if/while/for/do-while、break/continue、条件中的 && 与 ||、局部变量的读取与赋值、
+// 作为语句的 ++/--、字面量、算术与比较运算。
+// 函数与方法调用、数组与对象访问等其余表达式和语句通过 EVAL/EXEC 交给原节点执行：
+// 参数绑定（引用传递、命名参数、展开、strict_types 调用点）等语义只在节点中实现一次，两种引擎共用同一套运行时与标准库。
+// 含 goto 标签的语句保持树遍历（goto 可以跳到标签处）。
+func Compile(program *node.Program, classes []data.ClassStmt) {
+	w := &walker{seen: map[uintptr]struct{}{}}
+	program.Statements = w.list(program.Statements)
+	for _, c := range classes {
+		w.class(c)
+	}
+}
+
+type walker struct {
+	seen map[uintptr]struct{}
+}
+
+func (w *walker) list(stmts []data.GetValue) []data.GetValue {
+	for i, stmt := range stmts {
+		switch stmt.(type) {
+		case *node.IfStatement, *node.WhileStatement, *node.ForStatement, *node.DoWhileStatement:
+			if hasLabel(stmt) {
+				break
+			}
+			chunk, fallback := compileStatement(stmt)
+			gf := stmt.(node.GetFrom)
+			stmts[i] = &Statement{Node: node.NewNode(gf.GetFrom()), Chunk: chunk, Orig: stmt}
+			for _, n := range fallback {
+				w.visit(n)
+			}
+			continue
+		}
+		w.visit(stmt)
+	}
+	return stmts
+}
+
+// hasLabel goto 的目标标签在 chunk 内时无法跳转，含标签的语句保持树遍历执行；
+// 只含 goto 的语句可以编译，goto 作为控制流交给外层处理
+func hasLabel(stmt data.GetValue) bool {
+	found := false
+	node.Inspect(stmt, func(n data.GetValue) bool {
+		if _, ok := n.(*node.LabelStatement); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func (w *walker) visit(n data.GetValue) {
+	rv := reflect.ValueOf(n)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct || rv.Elem().Type().PkgPath() != nodePkgPath {
+		return
+	}
+	if _, ok := w.seen[rv.Pointer()]; ok {
+		return
+	}
+	w.seen[rv.Pointer()] = struct{}{}
+	// 生成器函数体在独立协程中执行，yield 挂起整个协程，编译后的语句同样可以在任意位置挂起
+	if s, ok := n.(data.ClassStmt); ok {
+		w.class(s)
+		return
+	}
+	w.fields(rv.Elem())
+}
+
+func (w *walker) fields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("pp") == "-" {
+			continue
+		}
+		w.field(v.Field(i))
+	}
+}
+
+func (w *walker) field(fv reflect.Value) {
+	if fv.Type() == getValueSliceTy {
+		if fv.Len() > 0 && fv.CanSet() {
+			fv.Set(reflect.ValueOf(w.list(fv.Interface().([]data.GetValue))))
+		}
+		return
+	}
+	switch fv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if fv.IsNil() || !fv.CanInterface() {
+			return
+		}
+		if n, ok := fv.Interface().(data.GetValue); ok {
+			w.visit(n)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			w.field(fv.Index(i))
+		}
+	case reflect.Struct:
+		if fv.Type().PkgPath() == nodePkgPath {
+			w.fields(fv)
+		}
+	}
+}
+
+func (w *walker) class(c data.ClassStmt) {
+	var cs *node.ClassStatement
+	switch s := c.(type) {
+	case *node.ClassStatement:
+		cs = s
+	case *node.AbstractClassStatement:
+		cs = s.ClassStatement
+	case *node.ClassGeneric:
+		cs = s.ClassStatement
+	}
+	if cs == nil {
+		return
+	}
+	for _, methods := range []map[string]data.Method{cs.Methods, cs.StaticMethods} {
+		for _, m := range methods {
+			if cm, ok := m.(*node.ClassMethod); ok {
+				w.visit(cm)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package bytecode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op 字节码操作码
+type Op uint8
+
+const (
+	OpLoadK    Op = iota // R[A] = K[B]
+	OpEval               // R[A] = nodes[B].GetValue(ctx)，C-1 为所在循环
+	OpExec               // 执行语句 nodes[B]，丢弃结果，C-1 为所在循环
+	OpAdd                // R[A] = R[B] + R[B+1]，C 为 exprs 下标（非整数时回退到原运算）
+	OpSub                // R[A] = R[B] - R[B+1]
+	OpMul                // R[A] = R[B] * R[B+1]
+	OpRem                // R[A] = R[B] % R[B+1]
+	OpLt                 // R[A] = R[B] < R[B+1]
+	OpLe                 // R[A] = R[B] <= R[B+1]
+	OpGt                 // R[A] = R[B] > R[B+1]
+	OpGe                 // R[A] = R[B] >= R[B+1]
+	OpEq                 // R[A] = R[B] == R[B+1]
+	OpNe                 // R[A] = R[B] != R[B+1]
+	OpBinary             // R[A] = exprs[C](R[B], R[B+1])，其余二元运算
+	OpJmp                // pc = B
+	OpJmpFalse           // R[A] 为假时 pc = B，C 为 froms 下标（条件转换出错时报告位置）
+	OpJmpTrue            // R[A] 为真时 pc = B
+	OpTick               // 循环开始新一轮，检查执行时间限制，B 为 froms 下标
+	OpLoadVar            // R[A] = vars[B]
+	OpStoreVar           // vars[B] = R[A]，与树遍历的 $var = expr 相同
+	OpIncVar             // vars[A]++，非整数时执行 nodes[B]，C-1 为所在循环
+	OpDecVar             // vars[A]--，非整数时执行 nodes[B]
+)
+
+var opNames = [...]string{
+	OpLoadK:    "LOADK",
+	OpEval:     "EVAL",
+	OpExec:     "EXEC",
+	OpAdd:      "ADD",
+	OpSub:      "SUB",
+	OpMul:      "MUL",
+	OpRem:      "REM",
+	OpLt:       "LT",
+	OpLe:       "LE",
+	OpGt:       "GT",
+	OpGe:       "GE",
+	OpEq:       "EQ",
+	OpNe:       "NE",
+	OpBinary:   "BINARY",
+	OpJmp:      "JMP",
+	OpJmpFalse: "JMPF",
+	OpJmpTrue:  "JMPT",
+	OpTick:     "TICK",
+	OpLoadVar:  "LOADV",
+	OpStoreVar: "STOREV",
+	OpIncVar:   "INCV",
+	OpDecVar:   "DECV",
+}
+
+func (op Op) String() string {
+	if int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return fmt.Sprintf("OP(%d)", op)
+}
+
+// Instr 一条指令：操作码与三个操作数，操作数的含义见各操作码的说明
+type Instr struct {
+	Op      Op
+	A, B, C int32
+}
+
+// String 反汇编 chunk，便于调试与测试
+func (c *Chunk) String() string {
+	var b strings.Builder
+	for pc, in := range c.code {
+		fmt.Fprintf(&b, "%04d %-8s %d %d %d\n", pc, in.Op, in.A, in.B, in.C)
+	}
+	return b.String()
+}
@@ -0,0 +1,238 @@
+package bytecode
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// Run 执行 chunk；寄存器按次分配，同一 chunk 可被多个协程同时执行
+func (c *Chunk) Run(ctx data.Context) (data.GetValue, data.Control) {
+	regs := make([]data.GetValue, c.nregs)
+	code := c.code
+	pc := 0
+	for pc < len(code) {
+		in := &code[pc]
+		pc++
+		switch in.Op {
+		case OpLoadK:
+			regs[in.A] = c.consts[in.B]
+		case OpLoadVar:
+			v, ctl := ctx.GetVariableValue(c.vars[in.B])
+			if ctl != nil {
+				return nil, fillFrom(c.vars[in.B], ctl)
+			}
+			regs[in.A] = v
+		case OpStoreVar:
+			if ctl := store(ctx, c.vars[in.B], regs[in.A]); ctl != nil {
+				return nil, ctl
+			}
+		case OpIncVar, OpDecVar:
+			if step(ctx, c.vars[in.A].Index, in.Op == OpIncVar) {
+				continue
+			}
+			fallthrough
+		case OpEval, OpExec:
+			v, ctl := c.nodes[in.B].GetValue(ctx)
+			if ctl != nil {
+				target, ctl := c.control(in, ctl)
+				if ctl != nil {
+					return nil, ctl
+				}
+				pc = target
+				continue
+			}
+			if in.Op == OpEval {
+				regs[in.A] = v
+				continue
+			}
+			// 语句边界：析构已不可达的对象
+			if ctl := data.Safepoint(ctx); ctl != nil {
+				target, ctl := c.control(in, ctl)
+				if ctl != nil {
+					return nil, ctl
+				}
+				pc = target
+			}
+		case OpAdd, OpSub, OpMul, OpRem:
+			if l, ok := regs[in.B].(*data.IntValue); ok {
+				// 除数为 0 时交给原运算报错
+				if r, ok := regs[in.B+1].(*data.IntValue); ok && (in.Op != OpRem || r.Value != 0) {
+					regs[in.A] = intArith(in.Op, l.Value, r.Value)
+					continue
+				}
+			}
+			v, ctl := c.exprs[in.C](ctx, regs[in.B], regs[in.B+1])
+			if ctl != nil {
+				return nil, ctl
+			}
+			regs[in.A] = v
+		case OpLt, OpLe, OpGt, OpGe, OpEq, OpNe:
+			if l, ok := regs[in.B].(*data.IntValue); ok {
+				if r, ok := regs[in.B+1].(*data.IntValue); ok {
+					b := intCompare(in.Op, l.Value, r.Value)
+					// 紧跟条件跳转时直接跳转，省去 BoolValue 的分配
+					if pc < len(code) && (code[pc].Op == OpJmpFalse || code[pc].Op == OpJmpTrue) && code[pc].A == in.A {
+						if b == (code[pc].Op == OpJmpTrue) {
+							pc = int(code[pc].B)
+						} else {
+							pc++
+						}
+						continue
+					}
+					regs[in.A] = data.NewBoolValue(b)
+					continue
+				}
+			}
+			fallthrough
+		case OpBinary:
+			v, ctl := c.exprs[in.C](ctx, regs[in.B], regs[in.B+1])
+			if ctl != nil {
+				return nil, ctl
+			}
+			regs[in.A] = v
+		case OpJmp:
+			pc = int(in.B)
+		case OpJmpFalse, OpJmpTrue:
+			b, ctl := c.truth(regs[in.A], in.C)
+			if ctl != nil {
+				return nil, ctl
+			}
+			if b == (in.Op == OpJmpTrue) {
+				pc = int(in.B)
+			}
+		case OpTick:
+			node.CheckTimeLimit(c.froms[in.B])
+		}
+	}
+	return nil, nil
+}
+
+// store 与 BinaryAssignVariable 相同：标量按值写入变量槽，其余经 SetValue（引用绑定的类型检查等）
+func store(ctx data.Context, v *node.VariableExpression, rv data.GetValue) data.Control {
+	val, ok := rv.(data.Value)
+	if !ok {
+		if rv != nil {
+			return data.NewErrorThrow(v.GetFrom(), fmt.Errorf("TODO BinaryAssign rv=%T left=%T", rv, v))
+		}
+		val = data.NewNullValue()
+	}
+	if zv := ctx.GetIndexZVal(v.Index); zv != nil && zv.Guard == nil && data.IsScalarAssignFast(val) && !data.NeedsRelease(zv) {
+		data.AssignScalarToZVal(zv, val)
+		return nil
+	}
+	return v.SetValue(ctx, val)
+}
+
+// step 整数变量自增/自减的快速路径；溢出、非整数或引用了带类型属性时返回 false，交给原节点处理
+func step(ctx data.Context, index int, up bool) bool {
+	zv := ctx.GetIndexZVal(index)
+	if zv == nil || zv.Guard != nil {
+		return false
+	}
+	iv, ok := zv.Value.(*data.IntValue)
+	if !ok {
+		return false
+	}
+	if up {
+		if iv.Value == math.MaxInt {
+			return false
+		}
+		zv.Value = &data.IntValue{Value: iv.Value + 1}
+	} else {
+		if iv.Value == math.MinInt {
+			return false
+		}
+		zv.Value = &data.IntValue{Value: iv.Value - 1}
+	}
+	if binder, ok := ctx.(data.StaticLocalsBinder); ok {
+		if locals := binder.StaticLocalsStore(); locals != nil {
+			locals.Update(index, zv.Value)
+		}
+	}
+	return true
+}
+
+// intArith 整数运算的快速路径，溢出时与树遍历一样得到 float
+func intArith(op Op, l, r int) data.GetValue {
+	switch op {
+	case OpAdd:
+		return data.AddInt(l, r)
+	case OpSub:
+		return data.SubInt(l, r)
+	case OpMul:
+		return data.MulInt(l, r)
+	}
+	if r == -1 {
+		return data.NewIntValue(0)
+	}
+	return data.NewIntValue(l % r)
+}
+
+func intCompare(op Op, l, r int) bool {
+	switch op {
+	case OpLt:
+		return l < r
+	case OpLe:
+		return l <= r
+	case OpGt:
+		return l > r
+	case OpGe:
+		return l >= r
+	case OpEq:
+		return l == r
+	}
+	return l != r
+}
+
+// truth 条件值转为 bool，与树遍历的 if/while 判断一致
+func (c *Chunk) truth(v data.GetValue, from int32) (bool, data.Control) {
+	if b, ok := v.(*data.BoolValue); ok {
+		return b.Value, nil
+	}
+	if b, ok := v.(data.AsBool); ok {
+		t, err := b.AsBool()
+		if err != nil {
+			return false, data.NewErrorThrow(c.froms[from], err)
+		}
+		return t, nil
+	}
+	return v != nil, nil
+}
+
+// control 处理树遍历节点返回的控制流：chunk 内循环的 break/continue 转为跳转，其余原样返回
+func (c *Chunk) control(in *Instr, ctl data.Control) (int, data.Control) {
+	if in.C == 0 {
+		return 0, fillFrom(c.nodes[in.B], ctl)
+	}
+	target := in.C - 1
+	if b, ok := ctl.(data.BreakControl); ok && b.IsBreak() {
+		level := 1
+		if bs, ok := ctl.(*node.BreakStatement); ok && bs.Level > 1 {
+			level = bs.Level
+		}
+		for ; level > 1; level-- {
+			target = c.loops[target].parent
+			if target < 0 {
+				return 0, &node.BreakStatement{Node: ctl.(*node.BreakStatement).Node, Level: level - 1}
+			}
+		}
+		return int(c.loops[target].brk), nil
+	}
+	if b, ok := ctl.(data.ContinueControl); ok && b.IsContinue() {
+		return int(c.loops[target].cont), nil
+	}
+	return 0, fillFrom(c.nodes[in.B], ctl)
+}
+
+// fillFrom 没有位置的异常补上出错语句的位置
+func fillFrom(stmt data.GetValue, ctl data.Control) data.Control {
+	if e, ok := ctl.(*data.ThrowValue); ok && e.Error.From == nil {
+		if gf, ok := stmt.(node.GetFrom); ok && gf.GetFrom() != nil {
+			e.Error.From = gf.GetFrom()
+		}
+	}
+	return ctl
+}
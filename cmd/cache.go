@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/php-any/origami/opcache"
+	"github.com/php-any/origami/std/php/core"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理分词结果的磁盘缓存",
+	Long: `管理分词结果的磁盘缓存（opcache）。
+
+缓存以文件路径、修改时间、大小和内容哈希为键，源文件变化后自动失效。
+通过 ini 配置（可用 -d 在命令行设置）:
+  zy.cache_enable  是否启用，默认 0（与 PHP 的 opcache.enable_cli 一致）
+  zy.cache_dir     缓存目录，默认为用户缓存目录下的 zy
+
+示例:
+  zy cache stats
+  zy cache clear
+  zy -d zy.cache_enable=1 script.php`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		opcache.Setting = core.IniGet
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "查看缓存目录、文件数与大小",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := opcache.Stat()
+		if err != nil {
+			return err
+		}
+		state := "启用"
+		if !opcache.Enabled() {
+			state = "关闭"
+		}
+		fmt.Printf("状态: %s\n目录: %s\n缓存文件: %d（已失效 %d）\n大小: %d 字节\n", state, st.Dir, st.Entries, st.Stale, st.Bytes)
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "删除所有缓存文件",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := opcache.Clear()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已删除 %d 个缓存文件: %s\n", n, opcache.Dir())
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd)
+}
@@ -16,6 +16,7 @@ var (
 	compilePkg    string
 	compileBuild  bool
 	compileEntry  string
+	compileNoOpt  bool
 )
 
 // NewCommand 创建 compile 子命令。
@@ -42,6 +43,7 @@ func NewCommand(loader func(vm data.VM)) *cobra.Command {
 	cmd.Flags().StringVar(&compilePkg, "pkg", "build", "生成的 Go 包名")
 	cmd.Flags().BoolVarP(&compileBuild, "build", "b", false, "编译为独立二进制")
 	cmd.Flags().StringVar(&compileEntry, "entry", "", "入口 PHP 文件（--build 模式必填）")
+	cmd.Flags().BoolVar(&compileNoOpt, "no-opt", false, "生成代码前不优化语法树")
 	return cmd
 }
 
@@ -26,6 +26,16 @@ func (g *Generator) genTypes(ty data.Types) {
 			g.genTypes(ut)
 		}
 		g.printf("})")
+	case data.Generic:
+		g.printf("data.Generic{Name: %q, Bound: ", t.Name)
+		g.genTypes(t.Bound)
+		g.printf("}")
+	case data.TypedArray:
+		g.printf("data.TypedArray{Key: ")
+		g.genTypes(t.Key)
+		g.printf(", Value: ")
+		g.genTypes(t.Value)
+		g.printf("}")
 	default:
 		g.printf("data.NewBaseType(%q)", ty.String())
 	}
@@ -25,6 +25,7 @@ type ParsedFile struct {
 func parseFiles(files []string) ([]ParsedFile, []error) {
 	p := parser.NewParser()
 	baseVM := runtime.NewVM(p)
+	baseVM.(*runtime.VM).SetOptimize(!compileNoOpt)
 	if runtimeLoader != nil {
 		runtimeLoader(baseVM)
 	}
@@ -48,6 +49,7 @@ func parseFiles(files []string) ([]ParsedFile, []error) {
 			errs = append(errs, fmt.Errorf("解析 %s 失败: %v", file, acl))
 			continue
 		}
+		baseVM.(*runtime.VM).Optimize(program, clone)
 		augmentProgramASTFromBase(program, baseVM.(*runtime.VM), file)
 		vars := clone.GetVariables()
 		parsed = append(parsed, ParsedFile{
@@ -62,7 +62,7 @@ func (g *Generator) emitStructLiteral(v data.GetValue) error {
 		if f.Anonymous && f.Name == "Node" && f.Tag.Get("pp") == "-" {
 			needsNode = true
 		}
-		if !f.IsExported() && !(f.Anonymous && f.Name == "Node") {
+		if !f.IsExported() && !(f.Anonymous && f.Name == "Node") && f.Tag.Get("pp") != "-" {
 			return fmt.Errorf("unexported field %s", f.Name)
 		}
 	}
@@ -2,6 +2,7 @@ package compile
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
@@ -226,6 +227,36 @@ func (g *Generator) emitClassStatementInit(n *node.ClassStatement) error {
 	if n.IsAbstract {
 		g.printf("cls.IsAbstract = true\n")
 	}
+	if n.IsReadonly {
+		g.printf("cls.IsReadonly = true\n")
+	}
+	// 静态属性声明只用于写入时的类型检查，只输出带类型的属性且不含默认值
+	var typedStatic []string
+	for name, prop := range n.StaticProperties {
+		if prop.GetType() != nil {
+			typedStatic = append(typedStatic, name)
+		}
+	}
+	if len(typedStatic) > 0 {
+		sort.Strings(typedStatic)
+		g.printf("cls.StaticProperties = map[string]data.Property{\n")
+		g.indent++
+		for _, name := range typedStatic {
+			decl, ok := n.StaticProperties[name].(*node.ClassProperty)
+			if !ok {
+				continue
+			}
+			typed := *decl
+			typed.DefaultValue = nil
+			g.printf("%q: ", name)
+			if err := g.emitClassProperty(&typed); err != nil {
+				return err
+			}
+			g.printf(",\n")
+		}
+		g.indent--
+		g.printf("}\n")
+	}
 	if len(n.StaticMethods) > 0 {
 		g.printf("cls.StaticMethods = map[string]data.Method{\n")
 		g.indent++
@@ -777,6 +808,23 @@ func (g *Generator) emitClassProperty(p data.Property) error {
 		g.genTypes(cp.Type)
 	}
 	g.printf(")")
+	if cp.WriteModifier != data.ModifierPublic {
+		g.printf(".WithWriteModifier(%q)", modifierName(cp.WriteModifier))
+	}
+	if cp.HasHooks() {
+		g.printf(".WithHooks(")
+		for i, hook := range []data.Method{cp.GetHook, cp.SetHook} {
+			if i > 0 {
+				g.printf(", ")
+			}
+			if hook == nil {
+				g.printf("nil")
+			} else if err := g.emitClassMethod(hook); err != nil {
+				return err
+			}
+		}
+		g.printf(")")
+	}
 	return nil
 }
 
@@ -27,6 +27,8 @@ var (
 	phptShowSummary    bool
 	phptFailFastExpect bool
 	phptSkipDeprecated bool
+	// phptEngine 执行用例的引擎，取自全局 --engine 选项并透传给子进程
+	phptEngine string
 )
 
 var phptSectionHeaderRe = regexp.MustCompile(`^--([_A-Z]+)--`)
@@ -51,7 +53,8 @@ func NewCommand() *cobra.Command {
   zy phpt
   zy phpt php-src/tests
   zy phpt php-src/tests/basic/001.phpt
-  zy phpt --skip-deprecated=false   # 包含弃用相关用例`,
+  zy phpt --skip-deprecated=false   # 包含弃用相关用例
+  zy phpt --engine=bytecode         # 以字节码引擎执行用例`,
 		RunE: runPhptCommand,
 	}
 	cmd.Flags().BoolVarP(&phptVerboseOutput, "verbose", "v", false, "输出 PASS/SKIP 明细")
@@ -63,6 +66,7 @@ func NewCommand() *cobra.Command {
 
 func runPhptCommand(cmd *cobra.Command, args []string) error {
 	target := "php-src/tests"
+	phptEngine, _ = cmd.Flags().GetString("engine")
 	if len(args) > 0 {
 		target = args[0]
 	}
@@ -434,6 +438,9 @@ func runPhpSnippet(code string, sections map[string]string, phptPath string) (st
 		return "", err
 	}
 	cmdArgs := []string{runPath}
+	if phptEngine != "" {
+		cmdArgs = []string{"--engine=" + phptEngine, runPath}
+	}
 	if sections != nil {
 		if rawArgs := strings.TrimSpace(sections["ARGS"]); rawArgs != "" {
 			cmdArgs = append(cmdArgs, strings.Fields(rawArgs)...)
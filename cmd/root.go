@@ -1,13 +1,22 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/php-any/origami/runtime"
+	"github.com/php-any/origami/std/php/core"
 	"github.com/spf13/cobra"
 )
 
+// noOpt 关闭语法树优化（--no-opt），便于调试时对照原始语法树执行
+var noOpt bool
+
+// engine 执行引擎（--engine）：ast 直接遍历语法树，bytecode 把控制流语句编译为字节码执行
+var engine = runtime.EngineAST
+
 var rootCmd = &cobra.Command{
 	Use:   "zy [脚本路径]",
 	Short: "折言(origami-lang) - 融合型脚本语言",
@@ -35,12 +44,45 @@ func IsDirectScriptArg(arg string) bool {
 		return false
 	}
 	switch arg {
-	case "gen-std", "help", "completion", "phpt", "compile", "init":
+	case "gen-std", "help", "completion", "phpt", "compile", "init", "cache":
 		return false
 	}
 	return true
 }
 
+// ParseRunFlags 取出脚本路径之前的全局选项（如 zy --no-opt --engine=bytecode -d key=value script.php），返回剩余参数
+func ParseRunFlags(args []string) []string {
+	for len(args) > 0 {
+		switch {
+		case args[0] == "--no-opt":
+			noOpt = true
+		case args[0] == "-d" && len(args) > 1:
+			setIni(args[1])
+			args = args[1:]
+		case strings.HasPrefix(args[0], "-d") && len(args[0]) > 2:
+			setIni(args[0][2:])
+		case args[0] == "--engine" && len(args) > 1:
+			engine = args[1]
+			args = args[1:]
+		case strings.HasPrefix(args[0], "--engine="):
+			engine = strings.TrimPrefix(args[0], "--engine=")
+		default:
+			return args
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// setIni 处理 -d key=value，与 php -d 一致，只写 key 时值为 "1"
+func setIni(kv string) {
+	key, value, ok := strings.Cut(kv, "=")
+	if !ok {
+		value = "1"
+	}
+	core.IniSet(strings.TrimSpace(key), strings.TrimSpace(value))
+}
+
 // RunScriptFile 直接运行指定脚本，等价于 zy <脚本路径>。
 func RunScriptFile(scriptPath string) error {
 	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
@@ -49,19 +91,26 @@ func RunScriptFile(scriptPath string) error {
 	}
 
 	vm, p := getRuntimeVM()
-	_, err := vm.LoadAndRun(scriptPath)
-	if err != nil {
-		p.ShowControl(err)
+	_, acl := vm.LoadAndRun(scriptPath)
+	if acl != nil {
+		p.ShowControl(acl)
 	}
 	vm.RunShutdownCallbacks()
+	if acl != nil {
+		// 语法错误或未捕获的异常：以非零状态退出
+		return errors.New(acl.AsString())
+	}
 	return nil
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&noOpt, "no-opt", false, "关闭语法树优化（常量折叠、常量内联、删除不可达分支），用于调试")
+	rootCmd.PersistentFlags().StringVar(&engine, "engine", runtime.EngineAST, "执行引擎：ast（遍历语法树）或 bytecode（字节码）")
 	rootCmd.AddCommand(genStdCmd)
 	rootCmd.AddCommand(phptCmd)
 	rootCmd.AddCommand(compileCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(cacheCmd)
 }
 
 // RootHelp 显示根命令帮助信息。
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/parser"
 	"github.com/php-any/origami/runtime"
@@ -18,7 +21,12 @@ func getRuntimeVM() (*runtime.VM, *parser.Parser) {
 		panic("runtime loader not set, call cmd.SetRuntimeLoader from main")
 	}
 	p := parser.NewParser()
-	vm := runtime.NewVM(p)
+	vm := runtime.NewVM(p).(*runtime.VM)
+	vm.SetOptimize(!noOpt)
+	if err := vm.SetEngine(engine); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
 	runtimeLoader(vm)
-	return vm.(*runtime.VM), p
+	return vm, p
 }
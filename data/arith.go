@@ -0,0 +1,362 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 算术运算的错误，调用方转为同名的 PHP 异常
+var (
+	ErrDivisionByZero = errors.New("Division by zero")
+	ErrModuloByZero   = errors.New("Modulo by zero")
+	ErrNegativeShift  = errors.New("Bit shift by negative number")
+)
+
+// ArithErrorName 算术运算错误对应的 PHP 异常类名
+func ArithErrorName(err error) string {
+	switch err {
+	case ErrDivisionByZero, ErrModuloByZero:
+		return "DivisionByZeroError"
+	case ErrNegativeShift:
+		return "ArithmeticError"
+	}
+	return "Error"
+}
+
+// NumberKind 算术操作数转为数字的结果
+type NumberKind int
+
+const (
+	// NumberOK 数字、null、bool 或数字字符串
+	NumberOK NumberKind = iota
+	// NumberLeading "12abc" 这类前导数字字符串，取前导数字，PHP 输出 Warning: A non-numeric value encountered
+	NumberLeading
+	// NumberInvalid 非数字字符串、数组、对象等，PHP 抛出 TypeError: Unsupported operand types
+	NumberInvalid
+)
+
+// ToNumber 按 PHP 8 的规则将算术运算的操作数转为 *IntValue 或 *FloatValue：
+// null 为 0，bool 为 0/1，字符串允许前后空白，整数形式溢出时为 float
+func ToNumber(v Value) (Value, NumberKind) {
+	switch n := v.(type) {
+	case *IntValue, *FloatValue:
+		return n, NumberOK
+	case *NullValue:
+		return NewIntValue(0), NumberOK
+	case *BoolValue:
+		if n.Value {
+			return NewIntValue(1), NumberOK
+		}
+		return NewIntValue(0), NumberOK
+	case *StringValue:
+		if num, ok := ParseNumericString(n.Value); ok {
+			return num, NumberOK
+		}
+		if num, ok := parseLeadingNumeric(n.Value); ok {
+			return num, NumberLeading
+		}
+	}
+	return nil, NumberInvalid
+}
+
+// parseLeadingNumeric 解析 "12abc"、" 1.5e3 apples" 这类以数字开头的字符串的前导数字部分
+func parseLeadingNumeric(s string) (Value, bool) {
+	s = strings.TrimLeft(s, numericWhitespace)
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		j := i + 1
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+			digits++
+		}
+		if digits > 0 {
+			i = j
+		}
+	}
+	if digits == 0 {
+		return nil, false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	return ParseNumericString(s[:i])
+}
+
+// TypeName PHP 错误信息中的类型名（get_debug_type 风格），对象为类名
+func TypeName(v Value) string {
+	switch o := v.(type) {
+	case *IntValue:
+		return "int"
+	case *FloatValue:
+		return "float"
+	case *BoolValue:
+		return "bool"
+	case *StringValue:
+		return "string"
+	case *NullValue, nil:
+		return "null"
+	case *ArrayValue:
+		return "array"
+	case *ClassValue:
+		if o.Class != nil {
+			return o.Class.GetName()
+		}
+	case *ThisValue:
+		if o.ClassValue != nil && o.Class != nil {
+			return o.Class.GetName()
+		}
+	}
+	return "object"
+}
+
+// CheckedAdd 整数相加，溢出时 ok 为 false
+func CheckedAdd(l, r int) (int, bool) {
+	s := l + r
+	return s, (s > l) == (r > 0)
+}
+
+// CheckedSub 整数相减，溢出时 ok 为 false
+func CheckedSub(l, r int) (int, bool) {
+	s := l - r
+	return s, (s < l) == (r > 0)
+}
+
+// CheckedMul 整数相乘，溢出时 ok 为 false
+func CheckedMul(l, r int) (int, bool) {
+	if l == 0 || r == 0 {
+		return 0, true
+	}
+	if (l == -1 && r == math.MinInt) || (r == -1 && l == math.MinInt) {
+		return 0, false
+	}
+	p := l * r
+	return p, p/r == l
+}
+
+// AddInt 整数相加，溢出时与 PHP 一样得到 float
+func AddInt(l, r int) Value {
+	if s, ok := CheckedAdd(l, r); ok {
+		return NewIntValue(s)
+	}
+	return NewFloatValue(float64(l) + float64(r))
+}
+
+// SubInt 整数相减，溢出时得到 float
+func SubInt(l, r int) Value {
+	if s, ok := CheckedSub(l, r); ok {
+		return NewIntValue(s)
+	}
+	return NewFloatValue(float64(l) - float64(r))
+}
+
+// MulInt 整数相乘，溢出时得到 float
+func MulInt(l, r int) Value {
+	if p, ok := CheckedMul(l, r); ok {
+		return NewIntValue(p)
+	}
+	return NewFloatValue(float64(l) * float64(r))
+}
+
+// PowInt 整数的非负整数次幂，溢出时改用 float 计算
+func PowInt(base, exp int) Value {
+	result := 1
+	b := base
+	for e := exp; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			v, ok := CheckedMul(result, b)
+			if !ok {
+				return NewFloatValue(math.Pow(float64(base), float64(exp)))
+			}
+			result = v
+		}
+		if e > 1 {
+			v, ok := CheckedMul(b, b)
+			if !ok {
+				return NewFloatValue(math.Pow(float64(base), float64(exp)))
+			}
+			b = v
+		}
+	}
+	return NewIntValue(result)
+}
+
+// FloatToInt 与 PHP 的 (int) 转换一致：NaN、无穷为 0，超出范围时按 2^64 取模
+func FloatToInt(f float64) int {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	if f >= -9223372036854775808.0 && f < 9223372036854775808.0 {
+		return int(f)
+	}
+	const two64 = 18446744073709551616.0
+	m := math.Mod(math.Trunc(f), two64)
+	if m < 0 {
+		m += two64
+	}
+	return int(uint64(m))
+}
+
+// Arith 对已由 ToNumber 转换的操作数按 PHP 规则计算 + - * / % **，
+// op 为 'p' 表示 **，'l' 与 'r' 表示 << 与 >>
+func Arith(op byte, l, r Value) (Value, error) {
+	li, lInt := l.(*IntValue)
+	ri, rInt := r.(*IntValue)
+	switch op {
+	case '%':
+		a, b := numberToInt(l), numberToInt(r)
+		if b == 0 {
+			return nil, ErrModuloByZero
+		}
+		if b == -1 {
+			return NewIntValue(0), nil
+		}
+		return NewIntValue(a % b), nil
+	case 'l', 'r':
+		a, b := numberToInt(l), numberToInt(r)
+		if b < 0 {
+			return nil, ErrNegativeShift
+		}
+		// 移位数不小于 64 时 Go 与 PHP 一样得到 0（右移负数得到 -1）
+		if op == 'l' {
+			return NewIntValue(a << b), nil
+		}
+		return NewIntValue(a >> b), nil
+	}
+	if lInt && rInt {
+		switch op {
+		case '+':
+			return AddInt(li.Value, ri.Value), nil
+		case '-':
+			return SubInt(li.Value, ri.Value), nil
+		case '*':
+			return MulInt(li.Value, ri.Value), nil
+		case '/':
+			if ri.Value == 0 {
+				return nil, ErrDivisionByZero
+			}
+			if li.Value%ri.Value == 0 && !(li.Value == math.MinInt && ri.Value == -1) {
+				return NewIntValue(li.Value / ri.Value), nil
+			}
+			return NewFloatValue(float64(li.Value) / float64(ri.Value)), nil
+		case 'p':
+			if ri.Value >= 0 {
+				return PowInt(li.Value, ri.Value), nil
+			}
+		}
+	}
+	a, b := numberToFloat(l), numberToFloat(r)
+	switch op {
+	case '+':
+		return NewFloatValue(a + b), nil
+	case '-':
+		return NewFloatValue(a - b), nil
+	case '*':
+		return NewFloatValue(a * b), nil
+	case '/':
+		if b == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return NewFloatValue(a / b), nil
+	case 'p':
+		return NewFloatValue(math.Pow(a, b)), nil
+	}
+	return nil, errors.New("unknown arithmetic operator " + strconv.QuoteRune(rune(op)))
+}
+
+// Negate 一元负号，与 PHP 一样按乘以 -1 计算：-0 仍是 int 0，-PHP_INT_MIN 为 float
+func Negate(n Value) Value {
+	if i, ok := n.(*IntValue); ok {
+		return MulInt(i.Value, -1)
+	}
+	return NewFloatValue(-numberToFloat(n))
+}
+
+func numberToInt(v Value) int {
+	switch n := v.(type) {
+	case *IntValue:
+		return n.Value
+	case *FloatValue:
+		return FloatToInt(n.Value)
+	}
+	return 0
+}
+
+func numberToFloat(v Value) float64 {
+	switch n := v.(type) {
+	case *IntValue:
+		return float64(n.Value)
+	case *FloatValue:
+		return n.Value
+	}
+	return 0
+}
+
+// IncrementString 字符串的 ++：数字字符串加 1，空字符串为 "1"，
+// 其它字符串按字母数字进位（"a" → "b"、"Az" → "Ba"、"zz" → "aaa"）
+func IncrementString(s string) Value {
+	if s == "" {
+		return NewStringValue("1")
+	}
+	if n, ok := ParseNumericString(s); ok {
+		v, _ := Arith('+', n, NewIntValue(1))
+		return v
+	}
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		switch c := b[i]; {
+		case c >= 'a' && c < 'z', c >= 'A' && c < 'Z', c >= '0' && c < '9':
+			b[i]++
+			return NewStringValue(string(b))
+		case c == 'z':
+			b[i] = 'a'
+		case c == 'Z':
+			b[i] = 'A'
+		case c == '9':
+			b[i] = '0'
+		default:
+			// 遇到非字母数字字符时停止进位
+			return NewStringValue(string(b))
+		}
+	}
+	var first byte
+	switch c := s[0]; {
+	case c >= 'a' && c <= 'z':
+		first = 'a'
+	case c >= 'A' && c <= 'Z':
+		first = 'A'
+	default:
+		first = '1'
+	}
+	return NewStringValue(string(first) + string(b))
+}
+
+// DecrementString 字符串的 --：数字字符串减 1，空字符串为 -1，其它字符串不变
+func DecrementString(s string) Value {
+	if s == "" {
+		return NewIntValue(-1)
+	}
+	if n, ok := ParseNumericString(s); ok {
+		v, _ := Arith('-', n, NewIntValue(1))
+		return v
+	}
+	return NewStringValue(s)
+}
@@ -44,17 +44,3 @@ type GotoControl interface {
 	// GetLabel 获取跳转标签
 	GetLabel() string
 }
-
-// YieldValueControl 表示生成器yield控制流
-type YieldValueControl interface {
-	Control
-	GetYieldKey() Value
-	GetYieldValue() Value
-}
-
-// YieldControl 函数Yield中断
-type YieldControl interface {
-	Control
-	YieldValueControl
-	CreateStackState(ctx Context, fn FuncStmt, originalBody []GetValue, bodyIndex int) Generator
-}
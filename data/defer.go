@@ -0,0 +1,103 @@
+package data
+
+// defer 语句登记的延迟执行项。
+//
+// 延迟执行项保存在所属执行流（Routine）中，按函数帧（运行时上下文）区分：函数、方法、闭包返回或抛出异常时
+// 由 RunDefers 按后进先出执行本帧登记的项，生成器在函数体执行完毕或被销毁时执行；exit 与脚本结束时
+// 由 RunPendingDefers 执行执行流中仍未执行的项。延迟执行项在执行时才求值，看到的是变量当时的值。
+
+// deferEntry 一个延迟执行项：frame 为登记时的函数帧，ctx 为执行时使用的上下文（保留 $this 与绑定作用域）
+type deferEntry struct {
+	frame RoutineContext
+	ctx   Context
+	work  GetValue
+}
+
+// PushDefer defer 语句调用：在 ctx 所在的函数帧登记延迟执行项；没有执行流时返回 false
+func PushDefer(ctx Context, work GetValue) bool {
+	frame := routineContext(ctx)
+	if frame == nil {
+		return false
+	}
+	r := frame.Routine()
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	r.defers = append(r.defers, deferEntry{frame: frame, ctx: ctx, work: work})
+	r.deferCount.Add(1)
+	r.mu.Unlock()
+	return true
+}
+
+// RunDefers 函数帧结束时（defer 调用）按后进先出执行本帧登记的延迟执行项；
+// 延迟执行项抛出的异常替换函数原来的结果，之后的项仍会执行。用户函数返回时都会调用，同时结束 freshObject 的有效期
+func RunDefers(ctx Context, acl *Control) {
+	clearFresh()
+	frame := routineContext(ctx)
+	if frame == nil {
+		return
+	}
+	r := frame.Routine()
+	if r == nil || r.deferCount.Load() == 0 {
+		return
+	}
+	for _, e := range r.takeDefers(frame) {
+		if ctl := e.run(); ctl != nil {
+			*acl = ctl
+		}
+	}
+}
+
+// RunPendingDefers exit 与脚本结束时按后进先出执行 ctx 所属执行流中尚未执行的延迟执行项，
+// 返回第一个未捕获的异常
+func RunPendingDefers(ctx Context) Control {
+	return RoutineOf(ctx).RunPendingDefers()
+}
+
+// RunPendingDefers 见 data.RunPendingDefers
+func (r *Routine) RunPendingDefers() Control {
+	if r == nil {
+		return nil
+	}
+	var first Control
+	for r.deferCount.Load() != 0 {
+		for _, e := range r.takeDefers(nil) {
+			if ctl := e.run(); ctl != nil && first == nil {
+				first = ctl
+			}
+		}
+	}
+	return first
+}
+
+// takeDefers 取出 frame 登记的延迟执行项（frame 为 nil 时取出全部），按执行顺序（登记的逆序）返回
+func (r *Routine) takeDefers(frame RoutineContext) []deferEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var taken []deferEntry
+	kept := r.defers[:0]
+	for _, e := range r.defers {
+		if frame == nil || e.frame == frame {
+			taken = append(taken, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	clear(r.defers[len(kept):])
+	r.defers = kept
+	r.deferCount.Add(int32(-len(taken)))
+	for i, j := 0, len(taken)-1; i < j; i, j = i+1, j-1 {
+		taken[i], taken[j] = taken[j], taken[i]
+	}
+	return taken
+}
+
+func (e deferEntry) run() Control {
+	_, ctl := e.work.GetValue(e.ctx)
+	switch ctl.(type) {
+	case nil, ReturnControl:
+		return nil
+	}
+	return ctl
+}
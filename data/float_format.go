@@ -0,0 +1,120 @@
+package data
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// precision 与 serialize_precision 两个 ini 配置的当前值，由 ini_set 同步
+var (
+	floatPrecision     atomic.Int32
+	serializePrecision atomic.Int32
+)
+
+func init() {
+	floatPrecision.Store(14)
+	serializePrecision.Store(-1)
+}
+
+// SetFloatPrecision 设置 ini precision：浮点数转字符串（echo、字符串拼接、print_r）的有效数字位数
+func SetFloatPrecision(p int) {
+	floatPrecision.Store(int32(p))
+}
+
+// SetSerializePrecision 设置 ini serialize_precision：var_dump、var_export、json_encode、serialize
+// 输出浮点数的有效数字位数，-1 表示能精确还原的最短形式
+func SetSerializePrecision(p int) {
+	serializePrecision.Store(int32(p))
+}
+
+// FormatFloat 按 precision 将浮点数转为字符串，与 PHP 的 (string)$float 一致
+func FormatFloat(f float64) string {
+	return formatFloat(f, int(floatPrecision.Load()), 'E')
+}
+
+// SerializeFloat 按 serialize_precision 输出浮点数；zeroFrac 为 true 时整数值补 ".0"（var_export）；
+// exp 为指数符号，json_encode 使用 'e'
+func SerializeFloat(f float64, zeroFrac bool, exp byte) string {
+	s := formatFloat(f, int(serializePrecision.Load()), exp)
+	if zeroFrac && !math.IsInf(f, 0) && !math.IsNaN(f) && !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+// formatFloat 移植自 zend_gcvt：precision 小于 0 时取最短还原形式（最多 17 位），
+// 小数点位置超过有效位数或小于 -3 时使用 1.0E+25 形式的指数表示
+func formatFloat(f float64, precision int, exp byte) string {
+	switch {
+	case math.IsNaN(f):
+		return "NAN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+	if f == 0 {
+		if math.Signbit(f) {
+			return "-0"
+		}
+		return "0"
+	}
+	if precision == 0 {
+		precision = 1
+	}
+	digitsPrec := precision - 1
+	if precision < 0 {
+		precision = 17
+		digitsPrec = -1
+	} else if precision > 40 {
+		precision, digitsPrec = 40, 39
+	}
+	e := strconv.FormatFloat(math.Abs(f), 'e', digitsPrec, 64)
+	mant, expPart, _ := strings.Cut(e, "e")
+	digits := strings.TrimRight(strings.Replace(mant, ".", "", 1), "0")
+	if digits == "" {
+		digits = "0"
+	}
+	n, _ := strconv.Atoi(expPart)
+	decpt := n + 1
+
+	var b strings.Builder
+	if f < 0 {
+		b.WriteByte('-')
+	}
+	switch {
+	case decpt < -3 || decpt > precision:
+		b.WriteByte(digits[0])
+		b.WriteByte('.')
+		if len(digits) == 1 {
+			b.WriteByte('0')
+		} else {
+			b.WriteString(digits[1:])
+		}
+		b.WriteByte(exp)
+		decpt--
+		if decpt < 0 {
+			b.WriteByte('-')
+			decpt = -decpt
+		} else {
+			b.WriteByte('+')
+		}
+		b.WriteString(strconv.Itoa(decpt))
+	case decpt <= 0:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -decpt))
+		b.WriteString(digits)
+	default:
+		if len(digits) <= decpt {
+			b.WriteString(digits)
+			b.WriteString(strings.Repeat("0", decpt-len(digits)))
+		} else {
+			b.WriteString(digits[:decpt])
+			b.WriteByte('.')
+			b.WriteString(digits[decpt:])
+		}
+	}
+	return b.String()
+}
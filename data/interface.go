@@ -44,7 +44,7 @@ type Generator interface {
 	// Send 向暂停中的生成器传递一个值
 	Send(ctx Context, value Value) Control
 	// Throw 向暂停中的生成器抛出一个异常
-	Throw(ctx Context) Control
+	Throw(ctx Context, exception Value) Control
 	// GetReturn 获取生成器函数的 return 值。
 	GetReturn(ctx Context) (Value, Control)
 }
@@ -0,0 +1,580 @@
+package data
+
+import (
+	"cmp"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weak"
+)
+
+// 对象的生命周期与析构函数（__destruct）。
+//
+// 只登记定义了 __destruct 或被弱引用（WeakReference、WeakMap）的对象，其余对象完全交给 Go 的 GC。
+// 运行时没有引用计数，析构时机按对象的使用方式分两类：
+//   - `$x = new Foo(...);` 语句创建、此后只作为方法调用或属性访问的接收者使用的对象归变量 $x 的 ZVal 所有，
+//     变量被覆盖、unset 或函数返回（包括抛出异常）时立即析构，与 PHP 的引用计数一致；
+//   - 其它对象（变量被读出、放进数组或属性、作为参数传递过）在某个变量丢弃了非标量的值（对象、数组、闭包等）之后，
+//     由下一个语句边界（Safepoint）强制一次 GC，经弱引用确认不可达后析构。强制 GC 的累计耗时超过 gcAllowance 后
+//     不超过运行时间的 1/gcBudget，超出时推迟到后面的语句边界；Go 的 GC 自行回收的对象同样在之后的语句边界析构。
+//
+// 确认不可达时对象本身已被回收，析构函数在一个共用原属性存储、沿用原标识（spl_object_id）的新实例上执行。
+// 脚本结束时的顺序见 DestroyObjects。
+
+const (
+	lifeAlive int32 = iota
+	lifeDestructed
+)
+
+// gcBudget 强制 GC 的总耗时超过 gcAllowance 后，不超过运行时间的 1/gcBudget
+const (
+	gcBudget    = 10
+	gcAllowance = 50 * time.Millisecond
+	// gcDeferSteps 超出预算后再次读取时间前跳过的语句边界数
+	gcDeferSteps = 256
+)
+
+// maxCollectRounds 一个语句边界最多连续确认的轮数：析构释放的属性可能让更多对象不可达
+const maxCollectRounds = 8
+
+// escapedOwner 表示对象已被读出，不再归属任何变量
+var escapedOwner = &ZVal{}
+
+// lifetime 已登记对象的生命周期；不直接引用对象，对象被回收后仍保留重建实例所需的类、上下文与属性
+type lifetime struct {
+	seq    uint64
+	vm     VM
+	class  ClassStmt
+	ctx    Context
+	store  PropertyStore
+	object weak.Pointer[ObjectValue]
+	dtor   Method
+
+	owner atomic.Pointer[ZVal] // nil：新建；escapedOwner：已读出；其它：所属变量
+	state atomic.Int32
+	// internal 内置类的对象（生成器）：不计入 unowned，丢弃值时不为它强制 GC，
+	// 不归属变量后只在 Go 的 GC 自行回收或脚本结束时析构
+	internal bool
+	ref      Value        // WeakReference::create 对同一对象返回同一个实例
+	id       atomic.Int64 // 对象的标识，重建的实例沿用，见 ObjectValue.ID
+}
+
+var lifetimes struct {
+	sync.Mutex
+	all     map[*lifetime]struct{}
+	seq     uint64
+	count   atomic.Int32 // 存活的登记对象数，为 0 时各处钩子直接返回
+	unowned atomic.Int32 // 其中不归属任何变量（新建或已读出）的对象数，为 0 时丢弃值不需要强制 GC
+}
+
+var collector struct {
+	sync.Mutex
+	pending atomic.Bool // 有对象可能已不可达，下一个语句边界检查
+	force   atomic.Bool // 检查前需要强制 GC（Go 的 GC 自行回收时只需检查）
+	start   time.Time
+	spent   time.Duration
+	skip    int // 超出预算后跳过的语句边界数，避免每个语句边界都读取时间
+}
+
+func init() {
+	collector.start = time.Now()
+}
+
+// destructorEntry 缓存类的析构函数，method 为 nil 表示没有
+type destructorEntry struct {
+	method Method
+}
+
+// builtinDestructors 未实现 ShapeHolder 的类（内置类）的析构函数，按 Go 类型缓存
+var builtinDestructors sync.Map
+
+func classDestructor(cv *ClassValue) Method {
+	if h, ok := cv.Class.(ShapeHolder); ok {
+		c := h.ShapeCache()
+		if e := c.destructor.Load(); e != nil {
+			return e.method
+		}
+		m, _ := cv.GetMethod("__destruct")
+		c.destructor.Store(&destructorEntry{method: m})
+		return m
+	}
+	t := reflect.TypeOf(cv.Class)
+	if e, ok := builtinDestructors.Load(t); ok {
+		return e.(*destructorEntry).method
+	}
+	m, _ := cv.GetMethod("__destruct")
+	builtinDestructors.Store(t, &destructorEntry{method: m})
+	return m
+}
+
+// TrackObject 创建对象后、调用构造函数前登记对象；类没有 __destruct 时不登记
+func TrackObject(cv *ClassValue) {
+	if cv == nil || cv.ObjectValue == nil || cv.life != nil || cv.Class == nil {
+		return
+	}
+	if dtor := classDestructor(cv); dtor != nil {
+		track(cv, dtor, false)
+	}
+}
+
+// TrackInternal 登记内置类的对象：与定义了 __destruct 的对象一样在被释放时析构，析构时调用 dtor（如销毁生成器）；
+// 返回的 forget 在对象不再需要析构时（如生成器已结束）注销对象，不持有对象本身
+func TrackInternal(cv *ClassValue, dtor Method) (forget func()) {
+	if cv == nil || cv.ObjectValue == nil || cv.life != nil || dtor == nil {
+		return func() {}
+	}
+	l := track(cv, dtor, true)
+	return func() {
+		if l.state.CompareAndSwap(lifeAlive, lifeDestructed) {
+			unregister(l)
+		}
+	}
+}
+
+// ForgetObject 构造函数抛出异常时调用：对象不再析构，弱引用随即失效
+func ForgetObject(cv *ClassValue) {
+	if l := cv.life; l != nil && l.state.CompareAndSwap(lifeAlive, lifeDestructed) {
+		unregister(l)
+	}
+}
+
+func track(cv *ClassValue, dtor Method, internal bool) *lifetime {
+	o := cv.ObjectValue
+	l := &lifetime{class: cv.Class, ctx: cv.Context, store: o.property, dtor: dtor, object: weak.Make(o), internal: internal}
+	if cv.Context != nil {
+		l.vm = cv.GetVM()
+	}
+	l.id.Store(atomic.LoadInt64(&o.id))
+	o.life = l
+	runtime.AddCleanup(o, collected, l)
+	lifetimes.Lock()
+	if lifetimes.all == nil {
+		lifetimes.all = make(map[*lifetime]struct{})
+	}
+	lifetimes.seq++
+	l.seq = lifetimes.seq
+	lifetimes.all[l] = struct{}{}
+	lifetimes.count.Add(1)
+	l.addUnowned(1)
+	lifetimes.Unlock()
+	return l
+}
+
+// collected 对象被 Go 的 GC 回收：在之后的语句边界析构
+func collected(*lifetime) {
+	collector.pending.Store(true)
+}
+
+func unregister(l *lifetime) {
+	lifetimes.Lock()
+	if _, ok := lifetimes.all[l]; ok {
+		delete(lifetimes.all, l)
+		lifetimes.count.Add(-1)
+		if o := l.owner.Load(); o == nil || o == escapedOwner {
+			l.addUnowned(-1)
+		}
+	}
+	lifetimes.Unlock()
+}
+
+func lifeOf(v Value) *lifetime {
+	switch o := v.(type) {
+	case *ClassValue:
+		if o.ObjectValue != nil {
+			return o.life
+		}
+	case *ThisValue:
+		if o.ClassValue != nil && o.ObjectValue != nil {
+			return o.life
+		}
+	}
+	return nil
+}
+
+// addUnowned 调整不归属变量的对象数，内置类的对象不计入
+func (l *lifetime) addUnowned(delta int32) {
+	if !l.internal {
+		lifetimes.unowned.Add(delta)
+	}
+}
+
+// requestCollect 有变量丢弃了非标量的值：存在不归属变量的对象时，下一个语句边界强制 GC 后检查
+func requestCollect() {
+	if lifetimes.unowned.Load() > 0 {
+		collector.force.Store(true)
+		collector.pending.Store(true)
+	}
+}
+
+// escape 对象不再归属变量
+func (l *lifetime) escape() {
+	for {
+		old := l.owner.Load()
+		if old == escapedOwner {
+			return
+		}
+		if l.owner.CompareAndSwap(old, escapedOwner) {
+			if old != nil && l.state.Load() == lifeAlive {
+				l.addUnowned(1)
+			}
+			return
+		}
+	}
+}
+
+// Escape 对象被读出后不再归属原变量，改由 GC 确认不可达后析构；运行时上下文读取变量、$this 被读出时调用
+func Escape(v Value) {
+	if lifetimes.count.Load() != 0 {
+		if l := lifeOf(v); l != nil {
+			l.escape()
+		}
+	}
+}
+
+// Own 由运行时上下文在 `$x = new Foo(...);` 之后调用：z 中新建、尚未读出的对象归 z 所有
+func Own(z *ZVal) {
+	if lifetimes.count.Load() != 0 {
+		if l := lifeOf(z.Value); l != nil {
+			if l.owner.CompareAndSwap(nil, z) {
+				l.addUnowned(-1)
+			}
+		}
+	}
+}
+
+// NeedsRelease 跟踪对象生命周期时，覆盖 z 中的非标量值须经运行时上下文（见 Release），不能走标量快速写入
+func NeedsRelease(z *ZVal) bool {
+	if lifetimes.count.Load() == 0 {
+		return false
+	}
+	switch z.Value.(type) {
+	case *IntValue, *FloatValue, *BoolValue, *StringValue, *NullValue:
+		return false
+	}
+	return true
+}
+
+// Release 变量 z 不再持有 old（被覆盖、unset 或函数返回）：old 归 z 所有时立即析构并返回析构函数抛出的异常，
+// 否则 old 不是标量时在下一个语句边界检查是否有对象因此不可达。z 为 nil 表示与其它作用域共享的变量，只做检查
+func Release(ctx Context, z *ZVal, old Value) Control {
+	if lifetimes.count.Load() == 0 || old == nil {
+		return nil
+	}
+	switch o := old.(type) {
+	case *ClassValue, *ThisValue:
+		if l := lifeOf(o); l != nil && z != nil && l.owner.CompareAndSwap(z, escapedOwner) {
+			l.addUnowned(1)
+			return l.destruct(ctx, classValueOf(o))
+		}
+	case *IntValue, *FloatValue, *BoolValue, *StringValue, *NullValue:
+		return nil
+	}
+	requestCollect()
+	return nil
+}
+
+func classValueOf(v Value) *ClassValue {
+	if t, ok := v.(*ThisValue); ok {
+		return t.ClassValue
+	}
+	return v.(*ClassValue)
+}
+
+// FrameContext 由运行时上下文实现：变量对对象的所有权与函数返回时的释放
+type FrameContext interface {
+	PeekVariable(index int) (Value, bool)
+	OwnVariable(index int)
+	ShareVariables()
+	ReleaseFrame() Control
+}
+
+func frameContext(ctx Context) FrameContext {
+	f, _ := routineContext(ctx).(FrameContext)
+	return f
+}
+
+// freshObject 生成器函数调用刚返回的登记对象（生成器），到下一个语句边界或用户函数返回为止：
+// 期间没有执行任何语句，对象只可能在表达式中传递，语句级的 `$x = gen(...);` 据此让它归 $x 所有（见 OwnFresh）
+var freshObject atomic.Pointer[ObjectValue]
+
+// MarkFresh 生成器函数调用返回新建的生成器对象时调用
+func MarkFresh(cv *ClassValue) {
+	if cv.life != nil {
+		freshObject.Store(cv.ObjectValue)
+	}
+}
+
+// OwnFresh 语句级的 `$x = f(...);` 赋值之后调用：v 是生成器函数调用刚返回的对象时归 $x 所有
+func OwnFresh(ctx Context, index int, v Value) {
+	o := freshObject.Load()
+	if o == nil || !freshObject.CompareAndSwap(o, nil) {
+		return
+	}
+	if cv, ok := v.(*ClassValue); ok && cv.ObjectValue == o {
+		OwnVariable(ctx, index)
+	}
+}
+
+// clearFresh 语句边界与用户函数返回时调用：之后对象可能已被保存到别处
+func clearFresh() {
+	if freshObject.Load() != nil {
+		freshObject.Store(nil)
+	}
+}
+
+// PeekReceiver 读取作为方法调用或属性访问接收者的变量：对象只是被使用，不视为读出（不调用 Escape）；
+// 变量不是已登记的对象时返回 false，调用方按普通读取处理
+func PeekReceiver(ctx Context, index int) (*ClassValue, bool) {
+	if lifetimes.count.Load() == 0 {
+		return nil, false
+	}
+	f := frameContext(ctx)
+	if f == nil {
+		return nil, false
+	}
+	v, _ := f.PeekVariable(index)
+	if cv, ok := v.(*ClassValue); ok && cv.ObjectValue != nil && cv.life != nil {
+		return cv, true
+	}
+	return nil, false
+}
+
+// OwnVariable `$x = new Foo(...);` 语句赋值之后调用，index 为 $x 的下标
+func OwnVariable(ctx Context, index int) {
+	if lifetimes.count.Load() != 0 {
+		if f := frameContext(ctx); f != nil {
+			f.OwnVariable(index)
+		}
+	}
+}
+
+// ShareVariables 创建闭包时调用：闭包可能在函数返回后读取定义处的变量，其中的对象不再在返回时析构
+func ShareVariables(ctx Context) {
+	if f := frameContext(ctx); f != nil {
+		f.ShareVariables()
+	}
+}
+
+// ReleaseFrame 用户函数返回时释放局部变量持有的对象（defer 调用）；
+// 函数已抛出异常时保留原异常，否则 acl 为析构函数抛出的异常
+func ReleaseFrame(ctx Context, acl *Control) {
+	if lifetimes.count.Load() == 0 {
+		return
+	}
+	if f := frameContext(ctx); f != nil {
+		if ctl := f.ReleaseFrame(); ctl != nil && *acl == nil {
+			*acl = ctl
+		}
+	}
+}
+
+// Safepoint 语句边界调用：有对象可能已不可达时确认并析构，返回析构函数抛出的第一个异常
+func Safepoint(ctx Context) Control {
+	clearFresh()
+	if !collector.pending.Load() {
+		return nil
+	}
+	return collect(ctx)
+}
+
+func collect(ctx Context) Control {
+	// 析构函数内的语句边界、其它协程同时到达时跳过
+	if !collector.TryLock() {
+		return nil
+	}
+	defer collector.Unlock()
+	var first Control
+	for round := 0; round < maxCollectRounds && collector.pending.Load(); round++ {
+		if collector.force.Load() {
+			if collector.skip > 0 {
+				collector.skip--
+				return first
+			}
+			if collector.spent > gcAllowance && time.Since(collector.start) < collector.spent*gcBudget {
+				collector.skip = gcDeferSteps
+				return first
+			}
+			collector.force.Store(false)
+			collector.pending.Store(false)
+			t := time.Now()
+			runtime.GC()
+			collector.spent += time.Since(t)
+		} else {
+			collector.pending.Store(false)
+		}
+		for _, l := range unreachable() {
+			if acl := l.destruct(ctx, l.revive()); acl != nil && first == nil {
+				first = acl
+			}
+		}
+	}
+	return first
+}
+
+// unreachable 已被回收、尚未析构的对象，按创建顺序
+func unreachable() []*lifetime {
+	lifetimes.Lock()
+	defer lifetimes.Unlock()
+	var dead []*lifetime
+	for l := range lifetimes.all {
+		if l.object.Value() == nil {
+			dead = append(dead, l)
+		}
+	}
+	slices.SortFunc(dead, bySeq)
+	return dead
+}
+
+func bySeq(a, b *lifetime) int {
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// revive 对象仍存活时返回它，已被回收时用原属性存储重建实例
+func (l *lifetime) revive() *ClassValue {
+	o := l.object.Value()
+	if o == nil {
+		o = &ObjectValue{property: l.store, life: l}
+		o.id = l.id.Load()
+	}
+	return &ClassValue{Context: l.ctx, ObjectValue: o, Class: l.class}
+}
+
+// destruct 调用一次析构函数并注销对象；此后弱引用失效
+func (l *lifetime) destruct(ctx Context, cv *ClassValue) Control {
+	if !l.state.CompareAndSwap(lifeAlive, lifeDestructed) {
+		return nil
+	}
+	unregister(l)
+	defer func() {
+		// 不再持有属性：其中的对象可能随之不可达
+		if l.store != nil && l.store.Len() > 0 {
+			requestCollect()
+		}
+		l.store, l.ctx, l.ref = nil, nil, nil
+	}()
+	if l.dtor == nil {
+		return nil
+	}
+	fnCtx := cv.CreateContext(l.dtor.GetVariables())
+	BindRoutine(fnCtx, RoutineOf(ctx))
+	fnCtx.SetCallArgs([]GetValue{})
+	_, acl := l.dtor.Call(fnCtx)
+	if _, ok := acl.(ReturnControl); ok {
+		return nil
+	}
+	return acl
+}
+
+// ReleaseGlobals 脚本结束时按给定顺序（全局变量的逆序）析构只被这些变量引用的对象（PHP 中引用计数为 1 的对象）：
+// 归变量所有的对象直接析构；已读出的对象先清空变量，强制一次 GC 后析构已不可达的，其余恢复变量
+func ReleaseGlobals(ctx Context, vars []*ZVal) Control {
+	if lifetimes.count.Load() == 0 {
+		return nil
+	}
+	var first Control
+	type candidate struct {
+		z *ZVal
+		l *lifetime
+	}
+	var escaped []candidate
+	for _, z := range vars {
+		l := lifeOf(z.Value)
+		if l == nil || l.state.Load() != lifeAlive {
+			continue
+		}
+		old := z.Value
+		z.Value = NewNullValue()
+		if l.owner.Load() == z {
+			if acl := Release(ctx, z, old); acl != nil && first == nil {
+				first = acl
+			}
+			continue
+		}
+		escaped = append(escaped, candidate{z: z, l: l})
+	}
+	if len(escaped) == 0 {
+		return first
+	}
+	runtime.GC()
+	for _, c := range escaped {
+		if o := c.l.object.Value(); o != nil {
+			c.z.Value = &ClassValue{Context: c.l.ctx, ObjectValue: o, Class: c.l.class}
+			continue
+		}
+		if acl := c.l.destruct(ctx, c.l.revive()); acl != nil && first == nil {
+			first = acl
+		}
+	}
+	return first
+}
+
+// DestroyObjects 脚本结束时（全局变量已由 ReleaseGlobals 处理）按创建顺序析构 vm 中仍存活的对象，
+// 包括析构函数执行期间新建的对象；返回第一个未捕获的异常
+func DestroyObjects(ctx Context, vm VM) Control {
+	var first Control
+	for round := 0; round < maxCollectRounds; round++ {
+		lifetimes.Lock()
+		var live []*lifetime
+		for l := range lifetimes.all {
+			if l.vm == vm || l.vm == nil {
+				live = append(live, l)
+			}
+		}
+		lifetimes.Unlock()
+		if len(live) == 0 {
+			break
+		}
+		slices.SortFunc(live, bySeq)
+		for _, l := range live {
+			if acl := l.destruct(ctx, l.revive()); acl != nil && first == nil {
+				first = acl
+			}
+		}
+	}
+	return first
+}
+
+// WeakObject 对象的弱引用，不阻止对象析构；供 WeakReference、WeakMap 使用，可作为 map 的键
+type WeakObject struct {
+	l *lifetime
+}
+
+// NewWeakObject 对象的弱引用；没有登记的对象此时登记（已被读出，由 GC 确认不可达）
+func NewWeakObject(cv *ClassValue) WeakObject {
+	l := cv.life
+	if l == nil {
+		l = track(cv, nil, false)
+	}
+	l.escape()
+	return WeakObject{l: l}
+}
+
+// Get 对象仍存活时返回它，已析构或已被回收时返回 nil
+func (w WeakObject) Get() *ClassValue {
+	if w.l == nil || w.l.state.Load() != lifeAlive {
+		return nil
+	}
+	o := w.l.object.Value()
+	if o == nil {
+		return nil
+	}
+	return &ClassValue{Context: w.l.ctx, ObjectValue: o, Class: w.l.class}
+}
+
+// Alive 对象是否仍存活
+func (w WeakObject) Alive() bool {
+	return w.l != nil && w.l.state.Load() == lifeAlive && w.l.object.Value() != nil
+}
+
+// Ref 返回对象已有的弱引用实例，没有时保存 create 创建的实例
+func (w WeakObject) Ref(create func() Value) Value {
+	lifetimes.Lock()
+	defer lifetimes.Unlock()
+	if w.l.ref == nil {
+		w.l.ref = create()
+	}
+	return w.l.ref
+}
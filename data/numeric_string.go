@@ -0,0 +1,67 @@
+package data
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// numericWhitespace PHP 数字字符串允许的前后空白字符
+const numericWhitespace = " \t\n\r\v\f"
+
+// ParseNumericString 按 PHP 8 的规则解析数字字符串：允许前导和尾随空白，
+// 整数形式且不溢出时返回 *IntValue，其它合法形式（小数、指数、溢出的整数）返回 *FloatValue。
+// 非数字字符串（包括 "12abc" 这类前导数字字符串）返回 false。
+func ParseNumericString(s string) (Value, bool) {
+	s = strings.Trim(s, numericWhitespace)
+	if s == "" {
+		return nil, false
+	}
+	i := 0
+	if s[i] == '+' || s[i] == '-' {
+		i++
+	}
+	digits := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+		digits++
+	}
+	isFloat := false
+	if i < len(s) && s[i] == '.' {
+		isFloat = true
+		i++
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return nil, false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			isFloat = true
+			i = j
+		}
+	}
+	if i != len(s) {
+		return nil, false
+	}
+	if !isFloat {
+		if n, err := strconv.ParseInt(s, 10, strconv.IntSize); err == nil {
+			return NewIntValue(int(n)), true
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return nil, false
+	}
+	return NewFloatValue(f), true
+}
@@ -0,0 +1,167 @@
+package data
+
+import "fmt"
+
+// 运算符重载：用户类定义魔术方法后，运算符作用在其实例上时调用对应方法，未定义时保持原有行为。
+//
+//	+ __add   - __sub   * __mul   / __div   % __mod   ** __pow   . __concat
+//	== != __equals（未定义时用 __compare 的结果是否为 0）
+//	< <= > >= <=> 及 sort/rsort __compare
+//	一元 - __neg
+//
+// 左操作数定义了方法时调用 $left->__add($right, false)；否则右操作数定义了方法时调用
+// $right->__add($left, true)，第二个参数表示对象位于运算符右侧（如 10 - $money）。
+// __compare($other) 总是比较 $this 与 $other，对象位于右侧时由引擎取反结果；__equals 视为对称。
+
+// operatorObject 取出运算数中的类实例（含 $this）
+func operatorObject(v GetValue) *ClassValue {
+	switch c := v.(type) {
+	case *ClassValue:
+		return c
+	case *ThisValue:
+		return c.ClassValue
+	}
+	return nil
+}
+
+// callOperator 以 args 为实参调用实例 obj 的魔术方法：与普通方法调用一样检查可见性，
+// 未传入的参数取声明的默认值，方法声明的参数少于实参时忽略多余的实参
+func callOperator(ctx Context, obj *ClassValue, method Method, args ...Value) (GetValue, Control) {
+	if modifier := method.GetModifier(); modifier != ModifierPublic && !CallerInClassHierarchy(ctx, obj.Class) {
+		visibility := "private"
+		if modifier == ModifierProtected {
+			visibility = "protected"
+		}
+		return nil, NewErrorThrowByName(nil, fmt.Errorf("Call to %s method %s::%s() from %s",
+			visibility, obj.Class.GetName(), method.GetName(), callerScope(ctx)), "Error")
+	}
+	fnCtx := obj.CreateContext(method.GetVariables())
+	BindRoutine(fnCtx, RoutineOf(ctx))
+	vars := method.GetVariables()
+	for i, param := range method.GetParams() {
+		if i >= len(vars) {
+			break
+		}
+		if i < len(args) {
+			fnCtx.SetVariableValue(vars[i], args[i])
+			continue
+		}
+		if _, acl := param.GetValue(fnCtx); acl != nil {
+			return nil, acl
+		}
+	}
+	return method.Call(fnCtx)
+}
+
+// callerScope 错误信息中调用者所在的作用域
+func callerScope(ctx Context) string {
+	switch c := ctx.(type) {
+	case *BoundContext:
+		return "scope " + c.ScopeClass
+	case *ClassMethodContext:
+		return "scope " + c.Class.GetName()
+	case *ClassValue:
+		return "scope " + c.Class.GetName()
+	}
+	return "global scope"
+}
+
+// CallOperator 二元运算符的重载分派：任一操作数是定义了 name 方法的类实例时调用它，ok 为 false 表示应回退到原有行为
+func CallOperator(ctx Context, name string, left, right GetValue) (ret GetValue, acl Control, ok bool) {
+	if obj := operatorObject(left); obj != nil {
+		if method, has := obj.GetMethod(name); has && method != nil {
+			ret, acl = callOperator(ctx, obj, method, operatorArg(right), NewBoolValue(false))
+			return ret, acl, true
+		}
+	}
+	if obj := operatorObject(right); obj != nil {
+		if method, has := obj.GetMethod(name); has && method != nil {
+			ret, acl = callOperator(ctx, obj, method, operatorArg(left), NewBoolValue(true))
+			return ret, acl, true
+		}
+	}
+	return nil, nil, false
+}
+
+// CallUnaryOperator 一元运算符的重载分派（如 -$money 调用 __neg()）
+func CallUnaryOperator(ctx Context, name string, operand GetValue) (ret GetValue, acl Control, ok bool) {
+	obj := operatorObject(operand)
+	if obj == nil {
+		return nil, nil, false
+	}
+	method, has := obj.GetMethod(name)
+	if !has || method == nil {
+		return nil, nil, false
+	}
+	ret, acl = callOperator(ctx, obj, method)
+	return ret, acl, true
+}
+
+// CompareOperator 比较运算符与 sort 的重载分派：按 __compare 的结果返回 -1、0、1
+func CompareOperator(ctx Context, left, right GetValue) (cmp int, acl Control, ok bool) {
+	if obj := operatorObject(left); obj != nil {
+		if method, has := obj.GetMethod("__compare"); has && method != nil {
+			ret, acl := callOperator(ctx, obj, method, operatorArg(right))
+			return operatorSign(ret), acl, true
+		}
+	}
+	if obj := operatorObject(right); obj != nil {
+		if method, has := obj.GetMethod("__compare"); has && method != nil {
+			ret, acl := callOperator(ctx, obj, method, operatorArg(left))
+			return -operatorSign(ret), acl, true
+		}
+	}
+	return 0, nil, false
+}
+
+// EqualsOperator == 与 != 的重载分派：优先 __equals，其次 __compare 的结果是否为 0
+func EqualsOperator(ctx Context, left, right GetValue) (equal bool, acl Control, ok bool) {
+	for _, pair := range [2][2]GetValue{{left, right}, {right, left}} {
+		obj := operatorObject(pair[0])
+		if obj == nil {
+			continue
+		}
+		if method, has := obj.GetMethod("__equals"); has && method != nil {
+			ret, acl := callOperator(ctx, obj, method, operatorArg(pair[1]))
+			if acl != nil {
+				return false, acl, true
+			}
+			if b, ok := ret.(AsBool); ok {
+				equal, _ = b.AsBool()
+			}
+			return equal, nil, true
+		}
+	}
+	cmp, acl, ok := CompareOperator(ctx, left, right)
+	return cmp == 0, acl, ok
+}
+
+// operatorArg 运算数作为实参传入魔术方法
+func operatorArg(v GetValue) Value {
+	if val, ok := v.(Value); ok {
+		return val
+	}
+	return NewNullValue()
+}
+
+// operatorSign __compare 返回值的符号
+func operatorSign(v GetValue) int {
+	var f float64
+	switch n := v.(type) {
+	case *IntValue:
+		f = float64(n.Value)
+	case *FloatValue:
+		f = n.Value
+	case Value:
+		if num, kind := ToNumber(n); kind == NumberOK {
+			return operatorSign(num)
+		}
+	}
+	switch {
+	case f < 0:
+		return -1
+	case f > 0:
+		return 1
+	}
+	return 0
+}
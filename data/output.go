@@ -1,23 +1,26 @@
 package data
 
-import "fmt"
+import (
+	"fmt"
+	"sync/atomic"
+)
 
 // userOutputEmitted 表示本次请求是否已向 stdout 写出用户可见内容（echo/var_dump 等）
-var userOutputEmitted bool
+var userOutputEmitted atomic.Bool
 
 // MarkUserOutput 标记已有用户输出（用于 Fatal 前空行等格式）
 func MarkUserOutput() {
-	userOutputEmitted = true
+	userOutputEmitted.Store(true)
 }
 
 // HasUserOutput 是否已有用户输出
 func HasUserOutput() bool {
-	return userOutputEmitted
+	return userOutputEmitted.Load()
 }
 
 // ResetUserOutput 重置用户输出标记（每个脚本执行前调用）
 func ResetUserOutput() {
-	userOutputEmitted = false
+	userOutputEmitted.Store(false)
 }
 
 // OutputWriter 是输出写入函数类型
@@ -0,0 +1,297 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Routine 一个执行流（主脚本、spawn 启动的协程、一次 HTTP 请求）私有的执行状态：
+// 调用深度、set_exception_handler 注册的回调、ob_* 输出缓冲栈、函数/方法内的 static 局部变量、defer 延迟执行项，
+// 以及取消信号（Go 的 context.Context，阻塞的标准库操作在取消时抛出 CancelledError）。
+// 执行流由运行时上下文携带，创建子上下文时继承；同一执行流内的生成器、Fiber 与调用方交替执行，不会并发。
+// 所有方法都允许接收者为 nil（没有执行流的上下文，如 LSP），此时不记录状态。
+type Routine struct {
+	depth atomic.Int32
+	ctx   context.Context
+
+	mu        sync.Mutex
+	handler   Value
+	inHandler bool
+
+	output OutputBuffers
+
+	statics sync.Map // 函数/方法声明 → *StaticLocals
+
+	defers     []deferEntry // defer 语句登记、尚未执行的延迟执行项，见 defer.go
+	deferCount atomic.Int32
+}
+
+// NewRoutine 创建主执行流
+func NewRoutine() *Routine {
+	return &Routine{}
+}
+
+// Spawn 创建子执行流（spawn 的协程、HTTP 请求）：继承当前的异常处理回调与取消信号，
+// 调用深度、输出缓冲与 static 局部变量从空开始
+func (r *Routine) Spawn() *Routine {
+	return r.SpawnContext(r.Context())
+}
+
+// SpawnContext 与 Spawn 相同，取消信号改为 ctx
+func (r *Routine) SpawnContext(ctx context.Context) *Routine {
+	child := &Routine{ctx: ctx}
+	child.handler = r.ExceptionHandler()
+	return child
+}
+
+// Context 执行流的取消信号，没有时为 context.Background()
+func (r *Routine) Context() context.Context {
+	if r == nil || r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// EnterCall 进入一层调用，返回进入后的深度
+func (r *Routine) EnterCall() int {
+	if r == nil {
+		return 0
+	}
+	return int(r.depth.Add(1))
+}
+
+// LeaveCall 退出一层调用
+func (r *Routine) LeaveCall() {
+	if r == nil {
+		return
+	}
+	r.depth.Add(-1)
+}
+
+// SetExceptionHandler 设置异常处理回调，返回旧的回调
+func (r *Routine) SetExceptionHandler(handler Value) Value {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.handler
+	r.handler = handler
+	return old
+}
+
+// ExceptionHandler 当前的异常处理回调
+func (r *Routine) ExceptionHandler() Value {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.handler
+}
+
+// HandleUncaught 把未捕获的异常交给注册的异常处理回调。
+// handled 为 false 表示没有可用的回调（未注册、不是异常、或正在回调中），由调用方按默认方式处理；
+// 回调自身产生的未处理控制流通过 next 返回。
+func (r *Routine) HandleUncaught(acl Control, ctx Context) (handled bool, next Control) {
+	tv, ok := acl.(*ThrowValue)
+	if r == nil || !ok || tv == nil || tv.Error == nil {
+		return false, nil
+	}
+	r.mu.Lock()
+	// 目前仅支持 Closure/匿名函数形式的回调
+	fv, ok := r.handler.(*FuncValue)
+	if !ok || r.inHandler {
+		r.mu.Unlock()
+		return false, nil
+	}
+	// 仅处理一次，避免回调内部再次抛出未捕获异常导致无限递归
+	r.inHandler = true
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.inHandler = false
+		r.mu.Unlock()
+	}()
+
+	vars := fv.Value.GetVariables()
+	fnCtx := ctx.CreateContext(vars)
+	if len(vars) > 0 {
+		_ = fnCtx.SetVariableValue(vars[0], acl)
+	}
+	_, next = fv.Call(fnCtx)
+	return true, next
+}
+
+// StaticLocals 声明 owner（函数或方法）在本执行流中的 static 局部变量存储
+func (r *Routine) StaticLocals(owner any) *StaticLocals {
+	if r == nil {
+		return orphanStatics(owner)
+	}
+	return loadStatics(&r.statics, owner)
+}
+
+// Output 本执行流的输出缓冲栈
+func (r *Routine) Output() *OutputBuffers {
+	if r == nil {
+		return nil
+	}
+	return &r.output
+}
+
+// orphanStatics 没有执行流的上下文共用的 static 存储
+var orphanStaticsMap sync.Map
+
+func orphanStatics(owner any) *StaticLocals {
+	return loadStatics(&orphanStaticsMap, owner)
+}
+
+func loadStatics(m *sync.Map, owner any) *StaticLocals {
+	if s, ok := m.Load(owner); ok {
+		return s.(*StaticLocals)
+	}
+	s, _ := m.LoadOrStore(owner, NewStaticLocals())
+	return s.(*StaticLocals)
+}
+
+// RoutineContext 由运行时上下文实现，保存所属的执行流
+type RoutineContext interface {
+	Routine() *Routine
+	BindRoutine(r *Routine)
+}
+
+// routineContext 沿方法上下文、对象、绑定作用域的包装找到运行时上下文
+func routineContext(ctx Context) RoutineContext {
+	for i := 0; ctx != nil && i < 16; i++ {
+		switch c := ctx.(type) {
+		case *ClassMethodContext:
+			if c.ClassValue == nil {
+				return nil
+			}
+			ctx = c.ClassValue.Context
+		case *ClassValue:
+			ctx = c.Context
+		case *ObjectValue:
+			ctx = c.Context
+		case *BoundContext:
+			ctx = c.Context
+		default:
+			rc, _ := ctx.(RoutineContext)
+			return rc
+		}
+	}
+	return nil
+}
+
+// RoutineOf 上下文所属的执行流，没有时返回 nil
+func RoutineOf(ctx Context) *Routine {
+	if rc := routineContext(ctx); rc != nil {
+		return rc.Routine()
+	}
+	return nil
+}
+
+// BindRoutine 让新建的上下文 ctx 归属执行流 r。
+// 方法的上下文由对象创建时的上下文派生，调用时需改为调用方的执行流；只能用于刚创建、尚未共享的上下文。
+func BindRoutine(ctx Context, r *Routine) {
+	if r == nil {
+		return
+	}
+	if rc := routineContext(ctx); rc != nil {
+		rc.BindRoutine(r)
+	}
+}
+
+// GoContext 上下文所属执行流的取消信号
+func GoContext(ctx Context) context.Context {
+	return RoutineOf(ctx).Context()
+}
+
+// NewCancelledThrow 执行流被取消时阻塞操作抛出的 CancelledError，消息为取消原因
+func NewCancelledThrow(ctx context.Context) Control {
+	err := context.Cause(ctx)
+	if err == nil {
+		err = errors.New("操作已取消")
+	}
+	return NewErrorThrowByName(nil, err, "CancelledError")
+}
+
+// Output 输出字符串：执行流开启了输出缓冲时写入缓冲区，否则交给 WriteOutput
+func Output(ctx Context, s string) {
+	if RoutineOf(ctx).Output().Write(s) {
+		return
+	}
+	WriteOutput(s)
+}
+
+// OutputBuffers ob_start 开启的输出缓冲栈，栈顶为当前缓冲区
+type OutputBuffers struct {
+	mu      sync.Mutex
+	buffers []*strings.Builder
+}
+
+// Push 开启一层输出缓冲
+func (o *OutputBuffers) Push() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	o.buffers = append(o.buffers, &strings.Builder{})
+	o.mu.Unlock()
+}
+
+// Pop 关闭当前缓冲并返回其内容；没有缓冲时返回 false
+func (o *OutputBuffers) Pop() (string, bool) {
+	if o == nil {
+		return "", false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.buffers) == 0 {
+		return "", false
+	}
+	last := o.buffers[len(o.buffers)-1]
+	o.buffers[len(o.buffers)-1] = nil
+	o.buffers = o.buffers[:len(o.buffers)-1]
+	return last.String(), true
+}
+
+// Contents 当前缓冲区的内容
+func (o *OutputBuffers) Contents() (string, bool) {
+	if o == nil {
+		return "", false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.buffers) == 0 {
+		return "", false
+	}
+	return o.buffers[len(o.buffers)-1].String(), true
+}
+
+// Level 缓冲层数
+func (o *OutputBuffers) Level() int {
+	if o == nil {
+		return 0
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.buffers)
+}
+
+// Write 写入当前缓冲区；没有开启缓冲时返回 false
+func (o *OutputBuffers) Write(s string) bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.buffers) == 0 {
+		return false
+	}
+	o.buffers[len(o.buffers)-1].WriteString(s)
+	return true
+}
@@ -0,0 +1,72 @@
+package data
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRoutineSpawnIsolated(t *testing.T) {
+	main := NewRoutine()
+	handler := NewStringValue("handler")
+	main.SetExceptionHandler(handler)
+	main.Output().Push()
+	main.EnterCall()
+	owner := new(int)
+	main.StaticLocals(owner).Init(0, NewIntValue(1))
+
+	child := main.Spawn()
+	if child.ExceptionHandler() != handler {
+		t.Fatal("child routine should inherit the exception handler")
+	}
+	if child.Output().Level() != 0 || child.EnterCall() != 1 {
+		t.Fatal("child routine should start with empty output buffers and call depth")
+	}
+	if _, ok := child.StaticLocals(owner).Get(0); ok {
+		t.Fatal("static locals leaked into the child routine")
+	}
+	child.SetExceptionHandler(nil)
+	if main.ExceptionHandler() != handler {
+		t.Fatal("child handler change leaked into the parent")
+	}
+}
+
+func TestRoutineConcurrentDepth(t *testing.T) {
+	main := NewRoutine()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		r := main.Spawn()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				r.EnterCall()
+				main.EnterCall()
+				r.Output().Push()
+				r.Output().Write("x")
+				r.Output().Pop()
+				main.LeaveCall()
+				r.LeaveCall()
+			}
+			if d := r.EnterCall(); d != 1 {
+				t.Errorf("depth = %d, want 1", d)
+			}
+		}()
+	}
+	wg.Wait()
+	if d := main.EnterCall(); d != 1 {
+		t.Fatalf("main depth = %d, want 1", d)
+	}
+}
+
+func TestOutputNilRoutine(t *testing.T) {
+	var r *Routine
+	if r.Output().Write("x") || r.Output().Level() != 0 {
+		t.Fatal("nil routine should not buffer output")
+	}
+	if _, ok := r.Output().Pop(); ok {
+		t.Fatal("nil routine has no buffers to pop")
+	}
+	if r.StaticLocals(r) == nil {
+		t.Fatal("nil routine should fall back to the shared static store")
+	}
+}
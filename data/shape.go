@@ -0,0 +1,370 @@
+package data
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Shape 类实例的属性布局（隐藏类）：属性名到槽位下标的映射。
+// 每个类在第一次实例化时计算一次根布局（声明的实例属性，本类在前、父类在后），
+// 实例只保存槽位数组；写入未声明的动态属性时沿转换链得到新布局，同样顺序写入的实例共用同一布局。
+// 布局创建后不再修改，读取不需要加锁。
+type Shape struct {
+	root  *Shape
+	names []string       // 槽位 → 属性名
+	index map[string]int // 属性名 → 槽位
+
+	mu    sync.Mutex
+	next  map[string]*Shape // 增加一个动态属性后的布局
+	count *atomic.Int32     // 同一根布局派生出的布局数量
+}
+
+const (
+	// maxShapeDynamic 单个实例的动态属性超过该数量后改用字典存储
+	maxShapeDynamic = 64
+	// maxShapesPerClass 一个类派生的布局超过该数量后，新的转换改用字典存储，避免把对象当字典用时布局无限增长
+	maxShapesPerClass = 1024
+)
+
+// NewShape 创建根布局，names 为声明的实例属性（重复的名称只保留第一个）
+func NewShape(names []string) *Shape {
+	s := &Shape{index: make(map[string]int, len(names)), count: &atomic.Int32{}}
+	for _, name := range names {
+		if _, ok := s.index[name]; ok {
+			continue
+		}
+		s.index[name] = len(s.names)
+		s.names = append(s.names, name)
+	}
+	s.root = s
+	s.count.Store(1)
+	return s
+}
+
+// Slot 属性所在的槽位
+func (s *Shape) Slot(name string) (int, bool) {
+	i, ok := s.index[name]
+	return i, ok
+}
+
+// Len 槽位数量
+func (s *Shape) Len() int {
+	return len(s.names)
+}
+
+// Root 所属类的根布局
+func (s *Shape) Root() *Shape {
+	return s.root
+}
+
+// with 增加动态属性 name 后的布局；超出限制时返回 nil，调用方改用字典存储
+func (s *Shape) with(name string) *Shape {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.next[name]; ok {
+		return n
+	}
+	if len(s.names)-len(s.root.names) >= maxShapeDynamic || s.count.Load() >= maxShapesPerClass {
+		return nil
+	}
+	n := &Shape{root: s.root, count: s.count, index: make(map[string]int, len(s.names)+1)}
+	n.names = append(append(make([]string, 0, len(s.names)+1), s.names...), name)
+	for i, k := range n.names {
+		n.index[k] = i
+	}
+	if s.next == nil {
+		s.next = make(map[string]*Shape)
+	}
+	s.next[name] = n
+	s.count.Add(1)
+	return n
+}
+
+// PropertySlot 缓存属性声明在某个类根布局中的槽位，由属性声明持有。
+// 只记录第一次遇到的类：同一声明在多个类中使用时，其它类回退到布局的映射查找。
+type PropertySlot struct {
+	hint atomic.Pointer[slotHint]
+}
+
+type slotHint struct {
+	root *Shape
+	slot int
+}
+
+func (p *PropertySlot) lookup(s *Shape, name string) (int, bool) {
+	if h := p.hint.Load(); h != nil && h.root == s.root {
+		return h.slot, true
+	}
+	slot, ok := s.root.index[name]
+	if !ok {
+		// 动态属性不缓存
+		return s.Slot(name)
+	}
+	if p.hint.Load() == nil {
+		p.hint.Store(&slotHint{root: s.root, slot: slot})
+	}
+	return slot, true
+}
+
+// PropertySlotHolder 由属性声明实现，见 PropertySlot
+type PropertySlotHolder interface {
+	PropertySlot() *PropertySlot
+}
+
+// shapedStore 按布局存储的类实例属性，实现 PropertyStore。
+// 槽位为 nil 表示属性未初始化或已删除，遍历时跳过。
+// 动态属性过多或布局数量超限时转为字典存储（dict）。
+// 实例可能被多个协程（spawn）同时读写：写入在 mu 下进行，布局转换、槽位数组扩容与转为字典时
+// 整体替换 layout；读取只加载 layout，不加锁。
+type shapedStore struct {
+	mu     sync.Mutex
+	layout atomic.Pointer[shapedLayout]
+	count  atomic.Int32 // 非 nil 槽位数
+}
+
+// shapedLayout 实例当前的布局与槽位数组，替换后不再修改字段；
+// 槽位本身用原子指针，同一数组在布局转换前后共用
+type shapedLayout struct {
+	shape *Shape
+	slots []atomic.Pointer[ZVal]
+	dict  PropertyStore
+}
+
+// newShapedStore 槽位数组在第一次写入时分配（静态方法的上下文等不会写入属性）
+func newShapedStore(shape *Shape) *shapedStore {
+	s := &shapedStore{}
+	s.layout.Store(&shapedLayout{shape: shape})
+	return s
+}
+
+func (l *shapedLayout) zval(slot int) (*ZVal, bool) {
+	if slot < len(l.slots) {
+		if z := l.slots[slot].Load(); z != nil {
+			return z, true
+		}
+	}
+	return nil, false
+}
+
+// getSlot 按属性声明缓存的槽位读取
+func (s *shapedStore) getSlot(p *PropertySlot, name string) (*ZVal, bool) {
+	l := s.layout.Load()
+	if l.dict != nil {
+		return l.dict.GetZVal(name)
+	}
+	slot, ok := p.lookup(l.shape, name)
+	if !ok {
+		return nil, false
+	}
+	return l.zval(slot)
+}
+
+func (s *shapedStore) GetZVal(key string) (*ZVal, bool) {
+	l := s.layout.Load()
+	if l.dict != nil {
+		return l.dict.GetZVal(key)
+	}
+	slot, ok := l.shape.Slot(key)
+	if !ok {
+		return nil, false
+	}
+	return l.zval(slot)
+}
+
+func (s *shapedStore) Get(key string) (Value, bool) {
+	z, ok := s.GetZVal(key)
+	if !ok {
+		return nil, false
+	}
+	return z.Value, true
+}
+
+func (s *shapedStore) Set(key string, value Value) {
+	// 已存在的属性直接写入 ZVal，与局部变量的写入一致，不加锁
+	if z, ok := s.GetZVal(key); ok {
+		z.Value = value
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.layout.Load()
+	if l.dict != nil {
+		l.dict.Set(key, value)
+		return
+	}
+	slot, ok := l.shape.Slot(key)
+	if !ok {
+		next := l.shape.with(key)
+		if next == nil {
+			s.toDict(l).Set(key, value)
+			return
+		}
+		l = &shapedLayout{shape: next, slots: l.slots}
+		slot, _ = next.Slot(key)
+	}
+	if slot >= len(l.slots) {
+		slots := make([]atomic.Pointer[ZVal], max(slot+1, l.shape.Len()))
+		for i := range l.slots {
+			slots[i].Store(l.slots[i].Load())
+		}
+		l = &shapedLayout{shape: l.shape, slots: slots}
+	}
+	if z := l.slots[slot].Load(); z != nil {
+		z.Value = value
+	} else {
+		l.slots[slot].Store(NewZVal(value))
+		s.count.Add(1)
+	}
+	if l != s.layout.Load() {
+		s.layout.Store(l)
+	}
+}
+
+func (s *shapedStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.layout.Load()
+	if l.dict != nil {
+		l.dict.Delete(key)
+		return
+	}
+	if slot, ok := l.shape.Slot(key); ok && slot < len(l.slots) && l.slots[slot].Swap(nil) != nil {
+		s.count.Add(-1)
+	}
+}
+
+func (s *shapedStore) Range(fn func(key string, value Value) bool) {
+	l := s.layout.Load()
+	if l.dict != nil {
+		l.dict.Range(fn)
+		return
+	}
+	for i := range l.slots {
+		if z := l.slots[i].Load(); z != nil && !fn(l.shape.names[i], z.Value) {
+			return
+		}
+	}
+}
+
+func (s *shapedStore) Len() int {
+	if l := s.layout.Load(); l.dict != nil {
+		return l.dict.Len()
+	}
+	return int(s.count.Load())
+}
+
+func (s *shapedStore) GetByIndex(index int) (string, Value, bool) {
+	l := s.layout.Load()
+	if l.dict != nil {
+		return l.dict.GetByIndex(index)
+	}
+	if index < 0 || index >= int(s.count.Load()) {
+		return "", nil, false
+	}
+	if int(s.count.Load()) == len(l.slots) {
+		if z := l.slots[index].Load(); z != nil {
+			return l.shape.names[index], z.Value, true
+		}
+	}
+	for i := range l.slots {
+		z := l.slots[i].Load()
+		if z == nil {
+			continue
+		}
+		if index == 0 {
+			return l.shape.names[i], z.Value, true
+		}
+		index--
+	}
+	return "", nil, false
+}
+
+// toDict 转为字典存储，保留现有属性的顺序与 ZVal（引用绑定不受影响）；调用方持有 mu
+func (s *shapedStore) toDict(l *shapedLayout) PropertyStore {
+	n := int(s.count.Load())
+	m := &OrderedMap{
+		data:     make([]*ZVal, 0, n),
+		indexMap: make(map[string]int, n),
+		nameMap:  make(map[int]string, n),
+	}
+	for i := range l.slots {
+		z := l.slots[i].Load()
+		if z == nil {
+			continue
+		}
+		m.indexMap[l.shape.names[i]] = len(m.data)
+		m.nameMap[len(m.data)] = l.shape.names[i]
+		m.data = append(m.data, z)
+	}
+	s.layout.Store(&shapedLayout{shape: l.shape, dict: m})
+	s.count.Store(0)
+	return m
+}
+
+// ShapeCache 保存类的根布局与析构函数，由类定义实现 ShapeHolder 持有；类重新定义时随旧定义一起释放
+type ShapeCache struct {
+	shape      atomic.Pointer[Shape]
+	destructor atomic.Pointer[destructorEntry]
+}
+
+// Reset 类的属性或方法声明变化后（如合并 trait）丢弃已计算的布局与析构函数
+func (c *ShapeCache) Reset() {
+	c.shape.Store(nil)
+	c.destructor.Store(nil)
+}
+
+// ShapeHolder 由类定义实现，见 ShapeCache
+type ShapeHolder interface {
+	ShapeCache() *ShapeCache
+}
+
+// builtinShapes 未实现 ShapeHolder 的类（内置类）的根布局
+var builtinShapes sync.Map
+
+// ClassShape 类实例的根布局，第一次实例化时计算
+func ClassShape(class ClassStmt, ctx Context) *Shape {
+	if h, ok := class.(ShapeHolder); ok {
+		c := h.ShapeCache()
+		if s := c.shape.Load(); s != nil {
+			return s
+		}
+		s := NewShape(instancePropertyNames(class, ctx))
+		c.shape.Store(s)
+		return s
+	}
+	if class == nil || !reflect.TypeOf(class).Comparable() {
+		return NewShape(instancePropertyNames(class, ctx))
+	}
+	if s, ok := builtinShapes.Load(class); ok {
+		return s.(*Shape)
+	}
+	s, _ := builtinShapes.LoadOrStore(class, NewShape(instancePropertyNames(class, ctx)))
+	return s.(*Shape)
+}
+
+// instancePropertyNames 声明的实例属性名：本类在前，再沿继承链到父类
+func instancePropertyNames(class ClassStmt, ctx Context) []string {
+	var names []string
+	for depth := 0; class != nil && depth < 64; depth++ {
+		for _, p := range class.GetPropertyList() {
+			if p != nil && !p.GetIsStatic() {
+				names = append(names, p.GetName())
+			}
+		}
+		ext := class.GetExtend()
+		if ext == nil || *ext == "" || ctx == nil {
+			break
+		}
+		vm := ctx.GetVM()
+		if vm == nil {
+			break
+		}
+		next, acl := vm.GetOrLoadClass(*ext)
+		if acl != nil {
+			break
+		}
+		class = next
+	}
+	return names
+}
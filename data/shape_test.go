@@ -0,0 +1,104 @@
+package data
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestShapeTransitionsShared(t *testing.T) {
+	root := NewShape([]string{"a", "b", "a"})
+	if root.Len() != 2 {
+		t.Fatalf("duplicate names should be dropped, got %d slots", root.Len())
+	}
+
+	x, y := newShapedStore(root), newShapedStore(root)
+	for _, s := range []*shapedStore{x, y} {
+		s.Set("b", NewIntValue(2))
+		s.Set("c", NewIntValue(3))
+	}
+	if x.layout.Load().shape != y.layout.Load().shape || x.layout.Load().shape.Root() != root {
+		t.Fatal("instances adding the same dynamic property should share a shape")
+	}
+
+	x.Delete("b")
+	var keys []string
+	x.Range(func(key string, _ Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 1 || keys[0] != "c" || x.Len() != 1 {
+		t.Fatalf("unexpected properties after delete: %v", keys)
+	}
+	if name, _, ok := x.GetByIndex(0); !ok || name != "c" {
+		t.Fatalf("GetByIndex(0) = %q", name)
+	}
+	if v, ok := y.Get("b"); !ok || v.(*IntValue).Value != 2 {
+		t.Fatal("delete leaked into another instance")
+	}
+}
+
+func TestShapedStoreDictFallback(t *testing.T) {
+	s := newShapedStore(NewShape([]string{"a"}))
+	s.Set("a", NewIntValue(0))
+	z, _ := s.GetZVal("a")
+	for i := 0; i < maxShapeDynamic+8; i++ {
+		s.Set("p"+strconv.Itoa(i), NewIntValue(i))
+	}
+	if s.layout.Load().dict == nil {
+		t.Fatal("too many dynamic properties should switch to dictionary storage")
+	}
+	if got, _ := s.GetZVal("a"); got != z {
+		t.Fatal("dictionary storage should keep existing zvals")
+	}
+	if name, _, ok := s.GetByIndex(1); !ok || name != "p0" || s.Len() != maxShapeDynamic+9 {
+		t.Fatalf("insertion order lost: %q, len %d", name, s.Len())
+	}
+}
+
+func TestPropertySlotHint(t *testing.T) {
+	a := NewShape([]string{"x", "y"})
+	b := NewShape([]string{"y"})
+	p := &PropertySlot{}
+
+	sa, sb := newShapedStore(a), newShapedStore(b)
+	sa.Set("y", NewIntValue(1))
+	sb.Set("y", NewIntValue(2))
+	for i := 0; i < 2; i++ {
+		if z, ok := sa.getSlot(p, "y"); !ok || z.Value.(*IntValue).Value != 1 {
+			t.Fatal("slot read from first class failed")
+		}
+		if z, ok := sb.getSlot(p, "y"); !ok || z.Value.(*IntValue).Value != 2 {
+			t.Fatal("slot read from second class should fall back to the shape index")
+		}
+	}
+	if h := p.hint.Load(); h == nil || h.root != a || h.slot != 1 {
+		t.Fatal("hint should record the first class")
+	}
+}
+
+func TestShapedStoreConcurrentWrites(t *testing.T) {
+	s := newShapedStore(NewShape([]string{"a"}))
+	s.Set("a", NewIntValue(0))
+	const workers, props = 8, 16
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < props; i++ {
+				key := "w" + strconv.Itoa(w) + "_" + strconv.Itoa(i)
+				s.Set(key, NewIntValue(i))
+				s.Get("a")
+				if v, ok := s.Get(key); !ok || v.(*IntValue).Value != i {
+					t.Errorf("lost write to %s", key)
+				}
+				s.Range(func(string, Value) bool { return true })
+			}
+		}(w)
+	}
+	wg.Wait()
+	if s.Len() != workers*props+1 {
+		t.Fatalf("expected %d properties, got %d", workers*props+1, s.Len())
+	}
+}
@@ -1,11 +1,15 @@
 package data
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 // StaticLocals 函数/方法内 static 局部变量存储（跨调用持久）
 type StaticLocals struct {
 	mu   sync.Mutex
 	Vals map[int]Value
+	n    atomic.Int32 // 已注册的变量数
 }
 
 func NewStaticLocals() *StaticLocals {
@@ -29,6 +33,7 @@ func (s *StaticLocals) Init(index int, val Value) Value {
 		return v
 	}
 	s.Vals[index] = val
+	s.n.Add(1)
 	return val
 }
 
@@ -43,3 +48,17 @@ func (s *StaticLocals) Update(index int, val Value) {
 		s.Vals[index] = val
 	}
 }
+
+// Sync 函数返回时用上下文中的当前值更新所有已注册的 static 变量（赋值等不经过 Update 的修改）
+func (s *StaticLocals) Sync(ctx Context) {
+	if s == nil || s.n.Load() == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for idx := range s.Vals {
+		if zv := ctx.GetIndexZVal(idx); zv != nil {
+			s.Vals[idx] = zv.Value
+		}
+	}
+}
@@ -44,11 +44,23 @@ func (i Class) Is(value Value) bool {
 		if baseName == "Throwable" || baseName == "Exception" || baseName == "Error" {
 			return true
 		}
+		// 按名称创建的内部错误（TypeError、FiberError 等）可以用自身的类名或父类名捕获
+		for name := c.Name; name != ""; name = internalErrorParents[name] {
+			if name == baseName {
+				return true
+			}
+		}
 	}
 
 	return false
 }
 
+// internalErrorParents 按名称创建的内部错误的父类，用于 catch 父类时匹配
+var internalErrorParents = map[string]string{
+	"DivisionByZeroError": "ArithmeticError",
+	"ArgumentCountError":  "TypeError",
+}
+
 // isClassValueInstanceOf 检查一个 ClassStmt 是否实现了目标类型（类名或接口名）
 func isClassValueInstanceOf(target string, class ClassStmt, vm VM) bool {
 	if target == class.GetName() {
@@ -0,0 +1,223 @@
+package data
+
+import (
+	"math"
+)
+
+// CoerceType 按 PHP 的参数/返回值类型规则检查 v 是否满足 ty，并返回转换后的值。
+// strict 为 true 时对应 declare(strict_types=1)：标量只接受同类型的值（唯一例外是 int 可以传给 float）；
+// 否则为强制转换模式：int/float/string/bool 之间按 PHP 规则互相转换，数字字符串可以转为 int/float。
+// 非标量类型（类、array、callable、iterable 等）两种模式相同，由 Types.Is 判断。
+func CoerceType(ty Types, v Value, strict bool) (Value, bool) {
+	switch t := ty.(type) {
+	case nil:
+		return v, true
+	case NullableType:
+		if _, ok := v.(*NullValue); ok {
+			return v, true
+		}
+		return CoerceType(t.BaseType, v, strict)
+	case UnionType:
+		return coerceUnion(t, v, strict)
+	case Int, Float, String, Bool:
+		return coerceScalar(ty, v, strict, false)
+	}
+	return v, ty.Is(v)
+}
+
+// coerceUnion 联合类型：值的类型本身在联合类型中时直接接受，
+// 否则按 int、float、string、bool 的顺序尝试转换（与 PHP 相同）
+func coerceUnion(u UnionType, v Value, strict bool) (Value, bool) {
+	for _, t := range u.Types {
+		switch {
+		case t == nil:
+			return v, true
+		case isScalarType(t):
+			if sameScalar(t, v) {
+				return v, true
+			}
+		case t.Is(v):
+			return v, true
+		}
+	}
+	if strict {
+		if iv, ok := v.(*IntValue); ok && unionHas(u, Float{}) {
+			return NewFloatValue(float64(iv.Value)), true
+		}
+		return v, false
+	}
+	for _, want := range []Types{Int{}, Float{}, String{}, Bool{}} {
+		if !unionHas(u, want) {
+			continue
+		}
+		if r, ok := coerceScalar(want, v, false, true); ok {
+			return r, true
+		}
+	}
+	return v, false
+}
+
+func unionHas(u UnionType, want Types) bool {
+	for _, t := range u.Types {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+func isScalarType(t Types) bool {
+	switch t.(type) {
+	case Int, Float, String, Bool:
+		return true
+	}
+	return false
+}
+
+func sameScalar(t Types, v Value) bool {
+	switch t.(type) {
+	case Int:
+		_, ok := v.(*IntValue)
+		return ok
+	case Float:
+		_, ok := v.(*FloatValue)
+		return ok
+	case String:
+		_, ok := v.(*StringValue)
+		return ok
+	case Bool:
+		_, ok := v.(*BoolValue)
+		return ok
+	}
+	return false
+}
+
+// coerceScalar 转换为标量类型 want；exact 为 true 时（联合类型）不接受丢失精度的 float → int
+func coerceScalar(want Types, v Value, strict, exact bool) (Value, bool) {
+	if sameScalar(want, v) {
+		return v, true
+	}
+	// int → float 在严格模式下同样允许
+	if _, ok := want.(Float); ok {
+		if iv, ok := v.(*IntValue); ok {
+			return NewFloatValue(float64(iv.Value)), true
+		}
+	}
+	if strict {
+		return v, false
+	}
+	switch want.(type) {
+	case Int:
+		switch val := v.(type) {
+		case *FloatValue:
+			return floatToInt(val.Value, exact)
+		case *BoolValue:
+			if val.Value {
+				return NewIntValue(1), true
+			}
+			return NewIntValue(0), true
+		case *StringValue:
+			n, ok := ParseNumericString(val.Value)
+			if !ok {
+				return v, false
+			}
+			if f, ok := n.(*FloatValue); ok {
+				return floatToInt(f.Value, exact)
+			}
+			return n, true
+		}
+	case Float:
+		switch val := v.(type) {
+		case *BoolValue:
+			if val.Value {
+				return NewFloatValue(1), true
+			}
+			return NewFloatValue(0), true
+		case *StringValue:
+			n, ok := ParseNumericString(val.Value)
+			if !ok {
+				return v, false
+			}
+			if iv, ok := n.(*IntValue); ok {
+				return NewFloatValue(float64(iv.Value)), true
+			}
+			return n, true
+		}
+	case String:
+		switch val := v.(type) {
+		case *IntValue, *FloatValue:
+			return NewStringValue(v.AsString()), true
+		case *BoolValue:
+			if val.Value {
+				return NewStringValue("1"), true
+			}
+			return NewStringValue(""), true
+		case *ClassValue:
+			return stringableToString(val)
+		}
+	case Bool:
+		switch val := v.(type) {
+		case *IntValue:
+			return NewBoolValue(val.Value != 0), true
+		case *FloatValue:
+			return NewBoolValue(val.Value != 0), true
+		case *StringValue:
+			return NewBoolValue(val.Value != "" && val.Value != "0"), true
+		}
+	}
+	return v, false
+}
+
+// floatToInt float → int：NaN、Inf 与超出 int 范围的值不能转换；
+// 带小数部分的值在 exact 模式下拒绝，否则截断（PHP 8.1 起会给出弃用提示）
+func floatToInt(f float64, exact bool) (Value, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return nil, false
+	}
+	if exact && f != math.Trunc(f) {
+		return nil, false
+	}
+	return NewIntValue(int(f)), true
+}
+
+// stringableToString 调用对象的 __toString 转为字符串
+func stringableToString(obj *ClassValue) (Value, bool) {
+	method, ok := obj.GetMethod("__toString")
+	if !ok || method == nil {
+		return obj, false
+	}
+	fnCtx := obj.CreateContext(method.GetVariables())
+	ret, ctl := method.Call(fnCtx)
+	if ctl != nil {
+		return obj, false
+	}
+	if sv, ok := ret.(*StringValue); ok {
+		return sv, true
+	}
+	return obj, false
+}
+
+// TypeNameOf 返回值在类型错误消息中的名称（与 get_debug_type 一致）
+func TypeNameOf(v Value) string {
+	switch val := v.(type) {
+	case nil, *NullValue:
+		return "null"
+	case *IntValue:
+		return "int"
+	case *FloatValue:
+		return "float"
+	case *StringValue:
+		return "string"
+	case *BoolValue:
+		return "bool"
+	case *ArrayValue, *ObjectValue:
+		return "array"
+	case *ClassValue:
+		return val.Class.GetName()
+	case *ThisValue:
+		return val.Class.GetName()
+	case *FuncValue, *BoundFuncValue:
+		return "Closure"
+	}
+	return "mixed"
+}
@@ -0,0 +1,44 @@
+package data
+
+import "testing"
+
+func TestParseNumericString(t *testing.T) {
+	cases := map[string]string{
+		"12":                   "int",
+		" 12\n":                "int",
+		"-3":                   "int",
+		"1.5":                  "float",
+		"1e3":                  "float",
+		".5":                   "float",
+		"99999999999999999999": "float",
+	}
+	for s, want := range cases {
+		v, ok := ParseNumericString(s)
+		if !ok || TypeNameOf(v) != want {
+			t.Fatalf("ParseNumericString(%q) = %v, %v; want %s", s, v, ok, want)
+		}
+	}
+	for _, s := range []string{"", " ", "abc", "12abc", "1e", ".", "0x1A"} {
+		if _, ok := ParseNumericString(s); ok {
+			t.Fatalf("ParseNumericString(%q) should fail", s)
+		}
+	}
+}
+
+func TestCoerceType(t *testing.T) {
+	if v, ok := CoerceType(Int{}, NewStringValue("5"), false); !ok || v.(*IntValue).Value != 5 {
+		t.Fatal("coercive mode should convert numeric string to int")
+	}
+	if _, ok := CoerceType(Int{}, NewStringValue("5"), true); ok {
+		t.Fatal("strict mode should reject string for int")
+	}
+	if v, ok := CoerceType(Float{}, NewIntValue(2), true); !ok || v.(*FloatValue).Value != 2 {
+		t.Fatal("strict mode should widen int to float")
+	}
+	if _, ok := CoerceType(Bool{}, NewIntValue(1), true); ok {
+		t.Fatal("strict mode should reject int for bool")
+	}
+	if v, ok := CoerceType(NewUnionType([]Types{Int{}, String{}}), NewFloatValue(1.5), false); !ok || v.(*StringValue).Value != "1.5" {
+		t.Fatal("union int|string should take string for fractional float")
+	}
+}
@@ -2,16 +2,186 @@ package data
 
 import "fmt"
 
-// Generic 泛型
+// Generic 泛型类型参数，Bound 为 extends 约束（class Repo<T extends Entity>）
 type Generic struct {
 	Name  string
 	Types []Types
+	Bound Types
 }
 
+// Is 未绑定具体类型的类型参数只检查约束
 func (i Generic) Is(value Value) bool {
-	return true // TODO
+	if i.Bound == nil {
+		return true
+	}
+	return i.Bound.Is(value)
 }
 
 func (i Generic) String() string {
 	return fmt.Sprintf("%v", i.Name)
 }
+
+// TypedArray 声明了元素类型的数组 array<T>、array<K, V>
+type TypedArray struct {
+	Key   Types
+	Value Types
+}
+
+func (t TypedArray) Is(value Value) bool {
+	switch arr := value.(type) {
+	case *ArrayValue:
+		for i, z := range arr.List {
+			if t.Key != nil && !t.Key.Is(NewIntValue(i)) {
+				return false
+			}
+			if t.Value != nil && !t.Value.Is(z.Value) {
+				return false
+			}
+		}
+		return true
+	case *ObjectValue:
+		ok := true
+		arr.RangeProperties(func(key string, v Value) bool {
+			if t.Key != nil && !t.Key.Is(NewStringValue(key)) {
+				ok = false
+			} else if t.Value != nil && !t.Value.Is(v) {
+				ok = false
+			}
+			return ok
+		})
+		return ok
+	}
+	return false
+}
+
+func (t TypedArray) String() string {
+	value := "mixed"
+	if t.Value != nil {
+		value = t.Value.String()
+	}
+	if t.Key != nil {
+		return "array<" + t.Key.String() + ", " + value + ">"
+	}
+	return "array<" + value + ">"
+}
+
+// NewTypedArray 根据 array<...> 中的类型参数创建数组类型：一个参数为元素类型，两个参数为键、值类型
+func NewTypedArray(types []Types) Types {
+	switch len(types) {
+	case 0:
+		return Arrays{}
+	case 1:
+		return TypedArray{Value: types[0]}
+	default:
+		return TypedArray{Key: types[0], Value: types[1]}
+	}
+}
+
+// HasGenerics 类型中是否引用了类型参数
+func HasGenerics(ty Types) bool {
+	switch t := ty.(type) {
+	case Generic:
+		return true
+	case NullableType:
+		return HasGenerics(t.BaseType)
+	case UnionType:
+		for _, ut := range t.Types {
+			if HasGenerics(ut) {
+				return true
+			}
+		}
+	case TypedArray:
+		return HasGenerics(t.Key) || HasGenerics(t.Value)
+	}
+	return false
+}
+
+// ResolveGenerics 用类型实参替换类型中的类型参数，返回新的类型，不修改 ty 本身；
+// m 中映射为 nil 的类型参数表示 mixed
+func ResolveGenerics(ty Types, m map[string]Types) Types {
+	if len(m) == 0 {
+		return ty
+	}
+	switch t := ty.(type) {
+	case Generic:
+		if r, ok := m[t.Name]; ok {
+			return r
+		}
+	case NullableType:
+		base := ResolveGenerics(t.BaseType, m)
+		if base == nil {
+			return nil
+		}
+		return NullableType{BaseType: base}
+	case UnionType:
+		types := make([]Types, len(t.Types))
+		for i, ut := range t.Types {
+			if types[i] = ResolveGenerics(ut, m); types[i] == nil {
+				return nil
+			}
+		}
+		return UnionType{Types: types}
+	case TypedArray:
+		return TypedArray{Key: ResolveGenerics(t.Key, m), Value: ResolveGenerics(t.Value, m)}
+	}
+	return ty
+}
+
+// TypeOfValue 值的运行时类型，用于从实参推断类型参数
+func TypeOfValue(v Value) Types {
+	switch val := v.(type) {
+	case *ClassValue:
+		return Class{Name: val.Class.GetName()}
+	case *ThisValue:
+		return Class{Name: val.Class.GetName()}
+	case *IntValue:
+		return Int{}
+	case *FloatValue:
+		return Float{}
+	case *StringValue:
+		return String{}
+	case *BoolValue:
+		return Bool{}
+	case *ArrayValue, *ObjectValue:
+		return Arrays{}
+	case *FuncValue, *BoundFuncValue:
+		return ClosureType{}
+	}
+	return nil
+}
+
+// SatisfiesBound 类型实参 arg 是否满足约束 bound：类需要是 bound 的子类或实现了 bound 接口，其余类型需要相同
+func SatisfiesBound(vm VM, arg, bound Types) bool {
+	switch b := bound.(type) {
+	case nil:
+		return true
+	case NullableType:
+		if _, ok := arg.(NullType); ok {
+			return true
+		}
+		return SatisfiesBound(vm, arg, b.BaseType)
+	case UnionType:
+		for _, bt := range b.Types {
+			if SatisfiesBound(vm, arg, bt) {
+				return true
+			}
+		}
+		return false
+	case Class:
+		c, ok := arg.(Class)
+		if !ok {
+			return false
+		}
+		if c.Name == b.Name {
+			return true
+		}
+		if vm == nil {
+			return false
+		}
+		if stmt, acl := vm.GetOrLoadClass(c.Name); acl == nil && stmt != nil {
+			return isClassValueInstanceOf(b.Name, stmt, vm)
+		}
+		return interfaceExtends(vm, c.Name, b.Name)
+	}
+	return arg != nil && arg.String() == bound.String()
+}
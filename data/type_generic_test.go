@@ -0,0 +1,34 @@
+package data
+
+import "testing"
+
+func TestResolveGenerics(t *testing.T) {
+	ty := NewUnionType([]Types{NewNullableType(Generic{Name: "T"}), NewTypedArray([]Types{Generic{Name: "T"}})})
+	m := map[string]Types{"T": Int{}}
+	resolved := ResolveGenerics(ty, m)
+	if resolved.String() != "?int|array<int>" {
+		t.Fatalf("ResolveGenerics = %s", resolved.String())
+	}
+	if ty.String() != "?T|array<T>" {
+		t.Fatalf("ResolveGenerics must not modify the declared type, got %s", ty.String())
+	}
+	if HasGenerics(resolved) || !HasGenerics(ty) {
+		t.Fatal("HasGenerics mismatch")
+	}
+	if ResolveGenerics(Generic{Name: "T"}, map[string]Types{"T": nil}) != nil {
+		t.Fatal("type parameter mapped to mixed should resolve to nil")
+	}
+}
+
+func TestTypedArray(t *testing.T) {
+	ints := NewTypedArray([]Types{Int{}})
+	if !ints.Is(NewArrayValue([]Value{NewIntValue(1), NewIntValue(2)})) {
+		t.Fatal("array<int> should accept [1, 2]")
+	}
+	if ints.Is(NewArrayValue([]Value{NewIntValue(1), NewStringValue("x")})) {
+		t.Fatal("array<int> should reject [1, 'x']")
+	}
+	if (Generic{Name: "T", Bound: Int{}}).Is(NewStringValue("x")) {
+		t.Fatal("unresolved type parameter should still check its bound")
+	}
+}
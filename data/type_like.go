@@ -0,0 +1,172 @@
+package data
+
+import (
+	"strings"
+	"sync"
+)
+
+// LikeType 结构化类型 like Foo：值是对象，且具有接口或类 Foo 的全部公开实例方法、参数个数兼容即可，不要求声明 implements。
+// 检查结果按（类, 接口）缓存
+type LikeType struct {
+	Name string
+}
+
+func NewLikeType(name string) Types {
+	return LikeType{Name: name}
+}
+
+func (l LikeType) Is(value Value) bool {
+	missing, ok := l.Missing(value)
+	return ok && len(missing) == 0
+}
+
+func (l LikeType) String() string {
+	return "like " + l.Name
+}
+
+// Missing 返回对象缺少或参数个数不兼容的方法（如 "flush()"），value 不是对象或目标不存在时 ok 为 false
+func (l LikeType) Missing(value Value) (missing []string, ok bool) {
+	var class ClassStmt
+	var vm VM
+	switch c := value.(type) {
+	case *ClassValue:
+		class, vm = c.Class, c.GetVM()
+	case *ThisValue:
+		class, vm = c.Class, c.GetVM()
+	}
+	if class == nil || vm == nil {
+		return nil, false
+	}
+	target, acl := vm.LoadPkg(l.Name)
+	if acl != nil || target == nil {
+		return nil, false
+	}
+	return LikeMissing(vm, class, target), true
+}
+
+// likeKey 结构化检查的缓存键：（类, 目标接口或类）
+type likeKey struct {
+	class  ClassStmt
+	target GetValue
+}
+
+var likeCache sync.Map // likeKey → []string
+
+// LikeMissing 类 class 相对于目标接口或类 target 缺少或参数个数不兼容的方法，结果按（类, 目标）缓存
+func LikeMissing(vm VM, class ClassStmt, target GetValue) []string {
+	key := likeKey{class: class, target: target}
+	if v, ok := likeCache.Load(key); ok {
+		return v.([]string)
+	}
+	var missing []string
+	for _, want := range likeMethods(vm, target) {
+		have, ok := findClassMethod(vm, class, want.GetName())
+		switch {
+		case !ok:
+			missing = append(missing, want.GetName()+"()")
+		case !arityCompatible(have.GetParams(), want.GetParams()):
+			missing = append(missing, want.GetName()+"() with incompatible parameters")
+		}
+	}
+	likeCache.Store(key, missing)
+	return missing
+}
+
+// LikeMismatch 用于 TypeError 消息：ty 为 like 类型（或其可空形式）且 value 是对象时，列出缺少的方法
+func LikeMismatch(ty Types, value Value) string {
+	if n, ok := ty.(NullableType); ok {
+		ty = n.BaseType
+	}
+	l, ok := ty.(LikeType)
+	if !ok {
+		return ""
+	}
+	missing, ok := l.Missing(value)
+	if !ok || len(missing) == 0 {
+		return ""
+	}
+	return " (missing methods: " + strings.Join(missing, ", ") + ")"
+}
+
+// likeMethods 目标要求的方法：接口（含父接口）的全部方法，类的公开实例方法（不含构造函数）
+func likeMethods(vm VM, target GetValue) []Method {
+	switch t := target.(type) {
+	case InterfaceStmt:
+		var methods []Method
+		seen := map[string]bool{}
+		queue := []InterfaceStmt{t}
+		for len(queue) > 0 {
+			iface := queue[0]
+			queue = queue[1:]
+			for _, m := range iface.GetMethods() {
+				if name := strings.ToLower(m.GetName()); !seen[name] {
+					seen[name] = true
+					methods = append(methods, m)
+				}
+			}
+			for _, name := range iface.GetExtends() {
+				if parent, ok := vm.GetInterface(name); ok {
+					queue = append(queue, parent)
+				}
+			}
+		}
+		return methods
+	case ClassStmt:
+		var methods []Method
+		for _, m := range t.GetMethods() {
+			if m.GetModifier() != ModifierPublic || m.GetIsStatic() || strings.EqualFold(m.GetName(), "__construct") {
+				continue
+			}
+			methods = append(methods, m)
+		}
+		return methods
+	}
+	return nil
+}
+
+// findClassMethod 在类及其父类中查找公开的实例方法
+func findClassMethod(vm VM, class ClassStmt, name string) (Method, bool) {
+	for class != nil {
+		if m, ok := class.GetMethod(name); ok && m != nil {
+			return m, m.GetModifier() == ModifierPublic && !m.GetIsStatic()
+		}
+		ext := class.GetExtend()
+		if ext == nil {
+			break
+		}
+		next, acl := vm.GetOrLoadClass(*ext)
+		if acl != nil {
+			break
+		}
+		class = next
+	}
+	return nil, false
+}
+
+// arityCompatible 按目标方法可能收到的实参个数调用 have 都不会出错：
+// have 的必填参数不多于 want 的必填参数，且能接收 want 的全部参数
+func arityCompatible(have, want []GetValue) bool {
+	haveReq, haveMax, haveVariadic := arity(have)
+	wantReq, wantMax, wantVariadic := arity(want)
+	if haveReq > wantReq {
+		return false
+	}
+	if haveVariadic {
+		return true
+	}
+	return !wantVariadic && haveMax >= wantMax
+}
+
+func arity(params []GetValue) (required, max int, variadic bool) {
+	for _, p := range params {
+		if _, ok := p.(Parameters); ok {
+			variadic = true
+			continue
+		}
+		max++
+		if dp, ok := p.(Parameter); ok && dp.GetDefaultValue() == nil {
+			required = max
+		}
+	}
+	return required, max, variadic
+}
@@ -15,19 +15,29 @@ func NewArrayValue(v []Value) Value {
 	}
 }
 
-// CloneArrayValue 创建一个新的 ArrayValue。
-// 为了性能，这里仅复制 []*ZVal 切片本身（浅拷贝），不重新分配每个 ZVal：
-// - 结构不共享：两个数组的 List 是不同的 slice，结构性修改（如 array_shift/append）互不影响
-// - 元素仍按 ZVal 语义工作：写入单个元素时会替换对应 ZVal，不会影响其他数组
+// CloneArrayValue 按 PHP 值语义复制数组，O(1)：
+// 新数组与 src 共用 List 的底层存储（写时复制），任一方原地修改前须先调用 Separate。
+// 两个切片都截断了容量，未分离时的 append 会重新分配，不会写入对方可见的位置。
 func CloneArrayValue(src *ArrayValue) *ArrayValue {
 	if src == nil {
 		return nil
 	}
-	list := make([]*ZVal, len(src.List))
-	copy(list, src.List)
+	if src.share == nil {
+		src.share = &arrayShare{}
+		src.share.refs.Store(1)
+	}
+	src.share.refs.Add(1)
+	n := len(src.List)
+	src.List = src.List[:n:n]
+	if k := src.keys; k != nil && k.valid(src.List) && k.n == n {
+		// 索引此后由共用存储的数组一起读取，只读
+		k.owner = nil
+		src.share.keys.Store(k)
+	}
 	return &ArrayValue{
-		List:                  list,
+		List:                  src.List,
 		IndirectOverloadClass: src.IndirectOverloadClass,
+		share:                 src.share,
 	}
 }
 
@@ -55,6 +65,8 @@ type ArrayValue struct {
 	iterator int // 迭代器当前位置索引
 	// IndirectOverloadClass 非空表示该数组来自 ArrayAccess::offsetGet 的副本，对其元素的间接修改无效
 	IndirectOverloadClass string
+	share                 *arrayShare // 与其它数组共用 List 底层存储时非 nil，见 Separate
+	keys                  *arrayKeys  // 字符串键索引，由 FindSlot 惰性建立
 }
 
 func (a *ArrayValue) Current(ctx Context) (Value, Control) {
@@ -111,6 +123,11 @@ func (a *ArrayValue) AsBool() (bool, error) {
 }
 
 func (a *ArrayValue) GetMethod(name string) (Method, bool) {
+	switch name {
+	case "push", "pop", "shift", "unshift", "splice", "sort", "reverse":
+		// 原地修改的方法
+		a.Separate()
+	}
 	switch name {
 	case "push":
 		return &ArrayValuePush{&a.List}, true
@@ -211,11 +228,8 @@ func ParseIntArrayKeyName(name string) (int, bool) {
 
 // FindSlotByIntKey 按 PHP 整数键查找槽位（含稀疏键 Name=="6" 等）
 func (a *ArrayValue) FindSlotByIntKey(i int) (*ZVal, int) {
-	keyStr := IntArrayKeyName(i)
-	for j, z := range a.List {
-		if z != nil && z.Name == keyStr {
-			return z, j
-		}
+	if z, j := a.FindSlot(IntArrayKeyName(i)); z != nil {
+		return z, j
 	}
 	if i >= 0 && i < len(a.List) {
 		if z := a.List[i]; z != nil && z.Name == "" {
@@ -227,6 +241,7 @@ func (a *ArrayValue) FindSlotByIntKey(i int) (*ZVal, int) {
 
 // SetIntKey 设置整数键（不将稀疏数组转为 ObjectValue）
 func (a *ArrayValue) SetIntKey(i int, value Value) {
+	a.Separate()
 	if z, _ := a.FindSlotByIntKey(i); z != nil {
 		z.Value = value
 		return
@@ -254,34 +269,29 @@ func (a *ArrayValue) normalizeDenseIntKeys() {
 	for j, z := range a.List {
 		if z != nil && z.Name == "" {
 			z.Name = IntArrayKeyName(j)
+			a.keys = nil
 		}
 	}
 }
 
 // UnsetKey 删除整数或字符串键（不存在则无操作）
 func (a *ArrayValue) UnsetKey(index Value) {
+	key := ""
 	if iv, ok := index.(AsInt); ok {
 		i, err := iv.AsInt()
 		if err != nil {
 			return
 		}
+		a.Separate()
 		a.normalizeDenseIntKeys()
-		keyStr := IntArrayKeyName(i)
-		for j, z := range a.List {
-			if z != nil && z.Name == keyStr {
-				a.List = append(a.List[:j], a.List[j+1:]...)
-				return
-			}
-		}
+		key = IntArrayKeyName(i)
+	} else if sv, ok := index.(AsString); ok {
+		key = sv.AsString()
+	} else {
 		return
 	}
-	if sv, ok := index.(AsString); ok {
-		key := sv.AsString()
-		for j, z := range a.List {
-			if z != nil && z.Name == key {
-				a.List = append(a.List[:j], a.List[j+1:]...)
-				return
-			}
-		}
+	if _, j := a.FindSlot(key); j >= 0 {
+		a.Separate()
+		a.List = append(a.List[:j], a.List[j+1:]...)
 	}
 }
@@ -0,0 +1,161 @@
+package data
+
+import "sync/atomic"
+
+// 数组按值传递采用写时复制（copy-on-write）：
+// CloneArrayValue 只复制 ArrayValue 头部，新旧数组共用 List 的底层存储并共享一个引用计数；
+// 任一方在原地修改前调用 Separate，引用计数大于 1 时才真正复制槽位。
+// Go 没有析构，未写入就被丢弃的副本不会归还计数，因此原数组在之后第一次写入时最多多复制一次。
+
+// arrayShare 共用同一底层存储的数组之间共享的状态
+type arrayShare struct {
+	refs atomic.Int32
+	keys atomic.Pointer[arrayKeys] // 共用存储上的字符串键索引，建好后只读
+}
+
+// Separate 在原地修改 List 或其中的 ZVal 之前调用，保证当前数组独占存储。
+// 引用槽位（RefSlotCount > 0，如 $x = &$arr[0]）在副本间继续共享同一个 ZVal，与 PHP 一致；
+// 嵌套数组只复制头部，写入子数组时再逐层分离。
+func (a *ArrayValue) Separate() {
+	s := a.share
+	if s == nil {
+		return
+	}
+	a.share = nil
+	k := s.keys.Load()
+	if k == nil || !k.valid(a.List) {
+		k = a.keys
+	}
+	if s.refs.Add(-1) == 0 {
+		// 其它副本都已分离，剩下的存储归当前数组所有
+		a.keys = k
+		return
+	}
+	list := make([]*ZVal, len(a.List))
+	for i, z := range a.List {
+		if z == nil || z.RefSlotCount > 0 {
+			list[i] = z
+			continue
+		}
+		v := z.Value
+		if arr, ok := v.(*ArrayValue); ok {
+			v = CloneArrayValue(arr)
+		}
+		list[i] = &ZVal{Name: z.Name, Value: v}
+	}
+	a.keys = nil
+	if k != nil && k.valid(a.List) {
+		// 复制不改变槽位顺序，沿用原索引（只读）
+		a.keys = &arrayKeys{m: k.m, base: &list[0], n: k.n, last: list[k.n-1]}
+	}
+	a.List = list
+}
+
+// Shared 当前数组是否与其它数组共用存储
+func (a *ArrayValue) Shared() bool {
+	return a.share != nil && a.share.refs.Load() > 1
+}
+
+// keyIndexMinLen 槽位数达到该值后才为字符串键建立哈希索引，更小的数组直接线性查找
+const keyIndexMinLen = 16
+
+// arrayKeys 字符串键（ZVal.Name）到槽位下标的索引，保持插入顺序的仍是 List 本身。
+// List 可能被外部代码直接修改，因此每次查找都会校验：
+// 底层存储或末尾槽位变化、长度缩短时重建；只在末尾追加时增量补齐；命中后核对槽位名称。
+type arrayKeys struct {
+	m     map[string]int // 没有命名槽位时为 nil
+	base  **ZVal         // 建索引时 List 的底层存储
+	n     int            // 已索引的槽位数
+	last  *ZVal          // 已索引的最后一个槽位
+	owner *ArrayValue    // 可原地追加的数组，为 nil 时只读
+}
+
+// FindSlot 按字符串键查找槽位，不存在时返回 nil, -1
+func (a *ArrayValue) FindSlot(key string) (*ZVal, int) {
+	if len(a.List) < keyIndexMinLen {
+		for j, z := range a.List {
+			if z != nil && z.Name == key {
+				return z, j
+			}
+		}
+		return nil, -1
+	}
+	k := a.keyIndex()
+	if j, ok := k.m[key]; ok {
+		if j < len(a.List) && a.List[j] != nil && a.List[j].Name == key {
+			return a.List[j], j
+		}
+		// 槽位被原地重排或替换，重建后再查一次
+		k = a.storeKeys(buildKeys(a.List, a))
+		if j, ok := k.m[key]; ok {
+			return a.List[j], j
+		}
+	}
+	return nil, -1
+}
+
+func (a *ArrayValue) keyIndex() *arrayKeys {
+	if s := a.share; s != nil {
+		// 共用存储的数组读取同一份索引，副本不必各自重建
+		if k := s.keys.Load(); k != nil && k.valid(a.List) && k.n == len(a.List) {
+			return k
+		}
+		return a.storeKeys(buildKeys(a.List, nil))
+	}
+	k := a.keys
+	if k == nil || !k.valid(a.List) {
+		return a.storeKeys(buildKeys(a.List, a))
+	}
+	if k.n < len(a.List) {
+		if k.owner != a {
+			// 只读索引在追加前先复制一份
+			c := &arrayKeys{base: k.base, n: k.n, last: k.last, owner: a}
+			if k.m != nil {
+				c.m = make(map[string]int, len(k.m))
+				for name, j := range k.m {
+					c.m[name] = j
+				}
+			}
+			k = c
+			a.keys = k
+		}
+		k.index(a.List)
+	}
+	return k
+}
+
+func (a *ArrayValue) storeKeys(k *arrayKeys) *arrayKeys {
+	if a.share != nil {
+		k.owner = nil
+		a.share.keys.Store(k)
+	}
+	a.keys = k
+	return k
+}
+
+// valid 索引是否仍对应 list 的前 k.n 个槽位
+func (k *arrayKeys) valid(list []*ZVal) bool {
+	return k.n > 0 && k.n <= len(list) && k.base == &list[0] && list[k.n-1] == k.last
+}
+
+func buildKeys(list []*ZVal, owner *ArrayValue) *arrayKeys {
+	k := &arrayKeys{base: &list[0], owner: owner}
+	k.index(list)
+	return k
+}
+
+// index 把 list[k.n:] 中的命名槽位加入索引；重复的名称保留最先出现的位置，与线性查找一致
+func (k *arrayKeys) index(list []*ZVal) {
+	for j := k.n; j < len(list); j++ {
+		if z := list[j]; z != nil && z.Name != "" {
+			if k.m == nil {
+				k.m = make(map[string]int)
+			}
+			if _, ok := k.m[z.Name]; !ok {
+				k.m[z.Name] = j
+			}
+		}
+	}
+	k.n = len(list)
+	k.last = list[len(list)-1]
+}
@@ -0,0 +1,66 @@
+package data
+
+import "testing"
+
+func TestCloneArrayValueCopyOnWrite(t *testing.T) {
+	src := NewArrayValue([]Value{NewIntValue(1), NewIntValue(2)}).(*ArrayValue)
+	ref := src.List[1]
+	ref.AddRefSlot()
+
+	dst := CloneArrayValue(src)
+	if &dst.List[0] != &src.List[0] || !src.Shared() || !dst.Shared() {
+		t.Fatal("clone should share storage until written")
+	}
+
+	dst.SetIntKey(0, NewIntValue(9))
+	if src.List[0].Value.(*IntValue).Value != 1 || dst.List[0].Value.(*IntValue).Value != 9 {
+		t.Fatal("write to clone leaked into source")
+	}
+	if dst.List[1] != ref {
+		t.Fatal("reference slot should stay shared between copies")
+	}
+	if src.Shared() {
+		t.Fatal("source should own its storage after the clone separated")
+	}
+	src.Separate()
+	if src.List[0].Value.(*IntValue).Value != 1 {
+		t.Fatal("separating the last holder should not copy")
+	}
+}
+
+func TestFindSlotIndex(t *testing.T) {
+	a := NewArrayValue(nil).(*ArrayValue)
+	for i := 0; i < keyIndexMinLen*2; i++ {
+		a.List = append(a.List, NewNamedZVal(IntArrayKeyName(i)+"k", NewIntValue(i)))
+	}
+	if z, j := a.FindSlot("5k"); z == nil || j != 5 {
+		t.Fatalf("FindSlot(5k) = %v, %d", z, j)
+	}
+
+	// 直接追加、原地删除与重排后索引仍然正确
+	a.List = append(a.List, NewNamedZVal("new", NewNullValue()))
+	if _, j := a.FindSlot("new"); j != len(a.List)-1 {
+		t.Fatalf("appended key at %d", j)
+	}
+	a.UnsetKey(NewStringValue("3k"))
+	a.List = append(a.List, NewNamedZVal("tail", NewNullValue()))
+	if z, _ := a.FindSlot("3k"); z != nil {
+		t.Fatal("unset key still found")
+	}
+	if _, j := a.FindSlot("tail"); j != len(a.List)-1 {
+		t.Fatalf("tail key at %d", j)
+	}
+	a.List[0], a.List[1] = a.List[1], a.List[0]
+	if _, j := a.FindSlot("0k"); j != 1 {
+		t.Fatalf("swapped key at %d", j)
+	}
+
+	b := CloneArrayValue(a)
+	b.List = append(b.List, NewNamedZVal("only-b", NewNullValue()))
+	if z, _ := a.FindSlot("only-b"); z != nil {
+		t.Fatal("key appended to clone visible in source")
+	}
+	if z, _ := b.FindSlot("only-b"); z == nil {
+		t.Fatal("key appended to clone not found")
+	}
+}
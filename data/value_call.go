@@ -1,6 +1,9 @@
 package data
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
 
 func NewFuncValue(v FuncStmt) *FuncValue {
 	return &FuncValue{
@@ -33,11 +36,133 @@ func (c *FuncValue) GetMethod(name string) (Method, bool) {
 	switch name {
 	case "bindto", "bindTo":
 		return &funcBindToMethod{closure: c}, true
+	case "call":
+		return &funcCallMethod{closure: c}, true
+	case "__invoke":
+		return &funcInvokeMethod{closure: c}, true
 	}
 	return nil, false
 }
 
-// funcBindToMethod 实现 Closure::bindTo($newThis, $newScope)
+// BindableFunc 可以重新绑定 $this 与类作用域的闭包（Closure::bind、bindTo、call）
+type BindableFunc interface {
+	FuncStmt
+	// BindScope 闭包当前的类作用域，没有时为 nil
+	BindScope() ClassStmt
+	// Bind 返回绑定到 this（nil 表示解除绑定）与 scope 类作用域的副本；不能绑定时返回 PHP Warning 的内容
+	Bind(this *ClassValue, scope ClassStmt) (FuncStmt, string)
+}
+
+// BindClosure 实现 Closure::bind 与 bindTo：newThis 为 null 时解除 $this 绑定，
+// newScope 省略或为 "static" 时保留原来的类作用域；不能绑定时输出 Warning 并返回 null
+func BindClosure(ctx Context, closure *FuncValue, newThis, newScope Value) (GetValue, Control) {
+	var this *ClassValue
+	switch t := newThis.(type) {
+	case *ClassValue:
+		this = t
+	case *ThisValue:
+		this = t.ClassValue
+	}
+	fn, ok := closure.Value.(BindableFunc)
+	if !ok {
+		if this == nil && isStaticScope(newScope) {
+			return closure, nil
+		}
+		closureWarning(ctx, "Cannot rebind scope of closure created from function")
+		return NewNullValue(), nil
+	}
+
+	scope := fn.BindScope()
+	switch s := newScope.(type) {
+	case nil:
+	case *NullValue:
+		scope = nil
+	case *ClassValue:
+		scope = s.Class
+	case *ThisValue:
+		scope = s.Class
+	default:
+		if !isStaticScope(s) {
+			name := s.AsString()
+			cls, acl := ctx.GetVM().GetOrLoadClass(name)
+			if acl != nil || cls == nil {
+				closureWarning(ctx, fmt.Sprintf("Class \"%s\" not found", name))
+				return NewNullValue(), nil
+			}
+			scope = cls
+		}
+	}
+
+	bound, warning := fn.Bind(this, scope)
+	if warning != "" {
+		closureWarning(ctx, warning)
+		return NewNullValue(), nil
+	}
+	if this != nil {
+		// 闭包持有对象，对象不再归属原变量
+		Escape(this)
+	}
+	return NewFuncValue(bound), nil
+}
+
+// ArgumentCoercer 由带类型声明的形参实现（见 node.Parameter）：按参数类型检查并转换实参，
+// callFrom 为 nil 时按强制转换模式处理
+type ArgumentCoercer interface {
+	CoerceArgument(value Value, fnName string, position int, callFrom From) (Value, Control)
+}
+
+// CallFunc 以 args 为实参调用函数：与直接调用一样按参数类型检查并转换实参，
+// 由标准库回调时没有调用点，按强制转换模式处理
+func CallFunc(ctx Context, fn FuncStmt, args []Value) (GetValue, Control) {
+	vars := fn.GetVariables()
+	fnCtx := ctx.CreateContext(vars)
+	params := fn.GetParams()
+	for i := 0; i < len(vars) && i < len(args); i++ {
+		arg := args[i]
+		if i < len(params) {
+			// 可变参数收集剩余的实参
+			if _, ok := params[i].(Parameters); ok {
+				fnCtx.SetVariableValue(NewVariable("", i, nil), NewArrayValue(args[i:]))
+				break
+			}
+			if p, ok := params[i].(ArgumentCoercer); ok {
+				v, acl := p.CoerceArgument(arg, fn.GetName(), i+1, nil)
+				if acl != nil {
+					return nil, acl
+				}
+				arg = v
+			}
+		}
+		fnCtx.SetVariableValue(NewVariable("", i, nil), arg)
+	}
+	return fn.Call(fnCtx)
+}
+
+func isStaticScope(v Value) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(*StringValue)
+	return ok && s.Value == "static"
+}
+
+// closureWarning 输出 PHP Warning，位置取自当前调用的实参
+func closureWarning(ctx Context, msg string) {
+	file, line := "Unknown", 0
+	for _, arg := range ctx.GetCallArgs() {
+		if g, ok := arg.(interface{ GetFrom() From }); ok && g.GetFrom() != nil {
+			from := g.GetFrom()
+			file = from.GetSource()
+			if sl, _ := from.GetStartPosition(); sl >= 0 {
+				line = sl + 1
+			}
+			break
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nWarning: %s in %s on line %d\n", msg, file, line)
+}
+
+// funcBindToMethod 实现 Closure::bindTo($newThis, $newScope = "static")
 type funcBindToMethod struct {
 	closure *FuncValue
 }
@@ -61,26 +186,79 @@ func (m *funcBindToMethod) GetVariables() []Variable {
 func (m *funcBindToMethod) Call(ctx Context) (GetValue, Control) {
 	newThis, _ := ctx.GetIndexValue(0)
 	newScope, _ := ctx.GetIndexValue(1)
+	return BindClosure(ctx, m.closure, newThis, newScope)
+}
+
+// funcCallMethod 实现 Closure::call($newThis, ...$args)：临时绑定到 $newThis 及其类作用域后调用
+type funcCallMethod struct {
+	closure *FuncValue
+}
 
-	// 提取 scope 类名
-	var scopeClass string
-	if sv, ok := newScope.(*StringValue); ok {
-		scopeClass = sv.Value
-	} else if cv, ok := newScope.(*ClassValue); ok {
-		scopeClass = cv.Class.GetName()
-	} else if _, ok := newScope.(*NullValue); ok {
-		// scope = null → 无作用域绑定
-	} else if newThis != nil {
-		// 如果 newScope 是对象，用它的类名
-		if cv, ok := newThis.(*ClassValue); ok {
-			scopeClass = cv.Class.GetName()
+func (m *funcCallMethod) GetName() string       { return "call" }
+func (m *funcCallMethod) GetModifier() Modifier { return ModifierPublic }
+func (m *funcCallMethod) GetIsStatic() bool     { return false }
+func (m *funcCallMethod) GetReturnType() Types  { return nil }
+func (m *funcCallMethod) GetParams() []GetValue {
+	return []GetValue{
+		NewParameter("newThis", 0),
+		NewParameters("args", 1),
+	}
+}
+func (m *funcCallMethod) GetVariables() []Variable {
+	return []Variable{
+		NewVariable("newThis", 0, nil),
+		NewVariable("args", 1, nil),
+	}
+}
+func (m *funcCallMethod) Call(ctx Context) (GetValue, Control) {
+	newThis, _ := ctx.GetIndexValue(0)
+	var scope Value = NewNullValue()
+	switch t := newThis.(type) {
+	case *ClassValue, *ThisValue:
+		scope = t
+	default:
+		return nil, NewErrorThrowByName(nil, fmt.Errorf("Closure::call(): Argument #1 ($newThis) must be of type object, %s given", TypeName(newThis)), "TypeError")
+	}
+	bound, acl := BindClosure(ctx, m.closure, newThis, scope)
+	if acl != nil {
+		return nil, acl
+	}
+	fv, ok := bound.(*FuncValue)
+	if !ok {
+		return NewNullValue(), nil
+	}
+	var args []Value
+	if arr, ok := ctx.GetIndexValue(1); ok {
+		if list, ok := arr.(*ArrayValue); ok {
+			args = list.ToValueList()
 		}
 	}
+	return CallFunc(ctx, fv.Value, args)
+}
 
-	if scopeClass != "" {
-		return NewBoundFuncValue(m.closure.Value, scopeClass), nil
+// funcInvokeMethod 实现 Closure::__invoke(...$args)
+type funcInvokeMethod struct {
+	closure *FuncValue
+}
+
+func (m *funcInvokeMethod) GetName() string       { return "__invoke" }
+func (m *funcInvokeMethod) GetModifier() Modifier { return ModifierPublic }
+func (m *funcInvokeMethod) GetIsStatic() bool     { return false }
+func (m *funcInvokeMethod) GetReturnType() Types  { return nil }
+func (m *funcInvokeMethod) GetParams() []GetValue {
+	return []GetValue{NewParameters("args", 0)}
+}
+func (m *funcInvokeMethod) GetVariables() []Variable {
+	return []Variable{NewVariable("args", 0, nil)}
+}
+func (m *funcInvokeMethod) Call(ctx Context) (GetValue, Control) {
+	var args []Value
+	if arr, ok := ctx.GetIndexValue(0); ok {
+		if list, ok := arr.(*ArrayValue); ok {
+			args = list.ToValueList()
+		}
 	}
-	return m.closure, nil
+	return CallFunc(ctx, m.closure.Value, args)
 }
 
 // BoundFuncValue 表示通过 Closure::bind() 绑定了作用域的闭包
@@ -8,7 +8,7 @@ import (
 func NewClassValue(class ClassStmt, ctx Context) *ClassValue {
 	// 开始初始化属性
 	return &ClassValue{
-		ObjectValue: NewObjectValue(),
+		ObjectValue: &ObjectValue{property: newShapedStore(ClassShape(class, ctx))},
 		Class:       class,
 		Context:     ctx,
 	}
@@ -155,6 +155,12 @@ func (c *ClassValue) GetMethod(name string) (Method, bool) {
 	return nil, false
 }
 
+// IsVirtualProperty 属性是否为虚拟属性（带钩子且没有自身存储）
+func IsVirtualProperty(prop Property) bool {
+	v, ok := prop.(interface{ GetIsVirtual() bool })
+	return ok && v.GetIsVirtual()
+}
+
 func (c *ClassValue) GetProperties() map[string]Value {
 	result := make(map[string]Value)
 
@@ -167,6 +173,10 @@ func (c *ClassValue) GetProperties() map[string]Value {
 	// 然后获取类定义的属性
 	classProps := c.Class.GetPropertyList()
 	for _, prop := range classProps {
+		// 虚拟属性（只由钩子计算）没有存储
+		if IsVirtualProperty(prop) {
+			continue
+		}
 		// 如果实例中没有这个属性，则使用类定义的默认值
 		if _, exists := result[prop.GetName()]; !exists {
 			defaultValue := prop.GetDefaultValue()
@@ -197,7 +207,7 @@ func (c *ClassValue) GetProperties() map[string]Value {
 		parentProps := next.GetPropertyList()
 		for _, prop := range parentProps {
 			// 只添加非私有属性，且实例中没有的属性
-			if prop.GetModifier() != ModifierPrivate {
+			if prop.GetModifier() != ModifierPrivate && !IsVirtualProperty(prop) {
 				if _, exists := result[prop.GetName()]; !exists {
 					defaultValue := prop.GetDefaultValue()
 					if defaultValue != nil {
@@ -248,6 +258,9 @@ func (c *ClassValue) SetProperty(name string, value Value) Control {
 	} else {
 		switch arr := value.(type) {
 		case *ArrayValue:
+			if cur, ok := c.property.Get(name); ok && cur == value {
+				return nil
+			}
 			value = CloneArrayValue(arr)
 		case *ObjectValue:
 			value = CloneObjectValue(arr)
@@ -262,7 +275,7 @@ func (c *ClassValue) GetVariableValue(variable Variable) (Value, Control) {
 }
 
 func (c *ClassValue) GoContext() context.Context {
-	return context.Background()
+	return GoContext(c)
 }
 
 func (c *ClassValue) SetVM(vm VM) {
@@ -312,7 +325,7 @@ func (c *ClassMethodContext) StaticLocalsStore() *StaticLocals {
 }
 
 func (c *ClassMethodContext) GoContext() context.Context {
-	return context.Background()
+	return GoContext(c)
 }
 
 func (c *ClassValue) Marshal(serializer Serializer) ([]byte, error) {
@@ -326,3 +339,60 @@ func (c *ClassValue) Unmarshal(data []byte, serializer Serializer) error {
 func (c *ClassValue) ToGoValue(serializer Serializer) (any, error) {
 	return serializer.MarshalClass(c)
 }
+
+// CallerInClassHierarchy 检查调用者是否在目标类的类层次结构中
+// 用于确定是否允许调用 protected 方法
+func CallerInClassHierarchy(ctx Context, targetClass ClassStmt) bool {
+	// 检查是否通过 Closure::bind() 绑定了作用域（允许访问私有成员）
+	if bc, ok := ctx.(*BoundContext); ok {
+		if bc.ScopeClass == targetClass.GetName() {
+			return true
+		}
+	}
+
+	// 从上下文链中查找 ClassMethodContext 或 ClassValue
+	var callerClass ClassStmt
+	if cmc, ok := ctx.(*ClassMethodContext); ok {
+		callerClass = cmc.Class
+	} else if cv, ok := ctx.(*ClassValue); ok {
+		callerClass = cv.Class
+	} else {
+		return false
+	}
+
+	// 检查调用者类是否与目标类相同
+	if callerClass.GetName() == targetClass.GetName() {
+		return true
+	}
+
+	vm := ctx.GetVM()
+
+	// 检查调用者类是否是目标类的子类（调用者继承自目标）
+	extend := callerClass.GetExtend()
+	for extend != nil {
+		if *extend == targetClass.GetName() {
+			return true
+		}
+		cls, acl := vm.GetOrLoadClass(*extend)
+		if acl != nil {
+			return false
+		}
+		extend = cls.GetExtend()
+	}
+
+	// 检查目标类是否是调用者类的子类（目标继承自调用者）
+	// 父类可以访问子类实例上的 protected 属性
+	targetExtend := targetClass.GetExtend()
+	for targetExtend != nil {
+		if *targetExtend == callerClass.GetName() {
+			return true
+		}
+		cls, acl := vm.GetOrLoadClass(*targetExtend)
+		if acl != nil {
+			return false
+		}
+		targetExtend = cls.GetExtend()
+	}
+
+	return false
+}
@@ -73,3 +73,117 @@ func Compare(v1, v2 Value) int {
 	// 默认情况：返回 0
 	return 0
 }
+
+// CompareUnordered LooseCompare 的结果之一：有 NAN 参与，除 != 外的比较都为 false（<=> 为 1）
+const CompareUnordered = 2
+
+// LooseCompare 按 PHP 8 的规则比较两个标量（int、float、string、bool、null），返回 -1、0、1 或 CompareUnordered：
+// 有 bool 或 null 时按 bool 比较（null 与字符串按 "" 比较），数字与数字字符串（允许前后空白）按数值比较，
+// 数字与非数字字符串按字符串比较。有一侧不是标量时 ok 为 false，由调用方处理
+func LooseCompare(v1, v2 Value) (int, bool) {
+	if !isScalar(v1) || !isScalar(v2) {
+		return 0, false
+	}
+	_, null1 := v1.(*NullValue)
+	_, null2 := v2.(*NullValue)
+	if s, ok := v2.(*StringValue); ok && null1 {
+		return compareStrings("", s.Value), true
+	}
+	if s, ok := v1.(*StringValue); ok && null2 {
+		return compareStrings(s.Value, ""), true
+	}
+	_, bool1 := v1.(*BoolValue)
+	_, bool2 := v2.(*BoolValue)
+	if null1 || null2 || bool1 || bool2 {
+		return compareBools(scalarBool(v1), scalarBool(v2)), true
+	}
+	s1, str1 := v1.(*StringValue)
+	s2, str2 := v2.(*StringValue)
+	switch {
+	case str1 && str2:
+		n1, ok1 := ParseNumericString(s1.Value)
+		n2, ok2 := ParseNumericString(s2.Value)
+		if ok1 && ok2 {
+			return compareNumbers(n1, n2), true
+		}
+		return compareStrings(s1.Value, s2.Value), true
+	case str1:
+		if n1, ok := ParseNumericString(s1.Value); ok {
+			return compareNumbers(n1, v2), true
+		}
+		return compareStrings(s1.Value, v2.AsString()), true
+	case str2:
+		if n2, ok := ParseNumericString(s2.Value); ok {
+			return compareNumbers(v1, n2), true
+		}
+		return compareStrings(v1.AsString(), s2.Value), true
+	}
+	return compareNumbers(v1, v2), true
+}
+
+func isScalar(v Value) bool {
+	switch v.(type) {
+	case *IntValue, *FloatValue, *StringValue, *BoolValue, *NullValue:
+		return true
+	}
+	return false
+}
+
+func scalarBool(v Value) bool {
+	switch n := v.(type) {
+	case *BoolValue:
+		return n.Value
+	case *IntValue:
+		return n.Value != 0
+	case *FloatValue:
+		return n.Value != 0
+	case *StringValue:
+		return n.Value != "" && n.Value != "0"
+	}
+	return false
+}
+
+func compareBools(b1, b2 bool) int {
+	switch {
+	case b1 == b2:
+		return 0
+	case b1:
+		return 1
+	}
+	return -1
+}
+
+func compareStrings(s1, s2 string) int {
+	switch {
+	case s1 < s2:
+		return -1
+	case s1 > s2:
+		return 1
+	}
+	return 0
+}
+
+// compareNumbers 比较两个 *IntValue 或 *FloatValue：都是整数时按整数比较，避免大整数转为 float 后丢失精度
+func compareNumbers(v1, v2 Value) int {
+	if i1, ok := v1.(*IntValue); ok {
+		if i2, ok := v2.(*IntValue); ok {
+			switch {
+			case i1.Value < i2.Value:
+				return -1
+			case i1.Value > i2.Value:
+				return 1
+			}
+			return 0
+		}
+	}
+	f1, f2 := numberToFloat(v1), numberToFloat(v2)
+	switch {
+	case f1 < f2:
+		return -1
+	case f1 > f2:
+		return 1
+	case f1 == f2:
+		return 0
+	}
+	return CompareUnordered
+}
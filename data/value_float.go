@@ -1,7 +1,5 @@
 package data
 
-import "strconv"
-
 func NewFloatValue(v float64) Value {
 	return &FloatValue{
 		Value: v,
@@ -29,11 +27,11 @@ func (s *FloatValue) GetValue(ctx Context) (GetValue, Control) {
 }
 
 func (s *FloatValue) AsString() string {
-	return strconv.FormatFloat(s.Value, 'g', 14, 64)
+	return FormatFloat(s.Value)
 }
 
 func (s *FloatValue) AsInt() (int, error) {
-	return int(s.Value), nil
+	return FloatToInt(s.Value), nil
 }
 
 func (s *FloatValue) AsFloat() (float64, error) {
@@ -44,7 +42,7 @@ func (s *FloatValue) AsFloat32() (float32, error) {
 }
 
 func (s *FloatValue) AsBool() (bool, error) {
-	return s.Value > 0, nil
+	return s.Value != 0, nil
 }
 
 func (s *FloatValue) Marshal(serializer Serializer) ([]byte, error) {
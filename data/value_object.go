@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 )
 
 type AsObject interface {
@@ -33,6 +34,10 @@ func CloneObjectValue(src *ObjectValue) *ObjectValue {
 
 	// 按插入顺序复制所有键值对
 	src.property.Range(func(key string, value Value) bool {
+		if arr, ok := value.(*ArrayValue); ok {
+			// 子数组写时复制，避免经副本写入 $b['k'][0] 时修改原数组
+			value = CloneArrayValue(arr)
+		}
 		clone.property.Set(key, value)
 		return true
 	})
@@ -47,10 +52,35 @@ type ObjectValue struct {
 	iterator int // 迭代器当前位置索引
 	// IndirectOverloadClass 非空表示该对象来自 ArrayAccess::offsetGet 的副本
 	IndirectOverloadClass string
+	life                  *lifetime // 定义了 __destruct 或被弱引用的对象，见 lifetime.go
+	id                    int64     // 见 ID，用 atomic 读写
+}
+
+// objectIds 已分配的对象标识数
+var objectIds atomic.Int64
+
+// ID 对象的标识（spl_object_id、spl_object_hash）：第一次读取时分配，之后不变；
+// 析构时重建的实例沿用原对象的标识
+func (o *ObjectValue) ID() int {
+	if id := atomic.LoadInt64(&o.id); id != 0 {
+		return int(id)
+	}
+	if o.life != nil {
+		if id := o.life.id.Load(); id != 0 {
+			atomic.CompareAndSwapInt64(&o.id, 0, id)
+			return int(atomic.LoadInt64(&o.id))
+		}
+	}
+	atomic.CompareAndSwapInt64(&o.id, 0, objectIds.Add(1))
+	id := atomic.LoadInt64(&o.id)
+	if o.life != nil {
+		o.life.id.Store(id)
+	}
+	return int(id)
 }
 
 func (o *ObjectValue) GoContext() context.Context {
-	return context.Background()
+	return GoContext(o)
 }
 
 func (o *ObjectValue) GetValue(ctx Context) (GetValue, Control) {
@@ -105,6 +135,10 @@ func (o *ObjectValue) SetProperty(name string, value Value) Control {
 	// 避免多个属性/变量共享同一个 ArrayValue 实例，被 array_shift/array_pop 等原地修改时互相影响。
 	switch arr := value.(type) {
 	case *ArrayValue:
+		if cur, ok := o.property.Get(name); ok && cur == value {
+			// 属性中的数组原地修改后写回自身，无需再复制
+			return nil
+		}
 		value = CloneArrayValue(arr)
 	case *ObjectValue:
 		value = CloneObjectValue(arr)
@@ -137,6 +171,18 @@ func (o *ObjectValue) SetVariableValue(variable Variable, value Value) Control {
 }
 
 func (o *ObjectValue) GetVariableValue(variable Variable) (Value, Control) {
+	if s, ok := o.property.(*shapedStore); ok {
+		// 声明的属性按缓存的槽位读取
+		if h, ok := variable.(PropertySlotHolder); ok {
+			if slot := h.PropertySlot(); slot != nil {
+				z, ok := s.getSlot(slot, variable.GetName())
+				if !ok {
+					return nil, nil
+				}
+				return z.Value, nil
+			}
+		}
+	}
 	v, ok := o.property.Get(variable.GetName())
 	if !ok {
 		return nil, nil
@@ -40,6 +40,9 @@ type ThrowValue struct {
 	Error *Error
 	// 调用栈信息
 	StackFrames []StackFrame
+
+	// SyntaxErrors 同一次解析中排在该错误之后的其余语法错误（解析器从错误中恢复后继续解析收集到的）
+	SyntaxErrors []*ThrowValue
 }
 
 // AddStackWithInfo 添加调用栈信息，包含类名和方法名
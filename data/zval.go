@@ -6,6 +6,8 @@ type ZVal struct {
 	Value Value
 	// RefSlotCount 表示有多少变量通过 &$arr[i] 等方式绑定到该槽位（用于 COW / 写穿）
 	RefSlotCount int
+	// Guard 引用绑定到带类型的属性（$r = &$obj->prop）时设置，通过引用写入的值先经它检查/转换
+	Guard func(from From, v Value) (Value, Control)
 }
 
 // AddRefSlot 标记该数组槽位被引用绑定（如 $x =& $arr[0]）
@@ -14,7 +14,6 @@ import (
 	"github.com/php-any/origami/parser"
 	"github.com/php-any/origami/runtime"
 	"github.com/php-any/origami/std"
-	"github.com/php-any/origami/std/context"
 	netannotation "github.com/php-any/origami/std/net/annotation"
 	"github.com/php-any/origami/std/net/http"
 	"github.com/php-any/origami/std/net/websocket"
@@ -174,7 +173,6 @@ func loadStdLibraries(vm data.VM) {
 	websocket.Load(vm)
 	netannotation.Load(vm)
 	system.Load(vm)
-	context.Load(vm)
 }
 
 func buildModules(ctx data.Context, functions []data.FuncStmt, classes []data.ClassStmt) []PseudoCode {
@@ -98,6 +98,8 @@ func getTokenTypeName(t token.TokenType) string {
 		return "TRY"
 	case token.CATCH:
 		return "CATCH"
+	case token.DEFER:
+		return "DEFER"
 	case token.THROW:
 		return "THROW"
 
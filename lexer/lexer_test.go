@@ -414,3 +414,40 @@ func tokenizeRaw(lexer *Lexer, input string) []Token {
 
 	return tokens
 }
+
+func TestPipeOperatorToken(t *testing.T) {
+	tokens := NewLexer().Tokenize(`<?php $a |> trim(...) || $b | $c;`)
+	var pipes, lors, bitOrs int
+	for _, tok := range tokens {
+		switch tok.Type() {
+		case token.PIPE:
+			pipes++
+		case token.LOR:
+			lors++
+		case token.BIT_OR:
+			bitOrs++
+		}
+	}
+	if pipes != 1 || lors != 1 || bitOrs != 1 {
+		t.Errorf("|> 分词错误: PIPE=%d LOR=%d BIT_OR=%d", pipes, lors, bitOrs)
+	}
+}
+
+func TestDeferContextualKeyword(t *testing.T) {
+	tokens := NewLexer().Tokenize(`<?php function defer() {} const defer = 1; $o->defer(); $o?->defer(); A::defer(); function &defer() {} defer $f;`)
+	var keywords, names int
+	for _, tok := range tokens {
+		if tok.Literal() != "defer" {
+			continue
+		}
+		switch tok.Type() {
+		case token.DEFER:
+			keywords++
+		case token.IDENTIFIER:
+			names++
+		}
+	}
+	if keywords != 1 || names != 6 {
+		t.Errorf("defer 分词错误: DEFER=%d IDENTIFIER=%d", keywords, names)
+	}
+}
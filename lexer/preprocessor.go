@@ -86,6 +86,8 @@ func cannotAddSemicolon(t Token) bool {
 		return true
 	case token.NULLSAFE_CALL, token.NULL_COALESCE: // ??-> 和 ?? 后不用补充
 		return true
+	case token.PIPE: // |> 后不用补充
+		return true
 	case token.INCR, token.DECR: // ++ 和 -- 后不用补充
 		return true
 	case token.SHL, token.SHR: // << 和 >> 后不用补充
@@ -134,6 +136,8 @@ func cannotAddSemicolonAfter(t Token) bool {
 		return true
 	case token.NULLSAFE_CALL, token.NULL_COALESCE: // ??-> 和 ?? 前不用补充
 		return true
+	case token.PIPE: // |> 前不用补充（管道可换行书写）
+		return true
 	case token.COLON: // : 前不用补充
 		return true
 	case token.COMMA: // , 前不用补充
@@ -201,6 +205,13 @@ func (p *Preprocessor) Process() []Token {
 			filtered = append(filtered, processHeredocInterpolation(t))
 		case token.NOWDOC:
 			filtered = append(filtered, t)
+		case token.DEFER:
+			// defer 只在语句中是关键字：作为函数名、常量名、方法名或类常量名时是普通标识符
+			if deferIsName(filtered) {
+				filtered = append(filtered, NewWorkerToken(token.IDENTIFIER, t.Literal(), t.Start(), t.End(), t.Line(), t.Pos()))
+			} else {
+				filtered = append(filtered, t)
+			}
 		case token.STRING:
 			filtered = append(filtered, processStringInterpolation(t))
 		case token.DOLLAR:
@@ -891,3 +902,17 @@ func isNumber(r rune) bool {
 func isSpecialSymbol(r rune) bool {
 	return r == '_' || unicode.IsPunct(r)
 }
+
+// deferIsName defer 前面的 token 表明它是名称：function defer、function &defer、const defer、->defer、?->defer、::defer
+func deferIsName(filtered []Token) bool {
+	if len(filtered) == 0 {
+		return false
+	}
+	switch filtered[len(filtered)-1].Type() {
+	case token.FUNC, token.CONST, token.OBJECT_OPERATOR, token.NULLSAFE_CALL, token.SCOPE_RESOLUTION:
+		return true
+	case token.BIT_AND:
+		return len(filtered) > 1 && filtered[len(filtered)-2].Type() == token.FUNC
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package lexer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/php-any/origami/token"
+)
+
+// token 编码中的类型标记
+const (
+	codecWorker byte = iota
+	codecLing
+)
+
+// EncodeTokens 把 token 列表编码为字节序列（供磁盘缓存使用）
+func EncodeTokens(tokens []Token) ([]byte, error) {
+	buf := make([]byte, 0, len(tokens)*16)
+	return appendTokens(buf, tokens)
+}
+
+func appendTokens(buf []byte, tokens []Token) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(len(tokens)))
+	for _, t := range tokens {
+		switch tk := t.(type) {
+		case *WorkerToken:
+			buf = append(buf, codecWorker)
+			buf = appendToken(buf, tk.type_, tk.literal, tk.start, tk.end, tk.line, tk.pos)
+		case *LingToken:
+			buf = append(buf, codecLing)
+			buf = appendToken(buf, tk.type_, tk.literal, tk.start, tk.end, tk.line, tk.pos)
+			var err error
+			if buf, err = appendTokens(buf, tk.children); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("无法编码的 token 类型 %T", t)
+		}
+	}
+	return buf, nil
+}
+
+func appendToken(buf []byte, typ token.TokenType, literal string, start, end, line, pos int) []byte {
+	buf = binary.AppendVarint(buf, int64(typ))
+	buf = binary.AppendUvarint(buf, uint64(len(literal)))
+	buf = append(buf, literal...)
+	buf = binary.AppendVarint(buf, int64(start))
+	buf = binary.AppendVarint(buf, int64(end))
+	buf = binary.AppendVarint(buf, int64(line))
+	return binary.AppendVarint(buf, int64(pos))
+}
+
+var errCorruptTokens = errors.New("token 编码数据已损坏")
+
+// DecodeTokens 解码 EncodeTokens 生成的字节序列
+func DecodeTokens(data []byte) ([]Token, error) {
+	d := &tokenDecoder{data: data}
+	tokens := d.tokens()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if d.off != len(d.data) {
+		return nil, errCorruptTokens
+	}
+	return tokens, nil
+}
+
+type tokenDecoder struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (d *tokenDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.off:])
+	if n <= 0 {
+		d.err = errCorruptTokens
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+func (d *tokenDecoder) int() int {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data[d.off:])
+	if n <= 0 {
+		d.err = errCorruptTokens
+		return 0
+	}
+	d.off += n
+	return int(v)
+}
+
+func (d *tokenDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.data)-d.off) {
+		d.err = errCorruptTokens
+		return ""
+	}
+	s := string(d.data[d.off : d.off+int(n)])
+	d.off += int(n)
+	return s
+}
+
+func (d *tokenDecoder) tokens() []Token {
+	n := d.uvarint()
+	// 每个 token 至少占 7 个字节，数量超出剩余长度说明数据已损坏
+	if d.err != nil || n > uint64(len(d.data)-d.off) {
+		d.err = errCorruptTokens
+		return nil
+	}
+	tokens := make([]Token, 0, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		if d.off >= len(d.data) {
+			d.err = errCorruptTokens
+			break
+		}
+		kind := d.data[d.off]
+		d.off++
+		typ := token.TokenType(d.int())
+		literal := d.string()
+		start, end, line, pos := d.int(), d.int(), d.int(), d.int()
+		switch kind {
+		case codecWorker:
+			tokens = append(tokens, NewWorkerToken(typ, literal, start, end, line, pos))
+		case codecLing:
+			tokens = append(tokens, NewLingToken(typ, literal, start, end, line, pos, d.tokens()))
+		default:
+			d.err = errCorruptTokens
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,53 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenCodecRoundTrip(t *testing.T) {
+	src := "<?php\n$name = \"hi {$user->name}!\";\necho <<<EOT\nline $name\nEOT;\n?>\n<p><?= $name ?></p>\n"
+	tokens := NewLexer().TokenizeTemplate(src)
+	hasLing := false
+	for _, tk := range tokens {
+		if _, ok := tk.(*LingToken); ok {
+			hasLing = true
+		}
+	}
+	if !hasLing {
+		t.Fatal("expected interpolated string to produce a LingToken")
+	}
+
+	raw, err := EncodeTokens(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTokens(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(normalizeTokens(tokens), normalizeTokens(decoded)) {
+		t.Fatalf("tokens changed after round trip:\n%v\n%v", tokens, decoded)
+	}
+
+	if _, err := DecodeTokens(raw[:len(raw)-1]); err == nil {
+		t.Fatal("expected truncated data rejected")
+	}
+}
+
+// normalizeTokens 把空的子 token 列表统一为 nil，便于比较
+func normalizeTokens(tokens []Token) []Token {
+	out := make([]Token, len(tokens))
+	for i, tk := range tokens {
+		if l, ok := tk.(*LingToken); ok {
+			c := *l
+			c.children = normalizeTokens(l.children)
+			if len(c.children) == 0 {
+				c.children = nil
+			}
+			tk = &c
+		}
+		out[i] = tk
+	}
+	return out
+}
@@ -118,6 +118,7 @@ func (a *Annotation) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			varies := method.GetVariables()
 			params := method.GetParams()
 			fnCtx := object.CreateContext(varies)
+			data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 			// 入参的值设置到上下文中
 			for index, arg := range a.Arguments {
 				switch argTV := arg.(type) {
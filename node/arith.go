@@ -0,0 +1,75 @@
+package node
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/php-any/origami/data"
+)
+
+// emitWarning 输出 PHP 8+ Warning，格式与 emitUndefinedArrayKeyWarning 相同
+func emitWarning(from data.From, msg string) {
+	file := "Unknown"
+	line := 0
+	if from != nil {
+		if src := from.GetSource(); src != "" {
+			file = src
+		}
+		if sl, _ := from.GetStartPosition(); sl >= 0 {
+			line = sl + 1
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nWarning: %s in %s on line %d\n", msg, file, line)
+}
+
+// arithOperatorName 错误信息中的运算符，'p' 表示 **，'l' 与 'r' 表示 << 与 >>
+func arithOperatorName(op byte) string {
+	switch op {
+	case 'p':
+		return "**"
+	case 'l':
+		return "<<"
+	case 'r':
+		return ">>"
+	}
+	return string(op)
+}
+
+// arithOperands 按 PHP 8 的规则将两个操作数转为数字：前导数字字符串（"12abc"）输出 Warning 后取前导数字，
+// 非数字字符串、数组、对象抛出 TypeError
+func arithOperands(from data.From, op byte, lv, rv data.GetValue) (data.Value, data.Value, data.Control) {
+	lval, rval := operandValue(lv), operandValue(rv)
+	l, lk := data.ToNumber(lval)
+	r, rk := data.ToNumber(rval)
+	if lk == data.NumberInvalid || rk == data.NumberInvalid {
+		return nil, nil, data.NewErrorThrowByName(from, fmt.Errorf("Unsupported operand types: %s %s %s",
+			data.TypeName(lval), arithOperatorName(op), data.TypeName(rval)), "TypeError")
+	}
+	if lk == data.NumberLeading {
+		emitWarning(from, "A non-numeric value encountered")
+	}
+	if rk == data.NumberLeading {
+		emitWarning(from, "A non-numeric value encountered")
+	}
+	return l, r, nil
+}
+
+// arithmetic 按 PHP 规则计算 + - * / % **（'p'）<<（'l'）>>（'r'）：整数溢出得到 float，除数为 0 抛出 DivisionByZeroError
+func arithmetic(from data.From, op byte, lv, rv data.GetValue) (data.GetValue, data.Control) {
+	l, r, ctl := arithOperands(from, op, lv, rv)
+	if ctl != nil {
+		return nil, ctl
+	}
+	v, err := data.Arith(op, l, r)
+	if err != nil {
+		return nil, data.NewErrorThrowByName(from, err, data.ArithErrorName(err))
+	}
+	return v, nil
+}
+
+func operandValue(v data.GetValue) data.Value {
+	if val, ok := v.(data.Value); ok {
+		return val
+	}
+	return data.NewNullValue()
+}
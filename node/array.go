@@ -77,11 +77,9 @@ func setArrayLiteralEntry(av *data.ArrayValue, key, val data.Value) {
 		return
 	}
 	keyStr := key.AsString()
-	for _, z := range av.List {
-		if z != nil && z.Name == keyStr {
-			z.Value = val
-			return
-		}
+	if z, _ := av.FindSlot(keyStr); z != nil {
+		z.Value = val
+		return
 	}
 	av.List = append(av.List, &data.ZVal{Name: keyStr, Value: val})
 }
@@ -78,27 +78,6 @@ func mergeObjectWithArray(obj hasRangeProperties, arr *data.ArrayValue) *data.Ar
 	return data.NewArrayValue(result).(*data.ArrayValue)
 }
 
-func addOperandIsFloat(v data.GetValue) bool {
-	val, ok := v.(data.Value)
-	if !ok {
-		return false
-	}
-	_, ok = val.(*data.FloatValue)
-	return ok
-}
-
-func addOperandAsFloat64(v data.GetValue) (float64, bool) {
-	val, ok := v.(data.Value)
-	if !ok {
-		return 0, false
-	}
-	if f, ok := val.(data.AsFloat); ok {
-		fl, err := f.AsFloat()
-		return fl, err == nil
-	}
-	return 0, false
-}
-
 func (b *BinaryAdd) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	lv, lCtl := b.Left.GetValue(ctx)
 	if lCtl != nil {
@@ -110,11 +89,21 @@ func (b *BinaryAdd) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	// PHP：任一侧为 float 时，+ 结果为 float
-	if addOperandIsFloat(lv) || addOperandIsFloat(rv) {
-		if lf, ok := addOperandAsFloat64(lv); ok {
-			if rf, ok := addOperandAsFloat64(rv); ok {
-				return data.NewFloatValue(lf + rf), nil
+	// 操作数是定义了 __add 的类实例时按运算符重载调用，优先于属性合并与 __toString 拼接
+	if ret, acl, ok := data.CallOperator(ctx, "__add", lv, rv); ok {
+		return ret, acl
+	}
+
+	switch l := lv.(type) {
+	case *data.IntValue, *data.FloatValue:
+		// 数字与任意值相加按 PHP 算术规则：数字字符串参与运算，整数溢出得到 float
+		return arithmetic(b.from, '+', lv, rv)
+	case *data.StringValue, *data.NullValue, *data.BoolValue:
+		// 两侧都是数字、数字字符串、null 或 bool 时相加，否则为字符串拼接
+		if ln, lk := data.ToNumber(l.(data.Value)); lk == data.NumberOK {
+			if rn, rk := data.ToNumber(operandValue(rv)); rk == data.NumberOK {
+				v, _ := data.Arith('+', ln, rn)
+				return v, nil
 			}
 		}
 	}
@@ -127,64 +116,6 @@ func (b *BinaryAdd) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			return nil, rCtl
 		}
 		return data.NewStringValue(lStr + rStr), nil
-	case *data.IntValue:
-		switch r := rv.(type) {
-		case data.AsInt:
-			li, err := l.AsInt()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			ri, err := r.AsInt()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-
-			return data.NewIntValue(li + ri), nil
-		case data.AsFloat:
-			li, err := l.AsInt()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			rf, err := r.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			return data.NewFloatValue(float64(li) + rf), nil
-		case data.AsString:
-			return data.NewStringValue(l.AsString() + r.AsString()), nil
-		}
-	case *data.FloatValue:
-		switch r := rv.(type) {
-		case data.AsInt:
-			lf, err := l.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			ri, err := r.AsInt()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-
-			return data.NewFloatValue(lf + float64(ri)), nil
-		case *data.StringValue:
-			lf, err := l.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			rf := r.AsString()
-			return data.NewStringValue(data.NewFloatValue(lf).AsString() + rf), nil
-		case data.AsFloat:
-			lf, err := l.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			rf, err := r.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			return data.NewFloatValue(lf + rf), nil
-		}
-
 	case *data.BoolValue:
 		// 布尔值与任何类型相加：转换为整数（true->1, false->0）后相加
 		var li int
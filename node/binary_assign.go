@@ -115,19 +115,26 @@ func (b *BinaryAssign) GetValue(ctx data.Context) (data.GetValue, data.Control)
 			}
 			return v, nil
 		case *CallObjectProperty:
-			temp, acl := l.Object.GetValue(ctx)
+			temp, acl := receiverValue(ctx, l.Object)
 			if acl != nil {
 				return nil, acl
 			}
 			switch object := temp.(type) {
-			case *data.ClassValue: // 需要检查属性类型
-				property, ok := object.GetPropertyStmt(l.Property)
+			case *data.ClassValue, *data.ThisValue: // 需要检查 readonly 与属性类型
+				cv := classValueOf(object)
+				property, ok := cv.GetPropertyStmt(l.Property)
 				if ok {
-					if property.GetType() != nil && !property.GetType().Is(v) {
-						return nil, data.NewErrorThrow(b.GetFrom(), fmt.Errorf("%s 属性 %s 因为类型不一致无法赋值", TryGetCallClassName(object), l.Property))
+					if hooked, acl := writePropertyHook(ctx, l.GetFrom(), cv, property, v); hooked {
+						return v, acl
 					}
+					v, acl = checkPropertyAssign(ctx, l.GetFrom(), cv, property, v)
+					if acl != nil {
+						return nil, acl
+					}
+				} else if acl = checkDynamicProperty(l.GetFrom(), cv, l.Property); acl != nil {
+					return nil, acl
 				}
-				return v, object.SetProperty(l.Property, v)
+				return v, cv.SetProperty(l.Property, v)
 			case data.SetProperty:
 				return v, object.SetProperty(l.Property, v)
 			default:
@@ -205,6 +212,7 @@ func (b *BinaryAssign) GetValue(ctx data.Context) (data.GetValue, data.Control)
 				if method, exists := cv.GetMethod("offsetGet"); exists {
 					for i, set := range l.V {
 						fnCtx := cv.CreateContext(method.GetVariables())
+						data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 						if len(method.GetVariables()) > 0 {
 							fnCtx.SetVariableValue(method.GetVariables()[0], data.NewIntValue(i))
 						}
@@ -245,6 +253,10 @@ type BinaryAssignVariable struct {
 	*Node `pp:"-"`
 	Left  data.Variable
 	Right data.GetValue
+	// Owns 语句级的 `$x = new Foo(...);`，由解析器设置：新对象归变量所有，见 data.OwnVariable
+	Owns bool
+	// OwnsFresh 语句级的 `$x = f(...);`，由解析器设置：f 是生成器函数时新建的生成器归变量所有，见 data.OwnFresh
+	OwnsFresh bool
 }
 
 func (b *BinaryAssignVariable) GetValue(ctx data.Context) (data.GetValue, data.Control) {
@@ -258,9 +270,25 @@ func (b *BinaryAssignVariable) GetValue(ctx data.Context) (data.GetValue, data.C
 		// 以便 for 循环 VarStmtIncr 可安全原地自增。
 		// 仅适用于 *VariableExpression；对象属性等 Variable 无有效索引。
 		if ve, ok := b.Left.(*VariableExpression); ok {
-			if zv := ctx.GetIndexZVal(ve.Index); zv != nil && data.IsScalarAssignFast(v) {
-				data.AssignScalarToZVal(zv, v)
-				return zv.Value, nil
+			if data.IsScalarAssignFast(v) {
+				if zv := ctx.GetIndexZVal(ve.Index); zv != nil && zv.Guard == nil && !data.NeedsRelease(zv) {
+					data.AssignScalarToZVal(zv, v)
+					return zv.Value, nil
+				}
+			}
+			if b.Owns {
+				if acl := b.Left.SetValue(ctx, v); acl != nil {
+					return nil, acl
+				}
+				data.OwnVariable(ctx, ve.Index)
+				return v, nil
+			}
+			if b.OwnsFresh {
+				if acl := b.Left.SetValue(ctx, v); acl != nil {
+					return nil, acl
+				}
+				data.OwnFresh(ctx, ve.Index, v)
+				return v, nil
 			}
 		}
 		return v, b.Left.SetValue(ctx, v)
@@ -305,6 +333,7 @@ func (b *BinaryAssignVariableList) GetValue(ctx data.Context) (data.GetValue, da
 			if method, exists := cv.GetMethod("offsetGet"); exists {
 				for i, lv := range b.Left.Vars {
 					fnCtx := cv.CreateContext(method.GetVariables())
+					data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 					if len(method.GetVariables()) > 0 {
 						fnCtx.SetVariableValue(method.GetVariables()[0], data.NewIntValue(i))
 					}
@@ -80,6 +80,11 @@ func (b *BinaryDot) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	lv = unwrapValue(lv)
 	rv = unwrapValue(rv)
 
+	// 操作数是定义了 __concat 的类实例时按运算符重载调用，优先于 __toString
+	if ret, acl, ok := data.CallOperator(ctx, "__concat", lv, rv); ok {
+		return ret, acl
+	}
+
 	// 将两个操作数都转换为字符串
 	leftStr := ""
 	rightStr := ""
@@ -29,6 +29,19 @@ func (b *BinaryEq) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
+	// 标量按 PHP 8 的规则比较：数字字符串（允许前后空白）按数值比较
+	if c, ok := data.LooseCompare(operandValue(lv), operandValue(rv)); ok {
+		return data.NewBoolValue(c == 0), nil
+	}
+
+	// 操作数是定义了 __equals 或 __compare 的类实例时按运算符重载比较
+	if eq, acl, ok := data.EqualsOperator(ctx, lv, rv); ok {
+		if acl != nil {
+			return nil, acl
+		}
+		return data.NewBoolValue(eq), nil
+	}
+
 	// 简单的相等性比较
 	if lv == rv {
 		return data.NewBoolValue(true), nil
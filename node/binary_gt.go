@@ -29,6 +29,19 @@ func (b *BinaryGt) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
+	// 标量按 PHP 8 的规则比较：数字字符串（允许前后空白）按数值比较
+	if c, ok := data.LooseCompare(operandValue(lv), operandValue(rv)); ok {
+		return data.NewBoolValue(c == 1), nil
+	}
+
+	// 操作数是定义了 __compare 的类实例时按其结果比较
+	if c, acl, ok := data.CompareOperator(ctx, lv, rv); ok {
+		if acl != nil {
+			return nil, acl
+		}
+		return data.NewBoolValue(c == 1), nil
+	}
+
 	switch l := lv.(type) {
 	case *data.IntValue:
 		if ri, ok := rv.(data.AsInt); ok {
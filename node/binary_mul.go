@@ -1,10 +1,6 @@
 package node
 
-import (
-	"errors"
-
-	"github.com/php-any/origami/data"
-)
+import "github.com/php-any/origami/data"
 
 type BinaryMul struct {
 	*Node `pp:"-"`
@@ -31,30 +27,10 @@ func (b *BinaryMul) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	switch lvv := lv.(type) {
-	case *data.IntValue:
-		li, err := lvv.AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		ri, err := rv.(data.AsInt).AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		return data.NewIntValue(li * ri), nil
-	case *data.FloatValue:
-		lf, err := lvv.AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		rf, err := rv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		return data.NewFloatValue(lf * rf), nil
+	// 操作数是定义了 __mul 的类实例时按运算符重载调用
+	if ret, acl, ok := data.CallOperator(ctx, "__mul", lv, rv); ok {
+		return ret, acl
 	}
 
-	return nil, data.NewErrorThrow(b.from, errors.New("TODO 有未支持的类型乘法"))
+	return arithmetic(b.from, '*', lv, rv)
 }
@@ -1,10 +1,6 @@
 package node
 
-import (
-	"math"
-
-	"github.com/php-any/origami/data"
-)
+import "github.com/php-any/origami/data"
 
 type BinaryPow struct {
 	*Node `pp:"-"`
@@ -31,25 +27,10 @@ func (b *BinaryPow) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	lf, err := lv.(data.AsFloat).AsFloat()
-	if err != nil {
-		return nil, data.NewErrorThrow(b.from, err)
-	}
-	rf, err := rv.(data.AsFloat).AsFloat()
-	if err != nil {
-		return nil, data.NewErrorThrow(b.from, err)
-	}
-
-	result := math.Pow(lf, rf)
-
-	// 如果两个操作数都是整数且结果是精确整数，返回整数
-	if _, lok := lv.(*data.IntValue); lok {
-		if _, rok := rv.(*data.IntValue); rok {
-			if result == math.Trunc(result) && result <= math.MaxInt && result >= math.MinInt {
-				return data.NewIntValue(int(result)), nil
-			}
-		}
+	// 操作数是定义了 __pow 的类实例时按运算符重载调用
+	if ret, acl, ok := data.CallOperator(ctx, "__pow", lv, rv); ok {
+		return ret, acl
 	}
 
-	return data.NewFloatValue(result), nil
+	return arithmetic(b.from, 'p', lv, rv)
 }
@@ -1,10 +1,6 @@
 package node
 
-import (
-	"errors"
-
-	"github.com/php-any/origami/data"
-)
+import "github.com/php-any/origami/data"
 
 type BinaryQuo struct {
 	*Node `pp:"-"`
@@ -31,50 +27,10 @@ func (b *BinaryQuo) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	switch lv.(type) {
-	case *data.IntValue:
-		li, err := lv.(data.AsInt).AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		if ai, ok := rv.(data.AsFloat); ok {
-			ri, err := ai.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			if ri == 0 {
-				return nil, data.NewErrorThrow(b.from, errors.New("除零错误"))
-			}
-			return data.NewFloatValue(float64(li) / ri), nil
-		}
-
-		if ai, ok := rv.(data.AsInt); ok {
-			ri, err := ai.AsInt()
-			if err != nil {
-				return nil, data.NewErrorThrow(b.from, err)
-			}
-			if ri == 0 {
-				return nil, data.NewErrorThrow(b.from, errors.New("除零错误"))
-			}
-			return data.NewFloatValue(float64(li) / float64(ri)), nil
-		}
-	case *data.FloatValue:
-		lf, err := lv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		rf, err := rv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		if rf == 0 {
-			return nil, data.NewErrorThrow(b.from, errors.New("除零错误"))
-		}
-
-		return data.NewFloatValue(lf / rf), nil
+	// 操作数是定义了 __div 的类实例时按运算符重载调用
+	if ret, acl, ok := data.CallOperator(ctx, "__div", lv, rv); ok {
+		return ret, acl
 	}
 
-	return nil, data.NewErrorThrow(b.from, errors.New("TODO 有未支持的类型除法"))
+	return arithmetic(b.from, '/', lv, rv)
 }
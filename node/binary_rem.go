@@ -1,9 +1,6 @@
 package node
 
-import (
-	"errors"
-	"github.com/php-any/origami/data"
-)
+import "github.com/php-any/origami/data"
 
 type BinaryRem struct {
 	*Node `pp:"-"`
@@ -30,38 +27,10 @@ func (b *BinaryRem) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	switch lv.(type) {
-	case *data.IntValue:
-		li, err := lv.(data.AsInt).AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		ri, err := rv.(data.AsInt).AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		if ri == 0 {
-			return nil, data.NewErrorThrow(b.from, errors.New("除零错误"))
-		}
-
-		return data.NewIntValue(li % ri), nil
-	case *data.FloatValue:
-		lf, err := lv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		rf, err := rv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		if rf == 0 {
-			return nil, data.NewErrorThrow(b.from, errors.New("除零错误"))
-		}
-
-		return data.NewFloatValue(float64(int64(lf) % int64(rf))), nil
+	// 操作数是定义了 __mod 的类实例时按运算符重载调用
+	if ret, acl, ok := data.CallOperator(ctx, "__mod", lv, rv); ok {
+		return ret, acl
 	}
 
-	return nil, data.NewErrorThrow(b.from, errors.New("TODO 有未支持的类型取模"))
+	return arithmetic(b.from, '%', lv, rv)
 }
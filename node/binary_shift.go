@@ -30,17 +30,8 @@ func (b *BinaryShl) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	// 左移运算：将左操作数转换为整数，右操作数也是整数
-	li, err := lv.(data.AsInt).AsInt()
-	if err != nil {
-		return nil, data.NewErrorThrow(b.from, err)
-	}
-	ri, err := rv.(data.AsInt).AsInt()
-	if err != nil {
-		return nil, data.NewErrorThrow(b.from, err)
-	}
-
-	return data.NewIntValue(li << ri), nil
+	// 左移运算：操作数按 PHP 规则转为整数，移位数为负时抛出 ArithmeticError
+	return arithmetic(b.from, 'l', lv, rv)
 }
 
 // BinaryShr 表示右移运算符 >>
@@ -69,15 +60,6 @@ func (b *BinaryShr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	// 右移运算：将左操作数转换为整数，右操作数也是整数
-	li, err := lv.(data.AsInt).AsInt()
-	if err != nil {
-		return nil, data.NewErrorThrow(b.from, err)
-	}
-	ri, err := rv.(data.AsInt).AsInt()
-	if err != nil {
-		return nil, data.NewErrorThrow(b.from, err)
-	}
-
-	return data.NewIntValue(li >> ri), nil
+	// 右移运算：操作数按 PHP 规则转为整数，移位数为负时抛出 ArithmeticError
+	return arithmetic(b.from, 'r', lv, rv)
 }
@@ -33,18 +33,20 @@ func (b *BinarySpaceship) GetValue(ctx data.Context) (data.GetValue, data.Contro
 		return nil, c
 	}
 
-	// 调试：直接检查值的类型
-	if leftInt, ok := leftVal.(*data.IntValue); ok {
-		if rightInt, ok := rightVal.(*data.IntValue); ok {
-			n1 := leftInt.Value
-			n2 := rightInt.Value
-			if n1 < n2 {
-				return data.NewIntValue(-1), nil
-			} else if n1 > n2 {
-				return data.NewIntValue(1), nil
-			}
-			return data.NewIntValue(0), nil
+	// 标量按 PHP 8 的规则比较：数字字符串（允许前后空白）按数值比较
+	if c, ok := data.LooseCompare(operandValue(leftVal), operandValue(rightVal)); ok {
+		if c == data.CompareUnordered {
+			c = 1
 		}
+		return data.NewIntValue(c), nil
+	}
+
+	// 操作数是定义了 __compare 的类实例时返回其结果
+	if c, acl, ok := data.CompareOperator(ctx, leftVal, rightVal); ok {
+		if acl != nil {
+			return nil, acl
+		}
+		return data.NewIntValue(c), nil
 	}
 
 	// 尝试转换为可比较的值
@@ -1,9 +1,6 @@
 package node
 
-import (
-	"errors"
-	"github.com/php-any/origami/data"
-)
+import "github.com/php-any/origami/data"
 
 type BinarySub struct {
 	*Node `pp:"-"`
@@ -30,52 +27,10 @@ func (b *BinarySub) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, rCtl
 	}
 
-	switch lv.(type) {
-	case *data.StringValue:
-		li, err := lv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		ri, err := rv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		return data.NewFloatValue(li - ri), nil
-	case *data.IntValue:
-		li, err := lv.(data.AsInt).AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		ri, err := rv.(data.AsInt).AsInt()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		return data.NewIntValue(li - ri), nil
-	case *data.FloatValue:
-		li, err := lv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		ri, err := rv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		return data.NewFloatValue(li - ri), nil
-	case *data.NullValue:
-		li, err := lv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-		ri, err := rv.(data.AsFloat).AsFloat()
-		if err != nil {
-			return nil, data.NewErrorThrow(b.from, err)
-		}
-
-		return data.NewFloatValue(li - ri), nil
+	// 操作数是定义了 __sub 的类实例时按运算符重载调用
+	if ret, acl, ok := data.CallOperator(ctx, "__sub", lv, rv); ok {
+		return ret, acl
 	}
 
-	return nil, data.NewErrorThrow(b.from, errors.New("TODO 有未支持的类型减法"))
+	return arithmetic(b.from, '-', lv, rv)
 }
@@ -22,6 +22,9 @@ func (bs *BlockStatement) GetValue(ctx data.Context) (data.GetValue, data.Contro
 	var ctl data.Control
 	for _, stmt := range bs.Statements {
 		v, ctl = stmt.GetValue(ctx)
+		if ctl == nil {
+			ctl = data.Safepoint(ctx)
+		}
 		if ctl != nil {
 			return nil, ctl
 		}
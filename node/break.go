@@ -33,3 +33,11 @@ func NewBreakStatement(token *TokenFrom) *BreakStatement {
 func NewBreakStatementWithLevel(token *TokenFrom, level int) *BreakStatement {
 	return &BreakStatement{Node: NewNode(token), Level: level}
 }
+
+// outerBreak break N（N>1）跳出当前循环或 switch 后，返回交给外层的 break N-1；其余情况返回 nil
+func outerBreak(c data.Control) data.Control {
+	if bs, ok := c.(*BreakStatement); ok && bs.Level > 1 {
+		return &BreakStatement{Node: bs.Node, Level: bs.Level - 1}
+	}
+	return nil
+}
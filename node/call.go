@@ -3,6 +3,7 @@ package node
 import (
 	"errors"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/php-any/origami/data"
 )
@@ -124,9 +125,9 @@ func (pe *CallExpression) GetValue(ctx data.Context) (data.GetValue, data.Contro
 				if err != nil {
 					return nil, data.NewErrorThrow(pe.from, err)
 				}
-				acl = paramSetValue(fnCtx, ctx, nil, param, argTV, varies, index, arguments)
+				acl = paramSetValue(pe.FunName, fnCtx, ctx, nil, param, argTV, varies, index, arguments)
 			default:
-				acl = paramSetValue(fnCtx, ctx, nil, param, argTV, varies, index, arguments)
+				acl = paramSetValue(pe.FunName, fnCtx, ctx, nil, param, argTV, varies, index, arguments)
 			}
 		} else {
 			_, acl = param.GetValue(fnCtx)
@@ -161,11 +162,18 @@ func NewCallTodo(call *CallExpression, namespace string) *CallLater {
 type CallLater struct {
 	*CallExpression
 	namespace string
+	// resolved 第一次执行时确认的调用；AST 节点可能被多个协程同时执行，确认后整体替换而不修改 CallExpression
+	resolved atomic.Pointer[CallExpression] `pp:"-"`
 }
 
 func (pe *CallLater) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	if pe.Fun == nil {
-		fn, ok := ctx.GetVM().GetFunc(pe.FunName)
+	if call := pe.resolved.Load(); call != nil {
+		return call.GetValue(ctx)
+	}
+	fn := pe.Fun
+	if fn == nil {
+		var ok bool
+		fn, ok = ctx.GetVM().GetFunc(pe.FunName)
 		if !ok {
 			fn, ok = ctx.GetVM().GetFunc(pe.namespace + "\\" + pe.FunName)
 			if !ok {
@@ -180,9 +188,11 @@ func (pe *CallLater) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 				}
 			}
 		}
-
-		pe.FunName = fn.GetName()
-		pe.Fun = fn
 	}
-	return pe.CallExpression.GetValue(ctx)
+
+	call := *pe.CallExpression
+	call.FunName = fn.GetName()
+	call.Fun = fn
+	pe.resolved.Store(&call)
+	return call.GetValue(ctx)
 }
@@ -99,6 +99,7 @@ func (pe *CallMethod) handleStaticMethodWithLateBinding(ctx data.Context, sm *st
 	// 创建类方法上下文，绑定调用时的类（用于 static:: 后期静态绑定）
 	classValue := data.NewClassValue(sm.callClass, ctx)
 	fnCtx := classValue.CreateContext(varies)
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	// 设置后期静态绑定类
 	if cmc, ok := fnCtx.(*data.ClassMethodContext); ok {
 		cmc.StaticClass = sm.callClass
@@ -158,7 +159,7 @@ func (pe *CallMethod) handleFuncValue(ctx data.Context, call data.GetValue) (dat
 					if acl != nil {
 						return nil, acl
 					}
-					acl = argObj.SetValue(fnCtx, tempV.(data.Value))
+					acl = argObj.BindArgument(fnCtx, tempV.(data.Value), funcDisplayName(fn), index+1, pe.from)
 					if acl != nil {
 						return nil, acl
 					}
@@ -316,6 +317,14 @@ func (pe *CallMethod) handleFuncValue(ctx data.Context, call data.GetValue) (dat
 	return fn.Call(fnCtx)
 }
 
+// funcDisplayName 类型错误消息中的函数名，静态方法带上类名
+func funcDisplayName(fn data.FuncStmt) string {
+	if sm, ok := fn.(*staticMethodFunc); ok && sm.class != nil {
+		return sm.class.GetName() + "::" + sm.method.GetName()
+	}
+	return fn.GetName()
+}
+
 // doCallWithArgs PHP 数组可调用 [$obj, 'method'](...$args) 的支持
 func (pe *CallMethod) doCallWithArgs(ctx data.Context, object data.GetMethod, method data.Method) (data.GetValue, data.Control) {
 	varies := method.GetVariables()
@@ -324,6 +333,7 @@ func (pe *CallMethod) doCallWithArgs(ctx data.Context, object data.GetMethod, me
 		fnCtx = objCtx.CreateContext(varies)
 	} else if cv, ok := object.(*data.ClassValue); ok {
 		fnCtx = cv.CreateContext(varies)
+		data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	} else {
 		fnCtx = ctx.CreateContext(varies)
 	}
@@ -372,6 +382,7 @@ func (pe *CallMethod) doCallWithArgs(ctx data.Context, object data.GetMethod, me
 func (pe *CallMethod) invokeMagicInvoke(ctx data.Context, object data.Context, invoke data.Method) (data.GetValue, data.Control) {
 	varies := invoke.GetVariables()
 	fnCtx := object.CreateContext(varies)
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	for i, arg := range pe.Args {
 		if i >= len(varies) {
 			break
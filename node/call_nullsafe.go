@@ -24,7 +24,7 @@ func NewNullsafeCall(from *TokenFrom, object data.GetValue, callExpr data.GetVal
 // GetValue 获取空安全调用表达式的值
 func (n *NullsafeCall) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	// 先获取对象的值
-	objValue, ctl := n.Object.GetValue(ctx)
+	objValue, ctl := receiverValue(ctx, n.Object)
 	if ctl != nil {
 		return nil, ctl
 	}
@@ -27,7 +27,7 @@ func NewCallObjectDynamicMethod(from *TokenFrom, object data.GetValue, methodExp
 
 // GetValue 运行时先求值方法名，再委托给 CallObjectMethod 执行调用
 func (pe *CallObjectDynamicMethod) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	o, ctl := pe.Object.GetValue(ctx)
+	o, ctl := receiverValue(ctx, pe.Object)
 	if ctl != nil {
 		return nil, ctl
 	}
@@ -70,11 +70,11 @@ func (pe *CallObjectDynamicMethod) GetValue(ctx data.Context) (data.GetValue, da
 		method, has := class.GetMethod(methodName)
 		if has {
 			if method.GetModifier() == data.ModifierPrivate {
-				if !isCallerInClassHierarchy(ctx, class.Class) {
+				if !data.CallerInClassHierarchy(ctx, class.Class) {
 					return nil, data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("不能调用 private 方法: %s", methodName))
 				}
 			} else if method.GetModifier() == data.ModifierProtected {
-				if !isCallerInClassHierarchy(ctx, class.Class) {
+				if !data.CallerInClassHierarchy(ctx, class.Class) {
 					return nil, data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("对象方法 %s 非公开", methodName))
 				}
 			}
@@ -25,7 +25,7 @@ func NewCallObjectDynamicProperty(from data.From, object data.GetValue, nameExpr
 
 func (pe *CallObjectDynamicProperty) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	// 求值对象表达式
-	o, ctl := pe.Object.GetValue(ctx)
+	o, ctl := receiverValue(ctx, pe.Object)
 	if ctl != nil {
 		return nil, ctl
 	}
@@ -41,6 +41,9 @@ func (pe *CallObjectDynamicProperty) GetValue(ctx data.Context) (data.GetValue,
 	case *data.ThisValue:
 		// 优先查找声明的属性（包括父类）
 		if prop, ok := v.GetPropertyStmt(name); ok {
+			if ret, acl, hooked := readPropertyHook(pe.GetFrom(), v.ClassValue, prop); hooked {
+				return ret, acl
+			}
 			// 从 ObjectValue 动态属性存储中获取值
 			if val, ctl := v.ObjectValue.GetProperty(name); ctl == nil {
 				if _, isNull := val.(*data.NullValue); !isNull {
@@ -70,6 +73,9 @@ func (pe *CallObjectDynamicProperty) GetValue(ctx data.Context) (data.GetValue,
 	case *data.ClassValue:
 		// 优先查找声明的属性（包括父类）
 		if prop, ok := v.GetPropertyStmt(name); ok {
+			if ret, acl, hooked := readPropertyHook(pe.GetFrom(), v, prop); hooked {
+				return ret, acl
+			}
 			if val, ctl := v.ObjectValue.GetProperty(name); ctl == nil {
 				if _, isNull := val.(*data.NullValue); !isNull {
 					return val, nil
@@ -112,7 +118,7 @@ func (pe *CallObjectDynamicProperty) GetValue(ctx data.Context) (data.GetValue,
 }
 
 func (pe *CallObjectDynamicProperty) SetValue(ctx data.Context, value data.Value) data.Control {
-	temp, acl := pe.Object.GetValue(ctx)
+	temp, acl := receiverValue(ctx, pe.Object)
 	if acl != nil {
 		return acl
 	}
@@ -126,35 +132,49 @@ func (pe *CallObjectDynamicProperty) SetValue(ctx data.Context, value data.Value
 	case *data.ThisValue:
 		property, ok := object.GetPropertyStmt(name)
 		if ok {
-			if property.GetType() != nil && !property.GetType().Is(value) {
-				return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("%s 属性 %s 因为类型不一致无法赋值", TryGetCallClassName(object), name))
+			if hooked, acl := writePropertyHook(ctx, pe.GetFrom(), object.ClassValue, property, value); hooked {
+				return acl
+			}
+			value, acl = checkPropertyAssign(ctx, pe.GetFrom(), object.ClassValue, property, value)
+			if acl != nil {
+				return acl
 			}
 			return object.SetProperty(name, value)
 		}
 		if magic, hasSet := object.GetMethod("__set"); hasSet {
 			return pe.invokeMagicSet(object, magic, name, value)
 		}
+		if acl := checkDynamicProperty(pe.GetFrom(), object.ClassValue, name); acl != nil {
+			return acl
+		}
 		return object.SetProperty(name, value)
 	case *data.ClassValue:
 		property, ok := object.GetPropertyStmt(name)
 		if ok {
-			if property.GetType() != nil && !property.GetType().Is(value) {
-				return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("%s 属性 %s 因为类型不一致无法赋值", TryGetCallClassName(object), name))
-			}
 			if property.GetModifier() == data.ModifierPrivate {
-				if !isCallerInClassHierarchy(ctx, object.Class) {
+				if !data.CallerInClassHierarchy(ctx, object.Class) {
 					return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("对象(%s)属性(%s)是私有的", object.Class.GetName(), name))
 				}
 			} else if property.GetModifier() == data.ModifierProtected {
-				if !isCallerInClassHierarchy(ctx, object.Class) {
+				if !data.CallerInClassHierarchy(ctx, object.Class) {
 					return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("对象(%s)属性(%s)不是公开的", object.Class.GetName(), name))
 				}
 			}
+			if hooked, acl := writePropertyHook(ctx, pe.GetFrom(), object, property, value); hooked {
+				return acl
+			}
+			value, acl = checkPropertyAssign(ctx, pe.GetFrom(), object, property, value)
+			if acl != nil {
+				return acl
+			}
 			return object.SetProperty(name, value)
 		}
 		if magic, hasSet := object.GetMethod("__set"); hasSet {
 			return pe.invokeMagicSet(object, magic, name, value)
 		}
+		if acl := checkDynamicProperty(pe.GetFrom(), object, name); acl != nil {
+			return acl
+		}
 		return object.SetProperty(name, value)
 	case data.SetProperty:
 		return object.SetProperty(name, value)
@@ -3,6 +3,7 @@ package node
 import (
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/php-any/origami/data"
 )
@@ -13,6 +14,14 @@ type CallObjectMethod struct {
 	Object data.GetValue // 对象表达式
 	Method string        // 函数名
 	Args   []data.GetValue
+
+	methods inlineCache[objectMethodEntry] `pp:"-"` // 按对象的类缓存查找到的方法
+}
+
+// objectMethodEntry 方法查找结果，declaring 为方法继承自父类时声明它的类
+type objectMethodEntry struct {
+	method    data.Method
+	declaring data.ClassStmt
 }
 
 // NewObjectMethod 创建一个新的对象属性访问表达式
@@ -27,16 +36,17 @@ func NewObjectMethod(from *TokenFrom, object data.GetValue, method string, args
 
 // GetValue 获取对象属性访问表达式的值
 func (pe *CallObjectMethod) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	o, ctl := pe.Object.GetValue(ctx)
+	o, ctl := receiverValue(ctx, pe.Object)
 	if ctl != nil {
 		return nil, ctl
 	}
 
 	switch class := o.(type) {
 	case *data.ThisValue:
-		method, has := class.GetMethod(pe.Method)
+		entry, has := pe.lookupMethod(class.ClassValue)
 		if has {
-			fnCtx, acl := pe.callMethodParams(class, ctx, method)
+			method := entry.method
+			fnCtx, acl := pe.callResolvedMethod(class, ctx, entry)
 			if acl != nil {
 				if _, ok := acl.(ToClosure); ok {
 					return data.NewFuncValue(method), nil
@@ -54,19 +64,20 @@ func (pe *CallObjectMethod) GetValue(ctx data.Context) (data.GetValue, data.Cont
 		}
 		return nil, data.NewErrorThrow(pe.GetFrom(), errors.New("this 对象不存在对应函数: "+pe.Method))
 	case *data.ClassValue:
-		method, has := class.GetMethod(pe.Method)
+		entry, has := pe.lookupMethod(class)
 		if has {
+			method := entry.method
 			if method.GetModifier() == data.ModifierPrivate {
-				if !isCallerInClassHierarchy(ctx, class.Class) {
+				if !data.CallerInClassHierarchy(ctx, class.Class) {
 					return nil, data.NewErrorThrow(pe.GetFrom(), errors.New("不能调用 private 方法: "+pe.Method))
 				}
 			} else if method.GetModifier() == data.ModifierProtected {
-				if !isCallerInClassHierarchy(ctx, class.Class) {
+				if !data.CallerInClassHierarchy(ctx, class.Class) {
 					return nil, data.NewErrorThrow(pe.GetFrom(), errors.New("对象属性访问表达式对象属性访问函数非公开"))
 				}
 			}
 
-			fnCtx, acl := pe.callMethodParams(class, ctx, method)
+			fnCtx, acl := pe.callResolvedMethod(class, ctx, entry)
 			if acl != nil {
 				if _, ok := acl.(ToClosure); ok {
 					return data.NewFuncValue(method), nil
@@ -170,14 +181,51 @@ func (pe *CallObjectMethod) invokeMagicCall(object data.Context, ctx data.Contex
 		return nil, data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("__call 需要至少 2 个参数 (name, arguments)"))
 	}
 	fnCtx := object.CreateContext(varies)
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	fnCtx.SetVariableValue(varies[0], data.NewStringValue(methodName))
 	fnCtx.SetVariableValue(varies[1], data.NewArrayValue(argsList))
 	return magic.Call(fnCtx)
 }
 
+// lookupMethod 按对象的类查找方法，结果缓存在调用点上
+func (pe *CallObjectMethod) lookupMethod(object *data.ClassValue) (objectMethodEntry, bool) {
+	if entry, ok := pe.methods.lookup(object.Class); ok {
+		return entry, true
+	}
+	method, has := object.GetMethod(pe.Method)
+	if !has {
+		return objectMethodEntry{}, false
+	}
+	entry := objectMethodEntry{method: method, declaring: inheritedMethodClass(object, method)}
+	pe.methods.store(object.Class, entry)
+	return entry, true
+}
+
+// callResolvedMethod 同 callMethodParams，声明方法的类取自查找结果
+func (pe *CallObjectMethod) callResolvedMethod(object, ctx data.Context, entry objectMethodEntry) (data.Context, data.Control) {
+	fnCtx := object.CreateContext(entry.method.GetVariables())
+	if cmc, ok := fnCtx.(*data.ClassMethodContext); ok && entry.declaring != nil {
+		cmc.SelfClass = entry.declaring
+	}
+	return pe.bindMethodParams(object, ctx, entry.method, fnCtx)
+}
+
 func (pe *CallObjectMethod) callMethodParams(object, ctx data.Context, method data.Method) (data.Context, data.Control) {
+	fnCtx := object.CreateContext(method.GetVariables())
+	// 继承来的方法在声明它的类的作用域中执行（parent::、private(set)、readonly 按声明类判断）
+	if cmc, ok := fnCtx.(*data.ClassMethodContext); ok {
+		if declaring := inheritedMethodClass(cmc.ClassValue, method); declaring != nil {
+			cmc.SelfClass = declaring
+		}
+	}
+	return pe.bindMethodParams(object, ctx, method, fnCtx)
+}
+
+// bindMethodParams 计算实参并绑定到方法的参数
+func (pe *CallObjectMethod) bindMethodParams(object, ctx data.Context, method data.Method, fnCtx data.Context) (data.Context, data.Control) {
+	// 方法的上下文由对象创建时的上下文派生，执行流改为调用方的
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	varies := method.GetVariables()
-	fnCtx := object.CreateContext(varies)
 	params := method.GetParams()
 
 	// 先展开所有参数中的 ...$arr (SpreadArgument)，构建展平后的实参列表
@@ -212,24 +260,31 @@ func (pe *CallObjectMethod) callMethodParams(object, ctx data.Context, method da
 	}
 
 	// 将展平的实参绑定到方法参数
+	fnName := methodDisplayName(object, method)
 	for index, param := range params {
 		if index < len(flatArgs) {
 			var acl data.Control
 			switch p := param.(type) {
 			case *Parameter:
-				fnCtx.SetVariableValue(varies[index], flatArgs[index])
+				var value data.Value
+				if value, acl = p.CoerceArgument(flatArgs[index], fnName, index+1, pe.from); acl == nil {
+					fnCtx.SetVariableValue(varies[index], value)
+				}
 			case *ParameterReference:
 				// 引用参数：直接设置值
 				fnCtx.SetVariableValue(varies[index], flatArgs[index])
-			case *Parameters:
+			case *Parameters, *data.ParametersTODO:
 				// 可变参数：收集剩余的所有实参
 				remaining := flatArgs[index:]
 				arr := data.NewArrayValue(remaining)
 				fnCtx.SetVariableValue(varies[index], arr)
 				index = len(params) // 跳过后续参数
 			case *PromotedParameter:
-				fnCtx.SetVariableValue(varies[index], flatArgs[index])
-				acl = p.SetValue(object, flatArgs[index])
+				var value data.Value
+				if value, acl = p.CoerceArgument(flatArgs[index], fnName, index+1, pe.from); acl == nil {
+					fnCtx.SetVariableValue(varies[index], value)
+					acl = p.SetValue(object, value)
+				}
 			default:
 				fnCtx.SetVariableValue(varies[index], flatArgs[index])
 			}
@@ -238,7 +293,7 @@ func (pe *CallObjectMethod) callMethodParams(object, ctx data.Context, method da
 			}
 		} else {
 			// 实参不足
-			if pVar, ok := param.(*Parameters); ok {
+			if pVar, ok := param.(data.Parameters); ok {
 				// Variadic 带 0 实参 → 空数组（PHP 语义）
 				arr := data.NewArrayValue([]data.Value{})
 				fnCtx.SetVariableValue(pVar, arr)
@@ -262,6 +317,14 @@ func (pe *CallObjectMethod) callMethodParams(object, ctx data.Context, method da
 	return fnCtx, nil
 }
 
+// methodDisplayName 类型错误消息中的方法名：Class::method
+func methodDisplayName(object data.Context, method data.Method) string {
+	if n, ok := object.(data.GetName); ok {
+		return n.GetName() + "::" + method.GetName()
+	}
+	return method.GetName()
+}
+
 func findVariable(varies []data.Variable, name string) (data.Variable, error) {
 	for _, vary := range varies {
 		check := vary.GetName()
@@ -283,61 +346,31 @@ func findParams(varies []data.GetValue, name string) (data.GetValue, error) {
 	return nil, errors.New("无法找到变量: " + name)
 }
 
-// isCallerInClassHierarchy 检查调用者是否在目标类的类层次结构中
-// 用于确定是否允许调用 protected 方法
-func isCallerInClassHierarchy(ctx data.Context, targetClass data.ClassStmt) bool {
-	// 检查是否通过 Closure::bind() 绑定了作用域（允许访问私有成员）
-	if bc, ok := ctx.(*data.BoundContext); ok {
-		if bc.ScopeClass == targetClass.GetName() {
-			return true
-		}
-	}
-
-	// 从上下文链中查找 ClassMethodContext 或 ClassValue
-	var callerClass data.ClassStmt
-	if cmc, ok := ctx.(*data.ClassMethodContext); ok {
-		callerClass = cmc.Class
-	} else if cv, ok := ctx.(*data.ClassValue); ok {
-		callerClass = cv.Class
-	} else {
-		return false
-	}
-
-	// 检查调用者类是否与目标类相同
-	if callerClass.GetName() == targetClass.GetName() {
-		return true
+// inheritedMethodClass 方法继承自父类时返回声明它的父类，方法属于对象自身的类时返回 nil
+func inheritedMethodClass(object *data.ClassValue, method data.Method) data.ClassStmt {
+	name := method.GetName()
+	if m, ok := object.Class.GetMethod(name); ok && sameMethod(m, method) {
+		return nil
 	}
-
-	vm := ctx.GetVM()
-
-	// 检查调用者类是否是目标类的子类（调用者继承自目标）
-	extend := callerClass.GetExtend()
-	for extend != nil {
-		if *extend == targetClass.GetName() {
-			return true
-		}
-		cls, acl := vm.GetOrLoadClass(*extend)
-		if acl != nil {
-			return false
-		}
-		extend = cls.GetExtend()
-	}
-
-	// 检查目标类是否是调用者类的子类（目标继承自调用者）
-	// 父类可以访问子类实例上的 protected 属性
-	targetExtend := targetClass.GetExtend()
-	for targetExtend != nil {
-		if *targetExtend == callerClass.GetName() {
-			return true
+	vm := object.GetVM()
+	class := object.Class
+	for class.GetExtend() != nil {
+		next, acl := vm.GetOrLoadClass(*class.GetExtend())
+		if acl != nil || next == nil {
+			return nil
 		}
-		cls, acl := vm.GetOrLoadClass(*targetExtend)
-		if acl != nil {
-			return false
+		if m, ok := next.GetMethod(name); ok && sameMethod(m, method) {
+			return next
 		}
-		targetExtend = cls.GetExtend()
+		class = next
 	}
+	return nil
+}
 
-	return false
+// sameMethod 是否为同一个方法声明（不可比较的方法实现视为不同）
+func sameMethod(a, b data.Method) bool {
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta.Comparable() && a == b
 }
 
 // findClassMethodContext 从上下文链中查找含 $this 的类方法上下文
@@ -458,6 +491,7 @@ func (pe *CallObjectMethod) invokeMagicCallFromCallCtx(object data.Context, call
 		return nil, data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("__call 需要至少 2 个参数"))
 	}
 	fnCtx := object.CreateContext(varies)
+	data.BindRoutine(fnCtx, data.RoutineOf(callCtx))
 	fnCtx.SetVariableValue(varies[0], data.NewStringValue(methodName))
 	fnCtx.SetVariableValue(varies[1], data.NewArrayValue(argsList))
 	return magic.Call(fnCtx)
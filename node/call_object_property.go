@@ -12,6 +12,26 @@ type CallObjectProperty struct {
 	*Node    `pp:"-"`
 	Object   data.GetValue // 对象表达式
 	Property string        // 属性名
+
+	properties inlineCache[propertyEntry] `pp:"-"` // 按对象的类缓存属性声明
+}
+
+// propertyEntry 属性声明的查找结果，property 为 nil 表示类中没有声明该属性
+type propertyEntry struct {
+	property data.Property
+}
+
+// propertyStmt 按对象的类查找属性声明，结果缓存在调用点上
+func (pe *CallObjectProperty) propertyStmt(object *data.ClassValue) (data.Property, bool) {
+	if entry, ok := pe.properties.lookup(object.Class); ok {
+		return entry.property, entry.property != nil
+	}
+	property, ok := object.GetPropertyStmt(pe.Property)
+	if !ok {
+		property = nil
+	}
+	pe.properties.store(object.Class, propertyEntry{property: property})
+	return property, property != nil
 }
 
 func (pe *CallObjectProperty) GetIndex() int {
@@ -19,15 +39,34 @@ func (pe *CallObjectProperty) GetIndex() int {
 }
 
 func (pe *CallObjectProperty) GetZVal(ctx data.Context) (*data.ZVal, data.Control) {
-	temp, acl := pe.Object.GetValue(ctx)
+	temp, acl := receiverValue(ctx, pe.Object)
 	if acl != nil {
 		return nil, acl
 	}
 	switch object := temp.(type) {
 	case data.GetPropertyStmt: // 需要检查属性类型
-		property, ok := object.GetPropertyStmt(pe.Property)
+		var property data.Property
+		var ok bool
+		if cv := classValueOf(object); cv != nil {
+			property, ok = pe.propertyStmt(cv)
+		} else {
+			property, ok = object.GetPropertyStmt(pe.Property)
+		}
 		if ok {
-			return property.GetZVal(object)
+			// 按引用传递 readonly 属性等同于修改它
+			if cv := classValueOf(object); cv != nil && isReadonlyProperty(property) {
+				if acl := checkReadonlyWrite(ctx, pe.GetFrom(), cv, property, false); acl != nil {
+					return nil, acl
+				}
+			}
+			z, acl := property.GetZVal(object)
+			if acl != nil {
+				return nil, acl
+			}
+			if cv := classValueOf(object); cv != nil {
+				guardPropertyReference(z, propertyDeclaringClass(cv, property.GetName()).GetName(), property)
+			}
+			return z, nil
 		}
 	default:
 		return nil, data.NewErrorThrow(pe.GetFrom(), errors.New("object is not get property"))
@@ -44,18 +83,20 @@ func (pe *CallObjectProperty) GetType() data.Types {
 }
 
 func (pe *CallObjectProperty) SetValue(ctx data.Context, value data.Value) data.Control {
-	temp, acl := pe.Object.GetValue(ctx)
+	temp, acl := receiverValue(ctx, pe.Object)
 	if acl != nil {
 		return acl
 	}
 	switch object := temp.(type) {
 	case *data.ThisValue:
-		property, ok := object.GetPropertyStmt(pe.Property)
+		property, ok := pe.propertyStmt(object.ClassValue)
 		if ok {
-			if property.GetType() != nil && !property.GetType().Is(value) {
-				test := property.GetType()
-				test.Is(value)
-				return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("%s 属性 %s 因为类型不一致无法赋值", TryGetCallClassName(object), pe.Property))
+			if hooked, acl := writePropertyHook(ctx, pe.GetFrom(), object.ClassValue, property, value); hooked {
+				return acl
+			}
+			value, acl = checkPropertyAssign(ctx, pe.GetFrom(), object.ClassValue, property, value)
+			if acl != nil {
+				return acl
 			}
 			return object.SetProperty(pe.Property, value)
 		}
@@ -63,28 +104,38 @@ func (pe *CallObjectProperty) SetValue(ctx data.Context, value data.Value) data.
 		if magic, hasSet := object.GetMethod("__set"); hasSet {
 			return pe.invokeMagicSet(object, magic, pe.Property, value)
 		}
+		if acl := checkDynamicProperty(pe.GetFrom(), object.ClassValue, pe.Property); acl != nil {
+			return acl
+		}
 		return object.SetProperty(pe.Property, value)
 	case *data.ClassValue:
-		property, ok := object.GetPropertyStmt(pe.Property)
+		property, ok := pe.propertyStmt(object)
 		if ok {
-			if property.GetType() != nil && !property.GetType().Is(value) {
-				return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("%s 属性 %s 因为类型不一致无法赋值", TryGetCallClassName(object), pe.Property))
-			}
 			if property.GetModifier() == data.ModifierPrivate {
-				if !isCallerInClassHierarchy(ctx, object.Class) {
+				if !data.CallerInClassHierarchy(ctx, object.Class) {
 					return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("对象(%s)属性(%s)是私有的", object.Class.GetName(), pe.Property))
 				}
 			} else if property.GetModifier() == data.ModifierProtected {
-				if !isCallerInClassHierarchy(ctx, object.Class) {
+				if !data.CallerInClassHierarchy(ctx, object.Class) {
 					return data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("对象(%s)属性(%s)不是公开的", object.Class.GetName(), pe.Property))
 				}
 			}
+			if hooked, acl := writePropertyHook(ctx, pe.GetFrom(), object, property, value); hooked {
+				return acl
+			}
+			value, acl = checkPropertyAssign(ctx, pe.GetFrom(), object, property, value)
+			if acl != nil {
+				return acl
+			}
 			return object.SetProperty(pe.Property, value)
 		}
 		// 无声明属性时尝试 __set(string $name, mixed $value)
 		if magic, hasSet := object.GetMethod("__set"); hasSet {
 			return pe.invokeMagicSet(object, magic, pe.Property, value)
 		}
+		if acl := checkDynamicProperty(pe.GetFrom(), object, pe.Property); acl != nil {
+			return acl
+		}
 		return object.SetProperty(pe.Property, value)
 	case data.SetProperty:
 		return object.SetProperty(pe.Property, value)
@@ -93,6 +144,22 @@ func (pe *CallObjectProperty) SetValue(ctx data.Context, value data.Value) data.
 	}
 }
 
+// checkReadonlyReference 取 readonly 属性的引用前检查能否写入
+func (pe *CallObjectProperty) checkReadonlyReference(ctx data.Context) data.Control {
+	temp, acl := receiverValue(ctx, pe.Object)
+	if acl != nil {
+		return acl
+	}
+	object := classValueOf(temp)
+	if object == nil {
+		return nil
+	}
+	if property, ok := pe.propertyStmt(object); ok && isReadonlyProperty(property) {
+		return checkReadonlyWrite(ctx, pe.GetFrom(), object, property, false)
+	}
+	return nil
+}
+
 // NewObjectProperty 创建一个新的对象属性访问表达式
 func NewObjectProperty(token *TokenFrom, object data.GetValue, property string) *CallObjectProperty {
 	return &CallObjectProperty{
@@ -128,7 +195,7 @@ func (pe *CallObjectProperty) invokeMagicSet(object data.Context, magic data.Met
 
 // GetValue 获取对象属性访问表达式的值
 func (pe *CallObjectProperty) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	o, ctl := pe.Object.GetValue(ctx)
+	o, ctl := receiverValue(ctx, pe.Object)
 	if ctl != nil {
 		if ctl, ok := ctl.(data.AddStack); ok {
 			ctl.AddStackWithInfo(pe.from, TryGetCallClassName(pe.Object), pe.Property)
@@ -139,8 +206,11 @@ func (pe *CallObjectProperty) GetValue(ctx data.Context) (data.GetValue, data.Co
 	case *data.NullValue:
 		return data.NewNullValue(), nil
 	case *data.ThisValue:
-		property, ok := v.GetPropertyStmt(pe.Property)
+		property, ok := pe.propertyStmt(v.ClassValue)
 		if ok {
+			if ret, acl, hooked := readPropertyHook(pe.from, v.ClassValue, property); hooked {
+				return ret, acl
+			}
 			return property.GetValue(v)
 		}
 		// 无声明属性时尝试 __get(string $name)
@@ -149,17 +219,20 @@ func (pe *CallObjectProperty) GetValue(ctx data.Context) (data.GetValue, data.Co
 		}
 		return nil, data.NewErrorThrow(pe.from, fmt.Errorf("对象(%s)不存在属性(%s)", v.Class.GetName(), pe.Property))
 	case *data.ClassValue:
-		property, ok := v.GetPropertyStmt(pe.Property)
+		property, ok := pe.propertyStmt(v)
 		if ok {
 			if property.GetModifier() == data.ModifierPrivate {
-				if !isCallerInClassHierarchy(ctx, v.Class) {
+				if !data.CallerInClassHierarchy(ctx, v.Class) {
 					return nil, data.NewErrorThrow(pe.from, fmt.Errorf("对象(%s)属性(%s)是私有的", v.Class.GetName(), pe.Property))
 				}
 			} else if property.GetModifier() == data.ModifierProtected {
-				if !isCallerInClassHierarchy(ctx, v.Class) {
+				if !data.CallerInClassHierarchy(ctx, v.Class) {
 					return nil, data.NewErrorThrow(pe.from, fmt.Errorf("对象(%s)属性(%s)不是公开的", v.Class.GetName(), pe.Property))
 				}
 			}
+			if ret, acl, hooked := readPropertyHook(pe.from, v, property); hooked {
+				return ret, acl
+			}
 			return property.GetValue(v)
 		}
 		// 无声明属性时尝试 __get(string $name)
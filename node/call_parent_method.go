@@ -120,6 +120,21 @@ func (pe *CallParentMethod) GetValue(ctx data.Context) (data.GetValue, data.Cont
 		return nil, data.NewPHPUncaughtError(pe.GetFrom(), msg)
 	}
 
+	// parent::method(...) 得到父类方法的闭包
+	if IsFirstClassCallable(pe.Arguments) {
+		if method.GetIsStatic() || object == nil {
+			callClass := data.ClassStmt(class)
+			if cmc, ok := ctx.(*data.ClassMethodContext); ok && cmc.StaticClass != nil {
+				callClass = cmc.StaticClass
+			} else if object != nil {
+				callClass = object.Class
+			}
+			return data.NewFuncValue(&staticMethodFunc{class: foundClass, callClass: callClass, method: method}), nil
+		}
+		data.Escape(object)
+		return data.NewFuncValue(&ClassClosure{class: object, method: method}), nil
+	}
+
 	temp := &CallObjectMethod{
 		Node:   pe.Node,
 		Object: object,
@@ -38,7 +38,7 @@ func (pe *CallSelfProperty) GetValue(ctx data.Context) (data.GetValue, data.Cont
 		// 获取当前类的静态属性
 		property, has := getter.GetStaticProperty(pe.Property)
 		if has {
-			return property, nil
+			return staticValue(property)
 		}
 	}
 
@@ -56,7 +56,7 @@ func (pe *CallSelfProperty) GetValue(ctx data.Context) (data.GetValue, data.Cont
 		if parentGetter, ok := parentClass.(data.GetStaticProperty); ok {
 			property, has := parentGetter.GetStaticProperty(pe.Property)
 			if has {
-				return property, nil
+				return staticValue(property)
 			}
 		}
 
@@ -135,11 +135,9 @@ func (pe *CallSelfProperty) SetProperty(ctx data.Context, name string, value dat
 
 	switch c := currentClass.(type) {
 	case *ClassStatement:
-		c.StaticProperty.Store(name, value)
-		return nil
+		return c.storeStaticProperty(pe.GetFrom(), name, value)
 	case *ClassGeneric:
-		c.StaticProperty.Store(name, value)
-		return nil
+		return c.storeStaticProperty(pe.GetFrom(), name, value)
 	case data.SetProperty:
 		return c.SetProperty(name, value)
 	}
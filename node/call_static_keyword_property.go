@@ -89,7 +89,7 @@ func (pe *CallStaticKeywordProperty) GetValue(ctx data.Context) (data.GetValue,
 		return data.NewNullValue(), nil
 	}
 
-	return property, nil
+	return staticValue(property)
 }
 
 // SetProperty 设置 static::$prop 的值
@@ -118,11 +118,9 @@ func (pe *CallStaticKeywordProperty) SetProperty(ctx data.Context, name string,
 	// 在定义该属性的类中设置静态属性
 	switch c := definingClass.(type) {
 	case *ClassStatement:
-		c.StaticProperty.Store(name, value)
-		return nil
+		return c.storeStaticProperty(pe.GetFrom(), name, value)
 	case *ClassGeneric:
-		c.StaticProperty.Store(name, value)
-		return nil
+		return c.storeStaticProperty(pe.GetFrom(), name, value)
 	case data.SetProperty:
 		return c.SetProperty(name, value)
 	}
@@ -11,6 +11,15 @@ type CallStaticMethod struct {
 	*Node  `pp:"-"`
 	stmt   data.GetValue // 类名称 Class::fn() or Class::test::one
 	Method string        // 函数名
+
+	methods inlineCache[staticMethodEntry] `pp:"-"` // 按调用的类缓存查找到的静态方法
+	forward bool                           // self:: 调用，沿用调用方的后期静态绑定类
+}
+
+// staticMethodEntry 静态方法查找结果，class 为方法定义所在的类
+type staticMethodEntry struct {
+	method data.Method
+	class  data.ClassStmt
 }
 
 func NewCallStaticMethod(from *TokenFrom, path data.GetValue, method string) *CallStaticMethod {
@@ -32,9 +41,16 @@ func (pe *CallStaticMethod) GetValue(ctx data.Context) (data.GetValue, data.Cont
 	var classStmt data.ClassStmt // 方法定义所在的类
 	var callClass data.ClassStmt // 实际调用的类（用于 late static binding）
 	var has bool
+	var cacheClass data.ClassStmt // 查找结果只取决于该类时写入缓存
 
 	switch expr := pe.stmt.(type) {
 	case data.GetStaticMethod:
+		cls, _ := expr.(data.ClassStmt)
+		if entry, ok := pe.methods.lookup(cls); ok {
+			method, classStmt, callClass, has = entry.method, entry.class, cls, true
+			break
+		}
+		cacheClass = cls
 		// 先在当前类上查找静态方法
 		method, has = expr.GetStaticMethod(pe.Method)
 		if has {
@@ -167,6 +183,9 @@ func (pe *CallStaticMethod) GetValue(ctx data.Context) (data.GetValue, data.Cont
 		}
 	}
 
+	if has && cacheClass != nil && classStmt != nil {
+		pe.methods.store(cacheClass, staticMethodEntry{method: method, class: classStmt})
+	}
 	if !has {
 		if fn, ok := tryNewInstanceMagicCallViaStaticFunc(ctx, pe.Method); ok {
 			return data.NewFuncValue(fn), nil
@@ -188,6 +207,11 @@ func (pe *CallStaticMethod) GetValue(ctx data.Context) (data.GetValue, data.Cont
 				originalMethod: pe.Method,
 			}), nil
 		}
+		if pe.forward {
+			if late := lateStaticClass(ctx); late != nil {
+				callClass = late
+			}
+		}
 		return data.NewFuncValue(&staticMethodFunc{
 			class:          classStmt,
 			callClass:      callClass,
@@ -207,6 +231,15 @@ type CallStaticMethodLater struct {
 	method    string            // 方法名
 	namespace string            // 命名空间
 	call      *CallStaticMethod `pp:"-"` // 解析后缓存
+	forward   bool              // self:: 调用，沿用调用方的后期静态绑定类
+}
+
+// NewCallSelfStaticMethod 创建 self::method() 调用，与 PHP 一样转发后期静态绑定：
+// 子类实例中 self::make() 内的 static 仍是子类
+func NewCallSelfStaticMethod(from *TokenFrom, className, method string) *CallStaticMethodLater {
+	call := NewCallStaticMethodLater(from, className, method, className)
+	call.forward = true
+	return call
 }
 
 // NewCallStaticMethodLater 创建延迟的静态方法调用
@@ -244,7 +277,9 @@ func (pe *CallStaticMethodLater) resolveCall(ctx data.Context) (*CallStaticMetho
 	if !ok {
 		return nil, data.NewErrorThrow(pe.GetFrom(), fmt.Errorf("无法获取TokenFrom信息"))
 	}
-	pe.call = NewCallStaticMethod(tokenFrom, stmt, pe.method)
+	call := NewCallStaticMethod(tokenFrom, stmt, pe.method)
+	call.forward = pe.forward
+	pe.call = call
 	return pe.call, nil
 }
 
@@ -257,6 +292,20 @@ func (pe *CallStaticMethodLater) GetValue(ctx data.Context) (data.GetValue, data
 	return call.GetValue(ctx)
 }
 
+// lateStaticClass 当前上下文中 static 指向的类，不在类上下文中时为 nil
+func lateStaticClass(ctx data.Context) data.ClassStmt {
+	switch c := ctx.(type) {
+	case *data.ClassMethodContext:
+		if c.StaticClass != nil {
+			return c.StaticClass
+		}
+		return c.Class
+	case *data.ClassValue:
+		return c.Class
+	}
+	return nil
+}
+
 func NewStaticMethodFuncValue(class data.ClassStmt, method data.Method) *StaticMethodFuncValue {
 	return &StaticMethodFuncValue{
 		class:  class,
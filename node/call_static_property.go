@@ -27,12 +27,12 @@ func (pe *CallStaticProperty) GetValue(ctx data.Context) (data.GetValue, data.Co
 	case data.GetStaticProperty:
 		property, ok := expr.GetStaticProperty(pe.Property)
 		if ok {
-			return property, nil
+			return staticValue(property)
 		}
 		// 在父类中查找
 		if cs, ok := expr.(data.ClassStmt); ok {
 			if prop, found := pe.findStaticPropertyInParents(ctx, cs); found {
-				return prop, nil
+				return staticValue(prop)
 			}
 		}
 		return nil, data.NewErrorThrow(pe.GetFrom(), errors.New(fmt.Sprintf("无法调用属性(%s::%s)。", TryGetCallClassName(pe.Stmt), pe.Property)))
@@ -46,21 +46,21 @@ func (pe *CallStaticProperty) GetValue(ctx data.Context) (data.GetValue, data.Co
 			if c, ok := expr.Class.(data.GetStaticProperty); ok {
 				property, ok := c.GetStaticProperty(pe.Property)
 				if ok {
-					return property, nil
+					return staticValue(property)
 				}
 			}
 			if prop, found := pe.findStaticPropertyInParents(ctx, expr.Class); found {
-				return prop, nil
+				return staticValue(prop)
 			}
 
 		case data.GetStaticProperty:
 			property, ok := expr.GetStaticProperty(pe.Property)
 			if ok {
-				return property, nil
+				return staticValue(property)
 			}
 			if cs, ok := expr.(data.ClassStmt); ok {
 				if prop, found := pe.findStaticPropertyInParents(ctx, cs); found {
-					return prop, nil
+					return staticValue(prop)
 				}
 			}
 		}
@@ -94,11 +94,9 @@ func (pe *CallStaticProperty) findStaticPropertyInParents(ctx data.Context, clas
 func (pe *CallStaticProperty) SetProperty(ctx data.Context, name string, value data.Value) data.Control {
 	switch c := pe.Stmt.(type) {
 	case *ClassStatement:
-		c.StaticProperty.Store(name, value)
-		return nil
+		return c.storeStaticProperty(pe.GetFrom(), name, value)
 	case *ClassGeneric:
-		c.StaticProperty.Store(name, value)
-		return nil
+		return c.storeStaticProperty(pe.GetFrom(), name, value)
 	case data.SetProperty:
 		return c.SetProperty(name, value)
 	default:
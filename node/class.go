@@ -10,22 +10,33 @@ import (
 
 // ClassStatement 表示类定义语句
 type ClassStatement struct {
-	*Node           `pp:"-"`
-	Name            string                   // 类名
-	Extends         *string                  // 父类名
-	Implements      []string                 // 实现的接口列表
-	StaticProperty  sync.Map                 // 静态属性存储（运行时值）
-	PropertiesIndex []string                 // 属性列表
-	Properties      map[string]data.Property // 属性列表
-	Methods         map[string]data.Method   // 方法列表
-	StaticMethods   map[string]data.Method   // 静态方法列表
-	Annotations     []*data.ClassValue       // 类注解列表
+	*Node          `pp:"-"`
+	Name           string   // 类名
+	Extends        *string  // 父类名
+	Implements     []string // 实现的接口列表
+	StaticProperty sync.Map // 静态属性存储（运行时值）
+	// StaticProperties 静态属性声明，写入静态属性时按声明的类型检查
+	StaticProperties map[string]data.Property
+	PropertiesIndex  []string                 // 属性列表
+	Properties       map[string]data.Property // 属性列表
+	Methods          map[string]data.Method   // 方法列表
+	StaticMethods    map[string]data.Method   // 静态方法列表
+	Annotations      []*data.ClassValue       // 类注解列表
 
 	// 构造函数
 	Construct data.Method
 
 	// IsAbstract 为 true 表示 abstract class（允许未实现接口/抽象方法）
 	IsAbstract bool
+	// IsReadonly 为 true 表示 readonly class（所有实例属性只读，不允许动态属性）
+	IsReadonly bool
+
+	shapes data.ShapeCache // 实例属性布局
+}
+
+// ShapeCache 实现 data.ShapeHolder
+func (c *ClassStatement) ShapeCache() *data.ShapeCache {
+	return &c.shapes
 }
 
 // GetValue 获取类定义语句的值
@@ -37,7 +48,12 @@ func (c *ClassStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 	}
 	object := data.NewClassValue(c, ctx)
 
-	for _, property := range c.Properties {
+	// 按声明顺序初始化，同一个类的实例得到相同的属性布局
+	for _, name := range c.PropertiesIndex {
+		property, ok := c.Properties[name]
+		if !ok {
+			continue
+		}
 		def := property.GetDefaultValue()
 		if def == nil {
 			continue
@@ -196,6 +212,24 @@ func (c *ClassStatement) GetStaticProperty(name string) (data.Value, bool) {
 	return nil, false
 }
 
+// GetIsReadonly 是否为 readonly class
+func (c *ClassStatement) GetIsReadonly() bool {
+	return c.IsReadonly
+}
+
+// storeStaticProperty 写入静态属性，有类型声明时按属性类型检查并转换
+func (c *ClassStatement) storeStaticProperty(from data.From, name string, value data.Value) data.Control {
+	if property, ok := c.StaticProperties[name]; ok && property.GetType() != nil {
+		v, acl := coercePropertyValue(from, c.Name, property, value)
+		if acl != nil {
+			return acl
+		}
+		value = v
+	}
+	c.StaticProperty.Store(name, value)
+	return nil
+}
+
 func (c *ClassStatement) GetStaticMethod(name string) (data.Method, bool) {
 	if f, ok := c.StaticMethods[name]; ok {
 		return f, true
@@ -213,6 +247,19 @@ type ClassProperty struct {
 	DefaultValue data.GetValue      // 默认值
 	Annotations  []*data.ClassValue // 属性注解列表
 	Type         data.Types         // 属性类型
+
+	WriteModifier data.Modifier // 写入访问修饰符：private(set)/protected(set)，public 表示与 Modifier 相同
+	GetHook       data.Method   // get 钩子
+	SetHook       data.Method   // set 钩子
+	IsVirtual     bool          // 虚拟属性：钩子不使用属性自身的存储
+	IsConst       bool          // 类常量（const NAME = ...），以静态属性存储
+
+	slot *data.PropertySlot // 属性在类实例布局中的槽位缓存
+}
+
+// PropertySlot 实现 data.PropertySlotHolder
+func (p *ClassProperty) PropertySlot() *data.PropertySlot {
+	return p.slot
 }
 
 func (p *ClassProperty) GetIndex() int {
@@ -265,6 +312,7 @@ func NewPropertyWithPromoted(from data.From, name string, modifier string, isSta
 		IsPromoted:   isPromoted,
 		DefaultValue: defaultValue,
 		Type:         ty,
+		slot:         &data.PropertySlot{},
 	}
 }
 
@@ -317,17 +365,18 @@ func (p *ClassProperty) AddAnnotations(a *data.ClassValue) {
 }
 
 type ClassMethod struct {
-	*Node        `pp:"-"`
-	Name         string          // 方法名
-	Modifier     data.Modifier   // 访问修饰符
-	IsStatic     bool            // 是否是静态方法
-	Params       []data.GetValue // 参数列表
-	Body         []data.GetValue // 方法体
-	vars         []data.Variable
-	Annotations  []*data.ClassValue // 方法注解列表
-	Ret          data.Types         // 返回类型
-	IsGenerator  bool               // 是否是生成器方法（含 yield）
-	staticLocals *data.StaticLocals // 方法内 static 局部变量
+	*Node       `pp:"-"`
+	Name        string          // 方法名
+	Modifier    data.Modifier   // 访问修饰符
+	IsStatic    bool            // 是否是静态方法
+	Params      []data.GetValue // 参数列表
+	Body        []data.GetValue // 方法体
+	vars        []data.Variable
+	Annotations []*data.ClassValue // 方法注解列表
+	Ret         data.Types         // 返回类型
+	Generic     []data.Types       // 类型参数（public function map<U>(...)）
+	IsGenerator bool               // 是否是生成器方法（含 yield）
+	hasStatics  bool               // 方法体内是否声明了 static 局部变量
 }
 
 func (m *ClassMethod) GetValue(ctx data.Context) (data.GetValue, data.Control) {
@@ -347,6 +396,7 @@ func NewMethod(from data.From, name string, modifier string, isStatic bool, para
 		vars:        vars,
 		Ret:         ret,
 		IsGenerator: containsYield(body),
+		hasStatics:  containsStatic(body),
 	}
 }
 
@@ -385,48 +435,69 @@ func (m *ClassMethod) GetReturnType() data.Types {
 	return m.Ret
 }
 
-func (m *ClassMethod) methodStaticLocals() *data.StaticLocals {
-	if m.staticLocals == nil {
-		m.staticLocals = data.NewStaticLocals()
+func (m *ClassMethod) Call(ctx data.Context) (_ data.GetValue, acl data.Control) {
+	// static 局部变量与调用深度按执行流隔离
+	routine := data.RoutineOf(ctx)
+	var statics *data.StaticLocals
+	if m.hasStatics {
+		statics = routine.StaticLocals(m)
+		bindStaticLocals(ctx, statics)
 	}
-	return m.staticLocals
-}
 
-func (m *ClassMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	if b, ok := ctx.(data.StaticLocalsBinder); ok {
-		b.BindStaticLocals(m.methodStaticLocals())
+	generics, acl := bindGenerics(ctx, m.Generic, m.Params, m.Name, callSiteFrom(ctx))
+	if acl != nil {
+		return nil, acl
 	}
 
 	// PHP 语义：如果方法是 generator（含 yield），调用时立即返回 Generator 对象，不执行方法体
 	if m.IsGenerator {
-		generator := NewFuncYieldStackState(ctx, m, m.Body, 0, nil, nil)
-		generatorClass := NewGeneratorClass(generator)
-		return generatorClass.GetValue(ctx)
+		// 生成器持有 $this，对象不再归属调用方的变量
+		if cmc, ok := ctx.(*data.ClassMethodContext); ok {
+			data.Escape(cmc.ClassValue)
+		}
+		return newGeneratorValue(ctx, m, m.Body)
 	}
+	retType := data.ResolveGenerics(m.Ret, generics)
 
 	// 调用深度限制，防止无限递归导致栈溢出
-	if vm := ctx.GetVM(); vm != nil {
-		if depth := vm.EnterCall(); depth > 500 {
-			vm.LeaveCall()
-			return nil, data.NewErrorThrow(m.GetFrom(), fmt.Errorf("方法 %s 调用深度超过限制(%d)", m.Name, depth))
-		}
-		defer vm.LeaveCall()
+	if depth := routine.EnterCall(); depth > 500 {
+		routine.LeaveCall()
+		return nil, data.NewErrorThrow(m.GetFrom(), fmt.Errorf("方法 %s 调用深度超过限制(%d)", m.Name, depth))
 	}
+	defer routine.LeaveCall()
+	defer statics.Sync(ctx)
+	defer data.ReleaseFrame(ctx, &acl)
+	defer data.RunDefers(ctx, &acl)
 
 	var v data.GetValue
 	var ctl data.Control
-	for bodyIndex, statement := range m.Body {
+	for _, statement := range m.Body {
 		v, ctl = statement.GetValue(ctx)
+		if ctl == nil {
+			ctl = data.Safepoint(ctx)
+		}
 		if ctl != nil {
 			switch rv := ctl.(type) {
 			case data.ReturnControl:
 				ret := rv.ReturnValue()
-				if m.Ret == nil {
+				if retType == nil {
 					return ret, nil // 不判断类型
 				}
-				if m.Ret.Is(ret) {
+				// 返回值类型按方法定义所在文件的 strict_types 模式检查
+				strict := IsStrictTypes(m.from)
+				if !strict && retType.Is(ret) {
 					return ret, nil
 				}
+				if v, ok := data.CoerceType(retType, ret, strict); ok {
+					return v, nil
+				}
+				if strict || data.HasGenerics(m.Ret) {
+					name := m.Name
+					if c, ok := ctx.(data.GetName); ok {
+						name = c.GetName() + "::" + m.Name
+					}
+					return nil, returnTypeError(m.from, name, retType, ret)
+				}
 				// 允许 null 返回（PHP 兼容：方法可能隐式返回 null）
 				if _, isNull := ret.(*data.NullValue); isNull {
 					return data.NewStringValue(""), nil
@@ -436,6 +507,7 @@ func (m *ClassMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 					if obj, ok := ret.(*data.ClassValue); ok {
 						if toStr, ok := obj.GetMethod("__toString"); ok && toStr != nil {
 							fnCtx := obj.CreateContext(toStr.GetVariables())
+							data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 							fnCtx.SetCallArgs([]data.GetValue{})
 							val, ctl := toStr.Call(fnCtx)
 							if ctl == nil && val != nil {
@@ -445,16 +517,6 @@ func (m *ClassMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 					}
 				}
 				return nil, data.NewErrorThrow(m.GetFrom(), fmt.Errorf("方法(%s)返回值类型错误; 期望 %s, 实际 %T", m.Name, m.Ret.String(), ret))
-			case data.YieldControl:
-				// Generator 方法：将执行状态保存为生成器
-				generator := rv.CreateStackState(ctx, m, m.Body, bodyIndex)
-				generatorClass := NewGeneratorClass(generator)
-				return generatorClass.GetValue(ctx)
-			case data.YieldValueControl:
-				// Generator 方法：将执行状态保存为生成器
-				generator := NewFuncYieldStackState(ctx, m, m.Body, bodyIndex+1, rv.GetYieldKey(), rv.GetYieldValue())
-				generatorClass := NewGeneratorClass(generator)
-				return generatorClass.GetValue(ctx)
 			case data.AddStack:
 				if tv, ok := rv.(*data.ThrowValue); ok && tv.PHPUncaughtError {
 					return nil, ctl
@@ -2,37 +2,77 @@ package node
 
 import "github.com/php-any/origami/data"
 
-// ClassGeneric 泛型类
+// ClassGeneric 泛型类；每次实例化（DB<User>）通过 Clone 得到带自身类型映射的副本，
+// 属性类型在副本中单独替换，不修改共享的 ClassProperty，因此不同实例化之间互不影响
 type ClassGeneric struct {
 	*ClassStatement
 
 	Generic    []data.Types
 	GenericMap map[string]data.Types
+
+	properties map[string]data.Property // 替换了类型参数的属性
 }
 
 func (c *ClassGeneric) Clone(mT map[string]data.Types) data.ClassGeneric {
-	return &ClassGeneric{
+	cg := &ClassGeneric{
 		ClassStatement: c.ClassStatement,
 		Generic:        c.Generic,
 		GenericMap:     mT,
 	}
+	if len(mT) > 0 {
+		cg.properties = make(map[string]data.Property)
+		for name, property := range c.Properties {
+			cp, ok := property.(*ClassProperty)
+			if !ok || !data.HasGenerics(cp.Type) {
+				continue
+			}
+			resolved := *cp
+			resolved.Type = data.ResolveGenerics(cp.Type, mT)
+			cg.properties[name] = &resolved
+		}
+	}
+	return cg
 }
+
 func (c *ClassGeneric) GenericList() []data.Types {
 	return c.Generic
 }
 
-func (c *ClassGeneric) GetProperty(name string) (data.Property, bool) {
-	if f, ok := c.Properties[name]; ok {
-		// 替换泛型类型
-		if f.GetType() != nil {
-			t := f.GetType()
-			if gt, ok := t.(data.Generic); ok {
-				f.SetType(c.GenericMap[gt.Name])
+// TypeMap 类型参数到类型实参的映射；未实例化的类型参数映射为自身（只检查约束）
+func (c *ClassGeneric) TypeMap() map[string]data.Types {
+	m := make(map[string]data.Types, len(c.Generic))
+	for _, t := range c.Generic {
+		if g, ok := t.(data.Generic); ok {
+			if r, ok := c.GenericMap[g.Name]; ok {
+				m[g.Name] = r
+			} else {
+				m[g.Name] = g
 			}
 		}
+	}
+	return m
+}
+
+func (c *ClassGeneric) GetProperty(name string) (data.Property, bool) {
+	if f, ok := c.properties[name]; ok {
 		return f, true
 	}
-	return nil, false
+	return c.ClassStatement.GetProperty(name)
+}
+
+func (c *ClassGeneric) GetPropertyList() []data.Property {
+	properties := c.ClassStatement.GetPropertyList()
+	if len(c.properties) == 0 {
+		return properties
+	}
+	list := make([]data.Property, len(properties))
+	for i, property := range properties {
+		if f, ok := c.properties[property.GetName()]; ok {
+			property = f
+		}
+		list[i] = property
+	}
+	return list
 }
 
 func (c *ClassGeneric) GetValue(ctx data.Context) (data.GetValue, data.Control) {
@@ -43,7 +83,12 @@ func (c *ClassGeneric) GetValue(ctx data.Context) (data.GetValue, data.Control)
 	}
 	object := data.NewClassValue(c, ctx)
 
-	for _, property := range c.Properties {
+	// 按声明顺序初始化，同一个类的实例得到相同的属性布局
+	for _, name := range c.PropertiesIndex {
+		property, ok := c.Properties[name]
+		if !ok {
+			continue
+		}
 		def := property.GetDefaultValue()
 		if def == nil {
 			continue
@@ -52,6 +52,7 @@ func (n *CloneExpression) GetValue(ctx data.Context) (data.GetValue, data.Contro
 
 	// 创建新实例，保持同一个类与上下文
 	cloned := data.NewClassValue(obj.Class, obj.Context)
+	data.TrackObject(cloned)
 
 	// 复制实例属性（浅拷贝属性值，符合 PHP 克隆语义）
 	obj.RangeProperties(func(key string, v data.Value) bool {
@@ -63,11 +64,16 @@ func (n *CloneExpression) GetValue(ctx data.Context) (data.GetValue, data.Contro
 	if method, ok := cloned.GetMethod("__clone"); ok && method != nil {
 		varies := method.GetVariables()
 		fnCtx := cloned.CreateContext(varies)
+		data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 		// 记录调用参数（无参）
 		fnCtx.SetCallArgs([]data.GetValue{})
 
+		// __clone 执行期间允许对 readonly 属性重新赋值一次
+		cloningObjects.Store(cloned.ObjectValue, map[string]bool{})
 		_, acl = method.Call(fnCtx)
+		cloningObjects.Delete(cloned.ObjectValue)
 		if acl != nil {
+			data.ForgetObject(cloned)
 			if throwValue, ok := acl.(*data.ThrowValue); ok {
 				throwValue.AddStackWithInfo(n.from, cloned.Class.GetName(), "__clone")
 			}
@@ -13,6 +13,8 @@ func NewClassClosure(class *data.ClassValue, methodName string) (*data.FuncValue
 		return nil, utils.NewThrow(errors.New("method not found"))
 	}
 
+	// 闭包持有对象，对象不再归属原变量
+	data.Escape(class)
 	return data.NewFuncValue(&ClassClosure{
 		class:  class,
 		method: method,
@@ -27,6 +29,11 @@ type ClassClosure struct {
 
 func (c *ClassClosure) Call(ctx data.Context) (data.GetValue, data.Control) {
 	fnCtx := c.class.CreateContext(c.method.GetVariables())
+	// 继承来的方法在声明它的类的作用域中执行
+	if cmc, ok := fnCtx.(*data.ClassMethodContext); ok {
+		cmc.SelfClass = inheritedMethodClass(c.class, c.method)
+	}
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 
 	for i := 0; i < len(c.method.GetVariables()); i++ {
 		fnCtx.SetIndexZVal(i, ctx.GetIndexZVal(i))
@@ -46,3 +53,22 @@ func (c *ClassClosure) GetParams() []data.GetValue {
 func (c *ClassClosure) GetVariables() []data.Variable {
 	return c.method.GetVariables()
 }
+
+// BindScope 方法闭包的作用域为声明方法的类
+func (c *ClassClosure) BindScope() data.ClassStmt {
+	if declaring := inheritedMethodClass(c.class, c.method); declaring != nil {
+		return declaring
+	}
+	return c.class.Class
+}
+
+// Bind 方法闭包只能换绑到同一作用域中的其它对象
+func (c *ClassClosure) Bind(this *data.ClassValue, scope data.ClassStmt) (data.FuncStmt, string) {
+	if this == nil {
+		return nil, "Cannot unbind $this of method"
+	}
+	if scope == nil || scope.GetName() != c.BindScope().GetName() {
+		return nil, "Cannot rebind scope of closure created from method"
+	}
+	return &ClassClosure{class: this, method: c.method}, ""
+}
@@ -9,6 +9,10 @@ func (u *ConstStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 	}
 	// 跳过类型检查，直接赋值
 	ctx.SetVariableValue(u.Val, v.(data.Value))
+	if u.Name != "" {
+		// 同时登记为全局常量，供类常量的初始化器在运行时读取（见 ConstantFetch）；重复定义时保留先定义的值
+		_ = ctx.GetVM().SetConstant(u.Name, v.(data.Value))
+	}
 
 	return v, nil
 }
@@ -18,6 +22,8 @@ type ConstStatement struct {
 	*Node       `pp:"-"`
 	Val         data.Variable
 	Initializer data.GetValue
+	// Name 全局常量名（带命名空间），为空表示不是脚本顶层的常量
+	Name string
 }
 
 // NewConstStatement 创建一个新的常量声明语句
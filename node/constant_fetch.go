@@ -0,0 +1,94 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/php-any/origami/data"
+)
+
+// ConstantFetch 运行时按名称读取全局常量（const 语句或 define() 定义），用于类常量的初始化器：
+// 类常量在解析时求值，此时引用的全局常量可能还没有定义（见 NewLazyConstant）
+type ConstantFetch struct {
+	*Node    `pp:"-"`
+	Name     string // 带命名空间的常量名
+	Fallback string // 命名空间中没有定义时读取的全局常量名，完全限定的名称为空
+}
+
+// NewConstantFetch 创建全局常量读取节点
+func NewConstantFetch(from data.From, name, fallback string) *ConstantFetch {
+	return &ConstantFetch{
+		Node:     NewNode(from),
+		Name:     name,
+		Fallback: fallback,
+	}
+}
+
+// GetValue 读取常量，没有定义时抛出 Error
+func (c *ConstantFetch) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	vm := ctx.GetVM()
+	if v, ok := vm.GetConstant(c.Name); ok {
+		return v, nil
+	}
+	if c.Fallback != "" {
+		if v, ok := vm.GetConstant(c.Fallback); ok {
+			return v, nil
+		}
+	}
+	return nil, data.NewErrorThrowByName(c.GetFrom(), fmt.Errorf("Undefined constant \"%s\"", c.Name), "Error")
+}
+
+// HasConstantFetch 表达式是否读取了全局常量
+func HasConstantFetch(expr data.GetValue) bool {
+	found := false
+	Inspect(expr, func(n data.GetValue) bool {
+		if _, ok := n.(*ConstantFetch); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// lazyConstant 引用了全局常量的类常量：与 PHP 一样在第一次访问时求值，之后保存求得的值
+type lazyConstant struct {
+	expr  data.GetValue
+	ctx   data.Context
+	store *sync.Map
+	name  string
+}
+
+// NewLazyConstant 类常量的初始化器引用了全局常量时，代替求得的值保存到 store 中
+func NewLazyConstant(expr data.GetValue, ctx data.Context, store *sync.Map, name string) data.Value {
+	return &lazyConstant{expr: expr, ctx: ctx, store: store, name: name}
+}
+
+func (l *lazyConstant) GetValue(data.Context) (data.GetValue, data.Control) {
+	return l, nil
+}
+
+func (l *lazyConstant) AsString() string {
+	return ""
+}
+
+// resolve 求值并替换保存的占位值；初始化器抛出的异常（如常量未定义）每次访问都会重新抛出
+func (l *lazyConstant) resolve() (data.Value, data.Control) {
+	v, acl := l.expr.GetValue(l.ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	val, ok := v.(data.Value)
+	if !ok {
+		val = data.NewNullValue()
+	}
+	l.store.CompareAndSwap(l.name, l, val)
+	return val, nil
+}
+
+// staticValue 读取到的静态属性或类常量：尚未求值的类常量在此求值
+func staticValue(v data.Value) (data.Value, data.Control) {
+	if l, ok := v.(*lazyConstant); ok {
+		return l.resolve()
+	}
+	return v, nil
+}
@@ -0,0 +1,151 @@
+package node
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/php-any/origami/data"
+)
+
+// CoroutineSignal 恢复协程时传入的信号
+type CoroutineSignal struct {
+	Value   data.Value   // 作为挂起点表达式的结果（send/resume 传入的值）
+	Throw   data.Control // 非 nil 时在挂起点抛出该异常
+	Destroy bool         // 强制销毁：挂起点返回 DestroyControl，沿调用链展开并执行 finally
+
+	abort bool // 回收不可达的协程：直接结束 goroutine
+}
+
+// CoroutineEvent 协程交还控制权时带出的事件
+type CoroutineEvent struct {
+	Payload any          // 挂起时带出的数据（生成器为 key/value，Fiber 为 suspend 的值）
+	Done    bool         // 协程执行结束
+	Result  data.Value   // 结束时的返回值
+	Control data.Control // 结束时未处理的控制流（异常、exit 等）
+}
+
+// Coroutine 在独立 goroutine 中执行一段代码，并与调用方严格交替运行：
+// 同一时刻只有一方在执行，因此挂起/恢复是确定性的，不会并行。
+// 生成器与 Fiber 都建立在它之上，挂起点可以位于任意深度的控制结构或调用栈中。
+type Coroutine struct {
+	body     func(co *Coroutine) (data.GetValue, data.Control)
+	resumeCh chan CoroutineSignal
+	eventCh  chan CoroutineEvent
+
+	started    bool
+	running    bool
+	finished   bool
+	destroying bool
+}
+
+// NewCoroutine 创建一个尚未启动的协程
+func NewCoroutine(body func(co *Coroutine) (data.GetValue, data.Control)) *Coroutine {
+	return &Coroutine{
+		body:     body,
+		resumeCh: make(chan CoroutineSignal),
+		eventCh:  make(chan CoroutineEvent),
+	}
+}
+
+// IsStarted 是否已经启动
+func (co *Coroutine) IsStarted() bool { return co.started }
+
+// IsRunning 协程当前是否正在执行（调用方正阻塞在 Resume 中）
+func (co *Coroutine) IsRunning() bool { return co.running }
+
+// IsFinished 是否已经执行结束
+func (co *Coroutine) IsFinished() bool { return co.finished }
+
+// IsDestroying 是否处于强制销毁过程中
+func (co *Coroutine) IsDestroying() bool { return co.destroying }
+
+// Resume 启动或恢复协程，阻塞直到协程再次挂起或结束。
+// 首次调用时 sig 会被忽略（协程从头开始执行）。
+func (co *Coroutine) Resume(sig CoroutineSignal) CoroutineEvent {
+	if co.finished {
+		return CoroutineEvent{Done: true}
+	}
+	co.running = true
+	if !co.started {
+		co.started = true
+		go co.run()
+	} else {
+		if sig.Destroy {
+			co.destroying = true
+		}
+		co.resumeCh <- sig
+	}
+	ev := <-co.eventCh
+	co.running = false
+	if ev.Done {
+		co.finished = true
+	}
+	return ev
+}
+
+// Suspend 由协程内部调用：把 payload 交给调用方，并阻塞等待下一次恢复。
+func (co *Coroutine) Suspend(payload any) CoroutineSignal {
+	co.eventCh <- CoroutineEvent{Payload: payload}
+	sig := <-co.resumeCh
+	if sig.abort {
+		runtime.Goexit()
+	}
+	return sig
+}
+
+// Destroy 强制结束一个已挂起的协程：挂起点收到 DestroyControl 后沿调用链展开，
+// 途经的 finally 块会被执行。未启动或已结束的协程直接标记为结束。
+func (co *Coroutine) Destroy() CoroutineEvent {
+	if co.finished || co.running {
+		return CoroutineEvent{Done: true}
+	}
+	if !co.started {
+		co.finished = true
+		return CoroutineEvent{Done: true}
+	}
+	return co.Resume(CoroutineSignal{Destroy: true})
+}
+
+// Abort 回收一个已不可达的挂起协程：直接结束它的 goroutine，不再执行任何脚本代码（包括 finally）。
+// 仅用于垃圾回收场景，确定性的提前结束请使用 Destroy。
+func (co *Coroutine) Abort() {
+	if !co.started || co.finished || co.running {
+		return
+	}
+	co.finished = true
+	co.resumeCh <- CoroutineSignal{abort: true}
+}
+
+func (co *Coroutine) run() {
+	var ev CoroutineEvent
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ev = CoroutineEvent{Done: true, Control: data.NewErrorThrow(nil, fmt.Errorf("协程异常退出的 panic(%v)\nstack: %s", r, debug.Stack()))}
+			}
+		}()
+		v, ctl := co.body(co)
+		ev = CoroutineEvent{Done: true, Control: ctl}
+		if val, ok := v.(data.Value); ok {
+			ev.Result = val
+		}
+	}()
+	if _, ok := ev.Control.(*DestroyControl); ok {
+		// 销毁流程正常走完，不再向调用方传递
+		ev.Control = nil
+	}
+	co.eventCh <- ev
+}
+
+// DestroyControl 协程被强制销毁时由挂起点返回的控制流。
+// 它不是异常，catch 无法捕获，但 finally 会照常执行。
+type DestroyControl struct{}
+
+func (d *DestroyControl) GetValue(_ data.Context) (data.GetValue, data.Control) {
+	return nil, d
+}
+
+func (d *DestroyControl) AsString() string {
+	return "destroy"
+}
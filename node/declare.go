@@ -0,0 +1,85 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/php-any/origami/data"
+)
+
+// strictTypesFiles 记录声明了 declare(strict_types=1) 的源文件。
+// PHP 的 strict_types 作用于整个文件：参数类型按调用方所在文件判断，返回值类型按函数定义所在文件判断。
+var strictTypesFiles sync.Map
+
+// SetStrictTypes 设置源文件的 strict_types 模式
+func SetStrictTypes(file string, strict bool) {
+	if file == "" {
+		return
+	}
+	if strict {
+		strictTypesFiles.Store(file, true)
+	} else {
+		strictTypesFiles.Delete(file)
+	}
+}
+
+// IsStrictTypes 节点所在文件是否声明了 strict_types=1
+func IsStrictTypes(from data.From) bool {
+	if from == nil {
+		return false
+	}
+	_, ok := strictTypesFiles.Load(from.GetSource())
+	return ok
+}
+
+// DeclareStatement 表示 declare(...) 语句，目前只有 strict_types 会影响执行
+type DeclareStatement struct {
+	*Node       `pp:"-"`
+	Directives  map[string]data.Value
+	StrictTypes bool
+}
+
+// NewDeclareStatement 创建 declare 语句，并登记所在文件的 strict_types 模式
+func NewDeclareStatement(from data.From, directives map[string]data.Value) *DeclareStatement {
+	d := &DeclareStatement{
+		Node:       NewNode(from),
+		Directives: directives,
+	}
+	if v, ok := directives["strict_types"]; ok {
+		d.StrictTypes = v.AsString() == "1"
+	}
+	if from != nil {
+		SetStrictTypes(from.GetSource(), d.StrictTypes)
+	}
+	return d
+}
+
+// GetValue 执行时再次登记 strict_types（语法树来自缓存、未经过解析器时同样生效）
+func (d *DeclareStatement) GetValue(_ data.Context) (data.GetValue, data.Control) {
+	if d.from != nil {
+		SetStrictTypes(d.from.GetSource(), d.StrictTypes)
+	}
+	return nil, nil
+}
+
+// argumentTypeError 生成参数类型不匹配的 TypeError：
+// fn(): Argument #1 ($x) must be of type int, string given, called in %s on line %d
+func argumentTypeError(fnName string, position int, p *Parameter, value data.Value, callFrom data.From) data.Control {
+	msg := fmt.Sprintf("Argument #%d ($%s) must be of type %s, %s given", position, p.Name, p.Type.String(), data.TypeNameOf(value))
+	msg += data.LikeMismatch(p.Type, value)
+	if fnName != "" {
+		msg = fnName + "(): " + msg
+	}
+	if callFrom != nil && callFrom.GetSource() != "" {
+		line, _, _, _ := callFrom.GetRange()
+		msg += fmt.Sprintf(", called in %s on line %d", callFrom.GetSource(), line+1)
+	}
+	return data.NewErrorThrowByName(p.from, fmt.Errorf("%s", msg), "TypeError")
+}
+
+// returnTypeError 生成返回值类型不匹配的 TypeError
+func returnTypeError(from data.From, fnName string, ty data.Types, value data.Value) data.Control {
+	msg := fmt.Sprintf("%s(): Return value must be of type %s, %s returned", fnName, ty.String(), data.TypeNameOf(value))
+	msg += data.LikeMismatch(ty, value)
+	return data.NewErrorThrowByName(from, fmt.Errorf("%s", msg), "TypeError")
+}
@@ -0,0 +1,54 @@
+package node
+
+import (
+	"errors"
+
+	"github.com/php-any/origami/data"
+)
+
+// DeferStatement 表示 defer 语句：在当前函数帧登记延迟执行项，函数返回、抛出异常或 exit 时按后进先出执行。
+// Work 在执行时才求值；Work 为闭包字面量（defer function() {...};）时执行时调用该闭包
+type DeferStatement struct {
+	*Node `pp:"-"`
+	Work  data.GetValue
+	Call  bool
+}
+
+// NewDeferStatement 创建一个新的 defer 语句
+func NewDeferStatement(from *TokenFrom, work data.GetValue) *DeferStatement {
+	_, call := work.(*LambdaExpression)
+	return &DeferStatement{
+		Node: NewNode(from),
+		Work: work,
+		Call: call,
+	}
+}
+
+// GetValue 登记延迟执行项，不求值
+func (d *DeferStatement) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	var work data.GetValue = d.Work
+	if d.Call {
+		work = deferredClosure{d}
+	}
+	if !data.PushDefer(ctx, work) {
+		return nil, data.NewErrorThrow(d.from, errors.New("defer 语句只能在脚本执行时使用"))
+	}
+	return nil, nil
+}
+
+// deferredClosure 执行时创建并调用 defer 后的闭包
+type deferredClosure struct {
+	d *DeferStatement
+}
+
+func (c deferredClosure) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	v, acl := c.d.Work.GetValue(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	fv, ok := v.(*data.FuncValue)
+	if !ok {
+		return nil, nil
+	}
+	return data.CallFunc(ctx, fv.Value, nil)
+}
@@ -27,10 +27,13 @@ func (d *DoWhileStatement) GetValue(ctx data.Context) (data.GetValue, data.Contr
 		// 先执行循环体（至少执行一次）
 		for _, statement := range d.Body {
 			v, c = statement.GetValue(ctx)
+			if c == nil {
+				c = data.Safepoint(ctx)
+			}
 			if c != nil {
 				// break 跳出循环
 				if ctrl, ok := c.(data.BreakControl); ok && ctrl.IsBreak() {
-					return v, nil
+					return v, outerBreak(c)
 				}
 				// continue 跳到条件判断
 				if ctrl, ok := c.(data.ContinueControl); ok && ctrl.IsContinue() {
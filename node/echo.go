@@ -30,9 +30,9 @@ func (e *EchoStatement) GetValue(ctx data.Context) (data.GetValue, data.Control)
 		}
 
 		if s, ok := v.(data.Value); ok {
-			data.WriteOutput(s.AsString())
+			data.Output(ctx, s.AsString())
 		} else if v != nil {
-			data.WriteOutput(v.(data.Value).AsString())
+			data.Output(ctx, v.(data.Value).AsString())
 		}
 	}
 
@@ -27,13 +27,16 @@ func (u *UnaryExpression) GetValue(ctx data.Context) (data.GetValue, data.Contro
 
 	switch u.Operator {
 	case "-":
-		if b, ok := right.(data.AsFloat); ok {
-			bv, err := b.AsFloat()
-			if err != nil {
-				return nil, data.NewErrorThrow(u.from, err)
-			}
-			return data.NewFloatValue(-bv), nil
+		// 定义了 __neg 的类实例按运算符重载取反
+		if ret, acl, ok := data.CallUnaryOperator(ctx, "__neg", right); ok {
+			return ret, acl
+		}
+		// PHP 按 $x * -1 计算：-0 仍是 int 0，-PHP_INT_MIN 为 float
+		n, _, ctl := arithOperands(u.from, '*', right, data.NewIntValue(-1))
+		if ctl != nil {
+			return nil, ctl
 		}
+		return data.Negate(n), nil
 	case "!":
 		if b, ok := right.(data.AsBool); ok {
 			bv, err := b.AsBool()
@@ -0,0 +1,221 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/php-any/origami/data"
+)
+
+// IsFirstClassCallable 实参是否为一等可调用语法的 (...)
+func IsFirstClassCallable(args []data.GetValue) bool {
+	if len(args) != 1 {
+		return false
+	}
+	spread, ok := args[0].(*SpreadArgument)
+	return ok && spread.Expr == nil
+}
+
+// FunctionCallable 表示 strlen(...)，得到函数的闭包
+type FunctionCallable struct {
+	*Node     `pp:"-"`
+	Name      string
+	Fun       data.FuncStmt // 解析时已加载的函数，未加载时在运行时按命名空间查找
+	namespace string
+	resolved  atomic.Pointer[data.FuncStmt] `pp:"-"` // 运行时查找到的函数；节点可能被多个协程同时执行，不修改 Fun
+}
+
+func NewFunctionCallable(from data.From, name string, fun data.FuncStmt, namespace string) *FunctionCallable {
+	return &FunctionCallable{
+		Node:      NewNode(from),
+		Name:      name,
+		Fun:       fun,
+		namespace: namespace,
+	}
+}
+
+func (f *FunctionCallable) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	if f.Fun != nil {
+		return data.NewFuncValue(f.Fun), nil
+	}
+	if fn := f.resolved.Load(); fn != nil {
+		return data.NewFuncValue(*fn), nil
+	}
+	vm := ctx.GetVM()
+	fn, ok := vm.GetFunc(f.Name)
+	if !ok && f.namespace != "" {
+		fn, ok = vm.GetFunc(f.namespace + "\\" + f.Name)
+	}
+	if !ok {
+		return nil, data.NewErrorThrow(f.from, fmt.Errorf("Call to undefined function %s()", f.Name))
+	}
+	f.resolved.Store(&fn)
+	return data.NewFuncValue(fn), nil
+}
+
+// MethodCallable 表示 $obj->method(...)，得到绑定了该对象的方法闭包
+type MethodCallable struct {
+	*Node  `pp:"-"`
+	Object data.GetValue
+	Method string
+}
+
+func NewMethodCallable(from data.From, object data.GetValue, method string) *MethodCallable {
+	return &MethodCallable{
+		Node:   NewNode(from),
+		Object: object,
+		Method: method,
+	}
+}
+
+func (m *MethodCallable) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	o, acl := receiverValue(ctx, m.Object)
+	if acl != nil {
+		return nil, acl
+	}
+	return methodClosure(ctx, m.from, o, m.Method)
+}
+
+// ValueCallable 表示 $fn(...)、Foo::bar(...)、self::bar(...)、static::bar(...)，
+// 将被调用的值转为闭包
+type ValueCallable struct {
+	*Node  `pp:"-"`
+	Target data.GetValue
+}
+
+func NewValueCallable(from data.From, target data.GetValue) *ValueCallable {
+	return &ValueCallable{
+		Node:   NewNode(from),
+		Target: target,
+	}
+}
+
+func (c *ValueCallable) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	v, acl := c.Target.GetValue(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	switch fv := v.(type) {
+	case *StaticMethodFuncValue:
+		// Foo::bar(...) 中 static 为 Foo
+		return data.NewFuncValue(&staticMethodFunc{class: fv.class, callClass: fv.class, method: fv.method}), nil
+	case *staticMethodFuncWithLateBinding:
+		return data.NewFuncValue(&staticMethodFunc{class: fv.callClass, callClass: fv.callClass, method: fv.method}), nil
+	case data.Value:
+		return CallableToClosure(ctx, c.from, fv)
+	}
+	return nil, data.NewErrorThrow(c.from, fmt.Errorf("Value of type %s is not callable", data.TypeName(operandValue(v))))
+}
+
+// methodClosure 对象方法的闭包：检查可见性，方法不存在时经 __call 转发
+func methodClosure(ctx data.Context, from data.From, o data.GetValue, name string) (data.GetValue, data.Control) {
+	var object *data.ClassValue
+	switch v := o.(type) {
+	case *data.ThisValue:
+		object = v.ClassValue
+	case *data.ClassValue:
+		object = v
+	case data.GetMethod:
+		// 内置值的方法（如闭包的 bindTo）
+		if method, ok := v.GetMethod(name); ok {
+			return data.NewFuncValue(method), nil
+		}
+		return nil, data.NewErrorThrow(from, fmt.Errorf("Call to undefined method %s::%s()", data.TypeName(operandValue(o)), name))
+	default:
+		return nil, data.NewErrorThrow(from, fmt.Errorf("Call to a member function %s() on %s", name, data.TypeName(operandValue(o))))
+	}
+
+	if method, ok := object.GetMethod(name); ok {
+		if method.GetModifier() != data.ModifierPublic && !data.CallerInClassHierarchy(ctx, object.Class) {
+			declaring := inheritedMethodClass(object, method)
+			if declaring == nil {
+				declaring = object.Class
+			}
+			return nil, data.NewErrorThrow(from, fmt.Errorf("Call to %s method %s::%s() from %s", modifierName(method.GetModifier()), declaring.GetName(), name, scopeDescription(ctx)))
+		}
+		return NewClassClosure(object, name)
+	}
+	if _, ok := object.GetMethod("__call"); ok {
+		data.Escape(object)
+		return data.NewFuncValue(NewObjectMethodCallable(object, name)), nil
+	}
+	return nil, data.NewErrorThrow(from, fmt.Errorf("Call to undefined method %s::%s()", object.Class.GetName(), name))
+}
+
+// CallableToClosure 将 PHP callable（闭包、函数名、"Class::method"、[$obj, 'method']、[Class, 'method']）
+// 转为闭包，供 Closure::fromCallable 使用
+func CallableToClosure(ctx data.Context, from data.From, callable data.Value) (data.GetValue, data.Control) {
+	switch c := callable.(type) {
+	case *data.FuncValue:
+		return c, nil
+	case *data.BoundFuncValue:
+		return c, nil
+	case *data.ClassValue:
+		if _, ok := c.GetMethod("__invoke"); ok {
+			return methodClosure(ctx, from, c, "__invoke")
+		}
+	case *data.ArrayValue:
+		list := c.ToValueList()
+		if len(list) != 2 {
+			break
+		}
+		name := list[1].AsString()
+		switch target := list[0].(type) {
+		case *data.ClassValue, *data.ThisValue:
+			return methodClosure(ctx, from, target, name)
+		case *data.StringValue:
+			return staticMethodClosure(ctx, from, target.Value, name)
+		}
+	case *data.StringValue:
+		if className, method, ok := strings.Cut(c.Value, "::"); ok {
+			return staticMethodClosure(ctx, from, className, method)
+		}
+		if fn, ok := ctx.GetVM().GetFunc(strings.TrimPrefix(c.Value, "\\")); ok {
+			return data.NewFuncValue(fn), nil
+		}
+		return nil, data.NewErrorThrowByName(from, fmt.Errorf("Failed to create closure from callable: function \"%s\" not found or invalid function name", c.Value), "TypeError")
+	}
+	return nil, data.NewErrorThrowByName(from, errors.New("Failed to create closure from callable: no array or string given"), "TypeError")
+}
+
+// staticMethodClosure 静态方法的闭包，沿继承链查找方法，static 为 className 指定的类
+func staticMethodClosure(ctx data.Context, from data.From, className, name string) (data.GetValue, data.Control) {
+	vm := ctx.GetVM()
+	class, acl := vm.GetOrLoadClass(strings.TrimPrefix(className, "\\"))
+	if acl != nil {
+		return nil, acl
+	}
+	if class == nil {
+		return nil, data.NewErrorThrowByName(from, fmt.Errorf("Failed to create closure from callable: class \"%s\" not found", className), "TypeError")
+	}
+	for current := class; current != nil; {
+		method, ok := current.GetMethod(name)
+		if !ok {
+			if getter, isStatic := current.(data.GetStaticMethod); isStatic {
+				method, ok = getter.GetStaticMethod(name)
+			}
+		}
+		if ok {
+			return data.NewFuncValue(&staticMethodFunc{class: current, callClass: class, method: method}), nil
+		}
+		if current.GetExtend() == nil {
+			break
+		}
+		if current, acl = vm.GetOrLoadClass(*current.GetExtend()); acl != nil {
+			return nil, acl
+		}
+	}
+	return nil, data.NewErrorThrowByName(from, fmt.Errorf("Failed to create closure from callable: class %s does not have a method \"%s\"", class.GetName(), name), "TypeError")
+}
+
+func modifierName(m data.Modifier) string {
+	switch m {
+	case data.ModifierPrivate:
+		return "private"
+	case data.ModifierProtected:
+		return "protected"
+	}
+	return "public"
+}
@@ -36,7 +36,7 @@ func (tf *TokenFrom) SetEndPosition(endLine, endChar int) {
 
 // GetFilePath 返回文件路径
 func (tf *TokenFrom) GetFilePath() string {
-	if tf.filePath == nil {
+	if tf == nil || tf.filePath == nil {
 		return ""
 	}
 	return *tf.filePath
@@ -15,10 +15,11 @@ type FunctionStatement struct {
 	Body             []data.GetValue // 函数体
 	vars             []data.Variable // 符号表
 	Ret              data.Types      // 返回值类型
+	Generic          []data.Types    // 类型参数（function map<T, R>(...)）
 	IsGenerator      bool            // 是否是生成器函数（含 yield）
 	ReturnsReference bool            // 是否按引用返回（function &name()）
 	defineCtx        data.Context    // 闭包定义时的上下文（用于保留 self:: 语义）
-	staticLocals     *data.StaticLocals
+	hasStatics       bool            // 函数体内是否声明了 static 局部变量
 }
 
 // NewFunctionStatement 创建一个新的函数定义语句
@@ -31,6 +32,7 @@ func NewFunctionStatement(from data.From, name string, params []data.GetValue, b
 		vars:             vars,
 		Ret:              ret,
 		IsGenerator:      containsYield(body),
+		hasStatics:       containsStatic(body),
 		ReturnsReference: returnsReference,
 	}
 }
@@ -40,48 +42,6 @@ func (f *FunctionStatement) SetDefineCtx(ctx data.Context) {
 	f.defineCtx = ctx
 }
 
-// containsYield 递归检测函数体是否含有 yield 语句
-func containsYield(body []data.GetValue) bool {
-	for _, stmt := range body {
-		if isYieldNode(stmt) {
-			return true
-		}
-	}
-	return false
-}
-
-// isYieldNode 检查节点是否是 yield 语句（只检查直接节点，不深入嵌套函数/闭包）
-func isYieldNode(node data.GetValue) bool {
-	if node == nil {
-		return false
-	}
-	switch n := node.(type) {
-	case *YieldStatement, *YieldFromStatement:
-		return true
-	case *ForeachStatement:
-		return containsYield(n.Body)
-	case *ForStatement:
-		return containsYield(n.Body)
-	case *WhileStatement:
-		return containsYield(n.Body)
-	case *DoWhileStatement:
-		return containsYield(n.Body)
-	case *IfStatement:
-		if containsYield(n.ThenBranch) {
-			return true
-		}
-		for _, elif := range n.ElseIf {
-			if containsYield(elif.ThenBranch) {
-				return true
-			}
-		}
-		if containsYield(n.ElseBranch) {
-			return true
-		}
-	}
-	return false
-}
-
 // GetName 返回函数名
 func (f *FunctionStatement) GetName() string {
 	return f.Name
@@ -113,23 +73,12 @@ func (f *FunctionStatement) GetReturnType() data.Types {
 	return f.Ret
 }
 
-func (f *FunctionStatement) funcStaticLocals() *data.StaticLocals {
-	if f.staticLocals == nil {
-		f.staticLocals = data.NewStaticLocals()
-	}
-	return f.staticLocals
-}
-
-func (f *FunctionStatement) Call(ctx data.Context) (data.GetValue, data.Control) {
-	if b, ok := ctx.(data.StaticLocalsBinder); ok {
-		b.BindStaticLocals(f.funcStaticLocals())
-	}
-
-	// PHP 语义：如果函数是 generator（含 yield），调用时立即返回 Generator 对象，不执行函数体
-	if f.IsGenerator {
-		generator := NewFuncYieldStackState(ctx, f, f.Body, 0, nil, nil)
-		generatorClass := NewGeneratorClass(generator)
-		return generatorClass.GetValue(ctx)
+func (f *FunctionStatement) Call(ctx data.Context) (_ data.GetValue, acl data.Control) {
+	// static 局部变量按执行流隔离
+	var statics *data.StaticLocals
+	if f.hasStatics {
+		statics = data.RoutineOf(ctx).StaticLocals(f)
+		bindStaticLocals(ctx, statics)
 	}
 
 	// 闭包场景：如果定义时的上下文是类方法上下文，保留其 Class 信息以确保 self:: 正确解析
@@ -145,16 +94,32 @@ func (f *FunctionStatement) Call(ctx data.Context) (data.GetValue, data.Control)
 					execCtx.SetIndexZVal(i, zv)
 				}
 			}
-			if b, ok := execCtx.(data.StaticLocalsBinder); ok {
-				b.BindStaticLocals(f.funcStaticLocals())
-			}
+			data.BindRoutine(execCtx, data.RoutineOf(ctx))
+			bindStaticLocals(execCtx, statics)
 		}
 	}
 
+	generics, acl := bindGenerics(execCtx, f.Generic, f.Params, f.Name, callSiteFrom(ctx))
+	if acl != nil {
+		return nil, acl
+	}
+
+	// PHP 语义：如果函数是 generator（含 yield），调用时立即返回 Generator 对象，不执行函数体
+	if f.IsGenerator {
+		return newGeneratorValue(execCtx, f, f.Body)
+	}
+	retType := data.ResolveGenerics(f.Ret, generics)
+	defer statics.Sync(execCtx)
+	defer data.ReleaseFrame(execCtx, &acl)
+	defer data.RunDefers(execCtx, &acl)
+
 	var v data.GetValue
 	var ctl data.Control
-	for bodyIndex, statement := range f.Body {
+	for _, statement := range f.Body {
 		v, ctl = statement.GetValue(execCtx)
+		if ctl == nil {
+			ctl = data.Safepoint(execCtx)
+		}
 		if ctl != nil {
 			switch rv := ctl.(type) {
 			case data.ReturnControl:
@@ -166,26 +131,18 @@ func (f *FunctionStatement) Call(ctx data.Context) (data.GetValue, data.Control)
 						}
 					}
 				}
-				if f.Ret != nil {
-					if f.Ret.Is(ret) {
+				if retType != nil {
+					// 返回值类型按函数定义所在文件的 strict_types 模式检查
+					strict := IsStrictTypes(f.from)
+					if !strict && retType.Is(ret) {
 						return ret, nil
-					} else {
-						return nil, data.NewErrorThrow(f.from, errors.New("函数返回值类型错误"))
 					}
+					if v, ok := data.CoerceType(retType, ret, strict); ok {
+						return v, nil
+					}
+					return nil, returnTypeError(f.from, f.Name, retType, ret)
 				}
 				return ret, nil
-			case data.YieldControl:
-				// 把当前函数的执行状态保存下来; 表示"我不仅有这次 yield 的 key/value，还自带一整套如何构造生成器堆栈状态的逻辑
-				generator := rv.CreateStackState(execCtx, f, f.Body, bodyIndex)
-				// 将生成器包装成类值，支持 $data->valid() 等调用
-				generatorClass := NewGeneratorClass(generator)
-				return generatorClass.GetValue(execCtx)
-			case data.YieldValueControl:
-				// 把当前函数的执行状态保存下来; 表示"这里刚发生了一次 yield，我只告诉你当前的 key/value 和上下文"，但不知道如何把"后续执行状态"组织成生成器。
-				generator := NewFuncYieldStackState(execCtx, f, f.Body, bodyIndex+1, rv.GetYieldKey(), rv.GetYieldValue())
-				// 将生成器包装成类值，支持 $data->valid() 等调用
-				generatorClass := NewGeneratorClass(generator)
-				return generatorClass.GetValue(execCtx)
 			case data.AddStack:
 				if tv, ok := rv.(*data.ThrowValue); ok && tv.PHPUncaughtError {
 					return nil, ctl
@@ -199,23 +156,9 @@ func (f *FunctionStatement) Call(ctx data.Context) (data.GetValue, data.Control)
 		}
 	}
 
-	f.persistStaticLocals(execCtx)
 	return v, nil
 }
 
-func (f *FunctionStatement) persistStaticLocals(ctx data.Context) {
-	store := f.funcStaticLocals()
-	if store == nil {
-		return
-	}
-	for _, v := range f.vars {
-		idx := v.GetIndex()
-		if zv := ctx.GetIndexZVal(idx); zv != nil {
-			store.Update(idx, zv.Value)
-		}
-	}
-}
-
 // Parameter 表示函数参数
 type Parameter struct {
 	*Node        `pp:"-"`
@@ -238,17 +181,69 @@ func (p *Parameter) GetType() data.Types {
 }
 
 func (p *Parameter) SetValue(ctx data.Context, value data.Value) data.Control {
+	return p.BindArgument(ctx, value, "", p.Index+1, nil)
+}
+
+// BindArgument 把实参绑定到形参并检查类型：callFrom 为调用点，其所在文件声明了 strict_types=1 时按严格模式检查，
+// 否则按强制转换模式转换标量；fnName 与 position 用于 TypeError 消息。
+func (p *Parameter) BindArgument(ctx data.Context, value data.Value, fnName string, position int, callFrom data.From) data.Control {
+	v, acl := p.CoerceArgument(value, fnName, position, callFrom)
+	if acl != nil {
+		return acl
+	}
+	return ctx.SetVariableValue(p, v)
+}
+
+// callSiteFrom 调用点的位置：取本次调用记录的实参表达式的位置（与调用点在同一文件），
+// 没有记录实参（无参调用或由标准库回调）时返回 nil，按强制转换模式处理
+func callSiteFrom(ctx data.Context) data.From {
+	for _, arg := range ctx.GetCallArgs() {
+		if from := argumentFrom(arg); from != nil {
+			return from
+		}
+	}
+	return nil
+}
+
+// CoerceArgument 按参数类型检查并转换实参，不写入上下文
+func (p *Parameter) CoerceArgument(value data.Value, fnName string, position int, callFrom data.From) (data.Value, data.Control) {
 	if p.Type == nil {
-		return ctx.SetVariableValue(p, value)
+		return value, nil
 	}
-	// null 可以传递给任何类型的参数（PHP 兼容）
+	strict := IsStrictTypes(callFrom)
 	if _, isNull := value.(*data.NullValue); isNull {
-		return ctx.SetVariableValue(p, value)
+		// 用户函数的参数在两种模式下都只接受可空类型或默认值为 null；
+		// 与 PHP 一致，强制转换模式下内置函数（参数没有源码位置）的参数仍可以传递 null
+		if p.acceptsNull() || (!strict && p.builtin()) {
+			return value, nil
+		}
 	}
-	if p.Type.Is(value) {
-		return ctx.SetVariableValue(p, value)
+	if v, ok := data.CoerceType(p.Type, value, strict); ok {
+		return v, nil
 	}
-	return data.NewErrorThrow(p.from, errors.New("变量类型和赋值类型不一致, 变量类型("+p.Type.String()+"), 赋值("+TryGetCallClassName(value)+")"))
+	return nil, argumentTypeError(fnName, position, p, value, callFrom)
+}
+
+// builtin 参数是否属于标准库中用 Go 实现的函数
+func (p *Parameter) builtin() bool {
+	return p.Node.GetFrom() == nil
+}
+
+// acceptsNull 参数是否可以接受 null（?T、T|null 或默认值为 null）
+func (p *Parameter) acceptsNull() bool {
+	if p.Type.Is(data.NewNullValue()) {
+		return true
+	}
+	if p.DefaultValue != nil {
+		if v, ok := p.DefaultValue.(data.Value); ok {
+			_, isNull := v.(*data.NullValue)
+			return isNull
+		}
+		if _, ok := p.DefaultValue.(*NullLiteral); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // NewParameter 创建一个新的参数
@@ -1,192 +1,375 @@
 package node
 
 import (
+	"errors"
 	"fmt"
+	"runtime"
+	"sync"
+	"weak"
 
 	"github.com/php-any/origami/data"
 )
 
-func NewFuncYieldStackState(ctx data.Context, fn data.FuncStmt, body []data.GetValue, index int, currentKey, currentValue data.Value) data.Generator {
-	generator := &FuncYieldStackState{
-		ctx:          ctx,
-		Fn:           fn,
-		BodyIndex:    index,
-		Body:         body,
-		CurrentKey:   currentKey,
-		CurrentValue: currentValue,
-		autoKeyIndex: 0,
-		initialized:  currentKey != nil || currentValue != nil, // 如果有初始值，表示已执行过第一次 yield
-	}
-	// 如果 currentKey 为 nil（第一次 yield 没有显式 key），分配 autoKey=0
-	if currentKey == nil && currentValue != nil {
-		generator.CurrentKey = data.NewIntValue(0)
-		generator.autoKeyIndex = 1
-	}
-	return generator
-}
-
-type FuncYieldStackState struct {
-	ctx          data.Context
-	Fn           data.FuncStmt
-	BodyIndex    int
-	Body         []data.GetValue
-	CurrentKey   data.Value
-	CurrentValue data.Value
-	autoKeyIndex int
-	initialized  bool // true 表示已执行过第一次 yield（有 CurrentValue）
-}
-
-func (f *FuncYieldStackState) AsString() string {
-	if f.CurrentValue == nil {
-		return "Generator(closed)"
+// generatorCoroutines 记录正在执行的生成器函数体上下文 → 所属协程，
+// yield 语句据此找到需要挂起的生成器（函数体内的语句共享同一个上下文）。
+var generatorCoroutines sync.Map
+
+// generatorYield 生成器挂起时带出的 key/value
+type generatorYield struct {
+	key       data.Value
+	value     data.Value
+	delegated bool // 来自 yield from，不影响外层的自增 key
+}
+
+// currentGeneratorCoroutine 返回 ctx 所属生成器的协程
+func currentGeneratorCoroutine(ctx data.Context) *Coroutine {
+	if co, ok := generatorCoroutines.Load(ctx); ok {
+		return co.(*Coroutine)
 	}
-	return fmt.Sprintf("Generator(%s)", f.Fn.GetName())
+	return nil
 }
 
-func (f *FuncYieldStackState) Current(_ data.Context) (data.Value, data.Control) {
-	if f.CurrentValue == nil {
-		return data.NewNullValue(), nil
+// newGeneratorValue 创建生成器对象；函数体在独立的可恢复帧中执行，第一次被迭代时才开始运行。
+// 函数体含 finally 或 defer 时生成器对象按对象生命周期登记：unset、覆盖变量或所在函数返回后被释放时销毁生成器，
+// 执行挂起点外层的 finally 与延迟执行项
+func newGeneratorValue(ctx data.Context, fn data.FuncStmt, body []data.GetValue) (data.GetValue, data.Control) {
+	frame := NewGeneratorFrame(ctx, fn, body)
+	v, acl := NewGeneratorClass(frame).GetValue(ctx)
+	if cv, ok := v.(*data.ClassValue); ok && generatorNeedsCleanup(body) {
+		frame.release = data.TrackInternal(cv, &generatorDestroyMethod{frame: frame})
+		data.MarkFresh(cv)
+	}
+	return v, acl
+}
+
+// generatorCleanup 函数体（按首条语句区分，同一函数的闭包实例共用）→ 是否含 finally 或 defer
+var generatorCleanup sync.Map
+
+func generatorNeedsCleanup(body []data.GetValue) bool {
+	if len(body) == 0 {
+		return false
+	}
+	if v, ok := generatorCleanup.Load(&body[0]); ok {
+		return v.(bool)
+	}
+	need := containsCleanup(body)
+	generatorCleanup.Store(&body[0], need)
+	return need
+}
+
+// generatorDestroyMethod 生成器对象被释放时调用，相当于生成器的析构函数
+type generatorDestroyMethod struct {
+	frame *GeneratorFrame
+}
+
+func (m *generatorDestroyMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, m.frame.Destroy(ctx)
+}
+
+func (m *generatorDestroyMethod) GetName() string               { return "__destruct" }
+func (m *generatorDestroyMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *generatorDestroyMethod) GetIsStatic() bool             { return false }
+func (m *generatorDestroyMethod) GetParams() []data.GetValue    { return nil }
+func (m *generatorDestroyMethod) GetVariables() []data.Variable { return nil }
+func (m *generatorDestroyMethod) GetReturnType() data.Types     { return nil }
+
+// NewGeneratorFrame 为生成器函数体创建可恢复的执行帧
+func NewGeneratorFrame(ctx data.Context, fn data.FuncStmt, body []data.GetValue) *GeneratorFrame {
+	var from data.From
+	if getFrom, ok := fn.(GetFrom); ok {
+		from = getFrom.GetFrom()
 	}
-	return f.CurrentValue, nil
-}
-
-func (f *FuncYieldStackState) Key(_ data.Context) (data.Value, data.Control) {
-	if f.CurrentKey == nil {
-		return data.NewIntValue(0), nil
-	}
-	return f.CurrentKey, nil
-}
-
-func (f *FuncYieldStackState) Next(_ data.Context) data.Control {
-	ctx := f.ctx
-	// 从 BodyIndex 开始执行剩余的 body
-	for bodyIndex := f.BodyIndex; bodyIndex < len(f.Body); bodyIndex++ {
-		statement := f.Body[bodyIndex]
-
-		var ctl data.Control
-		_, ctl = statement.GetValue(ctx)
-
-		if ctl != nil {
-			switch rv := ctl.(type) {
-			case data.YieldControl:
-				// 判断是否是已经初始化过的 YieldFromControl（即 body 中当前语句就是这个控制流对象本身）
-				if statement == rv {
-					// 已初始化的 yield from：直接取已设置的 key/value
-					key := rv.GetYieldKey()
-					if key == nil {
-						key = data.NewIntValue(f.autoKeyIndex)
-						f.autoKeyIndex++
-					}
-					f.CurrentKey = key
-					f.CurrentValue = rv.GetYieldValue()
-					f.BodyIndex = bodyIndex // 保持在当前位置，下次继续执行这个 yield from
-				} else {
-					// 第一次遇到：调用 CreateStackState 初始化并推进到第一个元素
-					newState := rv.CreateStackState(ctx, f.Fn, f.Body, bodyIndex)
-					if ns, ok := newState.(*FuncYieldStackState); ok {
-						f.Body = ns.Body
-						f.BodyIndex = ns.BodyIndex
-						f.CurrentKey = ns.CurrentKey
-						f.CurrentValue = ns.CurrentValue
-						f.initialized = true
-					}
-				}
-				return nil
-			case data.YieldValueControl:
-				// 遇到 yield，更新键和值
-				key := rv.GetYieldKey()
-				if key == nil {
-					key = data.NewIntValue(f.autoKeyIndex)
-					f.autoKeyIndex++
-				}
-				f.CurrentKey = key
-				f.CurrentValue = rv.GetYieldValue()
-				f.BodyIndex = bodyIndex + 1
-				return nil
-			case data.AddStack:
-				var from data.From
-				if getFrom, ok := f.Fn.(GetFrom); ok {
-					from = getFrom.GetFrom()
-				}
-				rv.AddStackWithInfo(from, "function", f.Fn.GetName())
-				return ctl
-			default:
-				// 其他控制流，直接返回
-				return ctl
+	g := &GeneratorFrame{
+		Fn:   fn,
+		from: from,
+	}
+	g.co = NewCoroutine(func(co *Coroutine) (data.GetValue, data.Control) {
+		generatorCoroutines.Store(ctx, co)
+		defer generatorCoroutines.Delete(ctx)
+		return runGeneratorBody(ctx, fn, from, body)
+	})
+	// 生成器对象不可达时回收挂起中的 goroutine（不再执行任何脚本代码）；含 finally 或 defer 的生成器在此之前
+	// 已按对象生命周期销毁（见 newGeneratorValue），脚本结束时仍存活的生成器由 DestroyGenerators 销毁并执行 finally
+	runtime.SetFinalizer(g, func(g *GeneratorFrame) { g.co.Abort() })
+	trackGenerator(ctx.GetVM(), g)
+	return g
+}
+
+// liveGenerators 已创建的生成器（弱引用，不阻止回收），按创建顺序排列
+var liveGenerators struct {
+	sync.Mutex
+	frames []liveGenerator
+	limit  int // 超过时清理已回收或已结束的项
+}
+
+type liveGenerator struct {
+	vm    data.VM
+	frame weak.Pointer[GeneratorFrame]
+}
+
+func trackGenerator(vm data.VM, g *GeneratorFrame) {
+	liveGenerators.Lock()
+	defer liveGenerators.Unlock()
+	if len(liveGenerators.frames) >= liveGenerators.limit {
+		kept := liveGenerators.frames[:0]
+		for _, l := range liveGenerators.frames {
+			if f := l.frame.Value(); f != nil && !f.co.IsFinished() {
+				kept = append(kept, l)
 			}
 		}
-		f.BodyIndex = bodyIndex + 1
+		clear(liveGenerators.frames[len(kept):])
+		liveGenerators.frames = kept
+		liveGenerators.limit = max(64, 2*len(kept))
 	}
-	f.CurrentKey = nil
-	f.CurrentValue = nil
-	return nil
+	liveGenerators.frames = append(liveGenerators.frames, liveGenerator{vm: vm, frame: weak.Make(g)})
+}
+
+// DestroyGenerators 脚本结束时销毁 vm 中仍挂起的生成器（后创建的先销毁），执行挂起点外层的 finally；
+// 返回第一个未捕获的异常
+func DestroyGenerators(vm data.VM) data.Control {
+	liveGenerators.Lock()
+	var frames []*GeneratorFrame
+	kept := liveGenerators.frames[:0]
+	for _, l := range liveGenerators.frames {
+		f := l.frame.Value()
+		switch {
+		case f == nil:
+		case l.vm == vm:
+			frames = append(frames, f)
+		default:
+			kept = append(kept, l)
+		}
+	}
+	clear(liveGenerators.frames[len(kept):])
+	liveGenerators.frames = kept
+	liveGenerators.Unlock()
+
+	var first data.Control
+	for i := len(frames) - 1; i >= 0; i-- {
+		if ctl := frames[i].Destroy(nil); ctl != nil && first == nil {
+			first = ctl
+		}
+	}
+	return first
 }
 
-func (f *FuncYieldStackState) Rewind(_ data.Context) (data.Value, data.Control) {
-	// PHP 语义：第一次 rewind 时如果未初始化，先执行到第一个 yield
-	if !f.initialized {
-		f.initialized = true
-		ctl := f.Next(f.ctx)
-		return data.NewNullValue(), ctl
+// runGeneratorBody 在协程中执行生成器函数体，return 的值作为 getReturn() 的结果。
+// 函数体执行完毕、抛出异常或被 Destroy 展开后执行 defer 登记的延迟执行项并释放局部变量；被回收（Abort）时不会返回，不再执行脚本代码
+func runGeneratorBody(ctx data.Context, fn data.FuncStmt, from data.From, body []data.GetValue) (data.GetValue, data.Control) {
+	v, acl := runGeneratorStatements(ctx, fn, from, body)
+	data.RunDefers(ctx, &acl)
+	data.ReleaseFrame(ctx, &acl)
+	return v, acl
+}
+
+func runGeneratorStatements(ctx data.Context, fn data.FuncStmt, from data.From, body []data.GetValue) (data.GetValue, data.Control) {
+	for _, statement := range body {
+		_, ctl := statement.GetValue(ctx)
+		if ctl == nil {
+			ctl = data.Safepoint(ctx)
+		}
+		if ctl == nil {
+			continue
+		}
+		switch rv := ctl.(type) {
+		case data.ReturnControl:
+			return rv.ReturnValue(), nil
+		case data.AddStack:
+			if tv, ok := rv.(*data.ThrowValue); ok && tv.PHPUncaughtError {
+				return nil, ctl
+			}
+			if f, ok := statement.(GetFrom); ok {
+				rv.AddStackWithInfo(f.GetFrom(), "generator body", TryGetCallClassName(statement))
+			}
+			rv.AddStackWithInfo(from, "generator", fn.GetName())
+		}
+		return nil, ctl
 	}
 	return data.NewNullValue(), nil
 }
 
-func (f *FuncYieldStackState) Valid(_ data.Context) (data.Value, data.Control) {
-	// 如果未初始化，先执行到第一个 yield
-	if !f.initialized {
-		f.initialized = true
-		ctl := f.Next(f.ctx)
-		if ctl != nil {
-			return data.NewBoolValue(false), ctl
+// GeneratorFrame 生成器的可恢复执行帧。
+// 函数体运行在独立协程中，yield 可以出现在任意嵌套的控制结构（while/if/switch/try/match/嵌套循环）里，
+// send()/throw() 在任意挂起点都能把值或异常送回 yield 表达式。
+type GeneratorFrame struct {
+	Fn   data.FuncStmt
+	from data.From
+	co   *Coroutine
+
+	currentKey   data.Value
+	currentValue data.Value
+	autoKey      int
+	advanced     bool // 已越过第一个 yield，不能再 rewind
+
+	returned    bool // 函数体正常执行完毕（可以 getReturn）
+	returnValue data.Value
+
+	release func() // 按对象生命周期登记时（见 newGeneratorValue）函数体结束后注销，不再需要销毁
+}
+
+func (g *GeneratorFrame) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return NewGeneratorClass(g).GetValue(ctx)
+}
+
+func (g *GeneratorFrame) AsString() string {
+	if g.co.IsFinished() {
+		return "Generator(closed)"
+	}
+	return fmt.Sprintf("Generator(%s)", g.Fn.GetName())
+}
+
+// resume 恢复协程直到下一个 yield 或结束
+func (g *GeneratorFrame) resume(sig CoroutineSignal) data.Control {
+	if g.co.IsRunning() {
+		return data.NewErrorThrowByName(g.from, errors.New("Cannot resume an already running generator"), "Error")
+	}
+	if g.co.IsFinished() {
+		return nil
+	}
+	ev := g.co.Resume(sig)
+	if ev.Done {
+		g.finished()
+		g.currentKey = nil
+		g.currentValue = nil
+		if ev.Control == nil {
+			g.returned = true
+			g.returnValue = ev.Result
 		}
+		return ev.Control
 	}
-	if f.CurrentValue == nil {
-		return data.NewBoolValue(false), nil
+	y := ev.Payload.(generatorYield)
+	key := y.key
+	if key == nil {
+		key = data.NewIntValue(g.autoKey)
+		g.autoKey++
+	} else if iv, ok := key.(*data.IntValue); ok && !y.delegated && iv.Value >= g.autoKey {
+		g.autoKey = iv.Value + 1
 	}
-	return data.NewBoolValue(true), nil
+	g.currentKey = key
+	g.currentValue = y.value
+	return nil
 }
 
-func (f *FuncYieldStackState) Send(_ data.Context, value data.Value) data.Control {
-	// Send 将值保存到 CurrentValue，这样 Current() 可以获取到
-	f.CurrentValue = value
-	// 然后继续执行（类似 Next），这样 yield 语句可以获取到这个值
-	return f.Next(f.ctx) // TODO 赋值语句需要匹配
+// ensureStarted 首次访问时执行到第一个 yield
+func (g *GeneratorFrame) ensureStarted() data.Control {
+	if g.co.IsStarted() {
+		return nil
+	}
+	return g.resume(CoroutineSignal{})
 }
 
-func (f *FuncYieldStackState) Throw(_ data.Context) data.Control {
-	// 抛出异常，清除键和值
-	f.CurrentKey = nil
-	f.CurrentValue = nil
-	var from data.From
-	if getFrom, ok := f.Fn.(GetFrom); ok {
-		from = getFrom.GetFrom()
+// advance 在当前挂起点恢复执行（send/next/throw 共用）
+func (g *GeneratorFrame) advance(sig CoroutineSignal) data.Control {
+	if ctl := g.ensureStarted(); ctl != nil {
+		return ctl
 	}
-	return data.NewErrorThrow(from, fmt.Errorf("生成器被异常终止"))
+	if g.co.IsFinished() {
+		return nil
+	}
+	g.advanced = true
+	return g.resume(sig)
 }
 
-func (f *FuncYieldStackState) GetReturn(_ data.Context) (data.Value, data.Control) {
-	// 执行完所有 body，查找返回值
-	for f.BodyIndex < len(f.Body) {
-		statement := f.Body[f.BodyIndex]
-		f.BodyIndex++
+func (g *GeneratorFrame) Current(_ data.Context) (data.Value, data.Control) {
+	if ctl := g.ensureStarted(); ctl != nil {
+		return nil, ctl
+	}
+	if g.currentValue == nil {
+		return data.NewNullValue(), nil
+	}
+	return g.currentValue, nil
+}
 
-		var ctl data.Control
-		_, ctl = statement.GetValue(f.ctx)
+func (g *GeneratorFrame) Key(_ data.Context) (data.Value, data.Control) {
+	if ctl := g.ensureStarted(); ctl != nil {
+		return nil, ctl
+	}
+	if g.currentKey == nil {
+		return data.NewNullValue(), nil
+	}
+	return g.currentKey, nil
+}
 
-		if ctl != nil {
-			if rv, ok := ctl.(data.ReturnControl); ok {
-				return rv.ReturnValue(), nil
-			}
-			// 其他控制流，继续执行
-		}
+func (g *GeneratorFrame) Next(_ data.Context) data.Control {
+	return g.advance(CoroutineSignal{})
+}
+
+func (g *GeneratorFrame) Rewind(_ data.Context) (data.Value, data.Control) {
+	if ctl := g.ensureStarted(); ctl != nil {
+		return nil, ctl
+	}
+	if g.advanced {
+		return nil, data.NewErrorThrow(g.from, errors.New("Cannot rewind a generator that was already run"))
 	}
-	// 没有找到 return，返回 null
 	return data.NewNullValue(), nil
 }
 
-func (f *FuncYieldStackState) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	// 返回包装成类值的生成器，支持 $data->valid() 等调用
-	generatorClass := NewGeneratorClass(f)
-	return generatorClass.GetValue(ctx)
+func (g *GeneratorFrame) Valid(_ data.Context) (data.Value, data.Control) {
+	if ctl := g.ensureStarted(); ctl != nil {
+		return data.NewBoolValue(false), ctl
+	}
+	return data.NewBoolValue(!g.co.IsFinished()), nil
+}
+
+func (g *GeneratorFrame) Send(_ data.Context, value data.Value) data.Control {
+	if value == nil {
+		value = data.NewNullValue()
+	}
+	return g.advance(CoroutineSignal{Value: value})
+}
+
+func (g *GeneratorFrame) Throw(_ data.Context, exception data.Value) data.Control {
+	throw := throwControlFromValue(g.from, exception)
+	if ctl := g.ensureStarted(); ctl != nil {
+		return ctl
+	}
+	if g.co.IsFinished() {
+		// 已关闭的生成器：异常在调用方抛出
+		return throw
+	}
+	g.advanced = true
+	return g.resume(CoroutineSignal{Throw: throw})
+}
+
+func (g *GeneratorFrame) GetReturn(_ data.Context) (data.Value, data.Control) {
+	if !g.returned {
+		return nil, data.NewErrorThrow(g.from, errors.New("Cannot get return value of a generator that hasn't returned"))
+	}
+	if g.returnValue == nil {
+		return data.NewNullValue(), nil
+	}
+	return g.returnValue, nil
+}
+
+// Destroy 提前销毁生成器：挂起点展开，途经的 finally 块会被执行
+func (g *GeneratorFrame) Destroy(_ data.Context) data.Control {
+	if g.co.IsRunning() {
+		return nil
+	}
+	g.currentKey = nil
+	g.currentValue = nil
+	ctl := g.co.Destroy().Control
+	g.finished()
+	return ctl
+}
+
+// finished 函数体已结束：注销对象生命周期登记
+func (g *GeneratorFrame) finished() {
+	if g.release != nil {
+		g.release()
+		g.release = nil
+	}
+}
+
+// throwControlFromValue 把 throw() 收到的异常对象转换为异常控制流
+func throwControlFromValue(from data.From, v data.Value) data.Control {
+	switch e := v.(type) {
+	case *data.ThrowValue:
+		return e
+	case *data.ClassValue:
+		return data.NewErrorThrowFromClassValue(from, e)
+	case nil:
+		return data.NewErrorThrowByName(from, errors.New("Generator::throw(): Argument #1 ($exception) must be of type Throwable, null given"), "TypeError")
+	}
+	return data.NewErrorThrowByName(from, fmt.Errorf("Generator::throw(): Argument #1 ($exception) must be of type Throwable, %s given", v.AsString()), "TypeError")
 }
@@ -2,6 +2,7 @@ package node
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/php-any/origami/data"
 )
@@ -61,7 +62,8 @@ type VarFastAssign struct {
 
 func (f *VarFastAssign) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	dstZv := ctx.GetIndexZVal(f.DstIdx)
-	if dstZv != nil {
+	// 绑定到带类型属性的引用须经 SetValue 检查类型；覆盖对象或数组须经 SetValue 释放
+	if dstZv != nil && dstZv.Guard == nil && !data.NeedsRelease(dstZv) {
 		switch f.op {
 		case vfaOpCopy:
 			// $dst = $src 或 $dst = IntLiteral（LhsIdx == -1 表示字面量，见 LhsLit）
@@ -83,18 +85,23 @@ func (f *VarFastAssign) GetValue(ctx data.Context) (data.GetValue, data.Control)
 		case vfaOpMul:
 			if li, okL := readIdx(ctx, f.LhsIdx, f.LhsLit); okL {
 				if ri, okR := readIdx(ctx, f.RhsIdx, f.RhsLit); okR {
-					data.AssignIntToZVal(dstZv, li*ri)
-					if iv, ok := dstZv.Value.(*data.IntValue); ok {
-						return iv, nil
+					// 溢出时走降级路径得到 float
+					if p, ok := data.CheckedMul(li, ri); ok {
+						data.AssignIntToZVal(dstZv, p)
+						if iv, ok := dstZv.Value.(*data.IntValue); ok {
+							return iv, nil
+						}
 					}
 				}
 			}
 		case vfaOpAdd:
 			if li, okL := readIdx(ctx, f.LhsIdx, f.LhsLit); okL {
 				if ri, okR := readIdx(ctx, f.RhsIdx, f.RhsLit); okR {
-					data.AssignIntToZVal(dstZv, li+ri)
-					if iv, ok := dstZv.Value.(*data.IntValue); ok {
-						return iv, nil
+					if s, ok := data.CheckedAdd(li, ri); ok {
+						data.AssignIntToZVal(dstZv, s)
+						if iv, ok := dstZv.Value.(*data.IntValue); ok {
+							return iv, nil
+						}
 					}
 				}
 			}
@@ -107,9 +114,11 @@ func (f *VarFastAssign) GetValue(ctx data.Context) (data.GetValue, data.Control)
 		return nil, ctl
 	}
 	if v, ok := rv.(data.Value); ok {
-		if zv := ctx.GetIndexZVal(f.DstIdx); zv != nil && data.IsScalarAssignFast(v) {
-			data.AssignScalarToZVal(zv, v)
-			return zv.Value, nil
+		if data.IsScalarAssignFast(v) {
+			if zv := ctx.GetIndexZVal(f.DstIdx); zv != nil && zv.Guard == nil && !data.NeedsRelease(zv) {
+				data.AssignScalarToZVal(zv, v)
+				return zv.Value, nil
+			}
 		}
 		return v, f.Dst.SetValue(ctx, v)
 	}
@@ -158,7 +167,7 @@ type VarPostIncr struct {
 
 func (f *VarPostIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	if zv := ctx.GetIndexZVal(f.VarIdx); zv != nil {
-		if iv, ok := zv.Value.(*data.IntValue); ok {
+		if iv, ok := zv.Value.(*data.IntValue); ok && iv.Value != math.MaxInt {
 			old := iv                                      // 旧指针直接作为返回值（原始值），无额外分配
 			zv.Value = &data.IntValue{Value: iv.Value + 1} // 仅一次分配
 			syncStaticLocalFromCtx(ctx, f.VarIdx)
@@ -178,7 +187,7 @@ type VarPostDecr struct {
 
 func (f *VarPostDecr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	if zv := ctx.GetIndexZVal(f.VarIdx); zv != nil {
-		if iv, ok := zv.Value.(*data.IntValue); ok {
+		if iv, ok := zv.Value.(*data.IntValue); ok && iv.Value != math.MinInt {
 			old := iv
 			zv.Value = &data.IntValue{Value: iv.Value - 1}
 			syncStaticLocalFromCtx(ctx, f.VarIdx)
@@ -220,8 +229,8 @@ type VarStmtIncr struct {
 
 func (f *VarStmtIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	if zv := ctx.GetIndexZVal(f.VarIdx); zv != nil {
-		if iv, ok := zv.Value.(*data.IntValue); ok {
-			iv.Value++ // 原地改写，0 次分配
+		if iv, ok := zv.Value.(*data.IntValue); ok && iv.Value != math.MaxInt {
+			iv.Value++ // 原地改写，0 次分配；溢出时由 Fallback 得到 float
 			syncStaticLocalFromCtx(ctx, f.VarIdx)
 			return iv, nil
 		}
@@ -313,11 +313,12 @@ type GeneratorThrowMethod struct {
 }
 
 func (m *GeneratorThrowMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	ctl := m.generator.Throw(ctx)
+	exception, _ := ctx.GetIndexValue(0)
+	ctl := m.generator.Throw(ctx, exception)
 	if ctl != nil {
 		return nil, ctl
 	}
-	return data.NewNullValue(), nil
+	return m.generator.Current(ctx)
 }
 
 func (m *GeneratorThrowMethod) GetName() string {
@@ -333,15 +334,19 @@ func (m *GeneratorThrowMethod) GetIsStatic() bool {
 }
 
 func (m *GeneratorThrowMethod) GetParams() []data.GetValue {
-	return []data.GetValue{}
+	return []data.GetValue{
+		NewParameter(nil, "exception", 0, nil, nil),
+	}
 }
 
 func (m *GeneratorThrowMethod) GetVariables() []data.Variable {
-	return []data.Variable{}
+	return []data.Variable{
+		NewVariable(nil, "exception", 0, data.NewBaseType("mixed")),
+	}
 }
 
 func (m *GeneratorThrowMethod) GetReturnType() data.Types {
-	return data.NewBaseType("void")
+	return data.NewBaseType("mixed")
 }
 
 // GeneratorGetReturnMethod 实现 getReturn() 方法
@@ -0,0 +1,110 @@
+package node
+
+import "github.com/php-any/origami/data"
+
+// 泛型的运行期检查：泛型类实例化（Repo<User>）时类型参数映射到类型实参，
+// 泛型函数/方法（function map<T, R>(array<T> $xs)）在调用时根据实参推断类型参数；
+// 参数与返回值按替换后的类型检查。类型映射只存在于本次调用中，不修改共享的声明。
+
+// classTypeMap 方法调用上下文所属泛型类实例化的类型映射
+func classTypeMap(ctx data.Context) map[string]data.Types {
+	cmc, ok := ctx.(*data.ClassMethodContext)
+	if !ok || cmc.ClassValue == nil {
+		return nil
+	}
+	if cg, ok := cmc.Class.(*ClassGeneric); ok {
+		return cg.TypeMap()
+	}
+	return nil
+}
+
+// bindGenerics 检查已绑定到 ctx 的实参是否满足类型参数，返回本次调用的类型映射；
+// 没有涉及类型参数时返回 nil。callFrom 为调用点，决定按 strict_types 的哪种模式检查（错误消息不附带调用点）
+func bindGenerics(ctx data.Context, typeParams []data.Types, params []data.GetValue, fnName string, callFrom data.From) (map[string]data.Types, data.Control) {
+	classMap := classTypeMap(ctx)
+	if len(typeParams) == 0 && len(classMap) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]data.Types, len(classMap)+len(typeParams))
+	for name, t := range classMap {
+		m[name] = t
+	}
+	free := make(map[string]bool, len(typeParams))
+	for _, t := range typeParams {
+		if g, ok := t.(data.Generic); ok {
+			m[g.Name] = g
+			free[g.Name] = true
+		}
+	}
+
+	for i, param := range params {
+		var p *Parameter
+		switch pt := param.(type) {
+		case *Parameter:
+			p = pt
+		case *PromotedParameter:
+			p = pt.Parameter
+		}
+		if p == nil || !data.HasGenerics(p.Type) {
+			continue
+		}
+		v, ok := ctx.GetIndexValue(p.Index)
+		if !ok || v == nil {
+			continue
+		}
+		inferGenerics(p.Type, v, m, free)
+
+		resolved := *p
+		resolved.Type = data.ResolveGenerics(p.Type, m)
+		cv, acl := resolved.CoerceArgument(v, fnName, i+1, callFrom)
+		if acl != nil {
+			name := fnName
+			if c, ok := ctx.(data.GetName); ok {
+				name = c.GetName() + "::" + fnName
+			}
+			return nil, argumentTypeError(name, i+1, &resolved, v, nil)
+		}
+		if cv != v {
+			ctx.SetVariableValue(p, cv)
+			if promoted, ok := param.(*PromotedParameter); ok {
+				if cmc, ok := ctx.(*data.ClassMethodContext); ok {
+					cmc.ObjectValue.SetProperty(promoted.PropertyName, cv)
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// inferGenerics 根据实参推断尚未确定的类型参数：T 取实参的类型，array<T> 取第一个元素的类型；
+// 实参不满足类型参数的约束时不推断，由后续的类型检查报错
+func inferGenerics(ty data.Types, v data.Value, m map[string]data.Types, free map[string]bool) {
+	switch t := ty.(type) {
+	case data.Generic:
+		g, ok := m[t.Name].(data.Generic)
+		if !ok || !free[t.Name] {
+			return
+		}
+		if vt := data.TypeOfValue(v); vt != nil && g.Is(v) {
+			m[t.Name] = vt
+		}
+	case data.NullableType:
+		if _, isNull := v.(*data.NullValue); !isNull {
+			inferGenerics(t.BaseType, v, m, free)
+		}
+	case data.TypedArray:
+		switch arr := v.(type) {
+		case *data.ArrayValue:
+			if len(arr.List) > 0 {
+				inferGenerics(t.Key, data.NewIntValue(0), m, free)
+				inferGenerics(t.Value, arr.List[0].Value, m, free)
+			}
+		case *data.ObjectValue:
+			arr.RangeProperties(func(key string, value data.Value) bool {
+				inferGenerics(t.Key, data.NewStringValue(key), m, free)
+				inferGenerics(t.Value, value, m, free)
+				return false
+			})
+		}
+	}
+}
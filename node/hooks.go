@@ -60,3 +60,8 @@ func checkTimeLimit(from data.From) {
 	}
 	CheckExecutionTimeLimit(file, line)
 }
+
+// CheckTimeLimit 供其他执行引擎（bytecode）在循环的每一轮检查执行时间限制
+func CheckTimeLimit(from data.From) {
+	checkTimeLimit(from)
+}
@@ -20,14 +20,15 @@ func InstantiateController(stmt data.ClassStmt, ctx data.Context) (data.GetValue
 	return cv, nil
 }
 
-// CallHTTPControllerMethod 在已准备好的接收者上调用路由方法（分发阶段不再 new）。
-func CallHTTPControllerMethod(receiver data.GetValue, method data.Method, args []data.Value) (data.GetValue, data.Control) {
+// CallHTTPControllerMethod 在已准备好的接收者上调用路由方法（分发阶段不再 new），ctx 为请求的上下文。
+func CallHTTPControllerMethod(ctx data.Context, receiver data.GetValue, method data.Method, args []data.Value) (data.GetValue, data.Control) {
 	if method.GetIsStatic() {
 		cv, ok := receiver.(*data.ClassValue)
 		if !ok {
 			return nil, data.NewErrorThrow(nil, errors.New("静态路由缺少 ClassValue"))
 		}
 		fnCtx := cv.CreateContext(method.GetVariables())
+		data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 		for i, arg := range args {
 			if i < len(method.GetVariables()) {
 				fnCtx.SetVariableValue(method.GetVariables()[i], arg)
@@ -47,6 +48,7 @@ func CallHTTPControllerMethod(receiver data.GetValue, method data.Method, args [
 	}
 
 	fnCtx := cv.CreateContext(m.GetVariables())
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	for i, arg := range args {
 		if i < len(m.GetVariables()) {
 			fnCtx.SetVariableValue(m.GetVariables()[i], arg)
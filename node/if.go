@@ -45,6 +45,9 @@ func (u *IfStatement) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		// 执行 then 分支
 		for _, statement := range u.ThenBranch {
 			v, c = statement.GetValue(ctx)
+			if c == nil {
+				c = data.Safepoint(ctx)
+			}
 			if c != nil {
 				return nil, c
 			}
@@ -76,6 +79,9 @@ func (u *IfStatement) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 				// 执行 else if 分支
 				for _, statement := range elseIf.ThenBranch {
 					v, c = statement.GetValue(ctx)
+					if c == nil {
+						c = data.Safepoint(ctx)
+					}
 					if c != nil {
 						return nil, c
 					}
@@ -89,6 +95,9 @@ func (u *IfStatement) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		if !executed && len(u.ElseBranch) > 0 {
 			for _, statement := range u.ElseBranch {
 				v, c = statement.GetValue(ctx)
+				if c == nil {
+					c = data.Safepoint(ctx)
+				}
 				if c != nil {
 					return nil, c
 				}
@@ -49,6 +49,7 @@ func callArrayAccessOffsetExists(ctx data.Context, classValue *data.ClassValue,
 		return false, nil
 	}
 	fnCtx := classValue.CreateContext(method.GetVariables())
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	if len(method.GetVariables()) > 0 {
 		fnCtx.SetVariableValue(method.GetVariables()[0], index)
 	}
@@ -184,6 +185,7 @@ func callArrayAccessOffsetGet(ctx data.Context, classValue *data.ClassValue, ind
 
 	// 创建参数上下文
 	fnCtx := classValue.CreateContext(method.GetVariables())
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 
 	// 设置参数值
 	params := method.GetParams()
@@ -314,6 +316,7 @@ func callArrayAccessOffsetUnset(ctx data.Context, classValue *data.ClassValue, i
 		return nil
 	}
 	fnCtx := classValue.CreateContext(method.GetVariables())
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	if len(method.GetVariables()) > 0 {
 		fnCtx.SetVariableValue(method.GetVariables()[0], index)
 	}
@@ -330,6 +333,7 @@ func callArrayAccessOffsetSet(ctx data.Context, classValue *data.ClassValue, ind
 
 	// 创建参数上下文
 	fnCtx := classValue.CreateContext(method.GetVariables())
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 
 	// 设置参数值
 	params := method.GetParams()
@@ -399,6 +403,8 @@ func (ie *IndexExpression) GetZVal(ctx data.Context) (*data.ZVal, data.Control)
 
 	switch v := temp.(type) {
 	case *data.ArrayValue:
+		// 返回的槽位可能被原地修改，先分离共用的存储
+		v.Separate()
 		i := 0
 		switch iv := index.(type) {
 		case *data.IntValue:
@@ -415,11 +421,8 @@ func (ie *IndexExpression) GetZVal(ctx data.Context) (*data.ZVal, data.Control)
 				return data.NewZVal(data.NewNullValue()), nil
 			}
 			// 通过 ZVal.Name 查找字符串键
-			key := iv.AsString()
-			for _, zval := range v.List {
-				if zval.Name == key {
-					return zval, nil
-				}
+			if zval, _ := v.FindSlot(iv.AsString()); zval != nil {
+				return zval, nil
 			}
 			// 未找到，返回 null（PHP 行为）
 			return data.NewZVal(data.NewNullValue()), nil
@@ -427,11 +430,8 @@ func (ie *IndexExpression) GetZVal(ctx data.Context) (*data.ZVal, data.Control)
 			if len(v.List) == 0 {
 				return data.NewZVal(data.NewNullValue()), nil
 			}
-			key := iv.AsString()
-			for _, zval := range v.List {
-				if zval.Name == key {
-					return zval, nil
-				}
+			if zval, _ := v.FindSlot(iv.AsString()); zval != nil {
+				return zval, nil
 			}
 			return data.NewZVal(data.NewNullValue()), nil
 		case data.AsInt:
@@ -474,6 +474,9 @@ func (ie *IndexExpression) GetZVal(ctx data.Context) (*data.ZVal, data.Control)
 }
 
 func (ie *IndexExpression) SetValue(ctx data.Context, value data.Value) data.Control {
+	if acl := checkReadonlyIndexWrite(ctx, ie); acl != nil {
+		return acl
+	}
 	indexVal, acl := ie.Index.GetValue(ctx)
 	if acl != nil {
 		return acl
@@ -490,6 +493,7 @@ func (ie *IndexExpression) SetValue(ctx data.Context, value data.Value) data.Con
 				return ctl
 			}
 		}
+		separateIndexPath(ctx, inner)
 		if v, ok := indexExpressionReadNoWarn(ctx, inner); ok {
 			arrayVal = v
 		} else {
@@ -556,6 +560,7 @@ func (ie *IndexExpression) SetValue(ctx data.Context, value data.Value) data.Con
 		return acl
 	case *data.ArrayValue:
 		// 数组索引赋值
+		arr.Separate()
 		// Handle null index first (before interface type assertions)
 		if _, isNull := indexVal.(*data.NullValue); isNull {
 			// $arr[] = $val（PHP 追加，不触发 null 下标弃用提示）
@@ -573,12 +578,10 @@ func (ie *IndexExpression) SetValue(ctx data.Context, value data.Value) data.Con
 		} else if iv, ok := indexVal.(data.AsString); ok {
 			// 字符串键：查找匹配 Name 的项并更新，找不到则追加
 			key := iv.AsString()
-			for _, zval := range arr.List {
-				if zval != nil && zval.Name == key {
-					zval.Value = value
-					writeBackArrayProperty(ctx, ie.Array, arr)
-					return nil
-				}
+			if zval, _ := arr.FindSlot(key); zval != nil {
+				zval.Value = value
+				writeBackArrayProperty(ctx, ie.Array, arr)
+				return nil
 			}
 			// 未找到，追加新项
 			arr.List = append(arr.List, &data.ZVal{Name: key, Value: value})
@@ -677,6 +680,21 @@ func (ie *IndexExpression) SetValue(ctx data.Context, value data.Value) data.Con
 	}
 }
 
+// separateIndexPath 写入 $a[x][y] 之前，由外向内分离路径上每一层共用存储的数组（写时复制），
+// 外层分离后其中的子数组才成为独立的副本，内层的写入不会影响其它共用者
+func separateIndexPath(ctx data.Context, ie *IndexExpression) {
+	var container data.GetValue
+	if inner, ok := ie.Array.(*IndexExpression); ok {
+		separateIndexPath(ctx, inner)
+		container, _ = indexExpressionReadNoWarn(ctx, inner)
+	} else {
+		container, _ = ie.Array.GetValue(ctx)
+	}
+	if arr, ok := container.(*data.ArrayValue); ok {
+		arr.Separate()
+	}
+}
+
 // GetValue 获取数组访问表达式的值
 func (ie *IndexExpression) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	temp, acl := ie.Array.GetValue(ctx)
@@ -715,10 +733,8 @@ func (ie *IndexExpression) GetValue(ctx data.Context) (data.GetValue, data.Contr
 		case *data.StringValue:
 			// 字符串键：在数组中搜索匹配的 Name
 			key := iv.Value
-			for _, zval := range v.List {
-				if zval != nil && zval.Name == key {
-					return zval.Value, nil
-				}
+			if zval, _ := v.FindSlot(key); zval != nil {
+				return zval.Value, nil
 			}
 			emitUndefinedArrayKeyWarning(ie.GetFrom(), key, false)
 			return data.NewNullValue(), nil
@@ -854,6 +870,9 @@ func assignIndexConcat(ctx data.Context, ie *IndexExpression, dot *BinaryDot) (d
 		rv = data.NewNullValue()
 	}
 
+	if inner, ok := ie.Array.(*IndexExpression); ok {
+		separateIndexPath(ctx, inner)
+	}
 	arrayVal, acl := ie.Array.GetValue(ctx)
 	if acl != nil {
 		if tv, ok := acl.(*data.ThrowValue); ok && tv.Name == "UndefinedIndexExpression" {
@@ -933,10 +952,8 @@ func (ie *IndexExpression) readArrayIndex(ctx data.Context, arr *data.ArrayValue
 	switch iv := index.(type) {
 	case *data.StringValue:
 		key := iv.Value
-		for _, zval := range arr.List {
-			if zval != nil && zval.Name == key {
-				return zval.Value, nil
-			}
+		if zval, _ := arr.FindSlot(key); zval != nil {
+			return zval.Value, nil
 		}
 		emitUndefinedArrayKeyWarning(ie.GetFrom(), key, false)
 		return data.NewNullValue(), nil
@@ -970,6 +987,7 @@ func arrayIntKeyValue(arr *data.ArrayValue, i int) (data.GetValue, bool) {
 func indexSetValueOnContainer(ctx data.Context, ie *IndexExpression, container data.GetValue, indexVal data.GetValue, value data.Value) data.Control {
 	switch arr := container.(type) {
 	case *data.ArrayValue:
+		arr.Separate()
 		if _, isNull := indexVal.(*data.NullValue); isNull {
 			arr.List = append(arr.List, data.NewZVal(value))
 			writeBackArrayProperty(ctx, ie.Array, arr)
@@ -977,12 +995,10 @@ func indexSetValueOnContainer(ctx data.Context, ie *IndexExpression, container d
 		}
 		if iv, ok := indexVal.(data.AsString); ok {
 			key := iv.AsString()
-			for _, zval := range arr.List {
-				if zval != nil && zval.Name == key {
-					zval.Value = value
-					writeBackArrayProperty(ctx, ie.Array, arr)
-					return nil
-				}
+			if zval, _ := arr.FindSlot(key); zval != nil {
+				zval.Value = value
+				writeBackArrayProperty(ctx, ie.Array, arr)
+				return nil
 			}
 			arr.List = append(arr.List, &data.ZVal{Name: key, Value: value})
 			writeBackArrayProperty(ctx, ie.Array, arr)
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"github.com/php-any/origami/data"
+)
+
+// inlineCacheWays 单个调用点最多缓存的类数量，超出后（多态过多）不再写入，回退到按名称查找
+const inlineCacheWays = 4
+
+// classEpoch 类定义的版本号，热重载（TempVM）注册新类时递增，使所有调用点的缓存失效
+var classEpoch atomic.Uint64
+
+// InvalidateInlineCaches 使所有调用点的内联缓存失效，在类的定义可能被替换时调用
+func InvalidateInlineCaches() {
+	classEpoch.Add(1)
+}
+
+// inlineCache 调用点上的内联缓存：以类为键，缓存按名称查找方法、属性声明的结果。
+// AST 节点可能被多个协程同时执行，缓存内容创建后不再修改，更新时整体替换。
+type inlineCache[T any] struct {
+	state atomic.Pointer[inlineCacheState[T]]
+}
+
+type inlineCacheState[T any] struct {
+	epoch   uint64
+	classes [inlineCacheWays]data.ClassStmt
+	values  [inlineCacheWays]T
+	n       int
+}
+
+func (c *inlineCache[T]) lookup(class data.ClassStmt) (T, bool) {
+	if s := c.state.Load(); s != nil && s.epoch == classEpoch.Load() {
+		for i := 0; i < s.n; i++ {
+			if s.classes[i] == class {
+				return s.values[i], true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func (c *inlineCache[T]) store(class data.ClassStmt, value T) {
+	// 不可比较的类实现无法作为键
+	if class == nil || !reflect.TypeOf(class).Comparable() {
+		return
+	}
+	epoch := classEpoch.Load()
+	next := &inlineCacheState[T]{epoch: epoch}
+	if s := c.state.Load(); s != nil && s.epoch == epoch {
+		if s.n >= inlineCacheWays {
+			return
+		}
+		*next = *s
+	}
+	next.classes[next.n] = class
+	next.values[next.n] = value
+	next.n++
+	c.state.Store(next)
+}
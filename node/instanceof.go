@@ -24,7 +24,7 @@ func NewInstanceOfExpression(from data.From, object data.GetValue, className dat
 
 // GetValue 获取 instanceof 表达式的值
 func (i *InstanceOfExpression) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	objectValue, c := i.Object.GetValue(ctx)
+	objectValue, c := receiverValue(ctx, i.Object)
 	if c != nil {
 		return nil, c
 	}
@@ -169,6 +169,14 @@ func instanceof(ctx data.Context, class string, objectValue data.GetValue) (data
 		}
 	}
 
+	// Go 内部按名称抛出的错误（TypeError、DivisionByZeroError 等）与 catch 使用相同的匹配规则
+	if tv, ok := objectValue.(*data.ThrowValue); ok {
+		if tv.Object != nil {
+			return instanceof(ctx, class, tv.Object)
+		}
+		return data.NewBoolValue(data.Class{Name: class}.Is(tv)), nil
+	}
+
 	switch class {
 	case "object":
 		switch objectValue.(type) {
@@ -84,6 +84,7 @@ func isSetViaOffsetExists(ctx data.Context, ie *IndexExpression) (bool, bool) {
 	}
 
 	fnCtx := obj.CreateContext(method.GetVariables())
+	data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 	if len(method.GetVariables()) > 0 {
 		if iv, ok := index.(data.Value); ok {
 			fnCtx.SetVariableValue(method.GetVariables()[0], iv)
@@ -180,10 +181,8 @@ func issetOnContainer(container data.GetValue, index data.GetValue) (isSet bool,
 	case *data.ArrayValue:
 		switch iv := index.(type) {
 		case *data.StringValue:
-			for _, z := range arr.List {
-				if z != nil && z.Name == iv.Value {
-					return z.Value != nil, true
-				}
+			if z, _ := arr.FindSlot(iv.Value); z != nil {
+				return z.Value != nil, true
 			}
 			return false, true
 		case data.AsInt:
@@ -228,15 +227,14 @@ func readIndexNoWarn(container data.GetValue, index data.GetValue) (data.GetValu
 	case *data.ArrayValue:
 		switch iv := index.(type) {
 		case *data.StringValue:
-			for _, z := range arr.List {
-				if z != nil && z.Name == iv.Value {
-					if z.Value == nil {
-						return data.NewNullValue(), true
-					}
-					return z.Value, true
-				}
+			z, _ := arr.FindSlot(iv.Value)
+			if z == nil {
+				return nil, false
 			}
-			return nil, false
+			if z.Value == nil {
+				return data.NewNullValue(), true
+			}
+			return z.Value, true
 		case data.AsInt:
 			i, err := iv.AsInt()
 			if err != nil {
@@ -329,12 +327,8 @@ func indexKeyExistsOnContainer(container data.GetValue, index data.GetValue) boo
 	case *data.ArrayValue:
 		switch iv := index.(type) {
 		case *data.StringValue:
-			for _, z := range arr.List {
-				if z != nil && z.Name == iv.Value {
-					return true
-				}
-			}
-			return false
+			z, _ := arr.FindSlot(iv.Value)
+			return z != nil
 		case data.AsInt:
 			i, err := iv.AsInt()
 			if err != nil {
@@ -9,16 +9,25 @@ type LambdaExpression struct {
 	*FunctionStatement
 	parent map[int]int
 	ctx    data.Context
+	// captured 创建闭包时按值捕获的 use 变量，键为闭包内的变量下标
+	captured map[int]data.Value
+	// Static static function / static fn 声明的闭包，不能绑定 $this
+	Static bool
+	// bound 经 Closure::bind、bindTo、call 重新绑定过，this 与 scope 取代定义时的上下文
+	bound bool
+	this  *data.ClassValue
+	scope data.ClassStmt
 }
 
 // NewLambdaExpression 创建一个新的Lambda表达式
 func NewLambdaExpression(from data.From, params []data.GetValue, body []data.GetValue, vars []data.Variable, parent map[int]int) *LambdaExpression {
 	return &LambdaExpression{
 		FunctionStatement: &FunctionStatement{
-			Node:   NewNode(from),
-			Params: params,
-			Body:   body,
-			vars:   vars,
+			Node:        NewNode(from),
+			Params:      params,
+			Body:        body,
+			vars:        vars,
+			IsGenerator: containsYield(body),
 		},
 		parent: parent,
 	}
@@ -30,31 +39,86 @@ func (f *LambdaExpression) GetParentBindings() map[int]int {
 }
 
 func (f *LambdaExpression) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	// 与 PHP 一致，按值捕获的变量在创建闭包时取值；闭包持有这些值与 $this，
+	// 其中的对象不再归属原变量，定义处的函数返回时也不析构
+	data.ShareVariables(ctx)
+	var captured map[int]data.Value
+	for cID, pID := range f.parent {
+		if _, isRef := f.vars[cID].(*VariableReference); isRef {
+			continue
+		}
+		if v, ok := ctx.GetIndexValue(pID); ok {
+			if captured == nil {
+				captured = make(map[int]data.Value, len(f.parent))
+			}
+			captured[cID] = v
+		}
+	}
+	if cmc, ok := ctx.(*data.ClassMethodContext); ok {
+		data.Escape(cmc.ClassValue)
+	}
 	return data.NewFuncValue(&LambdaExpression{
 		FunctionStatement: &FunctionStatement{
-			Node:   f.Node,
-			Params: f.Params,
-			Body:   f.Body,
-			vars:   f.vars,
+			Node:        f.Node,
+			Params:      f.Params,
+			Body:        f.Body,
+			vars:        f.vars,
+			IsGenerator: f.IsGenerator,
 		},
-		ctx:    ctx,
-		parent: f.parent,
+		ctx:      ctx,
+		parent:   f.parent,
+		captured: captured,
+		Static:   f.Static,
 	}), nil
 }
 
-func (f *LambdaExpression) Call(ctx data.Context) (data.GetValue, data.Control) {
+// BindScope 闭包当前的类作用域：重新绑定过的取绑定的作用域，否则为定义所在的类
+func (f *LambdaExpression) BindScope() data.ClassStmt {
+	if f.bound {
+		return f.scope
+	}
+	switch c := f.ctx.(type) {
+	case *data.ClassMethodContext:
+		if c.SelfClass != nil {
+			return c.SelfClass
+		}
+		return c.Class
+	case *data.BoundContext:
+		if stmt, acl := c.GetVM().GetOrLoadClass(c.ScopeClass); acl == nil {
+			return stmt
+		}
+	}
+	return nil
+}
+
+// Bind 返回绑定到 this 与 scope 的闭包副本，use 捕获的变量与原闭包共享
+func (f *LambdaExpression) Bind(this *data.ClassValue, scope data.ClassStmt) (data.FuncStmt, string) {
+	if f.Static && this != nil {
+		return nil, "Cannot bind an instance to a static closure"
+	}
+	bound := *f
+	bound.bound = true
+	bound.this = this
+	bound.scope = scope
+	return &bound, ""
+}
+
+func (f *LambdaExpression) Call(ctx data.Context) (_ data.GetValue, acl data.Control) {
 	// 为 lambda 创建独立的执行上下文，避免直接复用调用方 ctx 而污染上层环境。
 	var execCtx data.Context
-	if defineClassCtx, ok := f.ctx.(*data.ClassMethodContext); ok {
+	if f.bound {
+		execCtx = f.boundContext(ctx)
+	} else if defineClassCtx, ok := f.ctx.(*data.ClassMethodContext); ok {
 		// 在类方法中定义的 lambda：使用定义时对象创建新的 ClassMethodContext 作为执行上下文，
 		// 以保证 this 语义正确。
 		execCtx = defineClassCtx.ClassValue.CreateContext(f.vars)
+		data.BindRoutine(execCtx, data.RoutineOf(ctx))
 	} else {
 		// 普通场景：基于当前 ctx 再创建一层函数上下文，隔离变量写入。
 		execCtx = ctx.CreateContext(f.vars)
 	}
 	// 保留 BoundContext scope（来自 Closure::bind）以便闭包内可以访问私有成员
-	if bc := getBoundContext(ctx); bc != nil {
+	if bc := getBoundContext(ctx); bc != nil && !f.bound {
 		execCtx = &data.BoundContext{Context: execCtx, ScopeClass: bc.ScopeClass}
 	}
 	// 将调用方 ctx 中已经绑定好的参数 ZVal 复制到新的执行上下文中
@@ -67,7 +131,10 @@ func (f *LambdaExpression) Call(ctx data.Context) (data.GetValue, data.Control)
 
 	// 处理 use 捕获的外部变量：从定义时上下文 f.ctx 读取，写入 execCtx
 	for cID, pID := range f.parent {
-		v, ok := f.ctx.GetIndexValue(pID)
+		v, ok := f.captured[cID]
+		if !ok {
+			v, ok = f.ctx.GetIndexValue(pID)
+		}
 		if !ok {
 			continue
 		}
@@ -86,10 +153,19 @@ func (f *LambdaExpression) Call(ctx data.Context) (data.GetValue, data.Control)
 		}
 	}
 
+	if f.IsGenerator {
+		return newGeneratorValue(execCtx, f, f.Body)
+	}
+	defer data.ReleaseFrame(execCtx, &acl)
+	defer data.RunDefers(execCtx, &acl)
+
 	var v data.GetValue
 	var ctl data.Control
 	for _, statement := range f.Body {
 		v, ctl = statement.GetValue(execCtx)
+		if ctl == nil {
+			ctl = data.Safepoint(execCtx)
+		}
 		if ctl != nil {
 			switch rv := ctl.(type) {
 			case data.ReturnControl:
@@ -110,6 +186,40 @@ func (f *LambdaExpression) Call(ctx data.Context) (data.GetValue, data.Control)
 	return v, nil
 }
 
+// boundContext 重新绑定过的闭包的执行上下文：绑定了对象时 $this 为该对象、self:: 为绑定的作用域，
+// 否则在不含 $this 的上下文中执行，仍可访问绑定作用域的私有成员
+func (f *LambdaExpression) boundContext(ctx data.Context) data.Context {
+	if f.this != nil {
+		execCtx := f.this.CreateContext(f.vars)
+		if cmc, ok := execCtx.(*data.ClassMethodContext); ok && f.scope != nil {
+			cmc.SelfClass = f.scope
+		}
+		data.BindRoutine(execCtx, data.RoutineOf(ctx))
+		return execCtx
+	}
+	execCtx := withoutThis(ctx).CreateContext(f.vars)
+	if f.scope != nil {
+		return &data.BoundContext{Context: execCtx, ScopeClass: f.scope.GetName()}
+	}
+	return execCtx
+}
+
+// withoutThis 去掉上下文链中的对象与绑定作用域，得到普通的函数上下文
+func withoutThis(ctx data.Context) data.Context {
+	for {
+		switch c := ctx.(type) {
+		case *data.ClassMethodContext:
+			ctx = c.ClassValue.Context
+		case *data.ClassValue:
+			ctx = c.Context
+		case *data.BoundContext:
+			ctx = c.Context
+		default:
+			return ctx
+		}
+	}
+}
+
 // getBoundContext 从上下文链中查找 BoundContext（来自 Closure::bind）
 func getBoundContext(ctx data.Context) *data.BoundContext {
 	if bc, ok := ctx.(*data.BoundContext); ok {
@@ -20,10 +20,10 @@ func NewLikeExpression(from data.From, object data.GetValue, className string) *
 	}
 }
 
-// GetValue 获取 like 表达式的值
+// GetValue 获取 like 表达式的值：与 like 类型声明相同的结构化检查
 func (l *LikeExpression) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	// 计算对象表达式的值
-	objectValue, c := l.Object.GetValue(ctx)
+	objectValue, c := receiverValue(ctx, l.Object)
 	if c != nil {
 		return nil, c
 	}
@@ -35,64 +35,13 @@ func (l *LikeExpression) GetValue(ctx data.Context) (data.GetValue, data.Control
 		if acl != nil {
 			return nil, acl
 		}
-		if c != nil {
-			switch checkC := c.(type) {
-			case data.ClassStmt:
-				result := checkClassStructure(classValue.Class, checkC)
-				return data.NewBoolValue(result), nil
-			case data.InterfaceStmt:
-				result := checkInterfaceStructure(classValue.Class, checkC)
-				return data.NewBoolValue(result), nil
-			}
+		switch c.(type) {
+		case data.ClassStmt, data.InterfaceStmt:
+			missing := data.LikeMissing(ctx.GetVM(), classValue.Class, c)
+			return data.NewBoolValue(len(missing) == 0), nil
 		}
 	}
 
 	// 如果不是类实例，返回 false
 	return data.NewBoolValue(false), nil
 }
-
-// checkClassStructure 检查源类是否具有目标类的所有方法（结构化检查）
-func checkClassStructure(source data.ClassStmt, target data.ClassStmt) bool {
-	// 获取目标类的所有方法
-	targetMethods := target.GetMethods()
-
-	// 检查源类是否实现了目标类的所有方法
-	for _, targetMethod := range targetMethods {
-		methodName := targetMethod.GetName()
-		sourceMethod, exists := source.GetMethod(methodName)
-
-		if !exists {
-			return false
-		}
-
-		// 检查方法签名是否匹配（参数数量）
-		if len(sourceMethod.GetParams()) != len(targetMethod.GetParams()) {
-			return false
-		}
-	}
-
-	return true
-}
-
-// checkInterfaceStructure 检查源类是否具有目标接口的所有方法（结构化检查）
-func checkInterfaceStructure(source data.ClassStmt, target data.InterfaceStmt) bool {
-	// 获取目标接口的所有方法
-	targetMethods := target.GetMethods()
-
-	// 检查源类是否实现了目标接口的所有方法
-	for _, targetMethod := range targetMethods {
-		methodName := targetMethod.GetName()
-		sourceMethod, exists := source.GetMethod(methodName)
-
-		if !exists {
-			return false
-		}
-
-		// 检查方法签名是否匹配（参数数量）
-		if len(sourceMethod.GetParams()) != len(targetMethod.GetParams()) {
-			return false
-		}
-	}
-
-	return true
-}
@@ -37,6 +37,9 @@ func (n *Namespace) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	var ctl data.Control
 	for _, stmt := range n.Statements {
 		value, ctl = stmt.GetValue(ctx)
+		if ctl == nil {
+			ctl = data.Safepoint(ctx)
+		}
 		if ctl != nil {
 			return nil, ctl
 		}
@@ -2,6 +2,7 @@ package node
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/php-any/origami/data"
 )
@@ -31,7 +32,7 @@ func createInstanceFromClassStmt(
 	stmt data.ClassStmt,
 	arguments []data.GetValue,
 	ctx data.Context,
-) (data.GetValue, data.Control) {
+) (_ data.GetValue, acl data.Control) {
 	if IsAbstractClassStmt(stmt) {
 		msg := fmt.Sprintf("Uncaught Error: Cannot instantiate abstract class %s", stmt.GetName())
 		return nil, data.NewPHPUncaughtError(from, msg)
@@ -42,6 +43,13 @@ func createInstanceFromClassStmt(
 	}
 
 	if object, ok := object.(*data.ClassValue); ok {
+		// 构造函数执行前登记，构造函数抛出异常时不析构（与 PHP 一致）
+		data.TrackObject(object)
+		defer func() {
+			if acl != nil {
+				data.ForgetObject(object)
+			}
+		}()
 		if method := object.Class.GetConstruct(); method != nil {
 			varies := method.GetVariables()
 			params := method.GetParams()
@@ -56,9 +64,9 @@ func createInstanceFromClassStmt(
 						if err != nil {
 							return nil, data.NewErrorThrow(from, err)
 						}
-						acl = paramSetValue(fnCtx, ctx, object, param, argTV, varies, index, arguments)
+						acl = paramSetValue(stmt.GetName()+"::__construct", fnCtx, ctx, object, param, argTV, varies, index, arguments)
 					default:
-						acl = paramSetValue(fnCtx, ctx, object, param, argTV, varies, index, arguments)
+						acl = paramSetValue(stmt.GetName()+"::__construct", fnCtx, ctx, object, param, argTV, varies, index, arguments)
 					}
 				} else {
 					switch param := param.(type) {
@@ -90,7 +98,8 @@ func createInstanceFromClassStmt(
 	return object, acl
 }
 
-func paramSetValue(fnCtx, ctx, object data.Context, param, argTV data.GetValue, varies []data.Variable, index int, arguments []data.GetValue) data.Control {
+// fnName 为被调用函数的名称，用于参数类型错误消息；参数类型按实参所在文件的 strict_types 模式检查。
+func paramSetValue(fnName string, fnCtx, ctx, object data.Context, param, argTV data.GetValue, varies []data.Variable, index int, arguments []data.GetValue) data.Control {
 	// 处理 nil 实参（包括 nil 指针包装在接口中的情况）
 	if argTV == nil {
 		switch param := param.(type) {
@@ -213,9 +222,13 @@ func paramSetValue(fnCtx, ctx, object data.Context, param, argTV data.GetValue,
 		if index >= len(varies) {
 			return data.NewErrorThrow(nil, fmt.Errorf("对象构造函数参数数量超出限制"))
 		}
-		acl = fnCtx.SetVariableValue(varies[index], tempV.(data.Value))
+		value, acl := param.CoerceArgument(tempV.(data.Value), fnName, param.Index+1, argumentFrom(argTV))
+		if acl != nil {
+			return acl
+		}
+		acl = fnCtx.SetVariableValue(varies[index], value)
 		if acl == nil {
-			acl = param.SetValue(object, tempV.(data.Value))
+			acl = param.SetValue(object, value)
 		}
 		return acl
 	case *ParameterRawAST:
@@ -229,7 +242,7 @@ func paramSetValue(fnCtx, ctx, object data.Context, param, argTV data.GetValue,
 		if tempV == nil {
 			return nil
 		}
-		return param.SetValue(fnCtx, tempV.(data.Value))
+		return param.BindArgument(fnCtx, tempV.(data.Value), fnName, param.Index+1, argumentFrom(argTV))
 	case data.Variable:
 		tempV, acl := argTV.GetValue(ctx)
 		if acl != nil {
@@ -248,13 +261,20 @@ func createInstanceAndCallConstructorWithStmt(
 	stmt data.ClassStmt,
 	arguments []data.GetValue,
 	ctx data.Context,
-) (data.GetValue, data.Control) {
+) (_ data.GetValue, acl data.Control) {
 	object, acl := stmt.GetValue(ctx.CreateBaseContext())
 	if acl != nil {
 		return nil, acl
 	}
 
 	if object, ok := object.(*data.ClassValue); ok {
+		// 构造函数执行前登记，构造函数抛出异常时不析构（与 PHP 一致）
+		data.TrackObject(object)
+		defer func() {
+			if acl != nil {
+				data.ForgetObject(object)
+			}
+		}()
 		if method := object.Class.GetConstruct(); method != nil {
 			varies := method.GetVariables()
 			params := method.GetParams()
@@ -430,10 +450,17 @@ func (n *NewClassGenerated) resolveClass(ctx data.Context) (data.ClassStmt, data
 	mT := make(map[string]data.Types)
 	if classGeneric, ok := stmt.(data.ClassGeneric); ok {
 		for i, types := range classGeneric.GenericList() {
+			if i >= len(n.T) {
+				break
+			}
 			newType := n.T[i]
 			switch t := types.(type) {
 			case data.Generic:
-				mT[t.Name] = data.NewBaseType(newType)
+				arg := data.NewBaseType(newType)
+				if t.Bound != nil && !data.SatisfiesBound(ctx.GetVM(), arg, t.Bound) {
+					return nil, data.NewErrorThrowByName(n.from, fmt.Errorf("%s<%s>: type argument %s for %s must be a subtype of %s", n.ClassName, strings.Join(n.T, ", "), newType, t.Name, t.Bound.String()), "TypeError")
+				}
+				mT[t.Name] = arg
 			default:
 				panic("TODO 未支持的泛型类型")
 			}
@@ -602,3 +629,11 @@ func (n *NewExpressionDynamic) GetValue(ctx data.Context) (data.GetValue, data.C
 	}
 	return createInstanceAndCallConstructor(n.from, className, n.Arguments, ctx)
 }
+
+// argumentFrom 实参表达式所在位置，用于判断调用方文件的 strict_types 模式
+func argumentFrom(arg data.GetValue) data.From {
+	if f, ok := arg.(GetFrom); ok {
+		return f.GetFrom()
+	}
+	return nil
+}
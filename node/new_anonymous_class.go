@@ -26,7 +26,7 @@ func NewNewAnonymousClassExpression(from data.From, classStmt data.ClassStmt, ar
 
 // GetValue 实现 Value 接口
 // 在执行阶段才注册类、实例化对象并调用构造函数
-func (n *NewAnonymousClassExpression) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+func (n *NewAnonymousClassExpression) GetValue(ctx data.Context) (_ data.GetValue, acl data.Control) {
 	// 处理泛型
 	var classStmt data.ClassStmt = n.ClassStmt
 	if n.GenericTypes != nil {
@@ -50,6 +50,12 @@ func (n *NewAnonymousClassExpression) GetValue(ctx data.Context) (data.GetValue,
 
 	// 如果有构造函数，调用构造函数
 	if object, ok := object.(*data.ClassValue); ok {
+		data.TrackObject(object)
+		defer func() {
+			if acl != nil {
+				data.ForgetObject(object)
+			}
+		}()
 		if method := object.Class.GetConstruct(); method != nil {
 			varies := method.GetVariables()
 			fnCtx := object.CreateContext(varies)
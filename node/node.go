@@ -22,6 +22,9 @@ func NewNode(from data.From) *Node {
 
 // GetFrom 返回节点的来源
 func (n *Node) GetFrom() data.From {
+	if n == nil {
+		return nil
+	}
 	return n.from
 }
 
@@ -50,6 +53,9 @@ func (p *Program) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	for offset, statement := range p.Statements {
 		checkTimeLimit(fromForStatement(p, statement))
 		v, c = statement.GetValue(ctx)
+		if c == nil {
+			c = data.Safepoint(ctx)
+		}
 		if c != nil {
 			switch acl := c.(type) {
 			case data.ReturnControl:
@@ -105,6 +111,9 @@ func (p *Program) runLabel(ctx data.Context, label LabelControl) (data.GetValue,
 	for offset := label.Offset; offset < len(p.Statements); offset++ {
 		statement := p.Statements[offset]
 		v, c = statement.GetValue(ctx)
+		if c == nil {
+			c = data.Safepoint(ctx)
+		}
 		if c != nil {
 			switch acl := c.(type) {
 			case data.ReturnControl:
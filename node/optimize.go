@@ -0,0 +1,424 @@
+package node
+
+import (
+	"reflect"
+
+	"github.com/php-any/origami/data"
+)
+
+// 语法树优化：解析完成后、执行之前对语法树做一次改写
+//   - 折叠纯常量表达式："a" . "b"、2 * 60 * 60、!true、1 < 2 ? 'x' : 'y'
+//   - 内联值为标量的全局常量（const / define）与类常量（self::A、Foo::A）
+//   - 删除条件为常量的 if 中不会执行的分支
+// 折叠得到的字面量沿用原表达式的 From；求值出错（如除零）的表达式不折叠，错误仍在运行时原位置报告。
+
+var getValueSliceTy = reflect.TypeOf([]data.GetValue(nil))
+
+// Optimize 优化 program 以及同一文件中声明的类的方法体，直接修改语法树
+func Optimize(vm data.VM, program *Program, classes []data.ClassStmt) {
+	if program == nil {
+		return
+	}
+	o := &optimizer{
+		ctx:    vm.CreateContext(nil),
+		done:   map[data.GetValue]data.GetValue{},
+		consts: map[int]data.Value{},
+		counts: map[string]int{},
+	}
+	InspectList(program.Statements, func(n data.GetValue) bool {
+		if c, ok := n.(*ConstStatement); ok && c.Val != nil {
+			o.counts[c.Val.GetName()]++
+		}
+		return true
+	})
+	program.Statements = o.list(program.Statements, true)
+	for _, c := range classes {
+		o.class(c)
+	}
+}
+
+type optimizer struct {
+	ctx    data.Context
+	done   map[data.GetValue]data.GetValue // 已处理的节点及其替换结果，共享的子树只处理一次
+	consts map[int]data.Value              // 顶层已声明、可以内联的全局常量：变量索引 -> 值
+	counts map[string]int                  // 常量声明次数，重复声明（多次 define）的常量不内联
+	owner  *ClassStatement                 // 当前方法所属的类，用于内联 self::常量
+	depth  int                             // 函数、类的嵌套深度，0 表示文件顶层
+}
+
+// list 优化语句列表；被删除分支的 if 替换为保留分支中的语句。
+// top 为 true 表示文件顶层（含命名空间内）的语句，按顺序登记其中的常量声明
+func (o *optimizer) list(stmts []data.GetValue, top bool) []data.GetValue {
+	out := make([]data.GetValue, 0, len(stmts))
+	for _, stmt := range stmts {
+		if ns, ok := stmt.(*Namespace); ok && top {
+			ns.Statements = o.list(ns.Statements, true)
+			out = append(out, ns)
+			continue
+		}
+		r := o.rewrite(stmt)
+		if _, wasIf := stmt.(*IfStatement); wasIf {
+			if block, ok := r.(*BlockStatement); ok {
+				out = append(out, block.Statements...)
+				continue
+			}
+		}
+		if c, ok := r.(*ConstStatement); ok && top && o.depth == 0 {
+			o.declareConst(c)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// declareConst 登记初始化值为字面量、只声明一次的顶层常量，后续语句中对它的引用直接替换为值
+func (o *optimizer) declareConst(c *ConstStatement) {
+	if c.Val == nil || o.counts[c.Val.GetName()] != 1 {
+		return
+	}
+	if v, ok := literalValue(c.Initializer); ok {
+		o.consts[c.Val.GetIndex()] = v
+	}
+}
+
+// rewrite 先优化子节点，再尝试折叠节点本身，返回替换后的节点
+func (o *optimizer) rewrite(n data.GetValue) data.GetValue {
+	if n == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(n)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct || rv.Elem().Type().PkgPath() != nodePkgPath {
+		return n
+	}
+	if c, ok := n.(data.ClassStmt); ok {
+		o.class(c)
+		return n
+	}
+	if r, ok := o.done[n]; ok {
+		return r
+	}
+	o.done[n] = n
+	switch n.(type) {
+	case *FunctionStatement, *LambdaExpression:
+		o.depth++
+		defer func() { o.depth-- }()
+	}
+	o.fields(rv.Elem())
+
+	r := o.fold(n)
+	o.done[n] = r
+	return r
+}
+
+func (o *optimizer) fields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("pp") == "-" {
+			continue
+		}
+		o.field(v.Field(i))
+	}
+}
+
+// field 优化一个字段：data.GetValue 字段与 []data.GetValue 字段写回替换结果，其余字段只向下遍历
+func (o *optimizer) field(fv reflect.Value) {
+	switch fv.Type() {
+	case getValueIfaceTy:
+		if fv.IsNil() {
+			return
+		}
+		n := fv.Interface().(data.GetValue)
+		if r := o.rewrite(n); r != n && fv.CanSet() {
+			fv.Set(reflect.ValueOf(&r).Elem())
+		}
+		return
+	case getValueSliceTy:
+		if fv.Len() == 0 || !fv.CanSet() {
+			return
+		}
+		fv.Set(reflect.ValueOf(o.list(fv.Interface().([]data.GetValue), false)))
+		return
+	}
+	switch fv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if fv.IsNil() || !fv.CanInterface() {
+			return
+		}
+		if n, ok := fv.Interface().(data.GetValue); ok {
+			o.rewrite(n)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			o.field(fv.Index(i))
+		}
+	case reflect.Struct:
+		if fv.Type().PkgPath() == nodePkgPath {
+			o.fields(fv)
+		}
+	}
+}
+
+// class 优化类中声明的方法；从 trait 合并进来的方法由 trait 自己的 self 决定，不在这里处理
+func (o *optimizer) class(c data.ClassStmt) {
+	var cs *ClassStatement
+	switch s := c.(type) {
+	case *ClassStatement:
+		cs = s
+	case *AbstractClassStatement:
+		cs = s.ClassStatement
+	case *ClassGeneric:
+		cs = s.ClassStatement
+	}
+	if cs == nil {
+		return
+	}
+	if _, ok := o.done[cs]; ok {
+		return
+	}
+	o.done[cs] = cs
+
+	owner := o.owner
+	o.owner = cs
+	o.depth++
+	for _, methods := range []map[string]data.Method{cs.Methods, cs.StaticMethods} {
+		for _, m := range methods {
+			if cm, ok := m.(*ClassMethod); ok && declaredIn(cm, cs) {
+				o.rewrite(cm)
+			}
+		}
+	}
+	o.depth--
+	o.owner = owner
+}
+
+// declaredIn 方法是否写在类的声明中
+func declaredIn(m *ClassMethod, c *ClassStatement) bool {
+	mf, cf := m.GetFrom(), c.GetFrom()
+	if mf == nil || cf == nil || mf.GetSource() != cf.GetSource() {
+		return false
+	}
+	ms, me := mf.GetPosition()
+	cs, ce := cf.GetPosition()
+	return ms >= cs && me <= ce
+}
+
+// fold 折叠节点本身，子节点已经优化过
+func (o *optimizer) fold(n data.GetValue) data.GetValue {
+	switch e := n.(type) {
+	case *IfStatement:
+		return o.foldIf(e)
+	case *TernaryExpression:
+		if v, ok := literalValue(e.Condition); ok {
+			if ternaryTruth(v) {
+				return e.TrueValue
+			}
+			return e.FalseValue
+		}
+	case *VariableExpression:
+		if _, ok := e.Type.(data.Const); ok && o.depth == 0 {
+			if v, ok := o.consts[e.Index]; ok {
+				return newLiteral(e.GetFrom(), v)
+			}
+		}
+	case *CallStaticProperty:
+		if c, ok := e.Stmt.(classConstant); ok {
+			if v, ok := c.constValue(e.Property); ok {
+				return newLiteral(e.GetFrom(), v)
+			}
+		}
+	case *CallSelfProperty:
+		if o.owner != nil {
+			if v, ok := o.owner.constValue(e.Property); ok {
+				return newLiteral(e.GetFrom(), v)
+			}
+		}
+	case *CallStaticPropertyLater:
+		// 类自身的方法中以类名访问常量时，类在解析方法体时还未注册
+		if o.owner != nil && e.className == o.owner.Name {
+			if v, ok := o.owner.constValue(e.property); ok {
+				return newLiteral(e.GetFrom(), v)
+			}
+		}
+	case *UnaryExpression, *BinaryAdd, *BinarySub, *BinaryMul, *BinaryQuo, *BinaryRem, *BinaryPow,
+		*BinaryDot, *BinaryEq, *BinaryEqStrict, *BinaryNe, *BinaryNeStrict, *BinaryLt, *BinaryLe,
+		*BinaryGt, *BinaryGe, *BinarySpaceship, *BinaryLand, *BinaryLor,
+		*BinaryBitAnd, *BinaryBitOr, *BinaryBitXor, *BinaryShl, *BinaryShr:
+		if !literalOperands(n) {
+			return n
+		}
+		v, acl := n.GetValue(o.ctx)
+		if acl != nil {
+			return n
+		}
+		if val, ok := v.(data.Value); ok && isScalar(val) {
+			return newLiteral(n.(GetFrom).GetFrom(), val)
+		}
+	}
+	return n
+}
+
+// foldIf 删除条件为常量 false 的分支；遇到条件为常量 true 的分支时，它成为最后的 else，之后的分支都删除。
+// 所有条件都是常量时返回只包含执行分支语句的代码块；被删除的分支中有 goto 标签时不做处理
+func (o *optimizer) foldIf(s *IfStatement) data.GetValue {
+	branches := append([]ElseIfBranch{{Condition: s.Condition, ThenBranch: s.ThenBranch}}, s.ElseIf...)
+	kept := make([]ElseIfBranch, 0, len(branches))
+	elseBranch := s.ElseBranch
+	var dropped [][]data.GetValue
+	for i, b := range branches {
+		truth, known := ifTruth(b.Condition)
+		if !known {
+			kept = append(kept, b)
+			continue
+		}
+		if !truth {
+			dropped = append(dropped, b.ThenBranch)
+			continue
+		}
+		for _, rest := range branches[i+1:] {
+			dropped = append(dropped, rest.ThenBranch)
+		}
+		dropped = append(dropped, elseBranch)
+		elseBranch = b.ThenBranch
+		break
+	}
+	if len(kept) == len(branches) {
+		return s
+	}
+	for _, body := range dropped {
+		if hasLabel(body) {
+			return s
+		}
+	}
+	if len(kept) == 0 {
+		return &BlockStatement{Node: NewNode(s.GetFrom()), Statements: elseBranch}
+	}
+	s.Condition, s.ThenBranch = kept[0].Condition, kept[0].ThenBranch
+	s.ElseIf = kept[1:]
+	s.ElseBranch = elseBranch
+	return s
+}
+
+func hasLabel(body []data.GetValue) bool {
+	found := false
+	InspectList(body, func(n data.GetValue) bool {
+		if _, ok := n.(*LabelStatement); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// ifTruth 字面量条件按 if 语句的规则转换为布尔值
+func ifTruth(cond data.GetValue) (truth bool, known bool) {
+	v, ok := literalValue(cond)
+	if !ok {
+		return false, false
+	}
+	b, ok := v.(data.AsBool)
+	if !ok {
+		return false, false
+	}
+	truth, err := b.AsBool()
+	return truth, err == nil
+}
+
+// ternaryTruth 与 TernaryExpression.GetValue 的条件转换保持一致
+func ternaryTruth(v data.Value) bool {
+	switch c := v.(type) {
+	case *data.BoolValue:
+		return c.Value
+	case *data.IntValue:
+		return c.Value != 0
+	case *data.FloatValue:
+		return c.Value != 0
+	case *data.StringValue:
+		return len(c.Value) > 0
+	}
+	return false
+}
+
+// literalOperands 节点的操作数（data.GetValue 字段）是否都是字面量
+func literalOperands(n data.GetValue) bool {
+	v := reflect.ValueOf(n).Elem()
+	t := v.Type()
+	count := 0
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() || t.Field(i).Type != getValueIfaceTy {
+			continue
+		}
+		operand, _ := v.Field(i).Interface().(data.GetValue)
+		val, ok := literalValue(operand)
+		if !ok {
+			return false
+		}
+		// "12abc" 参与算术运算时每次执行都要输出 Warning，不能在编译期折叠
+		if str, isStr := val.(*data.StringValue); isStr {
+			if _, kind := data.ToNumber(str); kind == data.NumberLeading {
+				return false
+			}
+		}
+		count++
+	}
+	return count > 0
+}
+
+// literalValue 字面量节点的值
+func literalValue(n data.GetValue) (data.Value, bool) {
+	switch l := n.(type) {
+	case *IntLiteral:
+		return l.V, true
+	case *FloatLiteral:
+		return l.V, true
+	case *StringLiteral:
+		return data.NewStringValue(l.Value), true
+	case *BooleanLiteral:
+		return data.NewBoolValue(l.Value), true
+	case *NullLiteral:
+		return data.NewNullValue(), true
+	}
+	return nil, false
+}
+
+func isScalar(v data.Value) bool {
+	switch v.(type) {
+	case *data.IntValue, *data.FloatValue, *data.StringValue, *data.BoolValue, *data.NullValue:
+		return true
+	}
+	return false
+}
+
+// newLiteral 用值创建字面量节点，数值每次新建，避免多个字面量共享同一个值
+func newLiteral(from data.From, v data.Value) data.GetValue {
+	switch val := v.(type) {
+	case *data.IntValue:
+		return &IntLiteral{Node: NewNode(from), V: data.NewIntValue(val.Value)}
+	case *data.FloatValue:
+		return &FloatLiteral{Node: NewNode(from), V: data.NewFloatValue(val.Value)}
+	case *data.StringValue:
+		return &StringLiteral{Node: NewNode(from), Value: val.Value}
+	case *data.BoolValue:
+		return &BooleanLiteral{Node: NewNode(from), Value: val.Value}
+	default:
+		return &NullLiteral{Node: NewNode(from)}
+	}
+}
+
+// classConstant 可以在优化时读取类常量值的类声明
+type classConstant interface {
+	constValue(name string) (data.Value, bool)
+}
+
+// constValue 类自身声明的、值为标量的类常量；继承来的常量不处理
+func (c *ClassStatement) constValue(name string) (data.Value, bool) {
+	p, ok := c.StaticProperties[name].(*ClassProperty)
+	if !ok || !p.IsConst {
+		return nil, false
+	}
+	v, ok := c.GetStaticProperty(name)
+	if !ok || !isScalar(v) {
+		return nil, false
+	}
+	return v, true
+}
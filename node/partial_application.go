@@ -0,0 +1,119 @@
+package node
+
+import (
+	"errors"
+
+	"github.com/php-any/origami/data"
+)
+
+// Placeholder 部分应用中的占位实参 ?，如 str_replace('a', 'b', ?)
+type Placeholder struct {
+	*Node `pp:"-"`
+}
+
+func NewPlaceholder(from data.From) *Placeholder {
+	return &Placeholder{Node: NewNode(from)}
+}
+
+// GetValue 占位实参只在部分应用中出现，不会被直接求值
+func (p *Placeholder) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, data.NewErrorThrow(p.from, errors.New("Cannot use placeholder ? outside of a function call"))
+}
+
+// HasPlaceholder 实参中是否含有占位实参 ?
+func HasPlaceholder(args []data.GetValue) bool {
+	for _, arg := range args {
+		if _, ok := arg.(*Placeholder); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PartialApplication 表示 f('a', ?, 'c')：创建时求出非占位实参的值，得到按顺序接收占位实参的闭包
+type PartialApplication struct {
+	*Node    `pp:"-"`
+	Callable data.GetValue // 被部分应用的可调用值，如 FunctionCallable、MethodCallable
+	Args     []data.GetValue
+}
+
+func NewPartialApplication(from data.From, callable data.GetValue, args []data.GetValue) *PartialApplication {
+	return &PartialApplication{
+		Node:     NewNode(from),
+		Callable: callable,
+		Args:     args,
+	}
+}
+
+func (p *PartialApplication) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	cv, acl := p.Callable.GetValue(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	fn, acl := callableFunc(ctx, p.from, cv)
+	if acl != nil {
+		return nil, acl
+	}
+
+	partial := &partialFunc{fn: fn}
+	params := fn.GetParams()
+	for _, arg := range p.Args {
+		switch a := arg.(type) {
+		case *Placeholder:
+			index := len(partial.holes)
+			name := "arg"
+			if pos := len(partial.args); pos < len(params) {
+				if named, ok := params[pos].(data.GetName); ok {
+					name = named.GetName()
+				}
+			}
+			partial.holes = append(partial.holes, len(partial.args))
+			partial.params = append(partial.params, data.NewParameter(name, index))
+			partial.vars = append(partial.vars, data.NewVariable(name, index, nil))
+			partial.args = append(partial.args, nil)
+		case *NamedArgument:
+			return nil, data.NewErrorThrow(p.from, errors.New("Named arguments are not supported in partial application"))
+		case *SpreadArgument:
+			v, acl := a.Expr.GetValue(ctx)
+			if acl != nil {
+				return nil, acl
+			}
+			if arr, ok := v.(*data.ArrayValue); ok {
+				partial.args = append(partial.args, arr.ToValueList()...)
+			}
+		default:
+			v, acl := arg.GetValue(ctx)
+			if acl != nil {
+				return nil, acl
+			}
+			partial.args = append(partial.args, operandValue(v))
+		}
+	}
+	return data.NewFuncValue(partial), nil
+}
+
+// partialFunc 部分应用得到的闭包：调用时依次填入占位实参
+type partialFunc struct {
+	fn     data.FuncStmt
+	args   []data.Value // 已求值的实参，占位位置为 nil
+	holes  []int        // 占位实参在 args 中的位置
+	params []data.GetValue
+	vars   []data.Variable
+}
+
+func (f *partialFunc) GetName() string               { return f.fn.GetName() }
+func (f *partialFunc) GetParams() []data.GetValue    { return f.params }
+func (f *partialFunc) GetVariables() []data.Variable { return f.vars }
+
+func (f *partialFunc) Call(ctx data.Context) (data.GetValue, data.Control) {
+	args := make([]data.Value, len(f.args))
+	copy(args, f.args)
+	for i, pos := range f.holes {
+		v, ok := ctx.GetIndexValue(i)
+		if !ok || v == nil {
+			return nil, data.NewErrorThrowByName(nil, errors.New("Too few arguments to partially applied function "+f.fn.GetName()+"()"), "ArgumentCountError")
+		}
+		args[pos] = v
+	}
+	return data.CallFunc(ctx, f.fn, args)
+}
@@ -0,0 +1,61 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/php-any/origami/data"
+)
+
+// PipeExpression 表示管道运算符 $x |> f(...)：以左侧的值为唯一实参调用右侧的可调用值
+type PipeExpression struct {
+	*Node `pp:"-"`
+	Left  data.GetValue // 传入的值
+	Right data.GetValue // 可调用值，如 trim(...)、fn($s) => $s . '!'、'strtoupper'
+}
+
+// NewPipeExpression 创建一个新的管道表达式
+func NewPipeExpression(from *TokenFrom, left, right data.GetValue) *PipeExpression {
+	return &PipeExpression{
+		Node:  NewNode(from),
+		Left:  left,
+		Right: right,
+	}
+}
+
+// GetValue 先求左侧的值，再求右侧的可调用值并调用
+func (p *PipeExpression) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	lv, acl := p.Left.GetValue(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	rv, acl := p.Right.GetValue(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	fn, acl := callableFunc(ctx, p.from, rv)
+	if acl != nil {
+		return nil, acl
+	}
+	return data.CallFunc(ctx, fn, []data.Value{operandValue(lv)})
+}
+
+// callableFunc 将可调用值（闭包、函数名、[$obj, 'method'] 等）转为可直接调用的函数
+func callableFunc(ctx data.Context, from data.From, v data.GetValue) (data.FuncStmt, data.Control) {
+	if fv, ok := v.(*data.FuncValue); ok {
+		return fv.Value, nil
+	}
+	switch v.(type) {
+	case *data.StringValue, *data.ArrayValue, *data.ClassValue, *data.BoundFuncValue:
+		closure, acl := CallableToClosure(ctx, from, v.(data.Value))
+		if acl != nil {
+			return nil, acl
+		}
+		switch c := closure.(type) {
+		case *data.FuncValue:
+			return c.Value, nil
+		case *data.BoundFuncValue:
+			return c.Value, nil
+		}
+	}
+	return nil, data.NewErrorThrow(from, fmt.Errorf("Value of type %s is not callable", data.TypeName(operandValue(v))))
+}
@@ -50,7 +50,7 @@ func (p *PostfixDecr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			return nil, data.NewErrorThrow(p.from, err)
 		}
 		originalValue = data.NewIntValue(i)
-		newValue := data.NewIntValue(i - 1)
+		newValue := data.SubInt(i, 1)
 		if variable, ok := p.Left.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -76,7 +76,7 @@ func (p *PostfixDecr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			return nil, data.NewErrorThrow(p.from, err)
 		}
 		originalValue = data.NewIntValue(i)
-		newValue := data.NewIntValue(i - 1)
+		newValue := data.SubInt(i, 1)
 		if variable, ok := p.Left.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -85,13 +85,24 @@ func (p *PostfixDecr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return originalValue, nil
 	}
 
+	// 字符串：数字字符串按数字计算，其它字符串按 PHP 规则处理
+	if sv, ok := lv.(*data.StringValue); ok {
+		newValue := data.DecrementString(sv.Value)
+		if variable, ok := p.Left.(data.Variable); ok {
+			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
+				return nil, ctl
+			}
+		}
+		return sv, nil
+	}
+
 	if asInt, ok := lv.(data.AsInt); ok {
 		i, err := asInt.AsInt()
 		if err != nil {
 			return nil, data.NewErrorThrow(p.from, err)
 		}
 		originalValue = data.NewIntValue(i)
-		newValue := data.NewIntValue(i - 1)
+		newValue := data.SubInt(i, 1)
 		if variable, ok := p.Left.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -141,7 +152,7 @@ func (p *PostfixDecr) decrObjectProperty(ctx data.Context, cop *CallObjectProper
 	if _, ok := lv.(*data.NullValue); ok {
 		originalValue = data.NewNullValue()
 	}
-	newValue := data.NewIntValue(i - 1)
+	newValue := data.SubInt(i, 1)
 	if ctl := cop.SetValue(ctx, newValue); ctl != nil {
 		return nil, ctl
 	}
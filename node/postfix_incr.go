@@ -55,7 +55,7 @@ func (p *PostfixIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			return nil, data.NewErrorThrow(p.from, err)
 		}
 		originalValue = data.NewIntValue(i)
-		newValue := data.NewIntValue(i + 1)
+		newValue := data.AddInt(i, 1)
 		if variable, ok := p.Left.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -83,7 +83,7 @@ func (p *PostfixIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			return nil, data.NewErrorThrow(p.from, err)
 		}
 		originalValue = data.NewIntValue(i)
-		newValue := data.NewIntValue(i + 1)
+		newValue := data.AddInt(i, 1)
 		if variable, ok := p.Left.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -92,6 +92,17 @@ func (p *PostfixIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return originalValue, nil
 	}
 
+	// 字符串：数字字符串按数字计算，其它字符串按 PHP 规则处理
+	if sv, ok := lv.(*data.StringValue); ok {
+		newValue := data.IncrementString(sv.Value)
+		if variable, ok := p.Left.(data.Variable); ok {
+			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
+				return nil, ctl
+			}
+		}
+		return sv, nil
+	}
+
 	// 兜底：任何实现了 AsInt 的类型都按 int 自增
 	if asInt, ok := lv.(data.AsInt); ok {
 		i, err := asInt.AsInt()
@@ -99,7 +110,7 @@ func (p *PostfixIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			return nil, data.NewErrorThrow(p.from, err)
 		}
 		originalValue = data.NewIntValue(i)
-		newValue := data.NewIntValue(i + 1)
+		newValue := data.AddInt(i, 1)
 		if variable, ok := p.Left.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -150,7 +161,7 @@ func (p *PostfixIncr) incrObjectProperty(ctx data.Context, cop *CallObjectProper
 	if _, ok := lv.(*data.NullValue); ok {
 		originalValue = data.NewNullValue()
 	}
-	newValue := data.NewIntValue(i + 1)
+	newValue := data.AddInt(i, 1)
 	if ctl := cop.SetValue(ctx, newValue); ctl != nil {
 		return nil, ctl
 	}
@@ -0,0 +1,175 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/php-any/origami/data"
+)
+
+// 属性钩子（PHP 8.4）：public string $name { get => ...; set(string $v) { ... } }
+// 以及非对称可见性 public private(set) int $id。
+// 钩子执行期间，钩子内对同一对象同一属性的访问直接读写属性自身的存储（backing store）。
+
+// WithHooks 设置属性的 get/set 钩子；钩子中没有用到 $this->属性 时该属性是虚拟属性，没有自身的存储
+func (p *ClassProperty) WithHooks(get, set data.Method) *ClassProperty {
+	p.GetHook = get
+	p.SetHook = set
+	p.IsVirtual = (get != nil || set != nil) && !hooksUseBackingStore(p.Name, get, set)
+	return p
+}
+
+// WithWriteModifier 设置写入访问修饰符 private(set)/protected(set)
+func (p *ClassProperty) WithWriteModifier(modifier string) *ClassProperty {
+	p.WriteModifier = data.NewModifier(modifier)
+	return p
+}
+
+// HasHooks 属性是否声明了钩子
+func (p *ClassProperty) HasHooks() bool {
+	return p.GetHook != nil || p.SetHook != nil
+}
+
+// GetIsVirtual 是否为虚拟属性
+func (p *ClassProperty) GetIsVirtual() bool {
+	return p.IsVirtual
+}
+
+// hooksUseBackingStore 钩子方法体中是否访问了 $this->name
+func hooksUseBackingStore(name string, hooks ...data.Method) bool {
+	found := false
+	for _, hook := range hooks {
+		m, ok := hook.(*ClassMethod)
+		if !ok {
+			continue
+		}
+		InspectList(m.Body, func(n data.GetValue) bool {
+			if cop, ok := n.(*CallObjectProperty); ok && cop.Property == name {
+				if _, ok := cop.Object.(*This); ok {
+					found = true
+				}
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+type hookKey struct {
+	object *data.ObjectValue
+	name   string
+}
+
+// runningHooks 正在执行钩子的 (对象, 属性)
+var runningHooks sync.Map // hookKey -> struct{}
+
+// isHookedProperty 属性是否声明了钩子
+func isHookedProperty(property data.Property) bool {
+	cp, ok := property.(*ClassProperty)
+	return ok && cp.HasHooks()
+}
+
+// hookedProperty 返回声明了钩子的属性，且该属性当前不在自身的钩子中执行
+func hookedProperty(object *data.ClassValue, property data.Property) *ClassProperty {
+	cp, ok := property.(*ClassProperty)
+	if !ok || !cp.HasHooks() {
+		return nil
+	}
+	if _, running := runningHooks.Load(hookKey{object.ObjectValue, cp.Name}); running {
+		return nil
+	}
+	return cp
+}
+
+// callHook 执行属性钩子，执行期间对该属性的访问不再经过钩子
+func callHook(object *data.ClassValue, cp *ClassProperty, hook data.Method, args ...data.Value) (data.GetValue, data.Control) {
+	key := hookKey{object.ObjectValue, cp.Name}
+	runningHooks.Store(key, struct{}{})
+	defer runningHooks.Delete(key)
+
+	vars := hook.GetVariables()
+	fnCtx := object.CreateContext(vars)
+	for i, arg := range args {
+		fnCtx.SetVariableValue(vars[i], arg)
+	}
+	return hook.Call(fnCtx)
+}
+
+// readPropertyHook 读取带 get 钩子的属性；handled 为 false 时按普通属性读取
+func readPropertyHook(from data.From, object *data.ClassValue, property data.Property) (data.GetValue, data.Control, bool) {
+	cp := hookedProperty(object, property)
+	if cp == nil {
+		return nil, nil, false
+	}
+	if cp.GetHook == nil {
+		if cp.IsVirtual {
+			declaring := propertyDeclaringClass(object, cp.Name)
+			return nil, data.NewErrorThrowByName(from, fmt.Errorf("Property %s::$%s is write-only", declaring.GetName(), cp.Name), "Error"), true
+		}
+		return nil, nil, false
+	}
+	v, acl := callHook(object, cp, cp.GetHook)
+	if acl == nil && v == nil {
+		v = data.NewNullValue()
+	}
+	return v, acl, true
+}
+
+// writePropertyHook 写入带 set 钩子的属性；handled 为 false 时按普通属性写入
+func writePropertyHook(ctx data.Context, from data.From, object *data.ClassValue, property data.Property, value data.Value) (bool, data.Control) {
+	cp := hookedProperty(object, property)
+	if cp == nil {
+		return false, nil
+	}
+	declaring := propertyDeclaringClass(object, cp.Name)
+	if cp.SetHook == nil {
+		if cp.IsVirtual {
+			return true, data.NewErrorThrowByName(from, fmt.Errorf("Property %s::$%s is read-only", declaring.GetName(), cp.Name), "Error")
+		}
+		return false, nil
+	}
+	if acl := checkWriteVisibility(ctx, from, object, cp); acl != nil {
+		return true, acl
+	}
+	// set 钩子的参数声明了类型时按参数类型检查，否则按属性类型检查
+	var acl data.Control
+	if params := cp.SetHook.GetParams(); len(params) > 0 {
+		if param, ok := params[0].(*Parameter); ok && param.Type != nil {
+			v, ok := data.CoerceType(param.Type, value, IsStrictTypes(from))
+			if !ok {
+				return true, argumentTypeError(declaring.GetName()+"::"+cp.SetHook.GetName(), 1, param, value, from)
+			}
+			value = v
+		} else if value, acl = coercePropertyValue(from, declaring.GetName(), cp, value); acl != nil {
+			return true, acl
+		}
+	}
+	_, acl = callHook(object, cp, cp.SetHook, value)
+	return true, acl
+}
+
+// checkWriteVisibility 检查 private(set)/protected(set)：
+// private(set) 只能在声明类中写入，protected(set) 可以在声明类及其子类中写入
+func checkWriteVisibility(ctx data.Context, from data.From, object *data.ClassValue, property data.Property) data.Control {
+	cp, ok := property.(*ClassProperty)
+	if !ok || cp.WriteModifier == data.ModifierPublic {
+		return nil
+	}
+	declaring := propertyDeclaringClass(object, cp.Name)
+	var allowed bool
+	var visibility string
+	if cp.WriteModifier == data.ModifierPrivate {
+		allowed = scopeClassName(ctx) == declaring.GetName()
+		visibility = "private(set)"
+	} else {
+		allowed = inClassScope(ctx, declaring)
+		visibility = "protected(set)"
+	}
+	if allowed {
+		return nil
+	}
+	return data.NewErrorThrowByName(from, fmt.Errorf("Cannot modify %s property %s::$%s from %s", visibility, declaring.GetName(), cp.Name, scopeDescription(ctx)), "Error")
+}
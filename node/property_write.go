@@ -0,0 +1,238 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/php-any/origami/data"
+)
+
+// 属性写入检查：readonly 属性、readonly class 与属性类型。
+// 所有写入实例属性的路径（->、复合赋值、自增自减、引用、unset、数组元素写入）都经过这里，
+// 静态属性的类型检查见 ClassStatement.storeStaticProperty。
+
+// cloningObjects 正在执行 __clone 的对象，值为 __clone 中已重新初始化过的 readonly 属性名集合。
+// PHP 8.3 起允许在 __clone 中对每个 readonly 属性重新赋值一次。
+var cloningObjects sync.Map // *data.ObjectValue -> map[string]bool
+
+// isReadonlyProperty 属性是否声明为 readonly
+func isReadonlyProperty(property data.Property) bool {
+	cp, ok := property.(*ClassProperty)
+	return ok && cp.IsReadonly
+}
+
+// isReadonlyClass 类是否声明为 readonly class
+func isReadonlyClass(class data.ClassStmt) bool {
+	c, ok := class.(interface{ GetIsReadonly() bool })
+	return ok && c.GetIsReadonly()
+}
+
+// propertyDeclaringClass 查找声明属性的类（沿继承链向上），找不到时返回对象自身的类
+func propertyDeclaringClass(object *data.ClassValue, name string) data.ClassStmt {
+	class := object.Class
+	for class != nil {
+		if _, ok := class.GetProperty(name); ok {
+			return class
+		}
+		if class.GetExtend() == nil {
+			break
+		}
+		next, acl := object.GetVM().GetOrLoadClass(*class.GetExtend())
+		if acl != nil {
+			break
+		}
+		class = next
+	}
+	return object.Class
+}
+
+// classValueOf 取出对象值（$this 解包为 ClassValue），不是对象时返回 nil
+func classValueOf(v any) *data.ClassValue {
+	switch o := v.(type) {
+	case *data.ClassValue:
+		return o
+	case *data.ThisValue:
+		return o.ClassValue
+	}
+	return nil
+}
+
+// isPropertyInitialized 属性是否已经初始化（对象的属性存储中存在该属性）
+func isPropertyInitialized(object *data.ClassValue, name string) bool {
+	zv, _ := object.ObjectValue.GetZVal(name)
+	return zv != nil
+}
+
+// scopeClassName 当前代码所在的类作用域，全局作用域返回 ""
+func scopeClassName(ctx data.Context) string {
+	switch c := ctx.(type) {
+	case *data.BoundContext:
+		return c.ScopeClass
+	case *data.ClassMethodContext:
+		if c.SelfClass != nil {
+			return c.SelfClass.GetName()
+		}
+		return c.Class.GetName()
+	case *data.ClassValue:
+		return c.Class.GetName()
+	}
+	return ""
+}
+
+// inClassScope 当前作用域是否为 class 本身或其子类
+func inClassScope(ctx data.Context, class data.ClassStmt) bool {
+	scope := scopeClassName(ctx)
+	if scope == "" {
+		return false
+	}
+	for scope != class.GetName() {
+		stmt, acl := ctx.GetVM().GetOrLoadClass(scope)
+		if acl != nil || stmt == nil || stmt.GetExtend() == nil {
+			return false
+		}
+		scope = *stmt.GetExtend()
+	}
+	return true
+}
+
+// scopeDescription 错误消息中的作用域描述
+func scopeDescription(ctx data.Context) string {
+	if scope := scopeClassName(ctx); scope != "" {
+		return "scope " + scope
+	}
+	return "global scope"
+}
+
+// reinitializeInClone 对象正在执行 __clone 且该 readonly 属性尚未在 __clone 中重新赋值过
+func reinitializeInClone(object *data.ClassValue, name string) bool {
+	v, ok := cloningObjects.Load(object.ObjectValue)
+	if !ok {
+		return false
+	}
+	done := v.(map[string]bool)
+	if done[name] {
+		return false
+	}
+	done[name] = true
+	return true
+}
+
+// checkReadonlyWrite 检查 readonly 属性能否写入或 unset：
+// 已初始化的属性不能再修改（__clone 中可以重新赋值一次），未初始化的属性只能在声明类（及其子类）的作用域内初始化。
+func checkReadonlyWrite(ctx data.Context, from data.From, object *data.ClassValue, property data.Property, unset bool) data.Control {
+	name := property.GetName()
+	declaring := propertyDeclaringClass(object, name)
+	inScope := inClassScope(ctx, declaring)
+	action := "modify"
+	if unset {
+		action = "unset"
+	}
+	if isPropertyInitialized(object, name) {
+		if !unset && inScope && reinitializeInClone(object, name) {
+			return nil
+		}
+		return data.NewErrorThrowByName(from, fmt.Errorf("Cannot %s readonly property %s::$%s", action, declaring.GetName(), name), "Error")
+	}
+	if !inScope {
+		if !unset {
+			action = "initialize"
+		}
+		return data.NewErrorThrowByName(from, fmt.Errorf("Cannot %s readonly property %s::$%s from %s", action, declaring.GetName(), name, scopeDescription(ctx)), "Error")
+	}
+	return nil
+}
+
+// coercePropertyValue 按属性类型检查并转换写入的值；写入点所在文件声明了 strict_types=1 时按严格模式检查
+func coercePropertyValue(from data.From, className string, property data.Property, value data.Value) (data.Value, data.Control) {
+	ty := property.GetType()
+	if ty == nil {
+		return value, nil
+	}
+	if v, ok := data.CoerceType(ty, value, IsStrictTypes(from)); ok {
+		return v, nil
+	}
+	msg := fmt.Sprintf("Cannot assign %s to property %s::$%s of type %s", data.TypeNameOf(value), className, property.GetName(), ty.String())
+	msg += data.LikeMismatch(ty, value)
+	return nil, data.NewErrorThrowByName(from, fmt.Errorf("%s", msg), "TypeError")
+}
+
+// guardPropertyReference 引用绑定到带类型的属性后，通过引用写入的值同样按属性类型检查
+func guardPropertyReference(z *data.ZVal, className string, property data.Property) {
+	ty := property.GetType()
+	if z == nil || ty == nil || z.Guard != nil {
+		return
+	}
+	z.Guard = func(from data.From, value data.Value) (data.Value, data.Control) {
+		if v, ok := data.CoerceType(ty, value, IsStrictTypes(from)); ok {
+			return v, nil
+		}
+		msg := fmt.Sprintf("Cannot assign %s to reference held by property %s::$%s of type %s", data.TypeNameOf(value), className, property.GetName(), ty.String())
+		return nil, data.NewErrorThrowByName(from, fmt.Errorf("%s", msg), "TypeError")
+	}
+}
+
+// checkPropertyAssign 写入已声明的实例属性前检查写入可见性、readonly 与属性类型，返回按属性类型转换后的值
+func checkPropertyAssign(ctx data.Context, from data.From, object *data.ClassValue, property data.Property, value data.Value) (data.Value, data.Control) {
+	if acl := checkWriteVisibility(ctx, from, object, property); acl != nil {
+		return nil, acl
+	}
+	if isReadonlyProperty(property) {
+		if acl := checkReadonlyWrite(ctx, from, object, property, false); acl != nil {
+			return nil, acl
+		}
+	}
+	if property.GetType() == nil {
+		return value, nil
+	}
+	return coercePropertyValue(from, propertyDeclaringClass(object, property.GetName()).GetName(), property, value)
+}
+
+// checkDynamicProperty readonly class 不允许创建动态属性
+func checkDynamicProperty(from data.From, object *data.ClassValue, name string) data.Control {
+	if isReadonlyClass(object.Class) {
+		return data.NewErrorThrowByName(from, fmt.Errorf("Cannot create dynamic property %s::$%s", object.Class.GetName(), name), "Error")
+	}
+	return nil
+}
+
+// checkReadonlyIndexWrite $obj->prop[...] = $v 会修改数组属性本身，已初始化的 readonly 数组属性不能这样写入
+func checkReadonlyIndexWrite(ctx data.Context, ie *IndexExpression) data.Control {
+	var base data.GetValue = ie
+	for {
+		inner, ok := base.(*IndexExpression)
+		if !ok {
+			break
+		}
+		base = inner.Array
+	}
+	cop, ok := base.(*CallObjectProperty)
+	if !ok {
+		return nil
+	}
+	// 只检查 $this->prop[...] 与 $var->prop[...]，避免重复求值有副作用的对象表达式
+	switch cop.Object.(type) {
+	case *This, *VariableExpression:
+	default:
+		return nil
+	}
+	temp, acl := receiverValue(ctx, cop.Object)
+	if acl != nil {
+		return acl
+	}
+	object := classValueOf(temp)
+	if object == nil {
+		return nil
+	}
+	property, ok := object.GetPropertyStmt(cop.Property)
+	if !ok || !isReadonlyProperty(property) || !isPropertyInitialized(object, cop.Property) {
+		return nil
+	}
+	// readonly 属性中的对象本身仍然可以修改（如 ArrayAccess 对象）
+	v, _ := object.ObjectValue.GetProperty(cop.Property)
+	switch v.(type) {
+	case *data.ArrayValue, *data.ObjectValue:
+		declaring := propertyDeclaringClass(object, cop.Property)
+		return data.NewErrorThrowByName(ie.GetFrom(), fmt.Errorf("Cannot modify readonly property %s::$%s", declaring.GetName(), cop.Property), "Error")
+	}
+	return nil
+}
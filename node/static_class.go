@@ -24,7 +24,10 @@ func NewStaticClass(from data.From) *StaticClass {
 func (s *StaticClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	// 检查是否在类上下文中（类方法或类级初始化器）
 	var currentClass data.ClassStmt
-	if classCtx, ok := ctx.(*data.ClassMethodContext); ok {
+	if classCtx, ok := ctx.(*data.ClassMethodContext); ok && classCtx.StaticClass != nil {
+		// 后期静态绑定：Child::make() 中为 Child
+		currentClass = classCtx.StaticClass
+	} else if classCtx, ok := ctx.(*data.ClassMethodContext); ok {
 		// 获取最外层的类方法上下文
 		parent, ok2 := classCtx.Context.(*data.ClassMethodContext)
 		for ok2 {
@@ -13,10 +13,15 @@ type SwitchCase struct {
 
 // GetValue 获取switch分支的值
 func (s *SwitchCase) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	// 执行语句列表
+	return runSwitchBody(ctx, s.Statements)
+}
+
+// runSwitchBody 执行 case/default 分支的语句列表：break 结束 switch（多级 break 交给外层），
+// return、throw、continue、goto 以及生成器销毁（DestroyControl）等其余控制流原样交给外层
+func runSwitchBody(ctx data.Context, statements []data.GetValue) (data.GetValue, data.Control) {
 	var v data.GetValue
 	var c data.Control
-	for _, statement := range s.Statements {
+	for _, statement := range statements {
 		if stmt, ok := statement.(data.GetValue); ok {
 			v, c = stmt.GetValue(ctx)
 		} else {
@@ -24,14 +29,10 @@ func (s *SwitchCase) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 			v = statement
 		}
 		if c != nil {
-			switch c.(type) {
-			case data.BreakControl:
-				return v, nil
-			case data.ReturnControl:
-				return v, c
-			case data.ThrowControl:
-				return nil, c
+			if _, ok := c.(data.BreakControl); ok {
+				return v, outerBreak(c)
 			}
+			return v, c
 		}
 	}
 	return v, nil
@@ -82,26 +83,7 @@ func (s *SwitchStatement) GetValue(ctx data.Context) (data.GetValue, data.Contro
 
 	// 如果没有匹配的case，执行default分支
 	if len(s.DefaultCase) > 0 {
-		var v data.GetValue
-		var c data.Control
-		for _, statement := range s.DefaultCase {
-			if stmt, ok := statement.(data.GetValue); ok {
-				v, c = stmt.GetValue(ctx)
-			} else {
-				v = statement
-			}
-			if c != nil {
-				switch c.(type) {
-				case data.BreakControl:
-					return v, nil
-				case data.ReturnControl:
-					return v, c
-				case data.ThrowControl:
-					return nil, c
-				}
-			}
-		}
-		return v, nil
+		return runSwitchBody(ctx, s.DefaultCase)
 	}
 
 	// 如果没有匹配的分支，返回null
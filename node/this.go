@@ -17,7 +17,24 @@ func NewThis(from data.From) *This {
 
 func (u *This) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 	if classCtx, ok := ctx.(*data.ClassMethodContext); ok {
+		data.Escape(classCtx.ClassValue)
 		return data.NewThisValue(classCtx.ClassValue), nil
 	}
 	return nil, data.NewErrorThrow(u.from, errors.New("this关键字只能在类中使用"))
 }
+
+// receiverValue 求值方法调用、属性访问的接收者：变量与 $this 在这里只是被使用，
+// 对象仍归原变量所有（见 data.Escape），其它表达式按普通求值
+func receiverValue(ctx data.Context, expr data.GetValue) (data.GetValue, data.Control) {
+	switch e := expr.(type) {
+	case *VariableExpression:
+		if cv, ok := data.PeekReceiver(ctx, e.Index); ok {
+			return cv, nil
+		}
+	case *This:
+		if classCtx, ok := ctx.(*data.ClassMethodContext); ok {
+			return data.NewThisValue(classCtx.ClassValue), nil
+		}
+	}
+	return expr.GetValue(ctx)
+}
@@ -32,7 +32,7 @@ func (u *UnaryDecr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		if err != nil {
 			return nil, data.NewErrorThrow(u.from, err)
 		}
-		newValue := data.NewIntValue(i - 1)
+		newValue := data.SubInt(i, 1)
 
 		// 如果是变量，需要更新变量的值
 		if variable, ok := u.Right.(data.Variable); ok {
@@ -61,5 +61,16 @@ func (u *UnaryDecr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		return newValue, nil
 	}
 
+	// 字符串：数字字符串按数字计算，其它字符串按 PHP 规则处理
+	if sv, ok := rv.(*data.StringValue); ok {
+		newValue := data.DecrementString(sv.Value)
+		if variable, ok := u.Right.(data.Variable); ok {
+			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
+				return nil, ctl
+			}
+		}
+		return newValue, nil
+	}
+
 	return nil, data.NewErrorThrow(u.from, errors.New("不支持的类型自减"))
 }
@@ -32,7 +32,7 @@ func (u *UnaryIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		if err != nil {
 			return nil, data.NewErrorThrow(u.from, err)
 		}
-		newValue := data.NewIntValue(i + 1)
+		newValue := data.AddInt(i, 1)
 		if variable, ok := u.Right.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -58,7 +58,18 @@ func (u *UnaryIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		if err != nil {
 			return nil, data.NewErrorThrow(u.from, err)
 		}
-		newValue := data.NewIntValue(i + 1)
+		newValue := data.AddInt(i, 1)
+		if variable, ok := u.Right.(data.Variable); ok {
+			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
+				return nil, ctl
+			}
+		}
+		return newValue, nil
+	}
+
+	// 字符串：数字字符串按数字计算，其它字符串按 PHP 规则处理
+	if sv, ok := rv.(*data.StringValue); ok {
+		newValue := data.IncrementString(sv.Value)
 		if variable, ok := u.Right.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -73,7 +84,7 @@ func (u *UnaryIncr) GetValue(ctx data.Context) (data.GetValue, data.Control) {
 		if err != nil {
 			return nil, data.NewErrorThrow(u.from, err)
 		}
-		newValue := data.NewIntValue(i + 1)
+		newValue := data.AddInt(i, 1)
 		if variable, ok := u.Right.(data.Variable); ok {
 			if ctl := variable.SetValue(ctx, newValue); ctl != nil {
 				return nil, ctl
@@ -29,10 +29,23 @@ func (u *UnsetStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 	for _, argExpr := range u.Args {
 		// 优先处理对象属性：unset($obj->prop)，需要绕过类型检查
 		if callProp, ok := argExpr.(*CallObjectProperty); ok {
-			objValue, acl := callProp.Object.GetValue(ctx)
+			objValue, acl := receiverValue(ctx, callProp.Object)
 			if acl != nil || objValue == nil {
 				continue
 			}
+			// readonly 属性：已初始化的不能 unset，未初始化的只能在声明类作用域内 unset（无实际效果）
+			if cv := classValueOf(objValue); cv != nil {
+				if property, ok := cv.GetPropertyStmt(callProp.Property); ok && isHookedProperty(property) {
+					declaring := propertyDeclaringClass(cv, callProp.Property)
+					return nil, data.NewErrorThrowByName(callProp.GetFrom(), fmt.Errorf("Cannot unset hooked property %s::$%s", declaring.GetName(), callProp.Property), "Error")
+				}
+				if property, ok := cv.GetPropertyStmt(callProp.Property); ok && isReadonlyProperty(property) {
+					if acl := checkReadonlyWrite(ctx, callProp.GetFrom(), cv, property, true); acl != nil {
+						return nil, acl
+					}
+					continue
+				}
+			}
 			switch obj := objValue.(type) {
 			case *data.ObjectValue:
 				obj.SetProperty(callProp.Property, data.NewNullValue())
@@ -53,6 +66,9 @@ func (u *UnsetStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 
 		// 索引表达式：unset($arr['key'])
 		if indexExpr, ok := argExpr.(*IndexExpression); ok {
+			if inner, ok := indexExpr.Array.(*IndexExpression); ok {
+				separateIndexPath(ctx, inner)
+			}
 			arrayValue, acl := indexExpr.Array.GetValue(ctx)
 			if acl != nil || arrayValue == nil {
 				continue
@@ -107,7 +123,7 @@ func (u *UnsetStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 func writeBackArrayProperty(ctx data.Context, arrayExpr data.GetValue, arr *data.ArrayValue) {
 	switch a := arrayExpr.(type) {
 	case *CallObjectProperty:
-		obj, acl := a.Object.GetValue(ctx)
+		obj, acl := receiverValue(ctx, a.Object)
 		if acl != nil {
 			return
 		}
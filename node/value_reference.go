@@ -23,6 +23,12 @@ func NewValueReference(token *TokenFrom, value data.GetValue) *ValueReference {
 
 // GetValue 获取引用的值
 func (v *ValueReference) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	// 对象属性引用：&$obj->prop，引用 readonly 属性等同于修改它
+	if cop, ok := v.Value.(*CallObjectProperty); ok {
+		if acl := cop.checkReadonlyReference(ctx); acl != nil {
+			return nil, acl
+		}
+	}
 	// 变量引用：&$var
 	if variable, ok := v.Value.(data.Variable); ok {
 		return data.NewReferenceValue(variable, ctx), nil
@@ -63,6 +69,9 @@ func (v *ValueReference) GetValue(ctx data.Context) (data.GetValue, data.Control
 
 // resolveIndexRef 解析 &$array[$key] 或 &$array[] 引用
 func (v *ValueReference) resolveIndexRef(ctx data.Context, ie *IndexExpression) (data.GetValue, data.Control) {
+	if inner, ok := ie.Array.(*IndexExpression); ok {
+		separateIndexPath(ctx, inner)
+	}
 	arrVal, acl := ie.Array.GetValue(ctx)
 	if acl != nil {
 		return nil, acl
@@ -74,6 +83,8 @@ func (v *ValueReference) resolveIndexRef(ctx data.Context, ie *IndexExpression)
 
 	switch arr := arrVal.(type) {
 	case *data.ArrayValue:
+		// 槽位将与变量共享，先分离共用的存储
+		arr.Separate()
 		idx, ok := idxVal.(data.Value)
 		if !ok {
 			idx = data.NewNullValue()
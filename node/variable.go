@@ -128,6 +128,7 @@ func (vl *VariableList) SetValue(ctx data.Context, value data.Value) data.Contro
 		if method, exists := cv.GetMethod("offsetGet"); exists {
 			for i, v := range vl.Vars {
 				fnCtx := cv.CreateContext(method.GetVariables())
+				data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 				if len(method.GetVariables()) > 0 {
 					fnCtx.SetVariableValue(method.GetVariables()[0], data.NewIntValue(i))
 				}
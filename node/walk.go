@@ -0,0 +1,153 @@
+package node
+
+import (
+	"reflect"
+
+	"github.com/php-any/origami/data"
+)
+
+var (
+	nodePkgPath     = reflect.TypeOf(Node{}).PkgPath()
+	getValueIfaceTy = reflect.TypeOf((*data.GetValue)(nil)).Elem()
+)
+
+// Inspect 深度优先遍历语法树：先对节点本身调用 fn，fn 返回 true 时继续遍历它的子节点。
+// 子节点通过反射发现（导出的 data.GetValue 字段、切片以及 node 包内的结构体字段），
+// 带 `pp:"-"` 标签的字段会被跳过。
+func Inspect(root data.GetValue, fn func(data.GetValue) bool) {
+	w := &inspector{fn: fn, seen: map[uintptr]struct{}{}}
+	w.visit(reflect.ValueOf(root))
+}
+
+// InspectList 对一组语句依次调用 Inspect
+func InspectList(list []data.GetValue, fn func(data.GetValue) bool) {
+	w := &inspector{fn: fn, seen: map[uintptr]struct{}{}}
+	for _, stmt := range list {
+		w.visit(reflect.ValueOf(stmt))
+	}
+}
+
+type inspector struct {
+	fn   func(data.GetValue) bool
+	seen map[uintptr]struct{}
+}
+
+func (w *inspector) visit(v reflect.Value) {
+	for v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		if v.Type().Elem().Kind() != reflect.Struct || v.Type().Elem().PkgPath() != nodePkgPath {
+			if v.Type().Implements(getValueIfaceTy) && v.CanInterface() {
+				w.fn(v.Interface().(data.GetValue))
+			}
+			return
+		}
+		ptr := v.Pointer()
+		if _, ok := w.seen[ptr]; ok {
+			return
+		}
+		w.seen[ptr] = struct{}{}
+		if v.Type().Implements(getValueIfaceTy) && v.CanInterface() {
+			if !w.fn(v.Interface().(data.GetValue)) {
+				return
+			}
+		}
+		w.fields(v.Elem())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			w.visit(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type().PkgPath() == nodePkgPath {
+			w.fields(v)
+		}
+	}
+}
+
+func (w *inspector) fields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("pp") == "-" {
+			continue
+		}
+		w.visit(v.Field(i))
+	}
+}
+
+// containsYield 检测函数体中是否含有 yield（任意深度的控制结构内），
+// 不会深入嵌套的函数、闭包与类定义。
+func containsYield(body []data.GetValue) bool {
+	found := false
+	InspectList(body, func(n data.GetValue) bool {
+		if found {
+			return false
+		}
+		switch n.(type) {
+		case *YieldStatement, *YieldFromStatement:
+			found = true
+			return false
+		case *LambdaExpression, *FunctionStatement, *ClassStatement, *InterfaceStatement:
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// containsStatic 函数体内是否声明了 static 局部变量（不进入嵌套的函数、类）
+func containsStatic(body []data.GetValue) bool {
+	found := false
+	InspectList(body, func(n data.GetValue) bool {
+		if found {
+			return false
+		}
+		switch n.(type) {
+		case *StaticVarStatement:
+			found = true
+			return false
+		case *LambdaExpression, *FunctionStatement, *ClassStatement, *InterfaceStatement:
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// containsCleanup 函数体内是否有 finally 块或 defer 语句（不进入嵌套的函数、类），
+// 含有时生成器被提前释放需要销毁以执行它们
+func containsCleanup(body []data.GetValue) bool {
+	found := false
+	InspectList(body, func(n data.GetValue) bool {
+		if found {
+			return false
+		}
+		switch t := n.(type) {
+		case *TryStatement:
+			if len(t.FinallyBlock) > 0 {
+				found = true
+				return false
+			}
+		case *DeferStatement:
+			found = true
+			return false
+		case *LambdaExpression, *FunctionStatement, *ClassStatement, *InterfaceStatement:
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -6,6 +6,7 @@ func (u *WhileStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 	var v data.GetValue
 	var c data.Control
 
+next:
 	for {
 		checkTimeLimit(u.GetFrom())
 		// 判断条件
@@ -32,14 +33,17 @@ func (u *WhileStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 		// 执行循环体
 		for _, statement := range u.Body {
 			v, c = statement.GetValue(ctx)
+			if c == nil {
+				c = data.Safepoint(ctx)
+			}
 			if c != nil {
 				// break 跳出循环
 				if ctrl, ok := c.(data.BreakControl); ok && ctrl.IsBreak() {
-					return nil, nil
+					return nil, outerBreak(c)
 				}
 				// continue 跳到条件判断
 				if ctrl, ok := c.(data.ContinueControl); ok && ctrl.IsContinue() {
-					continue
+					continue next
 				}
 				// return/throw 直接返回
 				return nil, c
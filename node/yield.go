@@ -1,6 +1,10 @@
 package node
 
-import "github.com/php-any/origami/data"
+import (
+	"errors"
+
+	"github.com/php-any/origami/data"
+)
 
 // YieldStatement 表示yield语句
 type YieldStatement struct {
@@ -49,9 +53,30 @@ func (y *YieldStatement) GetValue(ctx data.Context) (data.GetValue, data.Control
 		value = data.NewNullValue()
 	}
 
-	// 返回 yield 控制流（普通 yield 使用 YieldValueControl）
+	co := currentGeneratorCoroutine(ctx)
+	if co == nil {
+		return nil, data.NewErrorThrowByName(y.GetFrom(), errors.New("yield 只能在生成器函数中使用"), "Error")
+	}
 	// key 为 nil 表示未显式指定，Generator 会自动分配自增整数 key
-	return nil, data.NewYieldControlWithContext(key, value, ctx)
+	return suspendGenerator(y.GetFrom(), co, generatorYield{key: key, value: value})
+}
+
+// suspendGenerator 挂起生成器并等待恢复，返回值即 yield 表达式的结果（send 传入的值）
+func suspendGenerator(from data.From, co *Coroutine, y generatorYield) (data.GetValue, data.Control) {
+	if co.IsDestroying() {
+		return nil, data.NewErrorThrowByName(from, errors.New("Cannot yield from finally in a force-closed generator"), "Error")
+	}
+	sig := co.Suspend(y)
+	if sig.Destroy {
+		return nil, &DestroyControl{}
+	}
+	if sig.Throw != nil {
+		return nil, checkThrowControlFrom(nil, sig.Throw)
+	}
+	if sig.Value == nil {
+		return data.NewNullValue(), nil
+	}
+	return sig.Value, nil
 }
 
 // YieldFromStatement 表示yield from语句
@@ -80,6 +105,9 @@ func (y *YieldFromStatement) GetValue(ctx data.Context) (data.GetValue, data.Con
 		return nil, data.NewErrorThrow(y.GetFrom(), data.NewError(y.GetFrom(), "yield from 需要一个可迭代对象", nil))
 	}
 
-	// 返回 yield from 控制流：使用单独的 YieldFromControl，实现委托逻辑
-	return nil, NewYieldFromControl(ctx, source)
+	co := currentGeneratorCoroutine(ctx)
+	if co == nil {
+		return nil, data.NewErrorThrowByName(y.GetFrom(), errors.New("yield from 只能在生成器函数中使用"), "Error")
+	}
+	return yieldFrom(ctx, y.GetFrom(), co, source)
 }
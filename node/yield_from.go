@@ -0,0 +1,167 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/php-any/origami/data"
+)
+
+// yieldFrom 把 source 的每个元素依次交给外层生成器（保留原有 key），
+// send()/throw() 会转发给被委托的生成器，结果为被委托生成器的 return 值。
+func yieldFrom(ctx data.Context, from data.From, co *Coroutine, source data.GetValue) (data.GetValue, data.Control) {
+	switch src := source.(type) {
+	case *data.ArrayValue:
+		list := make([]*data.ZVal, len(src.List))
+		copy(list, src.List)
+		for i, zval := range list {
+			var key data.Value = data.NewIntValue(i)
+			if zval.Name != "" {
+				key = data.NewStringValue(zval.Name)
+			}
+			if _, ctl := suspendGenerator(from, co, generatorYield{key: key, value: zval.Value, delegated: true}); ctl != nil {
+				return nil, ctl
+			}
+		}
+		return data.NewNullValue(), nil
+	case *data.ObjectValue:
+		var keys []string
+		var values []data.Value
+		src.RangeProperties(func(key string, value data.Value) bool {
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+		for i, key := range keys {
+			if _, ctl := suspendGenerator(from, co, generatorYield{key: data.NewStringValue(key), value: values[i], delegated: true}); ctl != nil {
+				return nil, ctl
+			}
+		}
+		return data.NewNullValue(), nil
+	case *data.ThisValue:
+		return yieldFrom(ctx, from, co, src.ClassValue)
+	case *data.ClassValue:
+		if gc, ok := src.Class.(*GeneratorClass); ok {
+			if inner, ok := gc.generator.(*GeneratorFrame); ok {
+				return yieldFromGenerator(from, co, inner)
+			}
+			return yieldFromIterator(ctx, from, co, gc.generator)
+		}
+		isIterator, ctl := checkClassIs(ctx, src.Class, "Iterator")
+		if ctl != nil {
+			return nil, ctl
+		}
+		if isIterator {
+			return yieldFromIterator(ctx, from, co, &classIterator{obj: src})
+		}
+		isAggregate, ctl := checkClassIs(ctx, src.Class, "IteratorAggregate")
+		if ctl != nil {
+			return nil, ctl
+		}
+		if isAggregate {
+			inner, ctl := callValueMethod(src, "getIterator")
+			if ctl != nil {
+				return nil, ctl
+			}
+			return yieldFrom(ctx, from, co, inner)
+		}
+	case data.Iterator:
+		return yieldFromIterator(ctx, from, co, src)
+	}
+	return nil, data.NewErrorThrowByName(from, fmt.Errorf("Can use \"yield from\" only with arrays and Traversables"), "Error")
+}
+
+// yieldFromGenerator 委托给另一个生成器帧
+func yieldFromGenerator(from data.From, co *Coroutine, inner *GeneratorFrame) (data.GetValue, data.Control) {
+	if ctl := inner.ensureStarted(); ctl != nil {
+		return nil, ctl
+	}
+	for !inner.co.IsFinished() {
+		sig, ctl := suspendGeneratorSignal(from, co, generatorYield{key: inner.currentKey, value: inner.currentValue, delegated: true})
+		if ctl != nil {
+			return nil, ctl
+		}
+		if sig.Throw != nil {
+			ctl = inner.Throw(nil, sig.Throw)
+		} else if sig.Value != nil {
+			ctl = inner.Send(nil, sig.Value)
+		} else {
+			ctl = inner.Next(nil)
+		}
+		if ctl != nil {
+			return nil, ctl
+		}
+	}
+	return inner.GetReturn(nil)
+}
+
+// yieldFromIterator 委托给任意 Iterator（不支持 send/throw 转发）
+func yieldFromIterator(ctx data.Context, from data.From, co *Coroutine, it data.Iterator) (data.GetValue, data.Control) {
+	if _, ctl := it.Rewind(ctx); ctl != nil {
+		return nil, ctl
+	}
+	for {
+		valid, ctl := it.Valid(ctx)
+		if ctl != nil {
+			return nil, ctl
+		}
+		if b, ok := valid.(data.AsBool); !ok {
+			break
+		} else if v, _ := b.AsBool(); !v {
+			break
+		}
+		value, ctl := it.Current(ctx)
+		if ctl != nil {
+			return nil, ctl
+		}
+		key, ctl := it.Key(ctx)
+		if ctl != nil {
+			return nil, ctl
+		}
+		if _, ctl := suspendGenerator(from, co, generatorYield{key: key, value: value, delegated: true}); ctl != nil {
+			return nil, ctl
+		}
+		if ctl := it.Next(ctx); ctl != nil {
+			return nil, ctl
+		}
+	}
+	return data.NewNullValue(), nil
+}
+
+// suspendGeneratorSignal 与 suspendGenerator 相同，但把 throw 信号原样交给调用方转发
+func suspendGeneratorSignal(from data.From, co *Coroutine, y generatorYield) (CoroutineSignal, data.Control) {
+	if co.IsDestroying() {
+		_, ctl := suspendGenerator(from, co, y)
+		return CoroutineSignal{}, ctl
+	}
+	sig := co.Suspend(y)
+	if sig.Destroy {
+		return sig, &DestroyControl{}
+	}
+	return sig, nil
+}
+
+// classIterator 把实现 Iterator 接口的脚本类适配为 data.Iterator
+type classIterator struct {
+	obj *data.ClassValue
+}
+
+func (c *classIterator) Current(_ data.Context) (data.Value, data.Control) {
+	return callValueMethod(c.obj, "current")
+}
+
+func (c *classIterator) Key(_ data.Context) (data.Value, data.Control) {
+	return callValueMethod(c.obj, "key")
+}
+
+func (c *classIterator) Next(_ data.Context) data.Control {
+	return callVoidMethod(c.obj, "next")
+}
+
+func (c *classIterator) Rewind(_ data.Context) (data.Value, data.Control) {
+	return data.NewNullValue(), callVoidMethod(c.obj, "rewind")
+}
+
+func (c *classIterator) Valid(_ data.Context) (data.Value, data.Control) {
+	ok, ctl := callBoolMethod(c.obj, "valid")
+	return data.NewBoolValue(ok), ctl
+}
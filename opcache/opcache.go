@@ -0,0 +1,293 @@
+// Package opcache 解析缓存，作用类似 PHP 的 opcache，分两层：
+//
+//   - Programs：进程内的解析结果缓存，保存构建并优化后的语法树，同一进程内反复解析同一文件
+//     （如每次请求渲染的模板）时直接复用，不再分词、解析与优化。语法树在解析时向 VM 注册类，
+//     因此每个 VM 各持有一份，不跨 VM 共享。
+//   - 磁盘缓存（Load/Store）：保存源文件分词后的 token 序列，供新进程跳过分词；
+//     由 zy.cache_enable 控制，与 PHP 的 opcache.enable_cli 一样默认关闭。
+//
+// 两层都以文件的绝对路径、修改时间、大小和内容哈希为键，任一项变化都会重新解析并覆盖旧缓存。
+// CLI、HTTP 开发模式（runtime.TempVM）与 LSP 都经过 parser.ParseFile，共用同一磁盘缓存目录。
+package opcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/php-any/origami/lexer"
+)
+
+// ini 配置项
+const (
+	IniEnable = "zy.cache_enable" // 是否启用磁盘缓存，默认 "0" 关闭
+	IniDir    = "zy.cache_dir"    // 缓存目录，为空时使用用户缓存目录下的 zy
+)
+
+// Setting 读取配置项，由 std/php 在 Load 时注入（ini_get）；为 nil 时缓存关闭
+var Setting func(key string) (string, bool)
+
+const (
+	magic = "ZYTK"
+	// version 缓存格式与分词结果的版本，词法分析器的输出变化时递增，使旧缓存失效
+	version = 3
+	ext     = ".tok"
+)
+
+// Enabled 是否启用磁盘缓存
+func Enabled() bool {
+	if Setting == nil {
+		return false
+	}
+	v, ok := Setting(IniEnable)
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "0", "off", "false", "no":
+		return false
+	}
+	return true
+}
+
+// Dir 缓存目录
+func Dir() string {
+	if Setting != nil {
+		if v, ok := Setting(IniDir); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "zy")
+	}
+	return filepath.Join(".zy", "cache")
+}
+
+// entry 缓存文件头
+type entry struct {
+	path  string
+	mtime int64
+	size  int64
+	sum   [sha256.Size]byte
+	body  []byte // 编码后的 token
+}
+
+func entryFile(dir, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+ext)
+}
+
+// Load 读取文件 path（内容为 content）的缓存 token，缓存不存在或已失效时返回 false
+func Load(path string, content []byte) ([]lexer.Token, bool) {
+	if !Enabled() {
+		return nil, false
+	}
+	abs, info, ok := source(path)
+	if !ok {
+		return nil, false
+	}
+	raw, err := os.ReadFile(entryFile(Dir(), abs))
+	if err != nil {
+		return nil, false
+	}
+	e, err := decodeEntry(raw)
+	if err != nil || e.path != abs || e.mtime != info.ModTime().UnixNano() || e.size != info.Size() || e.sum != sha256.Sum256(content) {
+		return nil, false
+	}
+	tokens, err := lexer.DecodeTokens(e.body)
+	if err != nil {
+		return nil, false
+	}
+	return tokens, true
+}
+
+// Store 写入文件 path 的分词结果；写入失败时静默忽略，不影响执行
+func Store(path string, content []byte, tokens []lexer.Token) {
+	if !Enabled() {
+		return
+	}
+	abs, info, ok := source(path)
+	if !ok {
+		return
+	}
+	body, err := lexer.EncodeTokens(tokens)
+	if err != nil {
+		return
+	}
+	e := &entry{path: abs, mtime: info.ModTime().UnixNano(), size: info.Size(), sum: sha256.Sum256(content), body: body}
+	dir := Dir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	// 先写临时文件再改名，并发的进程不会读到写了一半的缓存
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(e.encode())
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), entryFile(dir, abs)) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// Programs 进程内的解析结果缓存，零值可用，并发安全
+type Programs struct {
+	m sync.Map // 绝对路径 → *program
+}
+
+type program struct {
+	mtime int64
+	size  int64
+	sum   [sha256.Size]byte
+	value any
+}
+
+// Load 返回文件 path 缓存的解析结果，文件不存在或已变化时返回 false
+func (c *Programs) Load(path string) (any, bool) {
+	abs, info, ok := source(path)
+	if !ok {
+		return nil, false
+	}
+	v, ok := c.m.Load(abs)
+	if !ok {
+		return nil, false
+	}
+	e := v.(*program)
+	if e.mtime != info.ModTime().UnixNano() || e.size != info.Size() {
+		return nil, false
+	}
+	content, err := os.ReadFile(abs)
+	if err != nil || e.sum != sha256.Sum256(content) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Store 缓存文件 path 的解析结果 value，content 为解析时读到的文件内容
+func (c *Programs) Store(path string, content []byte, value any) {
+	abs, info, ok := source(path)
+	if !ok {
+		return
+	}
+	c.m.Store(abs, &program{mtime: info.ModTime().UnixNano(), size: info.Size(), sum: sha256.Sum256(content), value: value})
+}
+
+func source(path string) (string, os.FileInfo, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", nil, false
+	}
+	info, err := os.Stat(abs)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", nil, false
+	}
+	return abs, info, true
+}
+
+func (e *entry) encode() []byte {
+	buf := make([]byte, 0, len(e.body)+len(e.path)+64)
+	buf = append(buf, magic...)
+	buf = binary.AppendUvarint(buf, version)
+	buf = binary.AppendUvarint(buf, uint64(len(e.path)))
+	buf = append(buf, e.path...)
+	buf = binary.AppendVarint(buf, e.mtime)
+	buf = binary.AppendVarint(buf, e.size)
+	buf = append(buf, e.sum[:]...)
+	return append(buf, e.body...)
+}
+
+var errInvalidEntry = errors.New("无效的缓存文件")
+
+func decodeEntry(raw []byte) (*entry, error) {
+	if !bytes.HasPrefix(raw, []byte(magic)) {
+		return nil, errInvalidEntry
+	}
+	raw = raw[len(magic):]
+	v, n := binary.Uvarint(raw)
+	if n <= 0 || v != version {
+		return nil, errInvalidEntry
+	}
+	raw = raw[n:]
+	l, n := binary.Uvarint(raw)
+	if n <= 0 || l > uint64(len(raw)-n) {
+		return nil, errInvalidEntry
+	}
+	e := &entry{path: string(raw[n : n+int(l)])}
+	raw = raw[n+int(l):]
+	if e.mtime, n = binary.Varint(raw); n <= 0 {
+		return nil, errInvalidEntry
+	}
+	raw = raw[n:]
+	if e.size, n = binary.Varint(raw); n <= 0 {
+		return nil, errInvalidEntry
+	}
+	raw = raw[n:]
+	if len(raw) < sha256.Size {
+		return nil, errInvalidEntry
+	}
+	copy(e.sum[:], raw)
+	e.body = raw[sha256.Size:]
+	return e, nil
+}
+
+// Stats 缓存目录的统计信息
+type Stats struct {
+	Dir     string
+	Entries int   // 缓存文件数
+	Stale   int   // 源文件已修改或删除的缓存文件数
+	Bytes   int64 // 缓存文件总大小
+}
+
+// Stat 统计缓存目录
+func Stat() (Stats, error) {
+	st := Stats{Dir: Dir()}
+	files, err := filepath.Glob(filepath.Join(st.Dir, "*"+ext))
+	if err != nil {
+		return st, err
+	}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		st.Entries++
+		st.Bytes += int64(len(raw))
+		e, err := decodeEntry(raw)
+		if err != nil {
+			st.Stale++
+			continue
+		}
+		info, err := os.Stat(e.path)
+		if err != nil || info.ModTime().UnixNano() != e.mtime || info.Size() != e.size {
+			st.Stale++
+		}
+	}
+	return st, nil
+}
+
+// Clear 删除所有缓存文件，返回删除的数量
+func Clear() (int, error) {
+	dir := Dir()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil {
+		return 0, err
+	}
+	tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	n := 0
+	for _, file := range append(files, tmps...) {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return n, err
+		}
+		if strings.HasSuffix(file, ext) {
+			n++
+		}
+	}
+	return n, nil
+}
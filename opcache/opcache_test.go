@@ -0,0 +1,120 @@
+package opcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/php-any/origami/lexer"
+)
+
+func useCacheDir(t *testing.T, enable string) string {
+	t.Helper()
+	dir := t.TempDir()
+	old := Setting
+	Setting = func(key string) (string, bool) {
+		switch key {
+		case IniEnable:
+			return enable, true
+		case IniDir:
+			return dir, true
+		}
+		return "", false
+	}
+	t.Cleanup(func() { Setting = old })
+	return dir
+}
+
+func writeSource(t *testing.T, src string) (string, []byte) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "a.php")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file, []byte(src)
+}
+
+func TestLoadAfterStore(t *testing.T) {
+	useCacheDir(t, "1")
+	file, content := writeSource(t, "<?php\necho 1 + 2;\n")
+	if _, ok := Load(file, content); ok {
+		t.Fatal("expected miss before store")
+	}
+	tokens := lexer.NewLexer().TokenizeTemplate(string(content))
+	Store(file, content, tokens)
+
+	cached, ok := Load(file, content)
+	if !ok {
+		t.Fatal("expected hit after store")
+	}
+	if len(cached) != len(tokens) || cached[len(cached)-1].Literal() != tokens[len(tokens)-1].Literal() {
+		t.Fatalf("cached tokens differ: %d vs %d", len(cached), len(tokens))
+	}
+
+	st, err := Stat()
+	if err != nil || st.Entries != 1 || st.Stale != 0 {
+		t.Fatalf("unexpected stats %+v, %v", st, err)
+	}
+}
+
+func TestLoadInvalidatedBySourceChange(t *testing.T) {
+	useCacheDir(t, "1")
+	file, content := writeSource(t, "<?php\necho 1;\n")
+	Store(file, content, lexer.NewLexer().TokenizeTemplate(string(content)))
+
+	changed := []byte("<?php\necho 2;\n")
+	if err := os.WriteFile(file, changed, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Load(file, changed); ok {
+		t.Fatal("expected cache invalidated after the source changed")
+	}
+	if st, _ := Stat(); st.Stale != 1 {
+		t.Fatalf("expected 1 stale entry, got %+v", st)
+	}
+
+	if n, err := Clear(); err != nil || n != 1 {
+		t.Fatalf("expected 1 entry cleared, got %d, %v", n, err)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	dir := useCacheDir(t, "0")
+	file, content := writeSource(t, "<?php\necho 1;\n")
+	Store(file, content, lexer.NewLexer().TokenizeTemplate(string(content)))
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected nothing written with the cache disabled, got %d files", len(entries))
+	}
+}
+
+func TestProgramsInvalidatedBySourceChange(t *testing.T) {
+	file, content := writeSource(t, "<?php\necho 1;\n")
+	var c Programs
+	if _, ok := c.Load(file); ok {
+		t.Fatal("expected miss before store")
+	}
+	c.Store(file, content, "first")
+	if v, ok := c.Load(file); !ok || v != "first" {
+		t.Fatalf("expected hit after store, got %v, %v", v, ok)
+	}
+
+	// 修改时间与大小不变时按内容哈希判断
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("<?php\necho 2;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Load(file); ok {
+		t.Fatal("expected cache invalidated after the source changed")
+	}
+}
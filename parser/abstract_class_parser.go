@@ -22,8 +22,13 @@ func NewAbstractClassParser(parser *Parser) StatementParser {
 
 // Parse 解析 abstract class 定义
 func (p *AbstractClassParser) Parse() (data.GetValue, data.Control) {
-	// 跳过 abstract 关键字
+	// 跳过 abstract 关键字，允许 abstract readonly class
 	p.next()
+	if p.current().Type() == token.READONLY {
+		p.next()
+		p.definingReadonlyClass = true
+		defer func() { p.definingReadonlyClass = false }()
+	}
 
 	// 确保下一个是 class 关键字
 	if p.current().Type() != token.CLASS {
@@ -21,11 +21,13 @@ var parserRouter = map[token.TokenType]func(parser *Parser) StatementParser{
 	token.BREAK:          NewBreakParser,
 	token.CONTINUE:       NewContinueParser,
 	token.GOTO:           NewGotoParser,
+	token.DEFER:          NewDeferParser,
 	token.VAR:            NewVarParser,
 	token.CONST:          NewConstParser,
 	token.FUNC:           NewFunctionParser,
 	token.CLASS:          NewClassParser,
 	token.ABSTRACT:       NewAbstractClassParser,
+	token.READONLY:       NewReadonlyClassParser,
 	token.ENUM:           NewEnumParser,
 	token.INTERFACE:      NewInterfaceParser,
 	token.TRAIT:          NewTraitParser,
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/lexer"
@@ -155,12 +156,18 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 	var traits []string                       // trait 列表
 	var traitAliases []data.TraitAlias        // trait 方法别名列表
 	var constructorProperties []data.Property // 构造函数中声明的属性
+	// 成员声明出错时记录错误并从下一个成员继续
+members:
 	for !p.currentIsTypeOrEOF(token.RBRACE) {
+		start := p.position
 		// 先尝试解析注解
 		var memberAnnotations []*node.Annotation
 		for p.checkPositionIs(0, token.AT, token.HASH) {
 			ann, acl := p.parseAnnotation()
 			if acl != nil {
+				if p.recoverClassMember(acl, start) {
+					continue members
+				}
 				return nil, acl
 			}
 			if ann != nil {
@@ -172,6 +179,9 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 		if p.current().Type() == token.USE {
 			traitNames, aliases, acl := p.parseTraitUse()
 			if acl != nil {
+				if p.recoverClassMember(acl, start) {
+					continue members
+				}
 				return nil, acl
 			}
 			traits = append(traits, traitNames...)
@@ -195,7 +205,8 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 		// 解析访问修饰符
 		modifier := p.parseModifier()
 		if modifier == "" {
-			return nil, data.NewErrorThrow(p.newFrom(), errors.New("缺少访问修饰符"))
+			p.recoverClassMember(data.NewErrorThrow(p.newFrom(), errors.New("缺少访问修饰符")), start)
+			continue
 		}
 
 		// 解析 abstract/final 关键字（也可以在访问修饰符之后）
@@ -209,7 +220,8 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 			}
 		}
 		if isAbstractMethod && isFinalMethod {
-			return nil, data.NewCompileFatal(p.newFrom(), "Cannot use the final modifier on an abstract method")
+			p.recoverClassMember(data.NewCompileFatal(p.newFrom(), "Cannot use the final modifier on an abstract method"), start)
+			continue
 		}
 
 		// 解析readonly关键字（在访问修饰符之后）
@@ -225,15 +237,24 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 			isStatic = true
 			p.next()
 		}
+		// readonly 也可以写在 static 之后
+		if !isReadonly && p.current().Type() == token.READONLY {
+			isReadonly = true
+			p.next()
+		}
 
 		// 解析属性或方法
 		if p.current().Type() == token.VAR ||
 			p.current().Type() == token.CONST ||
 			p.current().Type() == token.VARIABLE ||
 			isIdentOrTypeToken(p.current().Type()) ||
+			isLikeTypeStart(p.Parser) ||
 			(p.checkPositionIs(0, token.TERNARY) && isIdentOrTypeToken(p.peek(1).Type())) {
 			prop, acl := p.parsePropertyWithAnnotations(modifier, isStatic, isReadonly, memberAnnotations)
 			if acl != nil {
+				if p.recoverClassMember(acl, start) {
+					continue members
+				}
 				return nil, acl
 			}
 			if prop != nil {
@@ -247,6 +268,9 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 		} else if p.current().Type() == token.FUNC {
 			method, props, acl := p.parseMethodWithAnnotations(modifier, isStatic, isAbstractMethod, memberAnnotations, &properties, &staticProperties)
 			if acl != nil {
+				if p.recoverClassMember(acl, start) {
+					continue members
+				}
 				return nil, acl
 			}
 			if method != nil {
@@ -264,7 +288,7 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 			p.next()
 			continue
 		} else {
-			return nil, data.NewErrorThrow(p.newFrom(), errors.New("缺少属性或方法声明"))
+			p.recoverClassMember(data.NewErrorThrow(p.newFrom(), errors.New("缺少属性或方法声明")), start)
 		}
 	}
 	p.next() // 跳过结束花括号
@@ -281,12 +305,17 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 		methods,
 	)
 	c.IsAbstract = p.definingAbstractClass
+	c.IsReadonly = p.definingReadonlyClass
+	c.StaticProperties = staticProperties
 	// 用 ClassValue 作为上下文，使 self::/parent::/static:: 在常量初始化器中可用
 	classVal := data.NewClassValue(c, p.vm.CreateContext([]data.Variable{}))
 	for _, s := range staticPropertiesIndex {
 		property := staticProperties[s]
 		defaultValue := property.GetDefaultValue()
-		if defaultValue != nil {
+		if defaultValue != nil && node.HasConstantFetch(defaultValue) {
+			// 引用了全局常量的类常量在第一次访问时求值，常量可以在类之后定义
+			c.StaticProperty.Store(s, node.NewLazyConstant(defaultValue, classVal, &c.StaticProperty, s))
+		} else if defaultValue != nil {
 			v, acl := defaultValue.GetValue(classVal)
 			if acl != nil {
 				return nil, acl
@@ -341,6 +370,7 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
+		p.classes = append(p.classes, classStmt)
 		if addAnn, ok := classStmt.(node.AddAnnotations); ok {
 			acl = callClassAnnotation(p.Parser, &annotations, addAnn)
 			if acl != nil {
@@ -354,6 +384,7 @@ func (p *ClassParser) Parse() (data.GetValue, data.Control) {
 	if acl != nil {
 		return nil, acl
 	}
+	p.classes = append(p.classes, classStmt)
 	if addAnn, ok := classStmt.(node.AddAnnotations); ok {
 		acl = callClassAnnotation(p.Parser, &annotations, addAnn)
 		if acl != nil {
@@ -383,6 +414,26 @@ func callClassAnnotation(p *Parser, ans *[]*node.Annotation, c node.AddAnnotatio
 	return nil
 }
 
+// checkReadonlyProperty 检查 readonly 属性声明：必须有类型、不能有默认值、不能是静态属性
+func (p *ClassParser) checkReadonlyProperty(from data.From, name string, isStatic, isReadonly bool, defaultValue data.GetValue, ty data.Types) data.Control {
+	if isStatic && p.definingReadonlyClass {
+		return data.NewCompileFatal(from, fmt.Sprintf("Readonly class %s cannot declare static properties", p.currentClass))
+	}
+	if !isReadonly {
+		return nil
+	}
+	name = strings.TrimPrefix(name, "$")
+	switch {
+	case isStatic:
+		return data.NewCompileFatal(from, fmt.Sprintf("Static property %s::$%s cannot be readonly", p.currentClass, name))
+	case ty == nil:
+		return data.NewCompileFatal(from, fmt.Sprintf("Readonly property %s::$%s must have type", p.currentClass, name))
+	case defaultValue != nil:
+		return data.NewCompileFatal(from, fmt.Sprintf("Readonly property %s::$%s cannot have default value", p.currentClass, name))
+	}
+	return nil
+}
+
 // parseClassName 解析类名, 只管定义
 func (p *ClassParser) parseClassName() string {
 	if p.current().Type() != token.IDENTIFIER {
@@ -442,19 +493,37 @@ func (p *ClassParser) ParseConstructorParameters() ([]data.GetValue, []data.Prop
 
 // parseModifier 解析访问修饰符
 func (p *ClassParser) parseModifier() string {
-	switch p.current().Type() {
-	case token.PUBLIC:
-		p.next()
-		return "public"
-	case token.PROTECTED:
+	p.writeModifier = ""
+	modifier := ""
+	for p.checkPositionIs(0, token.PUBLIC, token.PROTECTED, token.PRIVATE) {
+		m := p.current().Literal()
 		p.next()
-		return "protected"
-	case token.PRIVATE:
-		p.next()
-		return "private"
-	default:
+		// 非对称可见性：public private(set) int $id，单独的 private(set) 表示 public 读取
+		if w, ok := p.parseWriteModifier(m); ok {
+			p.writeModifier = w
+			continue
+		}
+		if modifier != "" {
+			p.position--
+			break
+		}
+		modifier = m
+	}
+	if modifier == "" {
 		return "public"
 	}
+	return modifier
+}
+
+// parseWriteModifier 已读取访问修饰符 m，后面跟着 (set) 时作为写入修饰符
+func (p *Parser) parseWriteModifier(m string) (string, bool) {
+	if !p.checkPositionIs(0, token.LPAREN) || p.peek(1).Literal() != "set" || !p.checkPositionIs(2, token.RPAREN) {
+		return "", false
+	}
+	p.next()
+	p.next()
+	p.next()
+	return strings.ToLower(m), true
 }
 
 // parseAnnotation 解析注解
@@ -545,6 +614,8 @@ func (p *ClassParser) parseAnnotation() (*node.Annotation, data.Control) {
 // parsePropertyWithAnnotations 解析属性（带注解）
 func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic bool, isReadonly bool, annotations []*node.Annotation) (data.Property, data.Control) {
 	tracker := p.StartTracking()
+	writeModifier := p.writeModifier
+	p.writeModifier = ""
 
 	// 解析访问修饰符（如果还没有解析）
 	if modifier == "" {
@@ -585,7 +656,9 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 		if p.current().Type() == token.ASSIGN {
 			p.next()
 			exprParser := NewExpressionParser(p.Parser)
+			p.definingClassConstant = true
 			defaultValue, acl = exprParser.Parse()
+			p.definingClassConstant = false
 			if acl != nil {
 				return nil, acl
 			}
@@ -605,6 +678,7 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 			isReadonly,
 			defaultValue,
 		)
+		ret.IsConst = true
 		for _, an := range annotations {
 			an.Target = ret
 		}
@@ -642,9 +716,17 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 		return ret, acl
 	}
 
+	// readonly class 中的实例属性都是只读的
+	if p.definingReadonlyClass && !isStatic {
+		isReadonly = true
+	}
+
 	// 解析属性类型（在访问修饰符之后，变量名之前）
 	var propertyType data.Types
-	if isIdentOrTypeToken(p.current().Type()) || p.checkPositionIs(0, token.NULL, token.FALSE, token.SELF) {
+	if isLikeTypeStart(p.Parser) {
+		// like Countable 结构化类型
+		propertyType = parseLikeType(p.Parser)
+	} else if isIdentOrTypeToken(p.current().Type()) || p.checkPositionIs(0, token.NULL, token.FALSE, token.SELF) {
 		// 检查是否是联合类型：string|int|null
 		var unionTypes []data.Types
 
@@ -712,6 +794,9 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 				baseType = data.NewBaseType("self")
 			}
 			propertyType = data.NewNullableType(baseType)
+		} else if p.checkPositionIs(0, token.GENERIC_TYPE) || p.peek(1).Type() == token.LT {
+			// ?T、?array<T>
+			propertyType = data.NewNullableType(parseType(p.Parser))
 		} else {
 			// 处理 ?ClassName 这种可空类类型，需要结合命名空间解析完整类名
 			name := p.current().Literal()
@@ -752,11 +837,24 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 		}
 	}
 
+	// 解析属性钩子 { get => ...; set { ... } }
+	var getHook, setHook data.Method
+	if p.current().Type() == token.LBRACE {
+		getHook, setHook, acl = p.parsePropertyHooks(name, propertyType)
+		if acl != nil {
+			return nil, acl
+		}
+	}
+
 	// 解析分号
 	if p.current().Type() == token.SEMICOLON {
 		p.next()
 	}
 
+	if acl := p.checkReadonlyProperty(tracker.EndBefore(), name, isStatic, isReadonly, defaultValue, propertyType); acl != nil {
+		return nil, acl
+	}
+
 	ret := node.NewPropertyWithReadonly(
 		tracker.EndBefore(),
 		name,
@@ -766,6 +864,18 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 		defaultValue,
 		propertyType,
 	)
+	if writeModifier != "" {
+		if propertyType == nil {
+			return nil, data.NewCompileFatal(tracker.EndBefore(), fmt.Sprintf("Property with asymmetric visibility %s::$%s must have type", p.currentClass, ret.Name))
+		}
+		ret.WithWriteModifier(writeModifier)
+	}
+	if getHook != nil || setHook != nil {
+		ret.WithHooks(getHook, setHook)
+		if acl := p.checkHookedProperty(tracker.EndBefore(), ret); acl != nil {
+			return nil, acl
+		}
+	}
 	for _, an := range annotations {
 		an.Target = ret
 	}
@@ -803,6 +913,104 @@ func (p *ClassParser) parsePropertyWithAnnotations(modifier string, isStatic boo
 	return ret, acl
 }
 
+// parsePropertyHooks 解析属性钩子列表：
+// get => expr; / get { ... } / set => expr; / set { ... } / set(Type $v) { ... }
+// set 没有声明参数时隐含参数 $value；set => expr 等同于 set { $this->属性 = expr; }
+func (p *ClassParser) parsePropertyHooks(name string, propertyType data.Types) (data.Method, data.Method, data.Control) {
+	propName := strings.TrimPrefix(name, "$")
+	p.next() // 跳过 {
+	var getHook, setHook data.Method
+	for !p.currentIsTypeOrEOF(token.RBRACE) {
+		tracker := p.StartTracking()
+		for p.checkPositionIs(0, token.FINAL, token.BIT_AND) {
+			p.next()
+		}
+		kind := strings.ToLower(p.current().Literal())
+		if kind != "get" && kind != "set" {
+			return nil, nil, data.NewErrorThrow(tracker.EndBefore(), fmt.Errorf("未知的属性钩子 %s", p.current().Literal()))
+		}
+		if (kind == "get" && getHook != nil) || (kind == "set" && setHook != nil) {
+			return nil, nil, data.NewCompileFatal(tracker.EndBefore(), fmt.Sprintf("Cannot redeclare property hook \"%s\"", kind))
+		}
+		p.next()
+
+		p.scopeManager.NewScope(false)
+		var params []data.GetValue
+		if kind == "set" {
+			if p.current().Type() == token.LPAREN {
+				var acl data.Control
+				params, acl = p.ParseParameters()
+				if acl != nil {
+					return nil, nil, acl
+				}
+				if len(params) != 1 {
+					return nil, nil, data.NewCompileFatal(tracker.EndBefore(), fmt.Sprintf("%s::$%s::set() must take exactly 1 argument", p.currentClass, propName))
+				}
+			} else {
+				// 隐含参数不声明类型，写入的值按属性类型检查
+				val := p.scopeManager.CurrentScope().AddVariable("value", propertyType, tracker.EndBefore())
+				params = []data.GetValue{node.NewParameter(tracker.EndBefore(), val.GetName(), val.GetIndex(), nil, nil)}
+			}
+		}
+
+		var body []data.GetValue
+		switch p.current().Type() {
+		case token.ARRAY_KEY_VALUE:
+			p.next()
+			expr, acl := NewExpressionParser(p.Parser).Parse()
+			if acl != nil {
+				return nil, nil, acl
+			}
+			from := tracker.EndBefore()
+			if kind == "get" {
+				body = []data.GetValue{node.NewReturnStatement(from, expr)}
+			} else {
+				target := node.NewObjectProperty(from, node.NewThis(from), propName)
+				body = []data.GetValue{node.NewBinaryAssign(from, target, expr)}
+			}
+			if p.current().Type() == token.SEMICOLON {
+				p.next()
+			}
+		case token.LBRACE:
+			var acl data.Control
+			body, acl = p.ParseFunctionBody()
+			if acl != nil {
+				return nil, nil, acl
+			}
+		default:
+			return nil, nil, data.NewErrorThrow(tracker.EndBefore(), fmt.Errorf("属性钩子 %s 缺少主体", kind))
+		}
+		vars := p.GetVariables()
+		p.scopeManager.PopScope()
+
+		var ret data.Types
+		if kind == "get" {
+			ret = propertyType
+		}
+		hook := node.NewMethod(tracker.EndBefore(), "$"+propName+"::"+kind, "public", false, params, body, vars, ret)
+		if kind == "get" {
+			getHook = hook
+		} else {
+			setHook = hook
+		}
+	}
+	p.next() // 跳过 }
+	return getHook, setHook, nil
+}
+
+// checkHookedProperty 检查带钩子的属性声明：不能是静态或 readonly 属性，虚拟属性不能有默认值
+func (p *ClassParser) checkHookedProperty(from data.From, prop *node.ClassProperty) data.Control {
+	switch {
+	case prop.IsStatic:
+		return data.NewCompileFatal(from, "Cannot declare hooks for static property")
+	case prop.IsReadonly:
+		return data.NewCompileFatal(from, "Hooked properties cannot be readonly")
+	case prop.IsVirtual && prop.DefaultValue != nil:
+		return data.NewCompileFatal(from, fmt.Sprintf("Cannot specify default value for virtual hooked property %s::$%s", p.currentClass, prop.Name))
+	}
+	return nil
+}
+
 // parseMethodWithAnnotations 解析方法（带注解）
 func (p *ClassParser) parseMethodWithAnnotations(modifier string, isStatic bool, isAbstract bool, annotations []*node.Annotation, properties *[]data.Property, staticProperties *map[string]data.Property) (data.Method, []data.Property, data.Control) {
 	// 跳过function关键字
@@ -824,6 +1032,10 @@ func (p *ClassParser) parseMethodWithAnnotations(modifier string, isStatic bool,
 	p.currentFunction = name
 	p.next()
 
+	// 方法的类型参数 public function map<U>(callable $f): array<U>
+	generics := parseGenericParams(p.Parser)
+	markGenericTokens(p.Parser, genericNames(generics))
+
 	// 检查是否是构造函数
 	isConstructor := name == token.ConstructName
 
@@ -905,6 +1117,10 @@ func (p *ClassParser) parseMethodWithAnnotations(modifier string, isStatic bool,
 			var unionTypes []data.Types
 
 			parseOneTypeAtom := func() (data.Types, data.Control) {
+				// like Writer 结构化类型
+				if isLikeTypeStart(p.Parser) {
+					return parseLikeType(p.Parser), nil
+				}
 				if !p.checkPositionIs(0,
 					token.IDENTIFIER,
 					token.STRING,
@@ -917,10 +1133,16 @@ func (p *ClassParser) parseMethodWithAnnotations(modifier string, isStatic bool,
 					token.STATIC,
 					token.SELF,
 					token.PARENT,
+					token.GENERIC_TYPE,
 				) {
 					return nil, data.NewErrorThrow(tracker.EndBefore(), errors.New("无法识别返回类型的定义符号"+p.current().Literal()))
 				}
 
+				// 类型参数 T 与 array<T>
+				if p.checkPositionIs(0, token.GENERIC_TYPE) || p.peek(1).Type() == token.LT {
+					return parseType(p.Parser), nil
+				}
+
 				// 处理 static 关键字（返回类型中的 static 表示调用类的实例）
 				if p.current().Type() == token.STATIC {
 					p.next()
@@ -1047,6 +1269,9 @@ func (p *ClassParser) parseMethodWithAnnotations(modifier string, isStatic bool,
 		vars,
 		retType,
 	)
+	if classMethod, ok := method.(*node.ClassMethod); ok {
+		classMethod.Generic = generics
+	}
 
 	// 如果是抽象方法，包装为 AbstractMethod
 	var ret data.Method = method
@@ -1100,31 +1325,9 @@ func (p *ClassParser) parseMethodWithAnnotations(modifier string, isStatic bool,
 	return ret, constructorProps, nil
 }
 
-// 解释泛型定义 class<T>、class<T, Y>、class<string, int>、class<T<int>>
+// 解释泛型定义 class<T>、class<T, Y>、class<string, int>、class<T<int>>、class<T extends Entity>
 func (p *ClassParser) parseGeneric() []data.Types {
-	if p.current().Type() != token.LT {
-		return nil
-	}
-	p.next() // 跳过 <
-
-	var types []data.Types
-	for {
-		typ := parseType(p.Parser)
-		if typ == nil {
-			break
-		}
-		types = append(types, typ)
-		if p.current().Type() == token.GT {
-			p.next() // 跳过 >
-			break
-		}
-		if p.current().Type() == token.COMMA {
-			p.next() // 跳过 ,
-		} else {
-			break
-		}
-	}
-	return types
+	return parseGenericParams(p.Parser)
 }
 
 // 解析类型（支持嵌套泛型）
@@ -1355,6 +1558,7 @@ func (p *ClassParser) mergeTraits(class *node.ClassStatement, traitNames []strin
 					// 添加到属性列表
 					class.Properties[propertyName] = property
 					class.PropertiesIndex = append(class.PropertiesIndex, propertyName)
+					class.ShapeCache().Reset()
 				}
 			}
 		}
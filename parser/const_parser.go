@@ -30,6 +30,9 @@ func (p *ConstParser) Parse() (data.GetValue, data.Control) {
 		return nil, data.NewErrorThrow(tracker.EndBefore(), fmt.Errorf("const 需要变量符号"))
 	}
 	name := p.current().Literal()
+	if name == "defer" {
+		p.deferConstant = true
+	}
 	p.next()
 
 	t := data.Const{}
@@ -50,9 +53,17 @@ func (p *ConstParser) Parse() (data.GetValue, data.Control) {
 	// 创建变量跟踪
 	val := p.scopeManager.CurrentScope().AddVariable(name, t, tracker.EndBefore())
 
-	return node.NewConstStatement(
+	stmt := node.NewConstStatement(
 		tracker.EndBefore(),
 		node.NewVariableWithFirst(tracker.EndBefore(), val),
 		initializer,
-	), acl
+	)
+	if p.scopeManager.CurrentScope().GetParent() == nil {
+		// 脚本顶层的常量同时是全局常量
+		stmt.Name = name
+		if p.namespace != nil {
+			stmt.Name = p.namespace.GetName() + "\\" + name
+		}
+	}
+	return stmt, acl
 }
@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
 	"github.com/php-any/origami/token"
@@ -16,9 +20,10 @@ func NewDeclareParser(p *Parser) StatementParser {
 }
 
 // Parse 解析 declare 语句
-// 语法：declare(strict_types=1);
-// 目前只做解析，不实现具体逻辑
+// 语法：declare(strict_types=1); declare(ticks=1, encoding='UTF-8');
+// 目前只有 strict_types 会影响执行，其它指令只做记录
 func (p *DeclareParser) Parse() (data.GetValue, data.Control) {
+	tracker := p.StartTracking()
 	p.next() // 跳过 declare
 
 	// 左括号
@@ -26,13 +31,40 @@ func (p *DeclareParser) Parse() (data.GetValue, data.Control) {
 		return nil, acl
 	}
 
+	directives := make(map[string]data.Value)
 	for !p.isEOF() && !p.checkPositionIs(0, token.RPAREN) {
+		name := p.current().Literal()
+		p.next()
+		if acl := p.nextAndCheck(token.ASSIGN); acl != nil {
+			return nil, acl
+		}
+		literal := p.current().Literal()
+		var value data.Value
+		if p.current().Type() == token.INT {
+			n, _ := strconv.Atoi(literal)
+			value = data.NewIntValue(n)
+		} else {
+			value = data.NewStringValue(strings.Trim(literal, "'\""))
+		}
 		p.next()
+
+		if name == "strict_types" {
+			if iv, ok := value.(*data.IntValue); !ok || (iv.Value != 0 && iv.Value != 1) {
+				return nil, data.NewCompileFatal(p.newFrom(), "strict_types declaration must have 0 or 1 as its value")
+			}
+		}
+		directives[strings.ToLower(name)] = value
+
+		if p.checkPositionIs(0, token.COMMA) {
+			p.next()
+		} else if !p.checkPositionIs(0, token.RPAREN) {
+			return nil, data.NewErrorThrow(p.newFrom(), fmt.Errorf("declare 语句语法错误: %s", p.current().Literal()))
+		}
 	}
 	p.next()
 	if p.checkPositionIs(0, token.SEMICOLON) {
 		p.next()
 	}
 
-	return node.NewTodo(), nil
+	return node.NewDeclareStatement(tracker.EndBefore(), directives), nil
 }
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/token"
+)
+
+// DeferParser 解析 defer 语句：defer <表达式>; 或 defer function() {...};
+// defer 是上下文关键字，作为名称的情况见 lexer 的 deferIsName
+type DeferParser struct {
+	*Parser
+}
+
+func NewDeferParser(p *Parser) StatementParser {
+	return &DeferParser{p}
+}
+
+func (p *DeferParser) Parse() (data.GetValue, data.Control) {
+	if p.deferIsName() {
+		return NewIdentParser(p.Parser).Parse()
+	}
+	tracker := p.StartTracking()
+	// 跳过 defer 关键字
+	p.next()
+
+	if p.current().Type() == token.SEMICOLON {
+		return nil, data.NewErrorThrow(tracker.EndBefore(), errors.New("defer 语句后面需要跟随表达式或闭包"))
+	}
+	work, acl := NewExpressionParser(p.Parser).Parse()
+	if acl != nil {
+		return nil, acl
+	}
+	if work == nil {
+		return nil, data.NewErrorThrow(tracker.EndBefore(), errors.New("defer 语句后面需要跟随表达式或闭包"))
+	}
+
+	from := tracker.EndBefore()
+	return node.NewDeferStatement(from, work), nil
+}
+
+// deferIsName defer 是否是函数或常量的名称：声明了名为 defer 的函数时（如 Laravel 的 defer() 辅助函数）
+// defer(...) 是函数调用；定义了名为 defer 的常量时，后面不能开始 defer 语句的 defer（如 echo defer;）是常量
+func (p *DeferParser) deferIsName() bool {
+	name := p.current().Literal()
+	if p.checkPositionIs(1, token.LPAREN) {
+		_, ok := p.findFullFunNameByNamespace(name)
+		return ok || p.deferFunction
+	}
+	if _, ok := p.vm.GetConstant(name); !ok && !p.deferConstant {
+		return false
+	}
+	return !p.checkPositionIs(1, token.VARIABLE, token.IDENTIFIER, token.FUNC, token.FN, token.STATIC, token.NEW,
+		token.THIS, token.PARENT, token.SELF, token.LBRACE, token.NAMESPACE_SEPARATOR)
+}
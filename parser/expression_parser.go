@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/php-any/origami/data"
@@ -84,12 +85,15 @@ func (ep *ExpressionParser) parseAssignment() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -107,6 +111,7 @@ func (ep *ExpressionParser) parseTernary() (data.GetValue, data.Control) {
 	case token.ELVIS:
 		// 这是 ?: 简写形式（Elvis 运算符）
 		// $a ?: $b 等价于 $a ? $a : $b
+		operator := ep.current()
 		ep.next() // 跳过 ?:
 
 		// 解析假值表达式
@@ -114,6 +119,9 @@ func (ep *ExpressionParser) parseTernary() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
+		if acl := ep.requireOperand(operator, falseValue); acl != nil {
+			return nil, acl
+		}
 		// 创建三目运算符表达式，真值使用条件表达式本身
 		return node.NewTernaryExpression(
 			tracker.EndBefore(),
@@ -152,15 +160,23 @@ func (ep *ExpressionParser) parseTernary() (data.GetValue, data.Control) {
 		}
 
 		// 否则按三目运算符处理
+		operator := ep.current()
 		ep.next() // 跳过 ?
 
-		// 解析真值表达式
-		trueValue, acl := ep.parseTernary()
-		if acl != nil {
-			return nil, acl
+		// 解析真值表达式；`? :` 中间有空白时同样是 ?: 简写
+		trueValue := expr
+		if ep.current().Type() != token.COLON {
+			trueValue, acl = ep.parseTernary()
+			if acl != nil {
+				return nil, acl
+			}
+			if acl := ep.requireOperand(operator, trueValue); acl != nil {
+				return nil, acl
+			}
 		}
 		// 检查是否有冒号 :
 		if ep.current().Type() == token.COLON {
+			colon := ep.current()
 			ep.next() // 跳过 :
 
 			// 解析假值表达式
@@ -168,6 +184,9 @@ func (ep *ExpressionParser) parseTernary() (data.GetValue, data.Control) {
 			if acl != nil {
 				return nil, acl
 			}
+			if acl := ep.requireOperand(colon, falseValue); acl != nil {
+				return nil, acl
+			}
 			// 创建三目运算符表达式
 			return node.NewTernaryExpression(
 				tracker.EndBefore(),
@@ -183,51 +202,29 @@ func (ep *ExpressionParser) parseTernary() (data.GetValue, data.Control) {
 	}
 }
 
-// parseNullCoalesce 解析 ??（null 合并运算符），优先级介于 ?: 和连接表达式之间。
+// parseNullCoalesce 解析 ??（null 合并运算符），优先级介于 ?: 和逻辑或之间。
 func (ep *ExpressionParser) parseNullCoalesce() (data.GetValue, data.Control) {
 	tracker := ep.StartTracking()
-	expr, acl := ep.parseConcatenation()
+	expr, acl := ep.parseLogicalOr()
 	if acl != nil {
 		return nil, acl
 	}
 	for ep.current().Type() == token.NULL_COALESCE {
+		operator := ep.current()
 		ep.next() // 跳过 ??
-		right, acl := ep.parseConcatenation()
+		right, acl := ep.parseLogicalOr()
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewNullCoalesceExpression(
-			tracker.EndBefore(),
-			expr,
-			right,
-		)
-	}
-	return expr, nil
-}
-
-// parseConcatenation 解析字符串连接表达式
-func (ep *ExpressionParser) parseConcatenation() (data.GetValue, data.Control) {
-	tracker := ep.StartTracking()
-	expr, acl := ep.parseLogicalOr()
-	if acl != nil {
-		return nil, acl
-	}
-	for ep.current().Type() == token.DOT {
-		operator := ep.current()
-		ep.next()
-
-		right, acl := ep.parseLogicalOr()
-		if acl != nil {
+		if acl := ep.requireOperand(operator, right); acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr = node.NewNullCoalesceExpression(
 			tracker.EndBefore(),
 			expr,
-			operator,
 			right,
 		)
 	}
-
 	return expr, nil
 }
 
@@ -247,12 +244,15 @@ func (ep *ExpressionParser) parseLogicalOr() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -279,12 +279,15 @@ func (ep *ExpressionParser) parseLogicalAnd() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -305,12 +308,15 @@ func (ep *ExpressionParser) parseEquality() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	// 处理 like 关键字
@@ -346,12 +352,15 @@ func (ep *ExpressionParser) parseBitwiseAnd() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -370,12 +379,15 @@ func (ep *ExpressionParser) parseBitwiseXor() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -394,12 +406,15 @@ func (ep *ExpressionParser) parseBitwiseOr() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -422,7 +437,10 @@ func (ep *ExpressionParser) parseBitwiseOrIndex() (data.GetValue, data.Control)
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -440,7 +458,10 @@ func (ep *ExpressionParser) parseBitwiseXorIndex() (data.GetValue, data.Control)
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -458,7 +479,10 @@ func (ep *ExpressionParser) parseBitwiseAndIndex() (data.GetValue, data.Control)
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -476,7 +500,10 @@ func (ep *ExpressionParser) parseEqualityIndex() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -494,7 +521,10 @@ func (ep *ExpressionParser) parseComparisonIndex() (data.GetValue, data.Control)
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -512,7 +542,10 @@ func (ep *ExpressionParser) parseShiftIndex() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -547,7 +580,10 @@ func (ep *ExpressionParser) parseTermNoRange() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(tracker.EndBefore(), expr, operator, right)
+		expr, acl = ep.newBinary(tracker.EndBefore(), expr, operator, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	return expr, nil
 }
@@ -555,7 +591,7 @@ func (ep *ExpressionParser) parseTermNoRange() (data.GetValue, data.Control) {
 // parseComparison 解析比较表达式
 func (ep *ExpressionParser) parseComparison() (data.GetValue, data.Control) {
 	tracker := ep.StartTracking()
-	expr, acl := ep.parseShift()
+	expr, acl := ep.parsePipe()
 	if acl != nil {
 		return nil, acl
 	}
@@ -569,16 +605,73 @@ func (ep *ExpressionParser) parseComparison() (data.GetValue, data.Control) {
 		operator := ep.current()
 		ep.next()
 
+		right, acl := ep.parsePipe()
+		if acl != nil {
+			return nil, acl
+		}
+		expr, acl = ep.newBinary(
+			tracker.EndBefore(),
+			expr,
+			operator,
+			right,
+		)
+		if acl != nil {
+			return nil, acl
+		}
+	}
+
+	return expr, nil
+}
+
+// parsePipe 解析管道运算符 |>（PHP 8.5+），左结合，优先级低于字符串连接、位移与算术，高于比较：
+// $x |> trim(...) |> strtoupper(...) 即 strtoupper(trim($x))，"a" . "b" |> strtoupper(...) 即 strtoupper("ab")
+func (ep *ExpressionParser) parsePipe() (data.GetValue, data.Control) {
+	tracker := ep.StartTracking()
+	expr, acl := ep.parseConcatenation()
+	if acl != nil {
+		return nil, acl
+	}
+	for ep.current().Type() == token.PIPE {
+		operator := ep.current()
+		ep.next()
+
+		right, acl := ep.parseConcatenation()
+		if acl != nil {
+			return nil, acl
+		}
+		if acl := ep.requireOperand(operator, right); acl != nil {
+			return nil, acl
+		}
+		expr = node.NewPipeExpression(tracker.EndBefore(), expr, right)
+	}
+
+	return expr, nil
+}
+
+// parseConcatenation 解析字符串连接表达式，与 PHP 8 一样优先级低于位移与算术、高于管道与比较
+func (ep *ExpressionParser) parseConcatenation() (data.GetValue, data.Control) {
+	tracker := ep.StartTracking()
+	expr, acl := ep.parseShift()
+	if acl != nil {
+		return nil, acl
+	}
+	for ep.current().Type() == token.DOT {
+		operator := ep.current()
+		ep.next()
+
 		right, acl := ep.parseShift()
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -599,12 +692,15 @@ func (ep *ExpressionParser) parseShift() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -640,17 +736,44 @@ func (ep *ExpressionParser) parseTerm() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
 }
 
+// newBinary 构建二元表达式；缺少操作数时报告语法错误，避免生成带 nil 操作数的节点
+func (p *Parser) newBinary(from data.From, left data.GetValue, operator lexer.Token, right data.GetValue) (data.GetValue, data.Control) {
+	if left == nil {
+		return nil, data.NewErrorThrow(from, fmt.Errorf("语法错误：运算符 '%s' 缺少左操作数", operator.Literal()))
+	}
+	if acl := p.requireOperand(operator, right); acl != nil {
+		return nil, acl
+	}
+	return node.NewBinaryExpression(from, left, operator, right), nil
+}
+
+// requireOperand 运算符后缺少操作数（如 `1 +;`、`$x = ;`、`!;`）时返回指向运算符的语法错误
+func (p *Parser) requireOperand(operator lexer.Token, operand data.GetValue) data.Control {
+	if operand != nil {
+		return nil
+	}
+	// parsePrimary 遇到 ';' 时会跳过它并返回 nil；退回到 ';'，让错误恢复停在本条语句末尾
+	if p.position > 0 && p.tokens[p.position-1].Type() == token.SEMICOLON {
+		p.position--
+	}
+	at := node.NewTokenFrom(p.source, operator.Start(), operator.End(), operator.Line(), operator.Pos())
+	return data.NewErrorThrow(at, fmt.Errorf("语法错误：运算符 '%s' 缺少右操作数", operator.Literal()))
+}
+
 func isSignedNumberToken(t lexer.Token) bool {
 	if t == nil {
 		return false
@@ -698,12 +821,15 @@ func (ep *ExpressionParser) parseFactor() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -713,27 +839,34 @@ func (ep *ExpressionParser) parseFactor() (data.GetValue, data.Control) {
 func (ep *ExpressionParser) parseUnary() (data.GetValue, data.Control) {
 	tracker := ep.StartTracking()
 	if ep.current().Type() == token.SUB || ep.current().Type() == token.NOT || ep.current().Type() == token.BIT_NOT {
-		operator := ep.current().Literal()
+		operator := ep.current()
 		ep.next()
 
 		right, acl := ep.parseUnary()
 		if acl != nil {
 			return nil, acl
 		}
+		if acl := ep.requireOperand(operator, right); acl != nil {
+			return nil, acl
+		}
 		return node.NewUnaryExpression(
 			tracker.EndBefore(),
-			operator,
+			operator.Literal(),
 			right,
 		), nil
 	}
 
 	// 处理引用取值 &$var
 	if ep.current().Type() == token.BIT_AND {
+		operator := ep.current()
 		ep.next()
 		right, acl := ep.parseUnary()
 		if acl != nil {
 			return nil, acl
 		}
+		if acl := ep.requireOperand(operator, right); acl != nil {
+			return nil, acl
+		}
 		return node.NewValueReference(tracker.EndBefore(), right), nil
 	}
 
@@ -746,6 +879,9 @@ func (ep *ExpressionParser) parseUnary() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
+		if acl := ep.requireOperand(operator, right); acl != nil {
+			return nil, acl
+		}
 		for ep.current().Type() == token.SEMICOLON {
 			// 跳过没意义的分号
 			ep.next()
@@ -817,12 +953,15 @@ func (ep *ExpressionParser) parseUnary() (data.GetValue, data.Control) {
 			if acl != nil {
 				return nil, acl
 			}
-			expr = node.NewBinaryExpression(
+			expr, acl = ep.newBinary(
 				tracker.EndBefore(),
 				expr,
 				operator,
 				right,
 			)
+			if acl != nil {
+				return nil, acl
+			}
 		}
 	}
 
@@ -846,12 +985,15 @@ func (ep *ExpressionParser) parsePower() (data.GetValue, data.Control) {
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(
+		expr, acl = ep.newBinary(
 			tracker.EndBefore(),
 			expr,
 			operator,
 			right,
 		)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 
 	return expr, nil
@@ -90,6 +90,7 @@ func (fp *FnParser) Parse() (data.GetValue, data.Control) {
 	vars := fp.scopeManager.CurrentScope().GetVariables()
 	// 弹出函数作用域
 	fp.scopeManager.PopScope()
+	declareCaptured(fp.scopeManager.CurrentScope(), freeVariableNames(vars, params), tracker.EndBefore())
 
 	// 构建 parent 映射，自动捕获外部变量
 	parent := make(map[int]int)
@@ -122,6 +123,17 @@ func (fp *FnParser) Parse() (data.GetValue, data.Control) {
 	return fn, nil
 }
 
+// freeVariableNames 箭头函数体内除形参外的变量名，即自动捕获的外部变量
+func freeVariableNames(vars []data.Variable, params []data.GetValue) []string {
+	var names []string
+	for _, v := range vars {
+		if v != nil && !isParameterName(params, v.GetName()) {
+			names = append(names, v.GetName())
+		}
+	}
+	return names
+}
+
 // isParameterName 判断 name 是否为 params 中某个形参的名称（形参不应从父作用域捕获）
 func isParameterName(params []data.GetValue, name string) bool {
 	for _, p := range params {
@@ -84,6 +84,7 @@ func (fp *FunctionParser) Parse() (data.GetValue, data.Control) {
 
 			// 弹出函数作用域，返回到外部作用域
 			fp.scopeManager.PopScope()
+			declareCaptured(fp.scopeManager.CurrentScope(), captureNames(captures), tracker.EndBefore())
 
 			// 构建 parent 映射，仅捕获 use 声明的变量
 			parent := make(map[int]int)
@@ -115,6 +116,9 @@ func (fp *FunctionParser) Parse() (data.GetValue, data.Control) {
 		return nil, data.NewErrorThrow(tracker.EndBefore(), errors.New("缺少函数名"))
 	}
 	name := fp.current().Literal()
+	if name == "defer" {
+		fp.deferFunction = true
+	}
 
 	if fp.namespace != nil {
 		name = fp.namespace.GetName() + "\\" + name
@@ -125,6 +129,10 @@ func (fp *FunctionParser) Parse() (data.GetValue, data.Control) {
 
 	fp.next()
 
+	// 类型参数 function map<T, R>(array<T> $xs, callable $f): array<R>
+	generics := parseGenericParams(fp.Parser)
+	markGenericTokens(fp.Parser, genericNames(generics))
+
 	// 创建新的函数作用域
 	fp.scopeManager.NewScope(false)
 
@@ -156,6 +164,7 @@ func (fp *FunctionParser) Parse() (data.GetValue, data.Control) {
 		ret,
 		returnsReference,
 	)
+	f.Generic = generics
 
 	//if acl := fp.vm.AddFunc(f); acl != nil {
 	//	return nil, acl
@@ -222,6 +231,24 @@ func (fp *FunctionParser) parseClosureUse() ([]UseCapture, data.Control) {
 	return captures, nil
 }
 
+// declareCaptured 在外层作用域登记闭包捕获的变量。
+// 外层只在嵌套闭包的 use 列表（或箭头函数体）中提到某个变量时，外层作用域里还没有它，
+// 外层自己的 use 就不会把值传下来，嵌套闭包读到的是空值。
+func declareCaptured(scope Scope, names []string, from data.From) {
+	for _, name := range names {
+		scope.AddVariable(name, nil, from)
+	}
+}
+
+// captureNames use 列表中的变量名
+func captureNames(captures []UseCapture) []string {
+	names := make([]string, len(captures))
+	for i, c := range captures {
+		names[i] = c.Name
+	}
+	return names
+}
+
 func (fp FunctionParser) parserReturnType() (data.Types, data.Control) {
 	// 检查是否有返回类型声明
 	// 语法: function name(): returnType 或 function name(): ?returnType
@@ -245,6 +272,10 @@ func (fp FunctionParser) parserReturnType() (data.Types, data.Control) {
 			var unionTypes []data.Types
 
 			parseOneTypeAtom := func() (data.Types, data.Control) {
+				// like Writer 结构化类型
+				if isLikeTypeStart(fp.Parser) {
+					return parseLikeType(fp.Parser), nil
+				}
 				if !fp.checkPositionIs(0,
 					token.IDENTIFIER,
 					token.STRING,
@@ -255,9 +286,14 @@ func (fp FunctionParser) parserReturnType() (data.Types, data.Control) {
 					token.NULL,
 					token.FALSE,
 					token.STATIC,
+					token.GENERIC_TYPE,
 				) {
 					return nil, data.NewErrorThrow(fp.newFrom(), errors.New("无法识别返回类型的定义符号"))
 				}
+				// 类型参数 T 与 array<T>
+				if fp.checkPositionIs(0, token.GENERIC_TYPE) || fp.peek(1).Type() == token.LT {
+					return parseType(fp.Parser), nil
+				}
 				// 处理 static 关键字
 				if fp.current().Type() == token.STATIC {
 					fp.next()
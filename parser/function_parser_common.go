@@ -27,15 +27,20 @@ func (p *FunctionParserCommon) ParseFunctionBody() ([]data.GetValue, data.Contro
 		p.next()
 		last := p.position
 		for !p.currentIsTypeOrEOF(token.RBRACE) {
+			start := p.position
 			stmt, acl := stmtParser.Parse()
 			if acl != nil {
+				if p.recoverStatement(acl, start, true) {
+					continue
+				}
 				return nil, acl
 			}
 			if stmt != nil {
+				markOwningAssign(stmt)
 				body = append(body, stmt)
 			} else if last == p.position {
 				t := p.current()
-				return nil, data.NewErrorThrow(p.newFrom(), errors.New("出现死循环 "+t.Literal()))
+				p.recoverStatement(data.NewErrorThrow(p.newFrom(), errors.New("出现死循环 "+t.Literal())), start, true)
 			} else {
 				last = p.position
 			}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/lexer"
+	"github.com/php-any/origami/token"
+)
+
+// parseGenericParams 解析类型参数列表 <T>、<T, R>、<T extends Entity>、<K extends int|string, V>
+func parseGenericParams(p *Parser) []data.Types {
+	if p.current().Type() != token.LT {
+		return nil
+	}
+	p.next() // 跳过 <
+
+	var types []data.Types
+	for {
+		typ := parseType(p)
+		if typ == nil {
+			break
+		}
+		if g, ok := typ.(data.Generic); ok && p.current().Type() == token.EXTENDS {
+			p.next() // 跳过 extends
+			g.Bound = parseGenericBound(p)
+			typ = g
+		}
+		types = append(types, typ)
+		if p.current().Type() == token.GT {
+			p.next() // 跳过 >
+			break
+		}
+		if p.current().Type() == token.COMMA {
+			p.next() // 跳过 ,
+		} else {
+			break
+		}
+	}
+	return types
+}
+
+// parseGenericBound 解析类型参数的约束；约束类在当前位置还未加载时 parseType 会把它当成类型参数，这里按类名处理
+func parseGenericBound(p *Parser) data.Types {
+	bound := parseConstructorParameterType(p)
+	if g, ok := bound.(data.Generic); ok && len(g.Types) == 0 {
+		full, _ := p.findFullClassNameByNamespace(g.Name)
+		return data.NewBaseType(full)
+	}
+	return bound
+}
+
+// genericNames 类型参数名
+func genericNames(types []data.Types) []string {
+	var names []string
+	for _, t := range types {
+		if g, ok := t.(data.Generic); ok {
+			names = append(names, g.Name)
+		}
+	}
+	return names
+}
+
+// markGenericTokens 把函数/方法声明中（从当前位置到方法体结束）与类型参数同名的标识符替换为 GENERIC_TYPE，
+// 这样参数、返回值类型中的 T 会被解析为类型参数；没有方法体的声明到分号结束
+func markGenericTokens(p *Parser, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	depth := 0
+	for i := p.position; i < len(p.tokens); i++ {
+		tok := p.tokens[i]
+		switch tok.Type() {
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			depth--
+		case token.SEMICOLON:
+			if depth == 0 {
+				return
+			}
+		case token.IDENTIFIER:
+			for _, name := range names {
+				if tok.Literal() == name {
+					p.tokens[i] = lexer.NewWorkerToken(token.GENERIC_TYPE, tok.Literal(), tok.Start(), tok.End(), tok.Line(), tok.Pos())
+					break
+				}
+			}
+		}
+		if depth == 0 && tok.Type() == token.RBRACE {
+			return
+		}
+	}
+}
+
+// isGenericTypeStart 当前位置是否是引用类型参数的类型声明：T、?T、array<T>、?array<T>
+func isGenericTypeStart(p *Parser) bool {
+	offset := 0
+	if p.checkPositionIs(0, token.TERNARY) {
+		offset = 1
+	}
+	if p.checkPositionIs(offset, token.GENERIC_TYPE) {
+		return true
+	}
+	return isIdentOrTypeToken(p.peek(offset).Type()) && p.checkPositionIs(offset+1, token.LT)
+}
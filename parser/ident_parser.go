@@ -160,6 +160,11 @@ func (p *IdentParser) Parse() (data.GetValue, data.Control) {
 
 	// 检查是否是变量
 	varInfo := p.scopeManager.LookupVariable(name)
+	if p.definingClassConstant {
+		if fetch := p.classConstantFetch(tracker, name, varInfo); fetch != nil {
+			return fetch, nil
+		}
+	}
 	if varInfo != nil {
 		// 解析后续操作（函数调用、数组访问等）
 		vp := &VariableParser{p.Parser}
@@ -232,6 +237,26 @@ func (p *IdentParser) Parse() (data.GetValue, data.Control) {
 	return node.NewStringLiteral(tracker.EndBefore(), name), nil
 }
 
+// classConstantFetch 类常量初始化器中的常量名：解析时已知的常量（如 PHP_INT_MAX）直接取值，
+// 全局 const 语句声明的常量与未知的名称在运行时读取；varInfo 是普通变量时返回 nil
+func (p *IdentParser) classConstantFetch(tracker *PositionTracker, name string, varInfo data.Variable) data.GetValue {
+	if varInfo != nil {
+		if _, isConst := varInfo.GetType().(data.Const); !isConst {
+			return nil
+		}
+	}
+	if v, ok := p.vm.GetConstant(name); ok {
+		return v
+	}
+	if strings.HasPrefix(name, "\\") {
+		return node.NewConstantFetch(tracker.EndBefore(), name[1:], "")
+	}
+	if p.namespace != nil && !strings.Contains(name, "\\") {
+		return node.NewConstantFetch(tracker.EndBefore(), p.namespace.GetName()+"\\"+name, name)
+	}
+	return node.NewConstantFetch(tracker.EndBefore(), name, "")
+}
+
 // parseIdentCall 解析标识符函数调用 name(...) 或可调用变量 name(...)
 func (p *IdentParser) parseIdentCall(tracker *PositionTracker, name string) (data.GetValue, data.Control) {
 	vp := &VariableParser{p.Parser}
@@ -244,6 +269,13 @@ func (p *IdentParser) parseIdentCall(tracker *PositionTracker, name string) (dat
 		if !ok {
 			return nil, data.NewErrorThrow(tracker.EndBefore(), fmt.Errorf("函数(%s)先加载后才能使用", name))
 		}
+		if node.IsFirstClassCallable(stmt) {
+			return vp.parseSuffix(node.NewFunctionCallable(tracker.EndBefore(), full, fn, ""))
+		}
+		if node.HasPlaceholder(stmt) {
+			from := tracker.EndBefore()
+			return vp.parseSuffix(node.NewPartialApplication(from, node.NewFunctionCallable(from, full, fn, ""), stmt))
+		}
 		callExpr := node.NewCallExpression(tracker.EndBefore(), full, stmt, fn)
 		return vp.parseSuffix(callExpr)
 	}
@@ -266,6 +298,13 @@ func (p *IdentParser) parseIdentCall(tracker *PositionTracker, name string) (dat
 	if acl != nil {
 		return nil, acl
 	}
+	if node.IsFirstClassCallable(stmt) {
+		return vp.parseSuffix(node.NewFunctionCallable(tracker.EndBefore(), name, nil, namespace))
+	}
+	if node.HasPlaceholder(stmt) {
+		from := tracker.EndBefore()
+		return vp.parseSuffix(node.NewPartialApplication(from, node.NewFunctionCallable(from, name, nil, namespace), stmt))
+	}
 	callExpr := node.NewCallTodo(node.NewCallExpression(tracker.EndBefore(), name, stmt, nil), namespace)
 	return vp.parseSuffix(callExpr)
 }
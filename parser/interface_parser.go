@@ -311,6 +311,10 @@ func (p *InterfaceParser) parseInterfaceMethod(modifier string) (data.Method, da
 			var unionTypes []data.Types
 
 			parseOneTypeAtom := func() (data.Types, data.Control) {
+				// like Writer 结构化类型
+				if isLikeTypeStart(p.Parser) {
+					return parseLikeType(p.Parser), nil
+				}
 				if !p.checkPositionIs(0,
 					token.IDENTIFIER,
 					token.STRING,
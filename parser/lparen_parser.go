@@ -182,7 +182,10 @@ func (ep *LparenParser) parseParenthesizedExpression(tracking *PositionTracker)
 		if acl != nil {
 			return nil, acl
 		}
-		expr = node.NewBinaryExpression(termTracker.EndBefore(), expr, op, right)
+		expr, acl = ep.newBinary(termTracker.EndBefore(), expr, op, right)
+		if acl != nil {
+			return nil, acl
+		}
 	}
 	// 检查是否有右括号
 	if ep.current().Type() != token.RPAREN {
@@ -330,6 +330,11 @@ func (p *NewStructParser) parseAnonymousClass(tracker *PositionTracker) (data.Ge
 	// 跳过 class 关键字
 	p.next()
 
+	// 匿名类不继承外层 readonly class 的只读属性
+	readonlyClass := p.definingReadonlyClass
+	p.definingReadonlyClass = false
+	defer func() { p.definingReadonlyClass = readonlyClass }()
+
 	// 根据文件名、行号和列号信息生成匿名类名
 	anonymousClassName := fmt.Sprintf("class@anonymous@%s@%d:%d", fileName, currentLine, currentPos)
 	if p.namespace != nil {
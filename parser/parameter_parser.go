@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/php-any/origami/data"
@@ -14,6 +15,7 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 
 	// 检查 readonly 关键字和访问修饰符（允许 private readonly 或 readonly private 顺序）
 	var paramModifier string
+	var writeModifier string
 	var isReadonly bool
 	for {
 		if parser.checkPositionIs(0, token.READONLY) {
@@ -22,15 +24,22 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 			continue
 		}
 		if parser.checkPositionIs(0, token.PUBLIC, token.PRIVATE, token.PROTECTED) {
+			var modifier string
 			switch parser.current().Type() {
 			case token.PUBLIC:
-				paramModifier = "public"
+				modifier = "public"
 			case token.PRIVATE:
-				paramModifier = "private"
+				modifier = "private"
 			case token.PROTECTED:
-				paramModifier = "protected"
+				modifier = "protected"
 			}
 			parser.next()
+			// 非对称可见性：public private(set) / private(set)
+			if w, ok := parser.parseWriteModifier(modifier); ok {
+				writeModifier = w
+			} else {
+				paramModifier = modifier
+			}
 			continue
 		}
 		break
@@ -50,6 +59,7 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 	}
 
 	varType := ""
+	var genericType data.Types
 	name := ""
 	isParams := false
 	isReference := false // 是否引用
@@ -81,6 +91,38 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 			isReference = true
 		}
 
+		// (T $item)、(?T $item)、(array<T> $items) 泛型类型参数
+		if !isVar && isGenericTypeStart(parser) {
+			genericType = parseConstructorParameterType(parser)
+			isVar = true
+			if parser.checkPositionIs(0, token.BIT_AND) {
+				isReference = true
+				parser.next()
+			}
+			if parser.checkPositionIs(0, token.ELLIPSIS) {
+				isParams = true
+				parser.next()
+			}
+			name = parser.current().Literal()
+			parser.next()
+		}
+
+		// (like Writer $w) 结构化类型参数
+		if !isVar && isLikeTypeStart(parser) {
+			genericType = parseLikeType(parser)
+			isVar = true
+			if parser.checkPositionIs(0, token.BIT_AND) {
+				isReference = true
+				parser.next()
+			}
+			if parser.checkPositionIs(0, token.ELLIPSIS) {
+				isParams = true
+				parser.next()
+			}
+			name = parser.current().Literal()
+			parser.next()
+		}
+
 		// (string|int|null $data) 联合类型参数，兼容引用 & 和可变参数 ...
 		if !isVar && isIdentOrTypeToken(parser.current().Type()) &&
 			parser.checkPositionIs(1, token.IDENTIFIER, token.VARIABLE, token.BIT_OR, token.ELLIPSIS, token.BIT_AND) {
@@ -173,6 +215,10 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 		}
 	}
 
+	if genericType != nil {
+		paramType = genericType
+	}
+
 	// 添加参数到作用域
 	val := parser.scopeManager.CurrentScope().AddVariable(name, paramType, tracking.EndBefore())
 
@@ -187,11 +233,25 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 			return nil, nil, acl
 		}
 	}
+	// readonly 参数或只声明了写入修饰符的参数即使没有访问修饰符也是提升属性（默认 public）
+	if (isReadonly || writeModifier != "") && paramModifier == "" {
+		paramModifier = "public"
+	}
 	// 如果有访问修饰符，创建属性（属性提升）
 	if paramModifier != "" {
+		// readonly class 中提升的属性同样是只读的
+		if parser.definingReadonlyClass {
+			isReadonly = true
+		}
+		if isReadonly && paramType == nil {
+			return nil, nil, data.NewCompileFatal(tracking.EndBefore(), fmt.Sprintf("Readonly property %s::$%s must have type", parser.currentClass, strings.TrimPrefix(name, "$")))
+		}
+		if writeModifier != "" && paramType == nil {
+			return nil, nil, data.NewCompileFatal(tracking.EndBefore(), fmt.Sprintf("Property with asymmetric visibility %s::$%s must have type", parser.currentClass, strings.TrimPrefix(name, "$")))
+		}
 		// 属性类型直接使用 paramType（已经支持联合类型）
 		propertyType := paramType
-		return node.NewPromotedParameter(tracking.EndBefore(), val.GetName(), val.GetIndex(), defaultValue, val.GetType()), node.NewPropertyWithPromoted(
+		property := node.NewPropertyWithPromoted(
 			tracking.EndBefore(),
 			name,
 			paramModifier,
@@ -200,7 +260,11 @@ func parseSingleParameter(parser *Parser) (data.GetValue, data.Property, data.Co
 			true, // 标记为属性提升
 			defaultValue,
 			propertyType,
-		), nil
+		)
+		if writeModifier != "" {
+			property.WithWriteModifier(writeModifier)
+		}
+		return node.NewPromotedParameter(tracking.EndBefore(), val.GetName(), val.GetIndex(), defaultValue, val.GetType()), property, nil
 	}
 
 	// 创建参数节点
@@ -300,6 +364,10 @@ func parseConstructorParameterType(p *Parser) data.Types {
 			}
 			return data.NewNullableType(baseType)
 		}
+		if p.checkPositionIs(0, token.GENERIC_TYPE) || p.peek(1).Type() == token.LT {
+			// ?T、?array<T>
+			return data.NewNullableType(parseType(p))
+		}
 		base := data.NewBaseType(p.current().Literal())
 		p.next()
 		return data.NewNullableType(base)
@@ -308,6 +376,29 @@ func parseConstructorParameterType(p *Parser) data.Types {
 	return data.NewBaseType("mixed")
 }
 
+// isLikeTypeStart 当前位置是否为结构化类型 like Foo 或可空的 ?like Foo
+func isLikeTypeStart(p *Parser) bool {
+	if p.checkPositionIs(0, token.TERNARY) {
+		return p.checkPositionIs(1, token.LIKE) && p.checkPositionIs(2, token.IDENTIFIER)
+	}
+	return p.checkPositionIs(0, token.LIKE) && p.checkPositionIs(1, token.IDENTIFIER)
+}
+
+// parseLikeType 解析结构化类型 like Foo 或 ?like Foo，Foo 按当前命名空间与 use 解析为完整名称
+func parseLikeType(p *Parser) data.Types {
+	nullable := p.checkPositionIs(0, token.TERNARY)
+	if nullable {
+		p.next() // 跳过 ?
+	}
+	p.next() // 跳过 like
+	name, _ := p.findFullClassNameByNamespace(p.current().Literal())
+	p.next()
+	if nullable {
+		return data.NewNullableType(data.NewLikeType(name))
+	}
+	return data.NewLikeType(name)
+}
+
 func parseType(p *Parser) data.Types {
 	// 支持类型关键字（bool, int, string, float, array 等）
 	if !isIdentOrTypeToken(p.current().Type()) {
@@ -367,6 +458,9 @@ func parseType(p *Parser) data.Types {
 		}
 	}
 
+	if typeName == "array" && len(subTypes) > 0 {
+		return data.NewTypedArray(subTypes)
+	}
 	if !data.ISBaseType(typeName) {
 		if full, ok := p.findFullClassNameByNamespace(typeName); ok {
 			typeName = full
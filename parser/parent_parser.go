@@ -40,6 +40,11 @@ func (pp *ParentParser) Parse() (data.GetValue, data.Control) {
 				return nil, acl
 			}
 
+			if node.HasPlaceholder(args) {
+				// parent::method('a', ?)：先取得父类方法的闭包再部分应用
+				callable := node.NewCallParentMethod(tokenFrom, pp.Parser.currentClass, methodName, []data.GetValue{node.NewSpreadArgument(tokenFrom, nil)})
+				return vp.parseSuffix(node.NewPartialApplication(tokenFrom, callable, args))
+			}
 			expr := node.NewCallParentMethod(tokenFrom, pp.Parser.currentClass, methodName, args)
 			return vp.parseSuffix(expr)
 		} else {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/php-any/origami/lexer"
 	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/opcache"
 	"github.com/php-any/origami/token"
 )
 
@@ -35,6 +36,18 @@ type Parser struct {
 
 	// definingAbstractClass 为 true 时正在解析 abstract class
 	definingAbstractClass bool
+	// definingReadonlyClass 为 true 时正在解析 readonly class，类中的属性（包括构造函数提升的属性）都是只读的
+	definingReadonlyClass bool
+	// writeModifier 最近一次解析到的非对称可见性写入修饰符（private(set)/protected(set)），由属性声明取走
+	writeModifier string
+	// classes 本次解析声明的类，供语法树优化处理类方法
+	classes []data.ClassStmt
+	// definingClassConstant 为 true 时正在解析类常量的初始化器，其中的常量名在运行时读取（见 node.ConstantFetch）
+	definingClassConstant bool
+	// deferFunction、deferConstant 为 true 时本文件声明了名为 defer 的函数或常量（两者都在运行时才注册），
+	// 之后的 defer(...) 是函数调用、不能开始 defer 语句的 defer 是常量（见 DeferParser）
+	deferFunction bool
+	deferConstant bool
 }
 
 // NewParser 创建一个新的解析器
@@ -61,6 +74,7 @@ func (p *Parser) reset() {
 	p.uses = make(map[string]string)
 	p.namespace = nil
 	p.scopeManager = NewScopeManager()
+	p.classes = nil
 }
 
 func (p *Parser) Clone() *Parser {
@@ -94,12 +108,37 @@ func (p *Parser) ParseFile(filename string) (*node.Program, data.Control) {
 	if err != nil {
 		return nil, utils.NewThrow(err)
 	}
+	return p.ParseSource(filename, content)
+}
 
+// ParseSource 解析文件 filename 已读出的内容 content，供需要按读到的内容缓存解析结果的调用方使用
+func (p *Parser) ParseSource(filename string, content []byte) (*node.Program, data.Control) {
 	// 重置解析器状态
 	p.reset()
 
 	p.source = &filename
-	// 进行分词
+	// strict_types 由文件内的 declare 语句重新登记
+	node.SetStrictTypes(filename, false)
+	// 进行分词；开启 opcache 时直接使用磁盘缓存中未失效的分词结果
+	if tokens, ok := opcache.Load(filename, content); ok {
+		p.tokens = tokens
+	} else {
+		p.tokens = p.tokenize(filename, content)
+		opcache.Store(filename, content, p.tokens)
+	}
+
+	// 解析程序
+	program, acl := p.parseProgram(make([]data.GetValue, 0))
+	if acl != nil {
+		// 语法错误时同时返回已解析的部分 AST
+		return program, acl
+	}
+
+	return program, nil
+}
+
+// tokenize 对文件内容分词，.php 文件按模板（含 HTML）处理
+func (p *Parser) tokenize(filename string, content []byte) []lexer.Token {
 	ext := len(filename)
 	if ext > 4 && filename[ext-4:] == ".php" {
 		phpContent := string(content)
@@ -113,30 +152,26 @@ func (p *Parser) ParseFile(filename string) (*node.Program, data.Control) {
 		}
 		// 转换 PHP 替代语法（if: endif; 等）为标准花括号语法
 		phpContent = convertAltPHPSyntax(filename, phpContent)
-		p.tokens = p.lexer.TokenizeTemplate(phpContent)
-	} else {
-		p.tokens = p.lexer.Tokenize(string(content))
-	}
-
-	// 解析程序
-	program, acl := p.parseProgram(make([]data.GetValue, 0))
-	if acl != nil {
-		return nil, acl
+		return p.lexer.TokenizeTemplate(phpContent)
 	}
-
-	return program, nil
+	return p.lexer.Tokenize(string(content))
 }
 
 // parseProgram 解析程序
 func (p *Parser) parseProgram(statements []data.GetValue) (*node.Program, data.Control) {
 	last := 0
-	// 解析所有语句
+	// 解析所有语句；语句出错时记录错误并从下一条语句继续，最后一起返回
 	for !p.isEOF() {
+		start := p.position
 		stmt, acl := p.parseStatement()
 		if acl != nil {
+			if p.recoverStatement(acl, start, false) {
+				continue
+			}
 			return nil, acl
 		}
 		if stmt != nil {
+			markOwningAssign(stmt)
 			if n, ok := stmt.(*node.Namespace); ok {
 				p.namespace = n
 				statements = append(statements, stmt)
@@ -163,11 +198,12 @@ func (p *Parser) parseProgram(statements []data.GetValue) (*node.Program, data.C
 		} else if p.position != last {
 			last = p.position
 		} else {
-			return nil, data.NewErrorThrow(p.newFrom(), errors.New("无法识别语句: "+p.current().Literal()))
+			p.recoverStatement(data.NewErrorThrow(p.newFrom(), errors.New("无法识别语句: "+p.current().Literal())), start, false)
 		}
 	}
 
-	return node.NewProgram(nil, statements), nil
+	// 有语法错误时仍返回已解析的部分 AST
+	return node.NewProgram(nil, statements), p.syntaxError()
 }
 
 // current 返回当前词法单元
@@ -231,6 +267,11 @@ func (p *Parser) GetVariables() []data.Variable {
 	return p.scopeManager.CurrentScope().GetVariables()
 }
 
+// Classes 最近一次解析的文件中声明的类
+func (p *Parser) Classes() []data.ClassStmt {
+	return p.classes
+}
+
 // GetNamespace 获取当前解析文件的命名空间名称
 func (p *Parser) GetNamespace() string {
 	if p.namespace != nil {
@@ -270,6 +311,15 @@ func (p *Parser) stopNext() {
 }
 
 func (p *Parser) ShowControl(acl data.Control) {
+	// 一次解析收集到多个语法错误时依次输出
+	if tv, ok := acl.(*data.ThrowValue); ok && len(tv.SyntaxErrors) > 0 {
+		rest := tv.SyntaxErrors
+		defer func() {
+			for _, e := range rest {
+				p.ShowControl(e)
+			}
+		}()
+	}
 	err := acl.AsString()
 
 	// 优先检查是否是 ThrowValue；先打印错误，再打印调用栈
@@ -407,6 +457,28 @@ func (p *Parser) parseStatement() (data.GetValue, data.Control) {
 	}
 }
 
+// markOwningAssign 语句级的 `$x = new Foo(...);` 让新对象归 $x 所有（见 data.OwnVariable），
+// `$x = f(...);` 在 f 是生成器函数时让新建的生成器归 $x 所有（见 data.OwnFresh）；
+// 只在解析语句列表时调用，嵌套在表达式中的赋值（如 `$a = $b = new Foo`、数组元素）不适用
+func markOwningAssign(stmt data.GetValue) {
+	b, ok := stmt.(*node.BinaryAssignVariable)
+	if !ok {
+		return
+	}
+	if _, ok := b.Left.(*node.VariableExpression); !ok {
+		return
+	}
+	switch b.Right.(type) {
+	case *node.NewExpression, *node.NewSelfExpression, *node.NewStaticExpression,
+		*node.NewVariableExpression, *node.NewExpressionDynamic, *node.NewAnonymousClassExpression:
+		b.Owns = true
+	case *node.CallExpression, *node.CallLater, *node.CallMethod,
+		*node.CallStaticMethod, *node.CallStaticMethodLater, *node.CallSelfMethod, *node.CallParentMethod,
+		*node.CallStaticKeywordMethod:
+		b.OwnsFresh = true
+	}
+}
+
 // 只会获取单个值, 不会有表达式, 并且必须有值, 没有就是错误
 func (p *Parser) parseValue() (data.GetValue, bool) {
 	tracker := p.StartTracking()
@@ -468,6 +540,7 @@ func (p *Parser) parseBlock() ([]data.GetValue, data.Control) {
 			return nil, acl
 		}
 		if stmt != nil {
+			markOwningAssign(stmt)
 			statements = append(statements, stmt)
 		}
 		return statements, nil
@@ -482,17 +555,22 @@ func (p *Parser) parseBlock() ([]data.GetValue, data.Control) {
 
 	// 解析语句块中的所有语句
 	for !p.isEOF() && p.current().Type() != token.RBRACE {
+		start := p.position
 		stmt, acl := p.parseStatement()
 		if acl != nil {
+			if p.recoverStatement(acl, start, true) {
+				continue
+			}
 			return nil, acl
 		}
 		for p.checkPositionIs(0, token.SEMICOLON) {
 			p.next()
 		}
 		if stmt != nil {
+			markOwningAssign(stmt)
 			statements = append(statements, stmt)
 		} else {
-			return statements, data.NewErrorThrow(p.newFrom(), errors.New("语法块无法识别"))
+			p.recoverStatement(data.NewErrorThrow(p.newFrom(), errors.New("语法块无法识别")), start, true)
 		}
 	}
 
@@ -703,7 +781,8 @@ func (p *Parser) ParseString(content string, filePath string) (*node.Program, da
 	// 解析程序
 	program, acl := p.parseProgram(make([]data.GetValue, 0))
 	if acl != nil {
-		return nil, acl
+		// 语法错误时同时返回已解析的部分 AST
+		return program, acl
 	}
 
 	// 恢复原始状态
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/token"
+)
+
+// ReadonlyClassParser 表示 readonly 关键字解析器（readonly class）
+type ReadonlyClassParser struct {
+	*Parser
+}
+
+// NewReadonlyClassParser 创建一个新的 readonly 解析器
+func NewReadonlyClassParser(parser *Parser) StatementParser {
+	return &ReadonlyClassParser{
+		parser,
+	}
+}
+
+// Parse 解析 readonly class 定义：类中所有实例属性都是只读的，且不允许动态属性
+func (p *ReadonlyClassParser) Parse() (data.GetValue, data.Control) {
+	// 跳过 readonly 关键字，允许 readonly final class
+	p.next()
+	for p.current().Type() == token.FINAL {
+		p.next()
+	}
+
+	p.definingReadonlyClass = true
+	defer func() { p.definingReadonlyClass = false }()
+
+	switch p.current().Type() {
+	case token.CLASS:
+		return NewClassParser(p.Parser).Parse()
+	case token.ABSTRACT:
+		return NewAbstractClassParser(p.Parser).Parse()
+	}
+	return nil, data.NewErrorThrow(p.newFrom(), errors.New("readonly 关键字后必须是 class 关键字"))
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/token"
+)
+
+// 语法错误恢复：语句、类成员、代码块出错时记录错误，跳过出错部分后继续解析，
+// 一次解析报告文件中的全部语法错误，同时保留其余部分的 AST（供补全等使用）。
+
+// maxSyntaxErrors 单个文件最多收集的语法错误数量，超过后停止解析
+const maxSyntaxErrors = 100
+
+// recordError 记录可以恢复的语法错误；acl 不是错误（如提前结束的控制流）时返回 false，由调用方直接返回
+func (p *Parser) recordError(acl data.Control) bool {
+	tv, ok := acl.(*data.ThrowValue)
+	if !ok {
+		return false
+	}
+	p.errors = append(p.errors, tv)
+	if len(p.errors) >= maxSyntaxErrors {
+		p.stopNext()
+	}
+	return true
+}
+
+// recoverStatement 记录语句中的错误并跳到下一条语句；start 为出错语句的开始位置，
+// inBlock 为 true 时遇到外层代码块的 '}' 停止，由代码块自己结束
+func (p *Parser) recoverStatement(acl data.Control, start int, inBlock bool) bool {
+	if !p.recordError(acl) {
+		return false
+	}
+	p.synchronize(start, inBlock, isStatementStart)
+	return true
+}
+
+// recoverClassMember 记录类成员声明中的错误并跳到下一个成员
+func (p *Parser) recoverClassMember(acl data.Control, start int) bool {
+	if !p.recordError(acl) {
+		return false
+	}
+	p.synchronize(start, true, isClassMemberStart)
+	return true
+}
+
+// synchronize 跳过出错部分剩余的单词：停在同层 ';' 之后、出错部分中完整代码块 '{...}' 之后、
+// 外层代码块的 '}' 之前，或下一个声明（由 isStart 判断）之前
+func (p *Parser) synchronize(start int, inBlock bool, isStart func(p *Parser) bool) {
+	// 至少跳过一个单词，避免在同一位置反复报错
+	if p.position == start && !p.isEOF() && !(inBlock && p.current().Type() == token.RBRACE) {
+		p.next()
+	}
+	depth, parens := 0, 0
+	for !p.isEOF() {
+		switch p.current().Type() {
+		case token.SEMICOLON:
+			if depth == 0 && parens == 0 {
+				p.next()
+				return
+			}
+		case token.LPAREN:
+			parens++
+		case token.RPAREN:
+			if parens > 0 {
+				parens--
+			}
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			if depth == 0 {
+				if inBlock {
+					return
+				}
+			} else if depth--; depth == 0 {
+				p.next()
+				return
+			}
+		default:
+			if depth == 0 && p.position > start && isStart(p) {
+				return
+			}
+		}
+		p.next()
+	}
+}
+
+// isStatementStart 当前单词是否开始一个新的声明语句
+func isStatementStart(p *Parser) bool {
+	switch p.current().Type() {
+	case token.CLASS, token.INTERFACE, token.TRAIT, token.ENUM, token.NAMESPACE:
+		return true
+	case token.FUNC:
+		return p.peek(1).Type() == token.IDENTIFIER
+	}
+	return false
+}
+
+// isClassMemberStart 当前单词是否开始一个新的类成员声明
+func isClassMemberStart(p *Parser) bool {
+	switch p.current().Type() {
+	case token.PUBLIC, token.PROTECTED, token.PRIVATE, token.FUNC, token.CONST, token.VAR,
+		token.ABSTRACT, token.FINAL, token.USE, token.HASH:
+		return true
+	}
+	return false
+}
+
+// syntaxError 把收集到的语法错误合并为一个控制流：第一个错误携带其余的错误
+func (p *Parser) syntaxError() data.Control {
+	if len(p.errors) == 0 {
+		return nil
+	}
+	first := p.errors[0].(*data.ThrowValue)
+	first.SyntaxErrors = first.SyntaxErrors[:0]
+	for _, acl := range p.errors[1:] {
+		if tv, ok := acl.(*data.ThrowValue); ok {
+			first.SyntaxErrors = append(first.SyntaxErrors, tv)
+		}
+	}
+	return first
+}
+
+// Errors 返回最近一次解析收集到的全部语法错误
+func (p *Parser) Errors() []data.Control {
+	return p.errors
+}
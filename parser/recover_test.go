@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/php-any/origami/data"
+)
+
+func TestParseRecoversFromSyntaxErrors(t *testing.T) {
+	src := `<?php
+$a = (1;
+function ok() {
+    $b = );
+    return 1;
+}
+echo 2;
+$c = (3;
+`
+	p := NewParser()
+	program, acl := p.ParseString(src, "recover.php")
+	if acl == nil {
+		t.Fatal("expected syntax errors")
+	}
+	if program == nil {
+		t.Fatal("expected partial AST")
+	}
+	tv, ok := acl.(*data.ThrowValue)
+	if !ok {
+		t.Fatalf("expected ThrowValue, got %T", acl)
+	}
+	lines := []int{}
+	for _, e := range append([]*data.ThrowValue{tv}, tv.SyntaxErrors...) {
+		sl, _ := e.GetFrom().GetStartPosition()
+		lines = append(lines, sl+1)
+	}
+	if len(lines) != 3 || lines[0] != 2 || lines[1] != 4 || lines[2] != 8 {
+		t.Fatalf("unexpected error lines %v", lines)
+	}
+	if len(p.Errors()) != 3 {
+		t.Fatalf("expected 3 errors, got %d", len(p.Errors()))
+	}
+}
+
+func TestParseReportsMissingOperands(t *testing.T) {
+	src := `<?php
+$a = 1 +;
+function f() {
+    $x = ;
+    return $y ?? ;
+}
+$b = !;
+echo 2;
+`
+	p := NewParser()
+	_, acl := p.ParseString(src, "operand.php")
+	tv, ok := acl.(*data.ThrowValue)
+	if !ok {
+		t.Fatalf("expected ThrowValue, got %T", acl)
+	}
+	lines := []int{}
+	for _, e := range append([]*data.ThrowValue{tv}, tv.SyntaxErrors...) {
+		sl, _ := e.GetFrom().GetStartPosition()
+		lines = append(lines, sl+1)
+	}
+	if len(lines) != 4 || lines[0] != 2 || lines[1] != 4 || lines[2] != 5 || lines[3] != 7 {
+		t.Fatalf("unexpected error lines %v", lines)
+	}
+}
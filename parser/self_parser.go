@@ -51,7 +51,7 @@ func (sp *SelfParser) Parse() (data.GetValue, data.Control) {
 			// 如果已知当前类名（解析时），直接使用 CallStaticMethodLater
 			// 这样在闭包等非 ClassMethodContext 中也能正确工作
 			if sp.currentClass != "" {
-				expr := node.NewCallStaticMethodLater(tokenFrom, sp.currentClass, memberName, sp.currentClass)
+				expr := node.NewCallSelfStaticMethod(tokenFrom, sp.currentClass, memberName)
 				return vp.parseSuffix(expr)
 			}
 			// 否则回退到运行时解析
@@ -173,6 +173,7 @@ func (sp *StaticParser) parseStaticFunction(tracker *PositionTracker) (data.GetV
 
 	// 弹出函数作用域
 	sp.scopeManager.PopScope()
+	declareCaptured(sp.scopeManager.CurrentScope(), captureNames(captures), sp.FromCurrentToken())
 
 	// 构建 parent 映射（捕获 use 声明的变量）
 	parent := make(map[int]int)
@@ -199,6 +200,7 @@ func (sp *StaticParser) parseStaticFunction(tracker *PositionTracker) (data.GetV
 		parent,
 	)
 
+	fn.Static = true
 	// 设置返回类型（如果指定了）
 	if ret != nil {
 		fn.FunctionStatement.Ret = ret
@@ -250,6 +252,7 @@ func (sp *StaticParser) parseStaticArrowFunction(tracker *PositionTracker) (data
 	vars := sp.scopeManager.CurrentScope().GetVariables()
 	// 弹出函数作用域
 	sp.scopeManager.PopScope()
+	declareCaptured(sp.scopeManager.CurrentScope(), freeVariableNames(vars, params), tracker.EndBefore())
 
 	// 构建 parent 映射，自动捕获外部变量
 	parent := make(map[int]int)
@@ -274,6 +277,7 @@ func (sp *StaticParser) parseStaticArrowFunction(tracker *PositionTracker) (data
 		parent,
 	)
 
+	fn.Static = true
 	// 设置返回类型（如果指定了）
 	if ret != nil {
 		fn.FunctionStatement.Ret = ret
@@ -119,6 +119,14 @@ func (vp *VariableParser) parseSuffix(expr data.GetValue) (data.GetValue, data.C
 				return nil, acl
 			}
 			from := tracker.EndBefore()
+			if node.IsFirstClassCallable(stmt) {
+				expr = node.NewValueCallable(from, expr)
+				continue
+			}
+			if node.HasPlaceholder(stmt) {
+				expr = node.NewPartialApplication(from, node.NewValueCallable(from, expr), stmt)
+				continue
+			}
 			expr = node.NewCallMethod(from, expr, stmt)
 		case token.LBRACKET:
 			expr, acl = vp.parseArrayAccess(expr)
@@ -246,6 +254,15 @@ func (vp *VariableParser) parseFunctionCall() ([]data.GetValue, data.Control) {
 					break
 				}
 				vp.next()
+			} else if vp.current().Type() == token.TERNARY && vp.checkPositionIs(1, token.COMMA, token.RPAREN) {
+				// 部分应用的占位实参：str_replace('a', 'b', ?)
+				tracker := vp.StartTracking()
+				vp.next()
+				args = append(args, node.NewPlaceholder(tracker.EndBefore()))
+				if vp.current().Type() != token.COMMA {
+					break
+				}
+				vp.next()
 			} else if vp.current().Type() == token.ELLIPSIS {
 				tracker := vp.StartTracking()
 				vp.next() // 跳过 ...
@@ -527,6 +544,12 @@ func (vp *VariableParser) parseMethodCall(object data.GetValue) (data.GetValue,
 		}
 		// 在解析完整个方法调用后设置范围
 		from := tracker.EndBefore()
+		if node.IsFirstClassCallable(stmt) {
+			return node.NewMethodCallable(from, object, method), nil
+		}
+		if node.HasPlaceholder(stmt) {
+			return node.NewPartialApplication(from, node.NewMethodCallable(from, object, method), stmt), nil
+		}
 		return node.NewObjectMethod(
 			from,
 			object,
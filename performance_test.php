@@ -30,4 +30,42 @@ echo "循环内 $value ：", $value, "\n";
 echo "循环内 $result ：", $result, "\n";
 echo "循环内 $sum ：", $sum, "\n";
 
+echo "\n=== 性能测试：一百万次方法调用 ===\n";
+
+class PerfBase {
+    protected $count = 0;
+
+    public function inc($n) {
+        $this->count += $n;
+        return $this;
+    }
+
+    public static function twice($n) {
+        return $n * 2;
+    }
+}
+
+class PerfCounter extends PerfBase {
+    public $step = 1;
+
+    public function count() {
+        return $this->count;
+    }
+}
+
+$counter = new PerfCounter();
+$startTime = microtime(true);
+
+// 继承的实例方法、静态方法与属性读取，调用点的内联缓存命中后不再按名称查找
+for ($i = 1; $i <= 1000000; $i++) {
+    $counter->inc(PerfCounter::twice($counter->step));
+}
+
+$endTime = microtime(true);
+$executionTime = $endTime - $startTime;
+
+echo "执行完成！计数：", $counter->count(), "\n";
+echo "总执行时间: " . number_format($executionTime, 4) . " 秒\n";
+echo "每秒调用次数: " . number_format(1000000 / $executionTime, 0) . " 次/秒\n";
+
 echo "\n=== 性能测试完成 ===\n"; 
\ No newline at end of file
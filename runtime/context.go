@@ -23,6 +23,23 @@ type Context struct {
 
 	// 当前调用绑定的 static 局部变量存储（由 ClassMethod/FunctionStatement.Call 设置）
 	staticLocals *data.StaticLocals
+
+	// 所属的执行流，子上下文继承
+	routine *data.Routine
+
+	// 与其它作用域共享 ZVal 的变量（引用、global、闭包 use 等）按下标置位，下标超过 63 的都视为共享；
+	// 函数返回时不释放其中的对象，见 ReleaseFrame
+	shared uint64
+}
+
+// Routine 返回上下文所属的执行流
+func (c *Context) Routine() *data.Routine {
+	return c.routine
+}
+
+// BindRoutine 设置上下文所属的执行流
+func (c *Context) BindRoutine(r *data.Routine) {
+	c.routine = r
 }
 
 // BindStaticLocals 绑定函数级 static 局部变量存储
@@ -59,20 +76,25 @@ func (c *Context) GetVariableValue(variable data.Variable) (data.Value, data.Con
 	if variable.GetIndex() >= len(c.variables) {
 		return nil, data.NewErrorThrow(nil, errors.New("Variable does not exist"))
 	}
-	return c.variables[variable.GetIndex()].Value, nil
+	v := c.variables[variable.GetIndex()].Value
+	data.Escape(v)
+	return v, nil
 }
 
 func (c *Context) GetIndexValue(index int) (data.Value, bool) {
 	if index < 0 || index >= len(c.variables) {
 		return nil, false
 	}
-	return c.variables[index].Value, true
+	v := c.variables[index].Value
+	data.Escape(v)
+	return v, true
 }
 
 func (c *Context) SetIndexZVal(index int, v *data.ZVal) {
 	if index < 0 || index >= len(c.variables) {
 		return
 	}
+	c.share(index)
 	c.variables[index] = v
 }
 
@@ -80,21 +102,102 @@ func (c *Context) GetIndexZVal(index int) *data.ZVal {
 	if index < 0 || index >= len(c.variables) {
 		return nil
 	}
+	c.share(index)
 	return c.variables[index]
 }
 
+func (c *Context) share(index int) {
+	if index < 64 {
+		c.shared |= 1 << index
+	}
+}
+
+func (c *Context) isShared(index int) bool {
+	return index >= 64 || c.shared&(1<<index) != 0
+}
+
+// PeekVariable 读取变量而不视为读出，见 data.PeekReceiver
+func (c *Context) PeekVariable(index int) (data.Value, bool) {
+	if index < 0 || index >= len(c.variables) {
+		return nil, false
+	}
+	return c.variables[index].Value, true
+}
+
+// OwnVariable `$x = new Foo(...);` 之后由 data.OwnVariable 调用：对象归变量的 ZVal 所有。
+// static 局部变量的值会复制到跨调用的存储中，不归属
+func (c *Context) OwnVariable(index int) {
+	if index < 0 || index >= len(c.variables) {
+		return
+	}
+	if _, ok := c.staticLocals.Get(index); ok {
+		return
+	}
+	data.Own(c.variables[index])
+}
+
+// ShareVariables 作用域内创建了闭包：闭包在函数返回后仍可读取这里的变量，全部视为共享
+func (c *Context) ShareVariables() {
+	c.shared = ^uint64(0)
+}
+
+// ReleaseFrame 函数返回时由 data.ReleaseFrame 调用：按逆序析构只属于本作用域的变量所有的对象，
+// 共享的变量只通知检查；返回析构函数抛出的第一个异常
+func (c *Context) ReleaseFrame() data.Control {
+	var first data.Control
+	for i := len(c.variables) - 1; i >= 0; i-- {
+		zv := c.variables[i]
+		if zv == nil {
+			continue
+		}
+		owner := zv
+		if c.isShared(i) {
+			owner = nil
+		}
+		if acl := data.Release(c, owner, zv.Value); acl != nil && first == nil {
+			first = acl
+		}
+	}
+	return first
+}
+
 // SetVariableValue 设置变量值
 func (c *Context) SetVariableValue(variable data.Variable, value data.Value) data.Control {
+	var old data.Value
+	var oldZv *data.ZVal
+	if idx := variable.GetIndex(); idx >= 0 && idx < len(c.variables) {
+		oldZv = c.variables[idx]
+		if oldZv != nil {
+			old = oldZv.Value
+		}
+	}
 	switch v := value.(type) {
 	case *data.ReferenceValue:
+		// 对象属性引用 &$obj->prop：变量与属性共享 ZVal
+		if prop, ok := v.Val.(*node.CallObjectProperty); ok {
+			z, acl := prop.GetZVal(v.Ctx)
+			if acl != nil {
+				return acl
+			}
+			if z != nil {
+				c.share(variable.GetIndex())
+				c.variables[variable.GetIndex()] = z
+			}
+			oldZv = nil
+			break
+		}
+		c.share(variable.GetIndex())
 		c.variables[variable.GetIndex()] = v.Ctx.GetIndexZVal(v.Val.GetIndex())
+		oldZv = nil
 	case *data.ArraySlotRef:
 		// &$array[] 语法：局部变量与数组元素共享 ZVal
 		if v.Arr != nil && v.Idx >= 0 && v.Idx < len(v.Arr.List) {
 			slot := v.Arr.List[v.Idx]
 			slot.AddRefSlot()
+			c.share(variable.GetIndex())
 			c.variables[variable.GetIndex()] = slot
 		}
+		oldZv = nil
 	case *data.ArrayValue:
 		c.variables[variable.GetIndex()].Value = data.CloneArrayValue(v)
 	case *data.ObjectValue:
@@ -107,11 +210,26 @@ func (c *Context) SetVariableValue(variable data.Variable, value data.Value) dat
 		if len(c.variables) <= idx {
 			return data.NewErrorThrow(variable.(node.GetFrom).GetFrom(), errors.New("index out of range"))
 		}
+		if guard := c.variables[idx].Guard; guard != nil {
+			var from data.From
+			if f, ok := variable.(node.GetFrom); ok {
+				from = f.GetFrom()
+			}
+			checked, acl := guard(from, value)
+			if acl != nil {
+				return acl
+			}
+			value = checked
+		}
 		c.variables[idx].Value = value
 	}
 
 	c.syncStaticLocal(variable.GetIndex())
-	return nil
+	if old == value {
+		return nil
+	}
+	// 变量改为引用其它 ZVal 时原 ZVal 可能仍被共享，只通知检查
+	return data.Release(c, oldZv, old)
 }
 
 func (c *Context) syncStaticLocal(index int) {
@@ -128,6 +246,7 @@ func (c *Context) CreateContext(vars []data.Variable) data.Context {
 	return &Context{
 		vm:        c.vm,
 		variables: makeSliceVariableWithNames(vars),
+		routine:   c.routine,
 	}
 }
 
@@ -135,6 +254,7 @@ func (c *Context) CreateBaseContext() data.Context {
 	return &Context{
 		vm:        c.vm,
 		variables: nil,
+		routine:   c.routine,
 	}
 }
 
@@ -143,7 +263,7 @@ func (c *Context) GetVM() data.VM {
 }
 
 func (c *Context) GoContext() context.Context {
-	return context.Background()
+	return c.routine.Context()
 }
 
 // SetVM 替换当前 Context 所绑定的 VM
@@ -187,6 +307,7 @@ func makeSliceVariableWithNames(vars []data.Variable) []*data.ZVal {
 func (c *Context) SetVariableByName(name string, value data.Value) {
 	for _, zv := range c.variables {
 		if zv != nil && zv.Name == name {
+			old := zv.Value
 			switch v := value.(type) {
 			case *data.ArrayValue:
 				zv.Value = data.CloneArrayValue(v)
@@ -195,6 +316,7 @@ func (c *Context) SetVariableByName(name string, value data.Value) {
 			default:
 				zv.Value = value
 			}
+			data.Release(c, nil, old)
 			return
 		}
 	}
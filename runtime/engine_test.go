@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/php-any/origami/bytecode"
+	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/parser"
+)
+
+func parseForEngine(t *testing.T, src string, engine string) *node.Program {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "engine.php")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := parser.NewParser()
+	vm := NewVM(p).(*VM)
+	if err := vm.SetEngine(engine); err != nil {
+		t.Fatal(err)
+	}
+	program, acl := p.ParseFile(file)
+	if acl != nil {
+		t.Fatalf("parse failed: %v", acl)
+	}
+	vm.prepare(program, p)
+	return program
+}
+
+func TestBytecodeEngineCompilesControlFlow(t *testing.T) {
+	src := "<?php\n$i = 0;\nwhile ($i < 3) { $i++; }\nfunction f($n) { for ($j = 0; $j < $n; $j++) { if ($j > 1) { break; } } }\n"
+	program := parseForEngine(t, src, EngineBytecode)
+
+	stmt, ok := program.Statements[1].(*bytecode.Statement)
+	if !ok {
+		t.Fatalf("expected while compiled, got %T", program.Statements[1])
+	}
+	if _, ok := stmt.Orig.(*node.WhileStatement); !ok {
+		t.Fatalf("expected original while kept, got %T", stmt.Orig)
+	}
+	if line, _ := stmt.GetFrom().GetStartPosition(); line != 2 {
+		t.Fatalf("expected compiled statement on line 2, got %d", line)
+	}
+	code := stmt.Chunk.String()
+	for _, op := range []string{"TICK", "LOADV", "LT", "JMPF", "INCV", "JMP"} {
+		if !strings.Contains(code, op) {
+			t.Fatalf("expected %s in:\n%s", op, code)
+		}
+	}
+
+	fn, ok := program.Statements[2].(*node.FunctionStatement)
+	if !ok {
+		t.Fatalf("expected function statement, got %T", program.Statements[2])
+	}
+	if _, ok := fn.Body[0].(*bytecode.Statement); !ok {
+		t.Fatalf("expected for in function body compiled, got %T", fn.Body[0])
+	}
+}
+
+func TestBytecodeEngineCompilesGoto(t *testing.T) {
+	src := "<?php\n$i = 0;\nwhile ($i < 3) { $i++; if ($i == 2) { goto done; } }\ndone:\necho $i;\n"
+	program := parseForEngine(t, src, EngineBytecode)
+	stmt, ok := program.Statements[1].(*bytecode.Statement)
+	if !ok {
+		t.Fatalf("expected loop with goto compiled, got %T", program.Statements[1])
+	}
+	if !strings.Contains(stmt.Chunk.String(), "EXEC") {
+		t.Fatalf("expected goto executed by the tree walker:\n%s", stmt.Chunk)
+	}
+	if _, ok := program.Statements[2].(*node.LabelStatement); !ok {
+		t.Fatalf("expected label kept, got %T", program.Statements[2])
+	}
+}
+
+func TestBytecodeEngineCompilesVariablesAndGenerators(t *testing.T) {
+	src := "<?php\nfunction g($n) { $s = 0; while ($s < $n && $n > 0) { $s = $s * 2 - $s; ++$s; yield $s; } }\n"
+	program := parseForEngine(t, src, EngineBytecode)
+	fn, ok := program.Statements[0].(*node.FunctionStatement)
+	if !ok {
+		t.Fatalf("expected function statement, got %T", program.Statements[0])
+	}
+	stmt, ok := fn.Body[1].(*bytecode.Statement)
+	if !ok {
+		t.Fatalf("expected while in generator body compiled, got %T", fn.Body[1])
+	}
+	code := stmt.Chunk.String()
+	for _, op := range []string{"LOADV", "STOREV", "INCV", "SUB"} {
+		if !strings.Contains(code, op) {
+			t.Fatalf("expected %s in:\n%s", op, code)
+		}
+	}
+	if strings.Contains(code, "EVAL") {
+		t.Fatalf("expected && condition compiled to jumps:\n%s", code)
+	}
+}
+
+func TestASTEngineLeavesTree(t *testing.T) {
+	src := "<?php\n$i = 0;\nwhile ($i < 3) { $i++; }\n"
+	program := parseForEngine(t, src, EngineAST)
+	if _, ok := program.Statements[1].(*node.WhileStatement); !ok {
+		t.Fatalf("expected while untouched, got %T", program.Statements[1])
+	}
+	var vm VM
+	if err := vm.SetEngine("jit"); err == nil {
+		t.Fatal("expected unknown engine rejected")
+	}
+}
@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/parser"
+)
+
+func parseForOptimize(t *testing.T, src string, optimize bool) *node.Program {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "opt.php")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := parser.NewParser()
+	vm := NewVM(p).(*VM)
+	vm.SetOptimize(optimize)
+	program, acl := p.ParseFile(file)
+	if acl != nil {
+		t.Fatalf("parse failed: %v", acl)
+	}
+	vm.Optimize(program, p)
+	return program
+}
+
+func countNodes(program *node.Program, match func(data.GetValue) bool) int {
+	n := 0
+	node.InspectList(program.Statements, func(v data.GetValue) bool {
+		if match(v) {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+func TestOptimizeFoldsConstantsAndDeadBranches(t *testing.T) {
+	src := "<?php\nconst H = 2 * 60 * 60;\n$a = H;\n$b = \"a\" . \"b\";\nif (false) { $c = 1; } else { $c = 2; }\n"
+	program := parseForOptimize(t, src, true)
+
+	isBinary := func(v data.GetValue) bool {
+		switch v.(type) {
+		case *node.BinaryMul, *node.BinaryDot:
+			return true
+		}
+		return false
+	}
+	if n := countNodes(program, isBinary); n != 0 {
+		t.Fatalf("expected constant expressions folded, %d left", n)
+	}
+	if n := countNodes(program, func(v data.GetValue) bool { _, ok := v.(*node.IfStatement); return ok }); n != 0 {
+		t.Fatalf("expected if (false) removed, %d left", n)
+	}
+
+	inlined := countNodes(program, func(v data.GetValue) bool {
+		lit, ok := v.(*node.IntLiteral)
+		return ok && lit.V.(*data.IntValue).Value == 7200
+	})
+	if inlined != 2 {
+		t.Fatalf("expected H folded and inlined, got %d literals of 7200", inlined)
+	}
+
+	// 折叠后的字面量保留原表达式的位置（第 4 行，行号从 0 开始）
+	var folded *node.StringLiteral
+	node.InspectList(program.Statements, func(v data.GetValue) bool {
+		if lit, ok := v.(*node.StringLiteral); ok && lit.Value == "ab" {
+			folded = lit
+		}
+		return true
+	})
+	if folded == nil {
+		t.Fatal("expected \"a\" . \"b\" folded to \"ab\"")
+	}
+	if line, _ := folded.GetFrom().GetStartPosition(); line != 3 {
+		t.Fatalf("expected folded literal on line 3, got %d", line)
+	}
+}
+
+func TestOptimizeDisabled(t *testing.T) {
+	src := "<?php\n$b = \"a\" . \"b\";\nif (false) { $c = 1; }\n"
+	program := parseForOptimize(t, src, false)
+	if n := countNodes(program, func(v data.GetValue) bool { _, ok := v.(*node.BinaryDot); return ok }); n != 1 {
+		t.Fatalf("expected tree untouched with optimization disabled, got %d BinaryDot", n)
+	}
+}
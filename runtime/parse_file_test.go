@@ -61,3 +61,44 @@ func TestParseFileBindsAssociativeArray(t *testing.T) {
 		t.Fatalf("rendered %q does not contain injected greeting", sv.AsString())
 	}
 }
+
+func TestParseFileReusesProgramUntilSourceChanges(t *testing.T) {
+	dir := t.TempDir()
+	htmlPath := filepath.Join(dir, "page.html")
+	if err := os.WriteFile(htmlPath, []byte("<p>{$greeting}</p>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := parser.NewParser()
+	vm := NewVM(p).(*VM)
+	render := func(greeting string) string {
+		t.Helper()
+		arr := &data.ArrayValue{List: []*data.ZVal{data.NewNamedZVal("greeting", data.NewStringValue(greeting))}}
+		rendered, acl := vm.ParseFile(htmlPath, arr)
+		if acl != nil {
+			t.Fatalf("ParseFile failed: %v", acl)
+		}
+		return rendered.(*data.StringValue).AsString()
+	}
+
+	if got := render("a"); !strings.Contains(got, "<p>a</p>") {
+		t.Fatalf("unexpected first render %q", got)
+	}
+	cached, ok := vm.programs.Load(htmlPath)
+	if !ok {
+		t.Fatal("expected the parsed template cached")
+	}
+	if got := render("b"); !strings.Contains(got, "<p>b</p>") {
+		t.Fatalf("unexpected cached render %q", got)
+	}
+	if again, _ := vm.programs.Load(htmlPath); again != cached {
+		t.Fatal("expected the second render to reuse the cached program")
+	}
+
+	if err := os.WriteFile(htmlPath, []byte("<div>{$greeting}</div>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := render("c"); !strings.Contains(got, "<div>c</div>") {
+		t.Fatalf("expected the changed template re-parsed, got %q", got)
+	}
+}
@@ -1,22 +1,57 @@
 package runtime
 
-import "github.com/php-any/origami/data"
+import (
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
 
 // AddShutdownCallback 注册一个 shutdown 回调。
 func (vm *VM) AddShutdownCallback(cb data.Value) {
 	vm.shutdownCallbacks = append(vm.shutdownCallbacks, cb)
 }
 
-// RunShutdownCallbacks 依次执行所有已注册的 shutdown 回调（仅执行一次）。
+// RunShutdownCallbacks 执行主执行流中尚未执行的 defer 延迟执行项（顶层代码登记的、exit 时仍在调用中的函数登记的），
+// 再依次执行所有已注册的 shutdown 回调（仅执行一次），之后销毁仍挂起的生成器、析构仍存活的对象。
 func (vm *VM) RunShutdownCallbacks() {
 	vm.shutdownRunOnce.Do(func() {
+		if acl := vm.main.RunPendingDefers(); acl != nil {
+			vm.acl(acl)
+		}
 		for _, cb := range vm.shutdownCallbacks {
 			callShutdownCallback(vm, cb)
 		}
+		if acl := node.DestroyGenerators(vm); acl != nil {
+			vm.acl(acl)
+		}
+		vm.destroyObjects()
 		runHeaderCallbacks(vm)
 	})
 }
 
+// destroyObjects 与 PHP 一致：先按逆序处理全局变量中只被该变量引用的对象，再按创建顺序析构其余对象
+func (vm *VM) destroyObjects() {
+	vm.mu.Lock()
+	ctx := vm.globalCtx
+	vm.mu.Unlock()
+	if ctx == nil {
+		ctx = vm.CreateContext(nil)
+	}
+	if c, ok := ctx.(*Context); ok {
+		vars := make([]*data.ZVal, 0, len(c.variables))
+		for i := len(c.variables) - 1; i >= 0; i-- {
+			if c.variables[i] != nil {
+				vars = append(vars, c.variables[i])
+			}
+		}
+		if acl := data.ReleaseGlobals(ctx, vars); acl != nil {
+			vm.acl(acl)
+		}
+	}
+	if acl := data.DestroyObjects(ctx, vm); acl != nil {
+		vm.acl(acl)
+	}
+}
+
 func callShutdownCallback(vm *VM, cb data.Value) {
 	switch c := cb.(type) {
 	case *data.FuncValue:
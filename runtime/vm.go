@@ -7,8 +7,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/php-any/origami/bytecode"
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/opcache"
 	"github.com/php-any/origami/parser"
 	"github.com/php-any/origami/utils"
 )
@@ -28,12 +30,13 @@ func NewVM(parser *parser.Parser) data.VM {
 		globalVars:    make(map[string]*data.ZVal),
 		phpFileCache:  make(map[string]struct{}),
 		compiledFiles: make(map[string]func() (data.GetValue, []data.Variable)),
+		main:          data.NewRoutine(),
 		acl: func(acl data.Control) {
 			parser.ShowControl(acl)
 			os.Exit(1)
 		},
 	}
-	vm.ctx = NewContext(vm)
+	vm.ctx = &Context{vm: vm, routine: vm.main}
 	parser.SetVM(vm)
 
 	return vm
@@ -50,38 +53,82 @@ type VM struct {
 	funcMap      map[string]data.FuncStmt
 	constantMap  map[string]data.Value // 全局常量映射
 	globalVars   map[string]*data.ZVal // 全局变量 ZVal 映射
+	globalCtx    data.Context          // 顶层脚本的上下文，脚本结束时按逆序析构其中的对象
 
 	// 已引入/加载过的 PHP 文件缓存
 	phpFileCache map[string]struct{}
+	// 模板文件的解析结果缓存，见 ParseFile
+	programs opcache.Programs
 
 	acl func(acl data.Control)
 
-	// PHP 级 set_exception_handler 注册的回调
-	exceptionHandler data.Value
-	// 防止在异常处理回调中递归调用自身
-	inExceptionHandler bool
+	// 主脚本的执行流：调用深度、set_exception_handler 注册的回调、输出缓冲等；
+	// spawn 的协程与 HTTP 请求使用由它派生的执行流
+	main *data.Routine
 
 	// PHP 级 register_shutdown_function 注册的回调列表
 	shutdownCallbacks []data.Value
 	shutdownRunOnce   sync.Once
 
-	// 调用深度追踪（用于检测无限递归）
-	callDepth int
-
 	// 注解 @Controller 注册的 HTTP 路由（flash 引导后写入此处）
 	httpRoutes []Route
 
 	// 预编译文件注册表
 	compiledFiles map[string]func() (data.GetValue, []data.Variable)
+
+	// noOpt 为 true 时解析得到的语法树不做优化，直接执行（--no-opt）
+	noOpt bool
+	// engine 执行引擎：EngineAST 或 EngineBytecode（--engine）
+	engine string
+}
+
+// 执行引擎
+const (
+	EngineAST      = "ast"      // 直接遍历语法树执行
+	EngineBytecode = "bytecode" // 控制流语句编译为字节码后执行
+)
+
+// SetOptimize 开启或关闭语法树优化，默认开启
+func (vm *VM) SetOptimize(enabled bool) {
+	vm.noOpt = !enabled
+}
+
+// Optimize 在执行前优化解析器 p 刚解析出的语法树：折叠常量表达式、内联常量、删除不可达分支
+func (vm *VM) Optimize(program *node.Program, p *parser.Parser) {
+	if vm.noOpt {
+		return
+	}
+	node.Optimize(vm, program, p.Classes())
+}
+
+// SetEngine 选择执行引擎，name 为空时使用默认的 EngineAST
+func (vm *VM) SetEngine(name string) error {
+	switch name {
+	case "", EngineAST:
+		vm.engine = EngineAST
+	case EngineBytecode:
+		vm.engine = EngineBytecode
+	default:
+		return fmt.Errorf("未知的执行引擎: %s（可选 %s、%s）", name, EngineAST, EngineBytecode)
+	}
+	return nil
+}
+
+// prepare 执行前处理刚解析出的语法树：优化，并按所选引擎编译
+func (vm *VM) prepare(program *node.Program, p *parser.Parser) {
+	vm.Optimize(program, p)
+	if vm.engine == EngineBytecode {
+		bytecode.Compile(program, p.Classes())
+	}
 }
 
+// EnterCall 主执行流的调用深度，各执行流的深度见 data.Routine
 func (vm *VM) EnterCall() int {
-	vm.callDepth++
-	return vm.callDepth
+	return vm.main.EnterCall()
 }
 
 func (vm *VM) LeaveCall() {
-	vm.callDepth--
+	vm.main.LeaveCall()
 }
 
 func (vm *VM) SetPhpFileCache(file string) {
@@ -116,51 +163,31 @@ func (vm *VM) SetThrowControl(fn func(acl data.Control)) {
 
 func (vm *VM) ThrowControl(acl data.Control) {
 	// 优先尝试调用用户通过 set_exception_handler 注册的 PHP 回调
-	if tv, ok := acl.(*data.ThrowValue); ok && vm.exceptionHandler != nil && !vm.inExceptionHandler {
-		// 只在真正有异常对象时尝试回调
-		if tv != nil && tv.Error != nil {
-			// 仅处理一次，避免回调内部再次抛出未捕获异常导致无限递归
-			vm.inExceptionHandler = true
-			defer func() { vm.inExceptionHandler = false }()
-
-			// 目前仅支持 Closure/匿名函数形式的回调（*data.FuncValue）
-			if fv, ok := vm.exceptionHandler.(*data.FuncValue); ok {
-				// 使用回调自身的变量列表创建上下文
-				vars := fv.Value.GetVariables()
-				ctx := vm.CreateContext(vars)
-
-				_ = ctx.SetVariableValue(vars[0], acl)
-
-				if _, hAcl := fv.Call(ctx); hAcl != nil {
-					// 如果回调自身又产生未处理控制流，继续交给底层处理
-					vm.acl(hAcl)
-					return
-				}
-				// 回调执行完毕后直接返回，不再走默认处理
-				return
-			}
+	if handled, next := vm.main.HandleUncaught(acl, vm.ctx); handled {
+		if next != nil {
+			// 如果回调自身又产生未处理控制流，继续交给底层处理
+			vm.acl(next)
 		}
+		return
 	}
 
 	// 默认行为：交给底层 Go 级别处理（打印并退出 / LSP 诊断等）
 	vm.acl(acl)
 }
 
-// SetExceptionHandler 设置 PHP 级异常处理回调，返回旧的回调（如果有）
+// SetExceptionHandler 设置主执行流的 PHP 级异常处理回调，返回旧的回调（如果有）
 func (vm *VM) SetExceptionHandler(handler data.Value) data.Value {
-	old := vm.exceptionHandler
-	vm.exceptionHandler = handler
-	return old
+	return vm.main.SetExceptionHandler(handler)
 }
 
-// GetExceptionHandler 返回当前注册的 PHP 级异常处理回调
+// GetExceptionHandler 返回主执行流当前注册的 PHP 级异常处理回调
 func (vm *VM) GetExceptionHandler() data.Value {
-	return vm.exceptionHandler
+	return vm.main.ExceptionHandler()
 }
 
 func (vm *VM) AddClass(c data.ClassStmt) data.Control {
-	vm.mu.RLock()
-	defer vm.mu.RUnlock()
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
 	// 检查 interfaceMap、classMap 中是否已存在
 	if has, ok := vm.classMap[c.GetName()]; ok {
 		cFrom := c.GetFrom()
@@ -183,8 +210,8 @@ func (vm *VM) AddClass(c data.ClassStmt) data.Control {
 }
 
 func (vm *VM) AddInterface(i data.InterfaceStmt) data.Control {
-	vm.mu.RLock()
-	defer vm.mu.RUnlock()
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
 
 	// 检查 interfaceMap、classMap 中是否已存在
 	if has, ok := vm.classMap[i.GetName()]; ok {
@@ -209,6 +236,8 @@ func (vm *VM) AddInterface(i data.InterfaceStmt) data.Control {
 }
 
 func (vm *VM) findClassCaseInsensitive(name string) (data.ClassStmt, bool) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
 	if v, ok := vm.classMap[name]; ok {
 		return v, true
 	}
@@ -253,19 +282,12 @@ func (vm *VM) LoadPkg(pkg string) (data.GetValue, data.Control) {
 		return nil, nil
 	}
 	if pkg[0:1] == "\\" {
-		temp := pkg[1:]
-		if c, ok := vm.classMap[temp]; ok {
-			return c, nil
-		}
-		if c, ok := vm.interfaceMap[temp]; ok {
+		if c, ok := vm.lookupPkg(pkg[1:]); ok {
 			return c, nil
 		}
 	}
 
-	if c, ok := vm.classMap[pkg]; ok {
-		return c, nil
-	}
-	if c, ok := vm.interfaceMap[pkg]; ok {
+	if c, ok := vm.lookupPkg(pkg); ok {
 		return c, nil
 	}
 
@@ -273,17 +295,29 @@ func (vm *VM) LoadPkg(pkg string) (data.GetValue, data.Control) {
 	if acl != nil {
 		return nil, acl
 	}
-	if c, ok := vm.classMap[pkg]; ok {
-		return c, nil
-	}
-	if c, ok := vm.interfaceMap[pkg]; ok {
+	if c, ok := vm.lookupPkg(pkg); ok {
 		return c, nil
 	}
 
 	return nil, nil
 }
 
+// lookupPkg 按名称查找已注册的类或接口
+func (vm *VM) lookupPkg(name string) (data.GetValue, bool) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if c, ok := vm.classMap[name]; ok {
+		return c, true
+	}
+	if c, ok := vm.interfaceMap[name]; ok {
+		return c, true
+	}
+	return nil, false
+}
+
 func (vm *VM) GetInterface(pkg string) (data.InterfaceStmt, bool) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
 	if inf, ok := vm.interfaceMap[pkg]; ok {
 		return inf, true
 	}
@@ -299,7 +333,7 @@ func (vm *VM) GetOrLoadInterface(pkg string) (data.InterfaceStmt, data.Control)
 		pkg = pkg[1:]
 	}
 
-	if inf, ok := vm.interfaceMap[pkg]; ok {
+	if inf, ok := vm.GetInterface(pkg); ok {
 		return inf, nil
 	}
 
@@ -309,7 +343,7 @@ func (vm *VM) GetOrLoadInterface(pkg string) (data.InterfaceStmt, data.Control)
 		return nil, acl
 	}
 
-	if inf, ok := vm.interfaceMap[pkg]; ok {
+	if inf, ok := vm.GetInterface(pkg); ok {
 		return inf, nil
 	}
 
@@ -317,8 +351,8 @@ func (vm *VM) GetOrLoadInterface(pkg string) (data.InterfaceStmt, data.Control)
 }
 
 func (vm *VM) AddFunc(f data.FuncStmt) data.Control {
-	vm.mu.RLock()
-	defer vm.mu.RUnlock()
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
 	if _, ok := vm.funcMap[f.GetName()]; ok {
 		switch ff := f.(type) {
 		case node.GetFrom:
@@ -332,6 +366,8 @@ func (vm *VM) AddFunc(f data.FuncStmt) data.Control {
 	return nil
 }
 func (vm *VM) GetFunc(pkg string) (data.FuncStmt, bool) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
 	if v, ok := vm.funcMap[pkg]; ok {
 		return v, true
 	} else if len(pkg) > 0 && pkg[0:1] == "\\" {
@@ -441,6 +477,7 @@ func (vm *VM) LoadAndRun(file string) (data.GetValue, data.Control) {
 	if acl != nil {
 		return nil, acl
 	}
+	vm.prepare(program, p)
 
 	vars := p.GetVariables()
 	ctx := vm.CreateContext(vars)
@@ -464,8 +501,12 @@ func (vm *VM) CompileLoad(file string) data.Control {
 	vm.SetPhpFileCache(file)
 
 	p := vm.parser.Clone()
-	_, acl := p.ParseFile(file)
-	return acl
+	program, acl := p.ParseFile(file)
+	if acl != nil {
+		return acl
+	}
+	vm.Optimize(program, p)
+	return nil
 }
 
 func bindTemplateVariables(ctx data.Context, varList []data.Variable, props map[string]data.Value) {
@@ -489,16 +530,34 @@ func templatePropsFromArray(arr *data.ArrayValue) map[string]data.Value {
 	return props
 }
 
-func (vm *VM) ParseFile(file string, object data.Value) (data.Value, data.Control) {
-	// 解析文件
-	p := vm.parser.Clone()
+// parsedFile 进程内缓存的模板解析结果
+type parsedFile struct {
+	program *node.Program
+	vars    []data.Variable
+}
 
-	program, acl := p.ParseFile(file)
-	if acl != nil {
-		return nil, acl
+func (vm *VM) ParseFile(file string, object data.Value) (data.Value, data.Control) {
+	// 模板每次渲染都会调用；文件未变化时复用已解析并优化的语法树
+	var parsed *parsedFile
+	if v, ok := vm.programs.Load(file); ok {
+		parsed = v.(*parsedFile)
+	} else {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, utils.NewThrow(err)
+		}
+		p := vm.parser.Clone()
+		program, acl := p.ParseSource(file, content)
+		if acl != nil {
+			return nil, acl
+		}
+		vm.prepare(program, p)
+		parsed = &parsedFile{program: program, vars: p.GetVariables()}
+		vm.programs.Store(file, content, parsed)
 	}
+	program := parsed.program
 
-	varList := p.GetVariables()
+	varList := parsed.vars
 	ctx := vm.CreateContext(varList)
 	switch v := object.(type) {
 	case *data.ObjectValue:
@@ -567,6 +626,9 @@ func (vm *VM) EnsureGlobalZVal(name string) *data.ZVal {
 func (vm *VM) RegisterGlobalContext(vars []data.Variable, ctx data.Context) {
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
+	if vm.globalCtx == nil {
+		vm.globalCtx = ctx
+	}
 	for _, v := range vars {
 		if v == nil {
 			continue
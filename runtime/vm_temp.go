@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
 	"github.com/php-any/origami/parser"
 )
 
@@ -53,6 +54,8 @@ type TempVM struct {
 func (vm *TempVM) AddClass(c data.ClassStmt) data.Control {
 	// 仅注册到临时 VM 的映射中（请求级生效）
 	vm.addedClasses[c.GetName()] = c
+	// 新的类定义可能替换上一次请求的同名类，调用点缓存的查找结果随之失效
+	node.InvalidateInlineCaches()
 	return nil
 }
 
@@ -107,6 +110,7 @@ func (vm *TempVM) LoadAndRun(file string) (data.GetValue, data.Control) {
 	if acl != nil {
 		return nil, acl
 	}
+	vm.Base.prepare(program, p)
 	return program.GetValue(vm.CreateContext(p.GetVariables()))
 }
 
@@ -1,6 +1,8 @@
 package channel
 
 import (
+	"context"
+
 	"github.com/php-any/origami/data"
 )
 
@@ -41,25 +43,34 @@ func (c *Channel) Construct(ctx data.Context, capacity data.Value) {
 }
 
 // Send 发送数据到 channel（对齐 Go 的用法）
-func (c *Channel) Send(value data.Value) bool {
+// ctx 被取消时放弃发送并返回 ctx 的错误
+func (c *Channel) Send(ctx context.Context, value data.Value) (bool, error) {
 	if c.closed || c.channel == nil {
-		return false
+		return false, nil
 	}
 
 	// 发送数据（Go 风格的发送）
-	c.channel <- value
-	return true
+	select {
+	case c.channel <- value:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
 }
 
-// Receive 从 channel 接收数据（对齐 Go 的用法）
-func (c *Channel) Receive() (data.Value, bool) {
+// Receive 从 channel 接收数据（对齐 Go 的用法），ctx 被取消时放弃接收并返回 ctx 的错误
+func (c *Channel) Receive(ctx context.Context) (data.Value, bool, error) {
 	if c.channel == nil {
-		return nil, false
+		return nil, false, nil
 	}
 
 	// 接收数据（Go 风格的接收）
-	value, ok := <-c.channel
-	return value, ok
+	select {
+	case value, ok := <-c.channel:
+		return value, ok, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
 }
 
 // Close 关闭 channel
@@ -71,6 +71,10 @@ func (c *ChannelClass) GetMethod(name string) (data.Method, bool) {
 		return &ChannelLenMethod{source: c}, true
 	case "cap":
 		return &ChannelCapMethod{source: c}, true
+	case "select":
+		return &ChannelSelectMethod{}, true
+	case "after":
+		return &ChannelAfterMethod{}, true
 	}
 	return nil, false
 }
@@ -84,6 +88,8 @@ func (c *ChannelClass) GetMethods() []data.Method {
 		&ChannelIsClosedMethod{source: c},
 		&ChannelLenMethod{source: c},
 		&ChannelCapMethod{source: c},
+		&ChannelSelectMethod{},
+		&ChannelAfterMethod{},
 	}
 }
 
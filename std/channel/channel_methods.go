@@ -2,6 +2,7 @@ package channel
 
 import (
 	"errors"
+	"time"
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
@@ -71,7 +72,10 @@ func (c *ChannelSendMethod) Call(ctx data.Context) (data.GetValue, data.Control)
 	}
 
 	// 发送数据（Go 风格的发送）
-	success := c.source.channel.Send(value)
+	success, err := c.source.channel.Send(ctx.GoContext(), value)
+	if err != nil {
+		return nil, data.NewCancelledThrow(ctx.GoContext())
+	}
 	return data.NewBoolValue(success), nil
 }
 
@@ -114,7 +118,10 @@ func (c *ChannelReceiveMethod) Call(ctx data.Context) (data.GetValue, data.Contr
 	}
 
 	// 接收数据（Go 风格的接收）
-	value, ok := c.source.channel.Receive()
+	value, ok, err := c.source.channel.Receive(ctx.GoContext())
+	if err != nil {
+		return nil, data.NewCancelledThrow(ctx.GoContext())
+	}
 	if !ok {
 		return data.NewNullValue(), nil
 	}
@@ -297,3 +304,116 @@ func (c *ChannelCapMethod) GetVariables() []data.Variable {
 func (c *ChannelCapMethod) GetReturnType() data.Types {
 	return data.NewBaseType("int")
 }
+
+// ChannelSelectMethod 静态方法 select(array $cases, ?float $timeout = null): array
+type ChannelSelectMethod struct{}
+
+func (c *ChannelSelectMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	cases, ok := ctx.GetIndexValue(0)
+	if !ok {
+		return nil, utils.NewThrow(errors.New("missing parameter: cases"))
+	}
+
+	// 不传超时时一直等待，0 表示没有分支就绪时立即返回
+	timeout := time.Duration(-1)
+	if v, ok := ctx.GetIndexValue(1); ok {
+		if _, isNull := v.(*data.NullValue); !isNull {
+			d, err := secondsArg(v)
+			if err != nil {
+				return nil, utils.NewThrow(err)
+			}
+			timeout = d
+		}
+	}
+
+	return Select(ctx.GoContext(), cases, timeout)
+}
+
+func (c *ChannelSelectMethod) GetName() string {
+	return "select"
+}
+
+func (c *ChannelSelectMethod) GetModifier() data.Modifier {
+	return data.ModifierPublic
+}
+
+func (c *ChannelSelectMethod) GetIsStatic() bool {
+	return true
+}
+
+func (c *ChannelSelectMethod) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "cases", 0, nil, data.NewBaseType("array")),
+		node.NewParameter(nil, "timeout", 1, data.NewNullValue(), nil),
+	}
+}
+
+func (c *ChannelSelectMethod) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "cases", 0, nil),
+		node.NewVariable(nil, "timeout", 1, nil),
+	}
+}
+
+func (c *ChannelSelectMethod) GetReturnType() data.Types {
+	return data.NewBaseType("array")
+}
+
+// ChannelAfterMethod 静态方法 after(float $seconds): Channel
+type ChannelAfterMethod struct{}
+
+func (c *ChannelAfterMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, ok := ctx.GetIndexValue(0)
+	if !ok {
+		return nil, utils.NewThrow(errors.New("missing parameter: seconds"))
+	}
+	d, err := secondsArg(v)
+	if err != nil {
+		return nil, utils.NewThrow(err)
+	}
+
+	return data.NewClassValue(&ChannelClass{
+		channel: After(d),
+	}, ctx.CreateBaseContext()), nil
+}
+
+func (c *ChannelAfterMethod) GetName() string {
+	return "after"
+}
+
+func (c *ChannelAfterMethod) GetModifier() data.Modifier {
+	return data.ModifierPublic
+}
+
+func (c *ChannelAfterMethod) GetIsStatic() bool {
+	return true
+}
+
+func (c *ChannelAfterMethod) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "seconds", 0, nil, nil),
+	}
+}
+
+func (c *ChannelAfterMethod) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "seconds", 0, nil),
+	}
+}
+
+func (c *ChannelAfterMethod) GetReturnType() data.Types {
+	return data.NewBaseType("Channel")
+}
+
+// secondsArg 把秒数（int 或 float）转换为时长，负数按 0 处理
+func secondsArg(v data.Value) (time.Duration, error) {
+	f, ok := v.(data.AsFloat)
+	if !ok {
+		return 0, errors.New("时长必须是数字（秒）")
+	}
+	seconds, err := f.AsFloat()
+	if err != nil {
+		return 0, err
+	}
+	return max(time.Duration(seconds*float64(time.Second)), 0), nil
+}
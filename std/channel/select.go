@@ -0,0 +1,165 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/utils"
+)
+
+// Receiver 可以作为 Channel::select 接收分支的其它通道类（如 Signal\Channel）。
+// SelectRecv 返回底层 chan 以及把接收到的值转换为脚本值的函数。
+type Receiver interface {
+	SelectRecv() (reflect.Value, func(reflect.Value) data.Value)
+}
+
+// selectBranch select 的一个分支
+type selectBranch struct {
+	key     data.Value
+	convert func(reflect.Value) data.Value
+}
+
+// Select 同时等待多个 channel（reflect.Select）。
+// cases 的每一项为一个分支：Channel / Signal\Channel 表示接收，[Channel, $value] 表示发送。
+// timeout < 0 时一直等待，等于 0 时没有分支就绪立即返回（相当于 default），大于 0 时最多等待该时长。
+// 返回 [key, value, ok]：key 为就绪分支在 cases 中的键，接收分支的 ok 为 false 表示 channel 已关闭；
+// 超时或 default 时返回 [null, null, false]；等待期间 ctx 被取消时抛出 CancelledError。
+func Select(ctx context.Context, cases data.Value, timeout time.Duration) (data.GetValue, data.Control) {
+	var keys []data.Value
+	var values []data.Value
+	switch arr := cases.(type) {
+	case *data.ArrayValue:
+		for i, v := range arr.ToValueList() {
+			keys = append(keys, data.NewIntValue(i))
+			values = append(values, v)
+		}
+	case *data.ObjectValue:
+		arr.RangeProperties(func(key string, value data.Value) bool {
+			if n, ok := data.ParseIntArrayKeyName(key); ok {
+				keys = append(keys, data.NewIntValue(n))
+			} else {
+				keys = append(keys, data.NewStringValue(key))
+			}
+			values = append(values, value)
+			return true
+		})
+	default:
+		return nil, utils.NewThrow(errors.New("Channel::select() 需要分支数组"))
+	}
+
+	selectCases := make([]reflect.SelectCase, 0, len(values)+1)
+	branches := make([]selectBranch, 0, len(values)+1)
+	for i, v := range values {
+		c, branch, err := selectCase(v)
+		if err != nil {
+			return nil, utils.NewThrow(fmt.Errorf("Channel::select() 分支 %s: %w", keys[i].AsString(), err))
+		}
+		branch.key = keys[i]
+		selectCases = append(selectCases, c)
+		branches = append(branches, branch)
+	}
+
+	switch {
+	case timeout == 0:
+		selectCases = append(selectCases, reflect.SelectCase{Dir: reflect.SelectDefault})
+		branches = append(branches, selectBranch{})
+	case timeout > 0:
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		selectCases = append(selectCases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)})
+		branches = append(branches, selectBranch{})
+	case len(selectCases) == 0 && ctx.Done() == nil:
+		return nil, utils.NewThrow(errors.New("Channel::select() 没有分支，将永远阻塞"))
+	}
+
+	if done := ctx.Done(); done != nil && timeout != 0 {
+		selectCases = append(selectCases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)})
+	}
+
+	chosen, recv, ok, err := doSelect(selectCases)
+	if err != nil {
+		return nil, utils.NewThrow(err)
+	}
+	if chosen == len(branches) {
+		return nil, data.NewCancelledThrow(ctx)
+	}
+	branch := branches[chosen]
+	if branch.key == nil {
+		return data.NewArrayValue([]data.Value{data.NewNullValue(), data.NewNullValue(), data.NewBoolValue(false)}), nil
+	}
+	value := data.Value(data.NewNullValue())
+	if selectCases[chosen].Dir == reflect.SelectSend {
+		ok = true
+	} else if ok && branch.convert != nil {
+		value = branch.convert(recv)
+	}
+	return data.NewArrayValue([]data.Value{branch.key, value, data.NewBoolValue(ok)}), nil
+}
+
+// doSelect 执行 reflect.Select；向已关闭的 channel 发送时 Go 会 panic，这里转为错误
+func doSelect(cases []reflect.SelectCase) (chosen int, recv reflect.Value, ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Channel::select() 失败: %v", r)
+		}
+	}()
+	chosen, recv, ok = reflect.Select(cases)
+	return
+}
+
+// selectCase 把一个分支转换为 reflect.SelectCase
+func selectCase(v data.Value) (reflect.SelectCase, selectBranch, error) {
+	if arr, ok := v.(*data.ArrayValue); ok {
+		list := arr.ToValueList()
+		if len(list) != 2 {
+			return reflect.SelectCase{}, selectBranch{}, errors.New("发送分支必须是 [Channel, 值]")
+		}
+		ch := channelOf(list[0])
+		if ch == nil {
+			return reflect.SelectCase{}, selectBranch{}, errors.New("发送分支的第一个元素必须是 Channel")
+		}
+		if ch.IsClosed() {
+			return reflect.SelectCase{}, selectBranch{}, errors.New("不能向已关闭的 channel 发送")
+		}
+		return reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(ch.channel), Send: reflect.ValueOf(&list[1]).Elem()}, selectBranch{}, nil
+	}
+	if ch := channelOf(v); ch != nil {
+		return reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.channel)}, selectBranch{convert: channelValue}, nil
+	}
+	if c, ok := v.(*data.ClassValue); ok {
+		if r, ok := c.Class.(Receiver); ok {
+			ch, convert := r.SelectRecv()
+			return reflect.SelectCase{Dir: reflect.SelectRecv, Chan: ch}, selectBranch{convert: convert}, nil
+		}
+	}
+	return reflect.SelectCase{}, selectBranch{}, errors.New("必须是 Channel、Signal\\Channel 或 [Channel, 值]")
+}
+
+func channelOf(v data.Value) *Channel {
+	if c, ok := v.(*data.ClassValue); ok {
+		if cc, ok := c.Class.(*ChannelClass); ok {
+			return cc.channel
+		}
+	}
+	return nil
+}
+
+func channelValue(v reflect.Value) data.Value {
+	if val, ok := v.Interface().(data.Value); ok && val != nil {
+		return val
+	}
+	return data.NewNullValue()
+}
+
+// After 返回一个在 d 之后收到当前时间戳（秒，浮点数）的 channel，对应 Go 的 time.After
+func After(d time.Duration) *Channel {
+	ch := &Channel{channel: make(chan data.Value, 1)}
+	time.AfterFunc(d, func() {
+		ch.channel <- data.NewFloatValue(float64(time.Now().UnixNano()) / 1e9)
+	})
+	return ch
+}
@@ -0,0 +1,50 @@
+package context
+
+import (
+	"context"
+	"errors"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// cancelPair with* 系列函数的返回值：[Context, Closure $cancel]
+func cancelPair(ctx data.Context, c context.Context, cancel context.CancelCauseFunc) data.GetValue {
+	return data.NewArrayValue([]data.Value{
+		data.NewClassValue(NewContextClassFrom(c), ctx),
+		data.NewFuncValue(&CancelFunction{cancel: cancel}),
+	})
+}
+
+// withoutCause 把 context.CancelFunc 适配为 CancelCauseFunc
+func withoutCause(cancel context.CancelFunc) context.CancelCauseFunc {
+	return func(error) { cancel() }
+}
+
+// CancelFunction with* 返回的取消闭包：$cancel(?string $cause = null)
+type CancelFunction struct {
+	cancel context.CancelCauseFunc
+}
+
+func (h *CancelFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
+	var cause error
+	if v, ok := ctx.GetIndexValue(0); ok {
+		if _, isNull := v.(*data.NullValue); !isNull && v.AsString() != "" {
+			cause = errors.New(v.AsString())
+		}
+	}
+	h.cancel(cause)
+	return nil, nil
+}
+
+func (h *CancelFunction) GetName() string { return "cancel" }
+func (h *CancelFunction) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "cause", 0, data.NewNullValue(), nil),
+	}
+}
+func (h *CancelFunction) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "cause", 0, nil),
+	}
+}
@@ -2,6 +2,7 @@ package context
 
 import (
 	contextsrc "context"
+	"reflect"
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
@@ -61,3 +62,8 @@ func (s *ContextClass) GetMethods() []data.Method {
 }
 
 func (s *ContextClass) GetConstruct() data.Method { return nil }
+
+// SelectRecv 作为 Channel::select 的接收分支：Context 被取消时就绪，与 Go 的 <-ctx.Done() 相同 ok 为 false
+func (s *ContextClass) SelectRecv() (reflect.Value, func(reflect.Value) data.Value) {
+	return reflect.ValueOf(s.source.Done()), func(reflect.Value) data.Value { return data.NewNullValue() }
+}
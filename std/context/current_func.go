@@ -0,0 +1,27 @@
+package context
+
+import (
+	"github.com/php-any/origami/data"
+)
+
+// CurrentFunction context\current()：当前执行流的 Context（spawn、TaskGroup 的任务中为任务自己的 Context）
+type CurrentFunction struct{}
+
+func NewCurrentFunction() data.FuncStmt {
+	return &CurrentFunction{}
+}
+
+func (h *CurrentFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(NewContextClassFrom(ctx.GoContext()), ctx), nil
+}
+
+func (h *CurrentFunction) GetName() string            { return "context\\current" }
+func (h *CurrentFunction) GetModifier() data.Modifier { return data.ModifierPublic }
+func (h *CurrentFunction) GetIsStatic() bool          { return true }
+func (h *CurrentFunction) GetParams() []data.GetValue {
+	return []data.GetValue{}
+}
+func (h *CurrentFunction) GetVariables() []data.Variable {
+	return []data.Variable{}
+}
+func (h *CurrentFunction) GetReturnType() data.Types { return data.NewBaseType("Context\\Context") }
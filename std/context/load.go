@@ -1,6 +1,8 @@
 package context
 
 import (
+	"context"
+
 	"github.com/php-any/origami/data"
 )
 
@@ -8,6 +10,7 @@ func Load(vm data.VM) {
 	// 添加顶级函数
 	for _, fun := range []data.FuncStmt{
 		NewBackgroundFunction(),
+		NewCurrentFunction(),
 		NewWithCancelFunction(),
 		NewWithCancelCauseFunction(),
 		NewWithDeadlineFunction(),
@@ -20,4 +23,7 @@ func Load(vm data.VM) {
 		vm.AddFunc(fun)
 	}
 
+	// 注册 Context\Context 类，用于类型声明与 instanceof
+	vm.AddClass(NewContextClassFrom(context.Background()))
+
 }
@@ -40,7 +40,7 @@ func (h *WithCancelFunction) Call(ctx data.Context) (data.GetValue, data.Control
 		return nil, utils.NewThrow(errors.New("参数类型不支持, index: 0"))
 	}
 	ret0, ret1 := context.WithCancel(arg0)
-	return data.NewAnyValue([]any{ret0, ret1}), nil
+	return cancelPair(ctx, ret0, withoutCause(ret1)), nil
 }
 
 func (h *WithCancelFunction) GetName() string            { return "context\\withCancel" }
@@ -56,4 +56,4 @@ func (h *WithCancelFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "parent", 0, nil),
 	}
 }
-func (h *WithCancelFunction) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (h *WithCancelFunction) GetReturnType() data.Types { return data.NewBaseType("array") }
@@ -40,7 +40,7 @@ func (h *WithCancelCauseFunction) Call(ctx data.Context) (data.GetValue, data.Co
 		return nil, utils.NewThrow(errors.New("参数类型不支持, index: 0"))
 	}
 	ret0, ret1 := context.WithCancelCause(arg0)
-	return data.NewAnyValue([]any{ret0, ret1}), nil
+	return cancelPair(ctx, ret0, ret1), nil
 }
 
 func (h *WithCancelCauseFunction) GetName() string            { return "context\\withCancelCause" }
@@ -56,4 +56,4 @@ func (h *WithCancelCauseFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "parent", 0, nil),
 	}
 }
-func (h *WithCancelCauseFunction) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (h *WithCancelCauseFunction) GetReturnType() data.Types { return data.NewBaseType("array") }
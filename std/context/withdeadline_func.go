@@ -59,7 +59,7 @@ func (h *WithDeadlineFunction) Call(ctx data.Context) (data.GetValue, data.Contr
 		return nil, utils.NewThrow(errors.New("参数类型不支持, index: 1"))
 	}
 	ret0, ret1 := context.WithDeadline(arg0, arg1)
-	return data.NewAnyValue([]any{ret0, ret1}), nil
+	return cancelPair(ctx, ret0, withoutCause(ret1)), nil
 }
 
 func (h *WithDeadlineFunction) GetName() string            { return "context\\withDeadline" }
@@ -77,4 +77,4 @@ func (h *WithDeadlineFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "d", 1, nil),
 	}
 }
-func (h *WithDeadlineFunction) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (h *WithDeadlineFunction) GetReturnType() data.Types { return data.NewBaseType("array") }
@@ -81,7 +81,7 @@ func (h *WithDeadlineCauseFunction) Call(ctx data.Context) (data.GetValue, data.
 		return nil, utils.NewThrow(errors.New("参数类型不支持, index: 2"))
 	}
 	ret0, ret1 := context.WithDeadlineCause(arg0, arg1, arg2)
-	return data.NewAnyValue([]any{ret0, ret1}), nil
+	return cancelPair(ctx, ret0, withoutCause(ret1)), nil
 }
 
 func (h *WithDeadlineCauseFunction) GetName() string            { return "context\\withDeadlineCause" }
@@ -101,4 +101,4 @@ func (h *WithDeadlineCauseFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "cause", 2, nil),
 	}
 }
-func (h *WithDeadlineCauseFunction) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (h *WithDeadlineCauseFunction) GetReturnType() data.Types { return data.NewBaseType("array") }
@@ -51,7 +51,7 @@ func (h *WithTimeoutFunction) Call(ctx data.Context) (data.GetValue, data.Contro
 	}
 	arg1 := time.Duration(arg1Int)
 	ret0, ret1 := context.WithTimeout(arg0, arg1)
-	return data.NewAnyValue([]any{ret0, ret1}), nil
+	return cancelPair(ctx, ret0, withoutCause(ret1)), nil
 }
 
 func (h *WithTimeoutFunction) GetName() string            { return "context\\withTimeout" }
@@ -69,4 +69,4 @@ func (h *WithTimeoutFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "timeout", 1, nil),
 	}
 }
-func (h *WithTimeoutFunction) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (h *WithTimeoutFunction) GetReturnType() data.Types { return data.NewBaseType("array") }
@@ -73,7 +73,7 @@ func (h *WithTimeoutCauseFunction) Call(ctx data.Context) (data.GetValue, data.C
 		return nil, utils.NewThrow(errors.New("参数类型不支持, index: 2"))
 	}
 	ret0, ret1 := context.WithTimeoutCause(arg0, arg1, arg2)
-	return data.NewAnyValue([]any{ret0, ret1}), nil
+	return cancelPair(ctx, ret0, withoutCause(ret1)), nil
 }
 
 func (h *WithTimeoutCauseFunction) GetName() string            { return "context\\withTimeoutCause" }
@@ -93,4 +93,4 @@ func (h *WithTimeoutCauseFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "cause", 2, nil),
 	}
 }
-func (h *WithTimeoutCauseFunction) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (h *WithTimeoutCauseFunction) GetReturnType() data.Types { return data.NewBaseType("array") }
@@ -17,6 +17,18 @@ func (f *FloatFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 		return data.NewFloatValue(0), nil
 	}
 
+	// 字符串按 PHP 规则取前导数字："1.5abc" 为 1.5，非数字字符串为 0
+	if sv, ok := v.(*data.StringValue); ok {
+		n, _ := data.ToNumber(sv)
+		switch n := n.(type) {
+		case *data.IntValue:
+			return data.NewFloatValue(float64(n.Value)), nil
+		case *data.FloatValue:
+			return n, nil
+		}
+		return data.NewFloatValue(0), nil
+	}
+
 	switch tv := v.(type) {
 	case data.AsFloat:
 		if f64, err := tv.AsFloat(); err == nil {
@@ -1,6 +1,7 @@
 package std
 
 import (
+	"math"
 	"strconv"
 
 	"github.com/php-any/origami/data"
@@ -17,6 +18,25 @@ func (f *IntFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 		return data.NewIntValue(0), nil
 	}
 
+	// 字符串按 PHP 规则取前导数字："12abc" 为 12，" 1e3" 为 1000，超出范围时取 int 边界值
+	if sv, ok := v.(*data.StringValue); ok {
+		n, kind := data.ToNumber(sv)
+		if kind == data.NumberInvalid {
+			return data.NewIntValue(0), nil
+		}
+		fv, isFloat := n.(*data.FloatValue)
+		if !isFloat {
+			return n, nil
+		}
+		if fv.Value >= math.MaxInt64 {
+			return data.NewIntValue(math.MaxInt), nil
+		}
+		if fv.Value <= math.MinInt64 {
+			return data.NewIntValue(math.MinInt), nil
+		}
+		return data.NewIntValue(data.FloatToInt(fv.Value)), nil
+	}
+
 	// Try common conversions in order
 	switch tv := v.(type) {
 	case data.AsInt:
@@ -25,7 +45,7 @@ func (f *IntFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 		}
 	case data.AsFloat:
 		if f64, err := tv.AsFloat(); err == nil {
-			return data.NewIntValue(int(f64)), nil
+			return data.NewIntValue(data.FloatToInt(f64)), nil
 		}
 	case data.AsBool:
 		if b, err := tv.AsBool(); err == nil {
@@ -41,8 +41,11 @@ func (d *DbDeleteMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 	query := fmt.Sprintf("DELETE FROM %s%s", tableName, whereClause)
 
 	// 执行删除
-	result, err := conn.Exec(query, values...)
+	result, err := conn.ExecContext(ctx.GoContext(), query, values...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("删除失败: %v", err)
 	}
 
@@ -46,8 +46,11 @@ func (d *db) runExecute(ctx data.Context, sqlStr string, goArgs []interface{}) (
 		return nil, utils.NewThrow(errors.New("数据库连接不可用"))
 	}
 
-	result, err := conn.Exec(sqlStr, goArgs...)
+	result, err := conn.ExecContext(ctx.GoContext(), sqlStr, goArgs...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("执行 SQL 语句失败: %v", err)
 	}
 
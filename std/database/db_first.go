@@ -36,8 +36,11 @@ func (d *DbFirstMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 	}
 
 	// 执行查询
-	rows, err := conn.Query(query, args...)
+	rows, err := conn.QueryContext(ctx.GoContext(), query, args...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("first查询失败: %v", err)
 	}
 	defer rows.Close()
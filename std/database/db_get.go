@@ -32,8 +32,11 @@ func (d *DbGetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 	}
 
 	// 执行查询
-	rows, err := conn.Query(query, args...)
+	rows, err := conn.QueryContext(ctx.GoContext(), query, args...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("get查询失败: err(%v); sql(%s)", err, query)
 	}
 	defer rows.Close()
@@ -88,8 +88,11 @@ func (d *DbInsertMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, columnStr, placeholderStr)
 
 	// 执行插入
-	result, err := conn.Exec(query, values...)
+	result, err := conn.ExecContext(ctx.GoContext(), query, values...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("插入失败: %v", err)
 	}
 
@@ -59,8 +59,11 @@ func (d *db) runQuery(ctx data.Context, sqlStr string, goArgs []interface{}) (da
 		return nil, utils.NewThrow(errors.New("数据库连接不可用"))
 	}
 
-	rows, err := conn.Query(sqlStr, goArgs...)
+	rows, err := conn.QueryContext(ctx.GoContext(), sqlStr, goArgs...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("执行 SQL 查询失败: %v", err)
 	}
 	defer rows.Close()
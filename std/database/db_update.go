@@ -99,8 +99,11 @@ func (d *DbUpdateMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 		whereClause)
 
 	// 执行更新
-	result, err := conn.Exec(query, values...)
+	result, err := conn.ExecContext(ctx.GoContext(), query, values...)
 	if err != nil {
+		if acl := queryCancelled(ctx); acl != nil {
+			return nil, acl
+		}
 		return nil, utils.NewThrowf("更新失败: %v; sql(%v", err, query)
 	}
 
@@ -29,3 +29,11 @@ func ConvertValueToGoType(val data.Value) interface{} {
 		return v.AsString()
 	}
 }
+
+// queryCancelled SQL 因执行流被取消（TaskGroup、Future::cancel 等）而失败时返回 CancelledError
+func queryCancelled(ctx data.Context) data.Control {
+	if goCtx := ctx.GoContext(); goCtx.Err() != nil {
+		return data.NewCancelledThrow(goCtx)
+	}
+	return nil
+}
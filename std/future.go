@@ -0,0 +1,217 @@
+package std
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/php-any/origami/data"
+)
+
+// Future spawn 启动的任务的结果：任务结束（返回或抛出异常）、被取消时完成，只完成一次。
+// 任务中未捕获的异常保存在 Future 中，await 时在调用方重新抛出，不会结束进程。
+// 任务在自己的 Context 中执行，取消 Future 即取消该 Context。
+type Future struct {
+	done chan struct{}
+	once sync.Once
+	stop context.CancelCauseFunc
+
+	result data.Value
+	acl    data.Control
+}
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+// settle 完成 Future，已完成时返回 false（例如任务在取消之后才返回，结果被丢弃）
+func (f *Future) settle(result data.GetValue, acl data.Control) bool {
+	settled := false
+	f.once.Do(func() {
+		if acl == nil {
+			f.result = futureValue(result)
+		}
+		f.acl = acl
+		settled = true
+		close(f.done)
+	})
+	return settled
+}
+
+func futureValue(v data.GetValue) data.Value {
+	if val, ok := v.(data.Value); ok && val != nil {
+		return val
+	}
+	return data.NewNullValue()
+}
+
+// IsDone 是否已完成
+func (f *Future) IsDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Cancel 取消尚未完成的 Future，之后 await 抛出 CancelledError。
+// 任务的 Context 随之取消：阻塞中的 channel、sleep、SQL、HTTP 请求抛出 CancelledError，
+// 不检查取消的代码继续执行到结束，它的返回值或异常被丢弃。
+func (f *Future) Cancel() bool {
+	if !f.settle(nil, futureError("CancelledError", "任务已取消")) {
+		return false
+	}
+	if f.stop != nil {
+		f.stop(errors.New("任务已取消"))
+	}
+	return true
+}
+
+// Await 等待完成并返回结果；timeout 小于 0 时一直等待，超时抛出 TimeoutError。
+// 等待方的 ctx 被取消时抛出 CancelledError（任务本身不受影响）
+func (f *Future) Await(ctx context.Context, timeout time.Duration) (data.GetValue, data.Control) {
+	var expired <-chan time.Time
+	if timeout >= 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-f.done:
+		return f.outcome()
+	case <-expired:
+		return nil, futureError("TimeoutError", fmt.Sprintf("等待任务超时(%s)", timeout))
+	case <-ctx.Done():
+		return nil, data.NewCancelledThrow(ctx)
+	}
+}
+
+func (f *Future) outcome() (data.GetValue, data.Control) {
+	if f.acl != nil {
+		return nil, f.acl
+	}
+	return f.result, nil
+}
+
+func futureError(name, msg string) data.Control {
+	return data.NewErrorThrowByName(nil, errors.New(msg), name)
+}
+
+// runTask 在新的协程中执行 call 并以其结果完成 Future。
+// 协程使用从 ctx 的执行流派生的新执行流，取消信号为 parent 派生的 Context（由 Future::cancel 取消），
+// 任务结束后释放；执行中的 Go panic 同样转为异常保存，不会结束进程。
+func runTask(f *Future, parent context.Context, ctx data.Context, call func(data.Context) (data.GetValue, data.Control)) {
+	taskCtx, stop := context.WithCancelCause(parent)
+	f.stop = stop
+	routine := data.RoutineOf(ctx).SpawnContext(taskCtx)
+	go func() {
+		defer stop(nil)
+		callCtx := ctx.CreateBaseContext()
+		data.BindRoutine(callCtx, routine)
+		f.settle(safeCall(call, callCtx))
+	}()
+}
+
+// safeCall 执行 call，把 Go panic 转为异常
+func safeCall(call func(data.Context) (data.GetValue, data.Control), ctx data.Context) (result data.GetValue, acl data.Control) {
+	defer func() {
+		if r := recover(); r != nil {
+			if c, ok := r.(data.Control); ok {
+				result, acl = nil, c
+				return
+			}
+			result, acl = nil, futureError("FutureError", fmt.Sprintf("任务异常终止: %v", r))
+		}
+	}()
+	return call(ctx)
+}
+
+// callClosure 以 args 为实参调用闭包（与 call_user_func 相同，按位置传参）
+func callClosure(fn data.Value, args []data.Value) func(data.Context) (data.GetValue, data.Control) {
+	return func(ctx data.Context) (data.GetValue, data.Control) {
+		callCtx := ctx.CreateContext(make([]data.Variable, len(args)))
+		for i, arg := range args {
+			callCtx.SetIndexZVal(i, data.NewZVal(arg))
+		}
+		switch c := fn.(type) {
+		case *data.BoundFuncValue:
+			return c.Call(callCtx)
+		case *data.FuncValue:
+			return c.Call(callCtx)
+		}
+		return nil, futureError("FutureError", "回调不可调用")
+	}
+}
+
+// watchFutures 返回的通道按完成顺序依次收到 futures 的下标
+func watchFutures(futures []*Future) chan int {
+	ch := make(chan int, len(futures))
+	for i, f := range futures {
+		go func(i int, f *Future) {
+			<-f.done
+			ch <- i
+		}(i, f)
+	}
+	return ch
+}
+
+// nextFuture 等待 watchFutures 的下一个下标，ctx 被取消时返回 CancelledError
+func nextFuture(ctx context.Context, ch chan int) (int, data.Control) {
+	select {
+	case i := <-ch:
+		return i, nil
+	case <-ctx.Done():
+		return 0, data.NewCancelledThrow(ctx)
+	}
+}
+
+// awaitAll 等待全部完成，按原顺序返回结果；任一失败时立即抛出其异常
+func awaitAll(ctx context.Context, futures []*Future) ([]data.Value, data.Control) {
+	ch := watchFutures(futures)
+	results := make([]data.Value, len(futures))
+	for range futures {
+		i, acl := nextFuture(ctx, ch)
+		if acl != nil {
+			return nil, acl
+		}
+		if acl := futures[i].acl; acl != nil {
+			return nil, acl
+		}
+		results[i] = futures[i].result
+	}
+	return results, nil
+}
+
+// awaitAny 返回第一个成功的结果；全部失败时抛出最后一个失败的异常
+func awaitAny(ctx context.Context, futures []*Future) (data.GetValue, data.Control) {
+	if len(futures) == 0 {
+		return nil, futureError("FutureError", "Future::any() 至少需要一个 Future")
+	}
+	ch := watchFutures(futures)
+	var last data.Control
+	for range futures {
+		i, acl := nextFuture(ctx, ch)
+		if acl != nil {
+			return nil, acl
+		}
+		if last = futures[i].acl; last == nil {
+			return futures[i].result, nil
+		}
+	}
+	return nil, last
+}
+
+// awaitRace 返回第一个完成的结果，先完成的失败时抛出其异常
+func awaitRace(ctx context.Context, futures []*Future) (data.GetValue, data.Control) {
+	if len(futures) == 0 {
+		return nil, futureError("FutureError", "Future::race() 至少需要一个 Future")
+	}
+	i, acl := nextFuture(ctx, watchFutures(futures))
+	if acl != nil {
+		return nil, acl
+	}
+	return futures[i].outcome()
+}
@@ -0,0 +1,276 @@
+package std
+
+import (
+	"time"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// FutureClass spawn 的返回值；每个实例持有一个 Future，不能直接 new
+type FutureClass struct {
+	node.Node
+	future *Future
+}
+
+func NewFutureClass() *FutureClass {
+	return &FutureClass{}
+}
+
+// newFutureValue 包装 Future 为 PHP 对象
+func newFutureValue(f *Future, ctx data.Context) *data.ClassValue {
+	return data.NewClassValue(&FutureClass{future: f}, ctx.CreateBaseContext())
+}
+
+// futureOf 取得值中的 Future，不是 Future 对象时返回 nil
+func futureOf(v data.Value) *Future {
+	if c, ok := v.(*data.ClassValue); ok {
+		if fc, ok := c.Class.(*FutureClass); ok {
+			return fc.future
+		}
+	}
+	return nil
+}
+
+func (f *FutureClass) GetName() string         { return "Future" }
+func (f *FutureClass) GetExtend() *string      { return nil }
+func (f *FutureClass) GetImplements() []string { return nil }
+
+func (f *FutureClass) GetMethods() []data.Method {
+	return []data.Method{
+		&FutureAwaitMethod{source: f},
+		&FutureIsDoneMethod{source: f},
+		&FutureCancelMethod{source: f},
+		&FutureThenMethod{source: f},
+		&FutureAllMethod{},
+		&FutureAnyMethod{},
+		&FutureRaceMethod{},
+	}
+}
+
+func (f *FutureClass) GetMethod(name string) (data.Method, bool) {
+	for _, method := range f.GetMethods() {
+		if method.GetName() == name {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+func (f *FutureClass) GetConstruct() data.Method {
+	return &FutureConstructMethod{}
+}
+
+func (f *FutureClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (f *FutureClass) GetPropertyList() []data.Property              { return nil }
+
+func (f *FutureClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&FutureClass{}, ctx.CreateBaseContext()), nil
+}
+
+// FutureConstructMethod Future 只能由 spawn 创建
+type FutureConstructMethod struct{}
+
+func (m *FutureConstructMethod) GetName() string               { return "__construct" }
+func (m *FutureConstructMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FutureConstructMethod) GetIsStatic() bool             { return false }
+func (m *FutureConstructMethod) GetParams() []data.GetValue    { return nil }
+func (m *FutureConstructMethod) GetVariables() []data.Variable { return nil }
+func (m *FutureConstructMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+func (m *FutureConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, futureError("FutureError", "Future 不能直接创建，请使用 spawn")
+}
+
+// FutureAwaitMethod await(?float $timeout = null): mixed
+// 等待任务完成并返回其结果；任务抛出的异常在此处重新抛出，超时抛出 TimeoutError
+type FutureAwaitMethod struct {
+	source *FutureClass
+}
+
+func (m *FutureAwaitMethod) GetName() string            { return "await" }
+func (m *FutureAwaitMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FutureAwaitMethod) GetIsStatic() bool          { return false }
+func (m *FutureAwaitMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "timeout", 0, data.NewNullValue(), nil)}
+}
+func (m *FutureAwaitMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "timeout", 0, data.Mixed{})}
+}
+func (m *FutureAwaitMethod) GetReturnType() data.Types { return data.Mixed{} }
+func (m *FutureAwaitMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	timeout := time.Duration(-1)
+	if v, ok := ctx.GetIndexValue(0); ok {
+		if _, isNull := v.(*data.NullValue); !isNull {
+			f, ok := v.(data.AsFloat)
+			if !ok {
+				return nil, futureError("TypeError", "Future::await() 的 timeout 必须是数字（秒）")
+			}
+			seconds, err := f.AsFloat()
+			if err != nil {
+				return nil, futureError("TypeError", err.Error())
+			}
+			timeout = max(time.Duration(seconds*float64(time.Second)), 0)
+		}
+	}
+	return m.source.future.Await(ctx.GoContext(), timeout)
+}
+
+// FutureIsDoneMethod isDone(): bool
+type FutureIsDoneMethod struct {
+	source *FutureClass
+}
+
+func (m *FutureIsDoneMethod) GetName() string               { return "isDone" }
+func (m *FutureIsDoneMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FutureIsDoneMethod) GetIsStatic() bool             { return false }
+func (m *FutureIsDoneMethod) GetParams() []data.GetValue    { return nil }
+func (m *FutureIsDoneMethod) GetVariables() []data.Variable { return nil }
+func (m *FutureIsDoneMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+func (m *FutureIsDoneMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.future.IsDone()), nil
+}
+
+// FutureCancelMethod cancel(): bool
+// 取消尚未完成的任务，返回是否取消成功；已完成的 Future 不受影响
+type FutureCancelMethod struct {
+	source *FutureClass
+}
+
+func (m *FutureCancelMethod) GetName() string               { return "cancel" }
+func (m *FutureCancelMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FutureCancelMethod) GetIsStatic() bool             { return false }
+func (m *FutureCancelMethod) GetParams() []data.GetValue    { return nil }
+func (m *FutureCancelMethod) GetVariables() []data.Variable { return nil }
+func (m *FutureCancelMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+func (m *FutureCancelMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.future.Cancel()), nil
+}
+
+// FutureThenMethod then(Closure $callback): Future
+// 任务成功后在新协程中以结果调用 callback，返回 callback 结果的 Future；任务失败时直接传递其异常
+type FutureThenMethod struct {
+	source *FutureClass
+}
+
+func (m *FutureThenMethod) GetName() string            { return "then" }
+func (m *FutureThenMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FutureThenMethod) GetIsStatic() bool          { return false }
+func (m *FutureThenMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "callback", 0, nil, nil)}
+}
+func (m *FutureThenMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "callback", 0, data.Mixed{})}
+}
+func (m *FutureThenMethod) GetReturnType() data.Types { return data.NewBaseType("Future") }
+func (m *FutureThenMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	cb, _ := ctx.GetIndexValue(0)
+	switch cb.(type) {
+	case *data.FuncValue, *data.BoundFuncValue:
+	default:
+		return nil, futureError("TypeError", "Future::then() 需要一个闭包")
+	}
+	prev := m.source.future
+	next := newFuture()
+	runTask(next, ctx.GoContext(), ctx, func(callCtx data.Context) (data.GetValue, data.Control) {
+		v, acl := prev.Await(callCtx.GoContext(), -1)
+		if acl != nil {
+			return nil, acl
+		}
+		return callClosure(cb, []data.Value{futureValue(v)})(callCtx)
+	})
+	return newFutureValue(next, ctx), nil
+}
+
+// futuresArg 读取 all/any/race 的参数：Future 数组（list 或关联数组），返回 Future 与对应的键
+func futuresArg(ctx data.Context, name string) ([]*Future, []string, data.Control) {
+	v, _ := ctx.GetIndexValue(0)
+	var values []data.Value
+	var keys []string
+	switch arr := v.(type) {
+	case *data.ArrayValue:
+		values = arr.ToValueList()
+	case *data.ObjectValue:
+		arr.RangeProperties(func(key string, value data.Value) bool {
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+	default:
+		return nil, nil, futureError("TypeError", "Future::"+name+"() 需要 Future 数组")
+	}
+	futures := make([]*Future, len(values))
+	for i, value := range values {
+		if futures[i] = futureOf(value); futures[i] == nil {
+			return nil, nil, futureError("TypeError", "Future::"+name+"() 的数组元素必须是 Future")
+		}
+	}
+	return futures, keys, nil
+}
+
+var futuresParams = []data.GetValue{node.NewParameter(nil, "futures", 0, nil, nil)}
+var futuresVariables = []data.Variable{node.NewVariable(nil, "futures", 0, data.Mixed{})}
+
+// FutureAllMethod 静态方法 all(array $futures): array
+// 等待全部完成并按原来的键返回结果；任一失败时抛出其异常
+type FutureAllMethod struct{}
+
+func (m *FutureAllMethod) GetName() string               { return "all" }
+func (m *FutureAllMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FutureAllMethod) GetIsStatic() bool             { return true }
+func (m *FutureAllMethod) GetParams() []data.GetValue    { return futuresParams }
+func (m *FutureAllMethod) GetVariables() []data.Variable { return futuresVariables }
+func (m *FutureAllMethod) GetReturnType() data.Types     { return data.NewBaseType("array") }
+func (m *FutureAllMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	futures, keys, acl := futuresArg(ctx, "all")
+	if acl != nil {
+		return nil, acl
+	}
+	results, acl := awaitAll(ctx.GoContext(), futures)
+	if acl != nil {
+		return nil, acl
+	}
+	if keys == nil {
+		return data.NewArrayValue(results), nil
+	}
+	obj := data.NewObjectValue()
+	for i, key := range keys {
+		obj.SetProperty(key, results[i])
+	}
+	return obj, nil
+}
+
+// FutureAnyMethod 静态方法 any(array $futures): mixed
+// 返回第一个成功完成的结果；全部失败时抛出最后一个异常
+type FutureAnyMethod struct{}
+
+func (m *FutureAnyMethod) GetName() string               { return "any" }
+func (m *FutureAnyMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FutureAnyMethod) GetIsStatic() bool             { return true }
+func (m *FutureAnyMethod) GetParams() []data.GetValue    { return futuresParams }
+func (m *FutureAnyMethod) GetVariables() []data.Variable { return futuresVariables }
+func (m *FutureAnyMethod) GetReturnType() data.Types     { return data.Mixed{} }
+func (m *FutureAnyMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	futures, _, acl := futuresArg(ctx, "any")
+	if acl != nil {
+		return nil, acl
+	}
+	return awaitAny(ctx.GoContext(), futures)
+}
+
+// FutureRaceMethod 静态方法 race(array $futures): mixed
+// 返回第一个完成的结果，它失败时抛出其异常
+type FutureRaceMethod struct{}
+
+func (m *FutureRaceMethod) GetName() string               { return "race" }
+func (m *FutureRaceMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FutureRaceMethod) GetIsStatic() bool             { return true }
+func (m *FutureRaceMethod) GetParams() []data.GetValue    { return futuresParams }
+func (m *FutureRaceMethod) GetVariables() []data.Variable { return futuresVariables }
+func (m *FutureRaceMethod) GetReturnType() data.Types     { return data.Mixed{} }
+func (m *FutureRaceMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	futures, _, acl := futuresArg(ctx, "race")
+	if acl != nil {
+		return nil, acl
+	}
+	return awaitRace(ctx.GoContext(), futures)
+}
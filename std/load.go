@@ -5,6 +5,7 @@ import (
 	"github.com/php-any/origami/std/channel"
 	"github.com/php-any/origami/std/cli"
 	"github.com/php-any/origami/std/container"
+	stdcontext "github.com/php-any/origami/std/context"
 	"github.com/php-any/origami/std/database"
 	"github.com/php-any/origami/std/exception"
 	"github.com/php-any/origami/std/log"
@@ -12,6 +13,7 @@ import (
 	"github.com/php-any/origami/std/protowire"
 	"github.com/php-any/origami/std/reflect"
 	"github.com/php-any/origami/std/signal"
+	stdsync "github.com/php-any/origami/std/sync"
 	"github.com/php-any/origami/std/system/os"
 )
 
@@ -29,6 +31,8 @@ func Load(vm data.VM) {
 		vm.AddFunc(fun)
 	}
 
+	vm.AddClass(NewFutureClass())
+	vm.AddClass(NewTaskGroupClass())
 	vm.AddClass(log.NewLogClass())
 	// 注册 Throwable / Stringable / JsonSerializable 接口与 Exception 类
 	vm.AddInterface(exception.NewThrowableInterface())
@@ -40,6 +44,8 @@ func Load(vm data.VM) {
 	reflect.Load(vm)
 	channel.Load(vm)
 	signal.Load(vm)
+	stdsync.Load(vm)
+	stdcontext.Load(vm)
 	loop.Load(vm)
 	database.Load(vm)
 	container.Load(vm)
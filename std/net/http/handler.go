@@ -32,7 +32,7 @@ func newMiddleware(v data.FuncStmt, ctx data.Context) (MiddlewareFunc, error) {
 			r, request := beginRequest(r)
 			defer detachRequestAttrs(r)
 
-			mctx := ctx.CreateContext(v.GetVariables())
+			mctx := requestContext(r, ctx, v.GetVariables())
 			nextHandler := data.NewFuncValue(NextHandler{next: next})
 
 			mctx.SetVariableValue(data.NewVariable("r", 0, nil), data.NewProxyValue(request, mctx))
@@ -59,7 +59,7 @@ func (f Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r, request := beginRequest(r)
 	defer detachRequestAttrs(r)
 
-	ctx := f.Ctx.CreateContext(f.Value.GetVariables())
+	ctx := requestContext(r, f.Ctx, f.Value.GetVariables())
 
 	ctx.SetVariableValue(data.NewVariable("r", 0, nil), data.NewProxyValue(request, ctx))
 	ctx.SetVariableValue(data.NewVariable("w", 1, nil), data.NewProxyValue(response, ctx))
@@ -83,7 +83,7 @@ func (f HotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r, request := beginRequest(r)
 	defer detachRequestAttrs(r)
 
-	ctx := f.Ctx.CreateContext(f.Value.GetVariables())
+	ctx := requestContext(r, f.Ctx, f.Value.GetVariables())
 	ctx.SetVM(runtimesrc.NewTempVM(f.Ctx.GetVM()))
 
 	ctx.SetVariableValue(data.NewVariable("r", 0, nil), data.NewProxyValue(request, ctx))
@@ -82,7 +82,7 @@ func invokeErrorHandler(server *ServerClass, w httpsrc.ResponseWriter, r *httpsr
 		panic(recovered)
 	}
 
-	mctx := slot.ctx.CreateContext(vars)
+	mctx := requestContext(r, slot.ctx, vars)
 	mctx.SetVariableValue(vars[0], data.NewProxyValue(request, mctx))
 	mctx.SetVariableValue(vars[1], data.NewProxyValue(response, mctx))
 	mctx.SetVariableValue(vars[2], errorValueFromRecovered(recovered))
@@ -23,10 +23,11 @@ func mountAnnotationRoutes(server *ServerClass, vm data.VM, ctx data.Context, la
 			r, request := beginRequest(r)
 			defer detachRequestAttrs(r)
 
-			reqProxy := data.NewProxyValue(request, ctx)
-			resProxy := data.NewProxyValue(response, ctx)
+			rctx := requestContext(r, ctx, nil)
+			reqProxy := data.NewProxyValue(request, rctx)
+			resProxy := data.NewProxyValue(response, rctx)
 
-			if _, acl := executeMiddlewareChain(vm, ctx, rt, reqProxy, resProxy); acl != nil {
+			if _, acl := executeMiddlewareChain(vm, rctx, rt, reqProxy, resProxy); acl != nil {
 				panic(acl)
 			}
 		})
@@ -40,6 +40,7 @@ func detachRequestAttrs(r *httpsrc.Request) {
 	if r != nil {
 		requestAttrBags.Delete(r)
 		requestFormatterSlots.Delete(r)
+		requestRoutines.Delete(r)
 	}
 }
 
@@ -59,3 +60,21 @@ func beginRequest(r *httpsrc.Request) (*httpsrc.Request, data.ClassStmt) {
 	attachRequestAttrs(r)
 	return r, NewRequestClassFrom(r)
 }
+
+var requestRoutines sync.Map
+
+// requestContext 为请求 r 创建调用上下文。同一请求的中间件与处理函数共用一个执行流，
+// 不同请求的调用深度、输出缓冲、static 局部变量互不影响；执行流的 Context 为 r.Context()，客户端断开时随之取消
+func requestContext(r *httpsrc.Request, base data.Context, vars []data.Variable) data.Context {
+	ctx := base.CreateContext(vars)
+	if r == nil {
+		data.BindRoutine(ctx, data.RoutineOf(base).Spawn())
+		return ctx
+	}
+	routine, ok := requestRoutines.Load(r)
+	if !ok {
+		routine, _ = requestRoutines.LoadOrStore(r, data.RoutineOf(base).SpawnContext(r.Context()))
+	}
+	data.BindRoutine(ctx, routine.(*data.Routine))
+	return ctx
+}
@@ -156,6 +156,7 @@ func executeMiddlewareChain(vm data.VM, ctx data.Context, rt runtime.Route, requ
 
 		// 绑定参数并调用 handle($request, $response, $next)
 		fnCtx := cv.CreateContext(vars)
+		data.BindRoutine(fnCtx, data.RoutineOf(ctx))
 		fnCtx.SetVariableValue(vars[0], request)
 		fnCtx.SetVariableValue(vars[1], response)
 		fnCtx.SetVariableValue(vars[2], nextFunc)
@@ -174,7 +175,7 @@ func executeControllerMethod(rt runtime.Route, reqProxy data.Value, resProxy dat
 	}
 
 	if rt.Receiver != nil {
-		return node.CallHTTPControllerMethod(rt.Receiver, rt.Target, args)
+		return node.CallHTTPControllerMethod(ctx, rt.Receiver, rt.Target, args)
 	}
 
 	mute := ctx.CreateContext(rt.Target.GetVariables())
@@ -50,7 +50,7 @@ func (h *ServerMiddlewareMethod) Call(ctx data.Context) (data.GetValue, data.Con
 				r, request := beginRequest(r)
 				defer detachRequestAttrs(r)
 
-				mctx := cv.CreateContext(method.GetVariables())
+				mctx := requestContext(r, cv, method.GetVariables())
 				nextHandler := data.NewFuncValue(ServerMiddlewareNext{
 					next: next,
 					vars: method.GetVariables(),
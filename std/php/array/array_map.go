@@ -81,12 +81,7 @@ func (f *ArrayMapFunction) Call(ctx data.Context) (data.GetValue, data.Control)
 
 		switch cb := cbVal.(type) {
 		case *data.FuncValue:
-			vars := cb.Value.GetVariables()
-			fnCtx := ctx.CreateContext(vars)
-			for ai := 0; ai < len(vars) && ai < len(args); ai++ {
-				fnCtx.SetVariableValue(data.NewVariable("", ai, nil), args[ai])
-			}
-			ret, ctl := cb.Value.Call(fnCtx)
+			ret, ctl := data.CallFunc(ctx, cb.Value, args)
 			if ctl != nil {
 				return nil, ctl
 			}
@@ -143,13 +138,7 @@ func (f *ArrayMapFunction) Call(ctx data.Context) (data.GetValue, data.Control)
 			funcName := cbVal.AsString()
 			fnStmt, exists := ctx.GetVM().GetFunc(funcName)
 			if exists {
-				fnValue := data.NewFuncValue(fnStmt)
-				vars := fnValue.Value.GetVariables()
-				fnCtx := ctx.CreateContext(vars)
-				for ai := 0; ai < len(vars) && ai < len(args); ai++ {
-					fnCtx.SetVariableValue(data.NewVariable("", ai, nil), args[ai])
-				}
-				ret, ctl := fnValue.Value.Call(fnCtx)
+				ret, ctl := data.CallFunc(ctx, fnStmt, args)
 				if ctl != nil {
 					return nil, ctl
 				}
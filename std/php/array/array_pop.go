@@ -28,6 +28,7 @@ func (f *ArrayPopFunction) Call(ctx data.Context) (data.GetValue, data.Control)
 	}
 
 	// 弹出最后一个元素
+	arrayRef.Separate()
 	lastElement := arrayRef.List[len(arrayRef.List)-1].Value
 	arrayRef.List = arrayRef.List[:len(arrayRef.List)-1]
 
@@ -20,6 +20,7 @@ func (f *ArrayPushFunction) Call(ctx data.Context) (data.GetValue, data.Control)
 
 	switch v := arrayValue.(type) {
 	case *data.ArrayValue:
+		v.Separate()
 		// 获取 Parameters 参数（包含所有传入的值）
 		paramsValue, _ := ctx.GetIndexValue(1)
 		if paramsValue != nil {
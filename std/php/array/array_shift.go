@@ -22,6 +22,7 @@ func (f *ArrayShiftFunction) Call(ctx data.Context) (data.GetValue, data.Control
 		}
 
 		// Shift first element
+		arr.Separate()
 		first := arr.List[0].Value
 		arr.List = arr.List[1:]
 
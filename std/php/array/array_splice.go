@@ -27,6 +27,7 @@ func (f *ArraySpliceFunction) Call(ctx data.Context) (data.GetValue, data.Contro
 	var source *[]*data.ZVal
 	switch arr := arrZVal.Value.(type) {
 	case *data.ArrayValue:
+		arr.Separate()
 		source = &arr.List
 	case *data.ObjectValue:
 		// ObjectValue -> 转为 ArrayValue 再操作
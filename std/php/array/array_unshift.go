@@ -45,6 +45,7 @@ func (f *ArrayUnshiftFunction) Call(ctx data.Context) (data.GetValue, data.Contr
 		}
 		newList = append(newList, arr.List...)
 
+		arr.Separate()
 		arr.List = newList
 
 		return data.NewIntValue(len(arr.List)), nil
@@ -29,6 +29,7 @@ func (fn *ArrayWalkFunction) Call(ctx data.Context) (data.GetValue, data.Control
 	var list []*data.ZVal
 	switch arr := arrZVal.Value.(type) {
 	case *data.ArrayValue:
+		arr.Separate()
 		list = arr.List
 	case *data.ObjectValue:
 		// ObjectValue 转成 ArrayValue 以便修改
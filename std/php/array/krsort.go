@@ -30,6 +30,7 @@ func (f *KrsortFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 			return data.NewBoolValue(true), nil
 		}
 		// Reverse the list
+		v.Separate()
 		for i, j := 0, len(v.List)-1; i < j; i, j = i+1, j-1 {
 			v.List[i], v.List[j] = v.List[j], v.List[i]
 		}
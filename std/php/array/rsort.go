@@ -40,10 +40,15 @@ func (f *RsortFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 		}
 	}
 
+	arrayRef.Separate()
+	var acl data.Control
 	sort.Slice(arrayRef.List, func(i, j int) bool {
 		// Reverse: compare j < i instead of i < j
-		return compareValues(arrayRef.List[j].Value, arrayRef.List[i].Value, flags)
+		return lessValues(ctx, arrayRef.List[j].Value, arrayRef.List[i].Value, flags, &acl)
 	})
+	if acl != nil {
+		return nil, acl
+	}
 
 	return data.NewBoolValue(true), nil
 }
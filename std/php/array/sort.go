@@ -45,13 +45,33 @@ func (f *SortFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 
 	// 对数组进行排序
 	// PHP 的 sort() 函数会重新索引数组的键
+	arrayRef.Separate()
+	var acl data.Control
 	sort.Slice(arrayRef.List, func(i, j int) bool {
-		return compareValues(arrayRef.List[i].Value, arrayRef.List[j].Value, flags)
+		return lessValues(ctx, arrayRef.List[i].Value, arrayRef.List[j].Value, flags, &acl)
 	})
+	if acl != nil {
+		return nil, acl
+	}
 
 	return data.NewBoolValue(true), nil
 }
 
+// lessValues 排序用的比较：SORT_REGULAR 下对象定义了 __compare 时按其结果排序，
+// __compare 抛出的异常记录到 acl，之后的比较不再调用
+func lessValues(ctx data.Context, a, b data.Value, flags int, acl *data.Control) bool {
+	if flags == 0 {
+		if *acl != nil {
+			return false
+		}
+		if c, ctl, ok := data.CompareOperator(ctx, a, b); ok {
+			*acl = ctl
+			return c < 0
+		}
+	}
+	return compareValues(a, b, flags)
+}
+
 // compareValues 比较两个值的大小
 // 根据 flags 参数决定比较方式
 func compareValues(a, b data.Value, flags int) bool {
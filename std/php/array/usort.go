@@ -40,6 +40,7 @@ func (f *UsortFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 		callbackVars = cb.Value.GetVariables()
 	}
 
+	arrayRef.Separate()
 	sort.Slice(arrayRef.List, func(i, j int) bool {
 		// 调用回调函数
 		fnCtx := ctx.CreateContext(callbackVars)
@@ -43,7 +43,7 @@ func (f *Base64DecodeFunction) GetName() string {
 
 func (f *Base64DecodeFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "data", 0, nil, nil),
+		node.NewParameter(nil, "data", 0, nil, data.String{}),
 		node.NewParameter(nil, "strict", 1, node.NewNullLiteral(nil), nil),
 	}
 }
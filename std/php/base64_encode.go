@@ -35,7 +35,7 @@ func (f *Base64EncodeFunction) GetName() string {
 
 func (f *Base64EncodeFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "data", 0, nil, nil),
+		node.NewParameter(nil, "data", 0, nil, data.String{}),
 	}
 }
 
@@ -43,7 +43,7 @@ func (f *ChrFunction) GetName() string {
 
 func (f *ChrFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "codepoint", 0, nil, nil),
+		node.NewParameter(nil, "codepoint", 0, nil, data.Int{}),
 	}
 }
 
@@ -22,16 +22,19 @@ func (f *CallUserFuncFunction) Call(ctx data.Context) (data.GetValue, data.Contr
 		return nil, utils.NewThrow(errors.New("call_user_func 缺少回调参数"))
 	}
 
-	// 收集实参（从 index 1 开始）
+	// 实参由可变参数 $args 收集
 	var argValues []data.Value
-	for i := 1; ; i++ {
-		v, ok := ctx.GetIndexValue(i)
-		if !ok {
-			break
+	if v, ok := ctx.GetIndexValue(1); ok {
+		if list, ok := v.(*data.ArrayValue); ok {
+			argValues = list.ToValueList()
 		}
-		argValues = append(argValues, v)
 	}
+	return CallUserFunc(ctx, cb, argValues)
+}
 
+// CallUserFunc 按 call_user_func 的规则以 args 为实参调用回调，供标准库中接受 callable 的函数与类使用
+func CallUserFunc(ctx data.Context, cb data.Value, argValues []data.Value) (data.GetValue, data.Control) {
+	f := &CallUserFuncFunction{}
 	// 解析回调为 FuncValue
 	fn, acl := f.resolveCallback(ctx, cb)
 	if acl != nil {
@@ -40,16 +43,11 @@ func (f *CallUserFuncFunction) Call(ctx data.Context) (data.GetValue, data.Contr
 	if fn == nil {
 		return data.NewBoolValue(false), nil
 	}
-	// 创建调用上下文，传入实参（index 0 为 callback）
-	callCtx := ctx.CreateContext(make([]data.Variable, len(argValues)))
-	for i := range argValues {
-		callCtx.SetIndexZVal(i, ctx.GetIndexZVal(i+1))
-	}
 	// BoundFuncValue 需要保留以确保 BoundContext 被创建
 	if bfv, ok := cb.(*data.BoundFuncValue); ok {
-		return bfv.Call(callCtx)
+		return data.CallFunc(&data.BoundContext{Context: ctx, ScopeClass: bfv.ScopeClass}, bfv.Value, argValues)
 	}
-	return fn.Call(callCtx)
+	return data.CallFunc(ctx, fn.Value, argValues)
 }
 
 func (f *CallUserFuncFunction) resolveCallback(ctx data.Context, cb data.GetValue) (*data.FuncValue, data.Control) {
@@ -2,22 +2,21 @@ package core
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
 	"github.com/php-any/origami/utils"
 )
 
-// ClosureClass 提供 PHP Closure 相关静态方法
+// ClosureClass 提供 PHP Closure 相关静态方法，实例方法（bindTo、call、__invoke）由 data.FuncValue 提供
 type ClosureClass struct {
 	node.Node
-	bind data.Method
+	bind         data.Method
+	fromCallable data.Method
 }
 
 func (c *ClosureClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	if c.bind == nil {
-		c.bind = &ClosureBindMethod{}
-	}
 	return data.NewClassValue(c, ctx.CreateBaseContext()), nil
 }
 
@@ -37,12 +36,17 @@ func (c *ClosureClass) GetMethods() []data.Method { return nil }
 
 // 静态方法
 func (c *ClosureClass) GetStaticMethod(name string) (data.Method, bool) {
-	if c.bind == nil {
-		c.bind = &ClosureBindMethod{}
-	}
 	switch name {
 	case "bind":
+		if c.bind == nil {
+			c.bind = &ClosureBindMethod{}
+		}
 		return c.bind, true
+	case "fromCallable":
+		if c.fromCallable == nil {
+			c.fromCallable = &ClosureFromCallableMethod{}
+		}
+		return c.fromCallable, true
 	}
 	return nil, false
 }
@@ -50,8 +54,7 @@ func (c *ClosureClass) GetStaticMethod(name string) (data.Method, bool) {
 // GetConstruct 无构造函数
 func (c *ClosureClass) GetConstruct() data.Method { return nil }
 
-// ClosureBindMethod 实现 Closure::bind
-// 当前简化实现：返回原始闭包，不改变绑定对象/作用域
+// ClosureBindMethod 实现 Closure::bind($closure, $newThis, $newScope = "static")
 type ClosureBindMethod struct{}
 
 func (m *ClosureBindMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
@@ -59,23 +62,19 @@ func (m *ClosureBindMethod) Call(ctx data.Context) (data.GetValue, data.Control)
 	if !ok {
 		return nil, utils.NewThrow(errors.New("缺少参数: closure"))
 	}
-
-	_, ok = ctx.GetIndexValue(1)
+	newThis, ok := ctx.GetIndexValue(1)
 	if !ok {
 		return nil, utils.NewThrow(errors.New("缺少参数: newThis"))
 	}
-
 	newScope, _ := ctx.GetIndexValue(2)
 
-	// 仅接受可调用类型
 	switch fv := closureVal.(type) {
 	case *data.FuncValue:
-		if scopeStr, ok := newScope.(*data.StringValue); ok && scopeStr.Value != "" {
-			return data.NewBoundFuncValue(fv.Value, scopeStr.Value), nil
-		}
-		return closureVal, nil
+		return data.BindClosure(ctx, fv, newThis, newScope)
+	case *data.BoundFuncValue:
+		return data.BindClosure(ctx, &fv.FuncValue, newThis, newScope)
 	default:
-		return nil, utils.NewThrow(errors.New("Closure::bind 需要传入闭包/可调用类型"))
+		return nil, data.NewErrorThrowByName(nil, fmt.Errorf("Closure::bind(): Argument #1 ($closure) must be of type Closure, %s given", data.TypeName(closureVal)), "TypeError")
 	}
 }
 
@@ -88,8 +87,8 @@ func (m *ClosureBindMethod) GetIsStatic() bool { return true }
 func (m *ClosureBindMethod) GetParams() []data.GetValue {
 	return []data.GetValue{
 		node.NewParameter(nil, "closure", 0, nil, nil),
-		node.NewParameter(nil, "newThis", 1, nil, nil),  // 占位，当前未使用
-		node.NewParameter(nil, "newScope", 2, nil, nil), // 占位，当前未使用
+		node.NewParameter(nil, "newThis", 1, nil, nil),
+		node.NewParameter(nil, "newScope", 2, data.NewStringValue("static"), nil),
 	}
 }
 
@@ -102,3 +101,34 @@ func (m *ClosureBindMethod) GetVariables() []data.Variable {
 }
 
 func (m *ClosureBindMethod) GetReturnType() data.Types { return nil }
+
+// ClosureFromCallableMethod 实现 Closure::fromCallable($callback)
+type ClosureFromCallableMethod struct{}
+
+func (m *ClosureFromCallableMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	callback, ok := ctx.GetIndexValue(0)
+	if !ok {
+		return nil, utils.NewThrow(errors.New("缺少参数: callback"))
+	}
+	return node.CallableToClosure(ctx, nil, callback)
+}
+
+func (m *ClosureFromCallableMethod) GetName() string { return "fromCallable" }
+
+func (m *ClosureFromCallableMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+
+func (m *ClosureFromCallableMethod) GetIsStatic() bool { return true }
+
+func (m *ClosureFromCallableMethod) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "callback", 0, nil, nil),
+	}
+}
+
+func (m *ClosureFromCallableMethod) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "callback", 0, data.Mixed{}),
+	}
+}
+
+func (m *ClosureFromCallableMethod) GetReturnType() data.Types { return data.NewBaseType("Closure") }
@@ -41,6 +41,12 @@ func (f *ExitFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 			}
 		}
 	}
+	// 退出前执行当前执行流中仍在调用中的函数登记的 defer 延迟执行项
+	if acl := data.RunPendingDefers(ctx); acl != nil {
+		ctx.GetVM().ThrowControl(acl)
+	}
+	// 与 PHP 相同，退出前执行 shutdown 回调并销毁挂起的生成器
+	ctx.GetVM().RunShutdownCallbacks()
 	os.Exit(code)
 	return data.NewNullValue(), nil
 }
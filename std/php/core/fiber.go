@@ -0,0 +1,179 @@
+package core
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// Fiber 可以在任意调用深度挂起的执行单元，建立在 node.Coroutine 之上：
+// start/resume/throw 把控制权交给 Fiber，Fiber::suspend() 再交还给调用方，两者严格交替执行，不会并行。
+type Fiber struct {
+	callback data.Value
+	co       *node.Coroutine
+
+	returned bool // 回调正常返回（可以 getReturn）
+	threw    bool // 回调以异常结束
+	result   data.Value
+}
+
+// fiberFrame 正在执行的 Fiber 及其对应的 PHP 对象
+type fiberFrame struct {
+	fiber  *Fiber
+	object *data.ClassValue
+}
+
+// fiberStack 正在执行的 Fiber 栈，栈顶为当前 Fiber。
+// 在 Fiber 内部再 start/resume 另一个 Fiber 时入栈，对方挂起或结束时出栈。
+var (
+	fiberMu    sync.Mutex
+	fiberStack []fiberFrame
+)
+
+func pushFiber(frame fiberFrame) {
+	fiberMu.Lock()
+	fiberStack = append(fiberStack, frame)
+	fiberMu.Unlock()
+}
+
+func popFiber() {
+	fiberMu.Lock()
+	fiberStack[len(fiberStack)-1] = fiberFrame{}
+	fiberStack = fiberStack[:len(fiberStack)-1]
+	fiberMu.Unlock()
+}
+
+// currentFiber 返回当前正在执行的 Fiber
+func currentFiber() (fiberFrame, bool) {
+	fiberMu.Lock()
+	defer fiberMu.Unlock()
+	if len(fiberStack) == 0 {
+		return fiberFrame{}, false
+	}
+	return fiberStack[len(fiberStack)-1], true
+}
+
+func fiberError(msg string) data.Control {
+	return data.NewErrorThrowByName(nil, errors.New(msg), "FiberError")
+}
+
+func (f *Fiber) IsStarted() bool    { return f.co != nil }
+func (f *Fiber) IsRunning() bool    { return f.co != nil && f.co.IsRunning() }
+func (f *Fiber) IsTerminated() bool { return f.co != nil && f.co.IsFinished() }
+func (f *Fiber) IsSuspended() bool {
+	return f.co != nil && !f.co.IsRunning() && !f.co.IsFinished()
+}
+
+// Start 启动 Fiber，执行到第一次 Fiber::suspend() 或回调结束
+func (f *Fiber) Start(ctx data.Context, object *data.ClassValue, args []data.Value) (data.GetValue, data.Control) {
+	if f.co != nil {
+		return nil, fiberError("Cannot start a fiber that has already been started")
+	}
+	// 回调在对象创建时的上下文中执行，协程不持有 Fiber 对象本身，挂起中的 Fiber 不可达时可以被回收
+	if object != nil {
+		ctx = object.Context
+	}
+	callback := f.callback
+	f.co = node.NewCoroutine(func(_ *node.Coroutine) (data.GetValue, data.Control) {
+		return callFiberCallback(ctx, callback, args)
+	})
+	runtime.SetFinalizer(f, func(f *Fiber) { f.co.Abort() })
+	return f.transfer(object, node.CoroutineSignal{})
+}
+
+// Resume 恢复已挂起的 Fiber，value 作为 Fiber::suspend() 的返回值
+func (f *Fiber) Resume(object *data.ClassValue, value data.Value) (data.GetValue, data.Control) {
+	if !f.IsSuspended() {
+		return nil, fiberError("Cannot resume a fiber that is not suspended")
+	}
+	if value == nil {
+		value = data.NewNullValue()
+	}
+	return f.transfer(object, node.CoroutineSignal{Value: value})
+}
+
+// Throw 恢复已挂起的 Fiber，并在 Fiber::suspend() 处抛出异常
+func (f *Fiber) Throw(object *data.ClassValue, exception data.Value) (data.GetValue, data.Control) {
+	if !f.IsSuspended() {
+		return nil, fiberError("Cannot resume a fiber that is not suspended")
+	}
+	var throw data.Control
+	switch e := exception.(type) {
+	case *data.ThrowValue:
+		throw = e
+	case *data.ClassValue:
+		throw = data.NewErrorThrowFromClassValue(nil, e)
+	default:
+		return nil, data.NewErrorThrowByName(nil, errors.New("Fiber::throw(): Argument #1 ($exception) must be of type Throwable"), "TypeError")
+	}
+	return f.transfer(object, node.CoroutineSignal{Throw: throw})
+}
+
+// GetReturn 回调正常结束后的返回值
+func (f *Fiber) GetReturn() (data.GetValue, data.Control) {
+	switch {
+	case f.returned:
+		if f.result == nil {
+			return data.NewNullValue(), nil
+		}
+		return f.result, nil
+	case f.co == nil:
+		return nil, fiberError("Cannot get fiber return value: The fiber has not been started")
+	case f.threw:
+		return nil, fiberError("Cannot get fiber return value: The fiber threw an exception")
+	}
+	return nil, fiberError("Cannot get fiber return value: The fiber has not returned")
+}
+
+// transfer 把控制权交给 Fiber，直到它再次挂起或结束；Fiber 内未捕获的异常交给恢复方
+func (f *Fiber) transfer(object *data.ClassValue, sig node.CoroutineSignal) (data.GetValue, data.Control) {
+	pushFiber(fiberFrame{fiber: f, object: object})
+	ev := f.co.Resume(sig)
+	popFiber()
+	if ev.Done {
+		if ev.Control != nil {
+			f.threw = true
+			return nil, ev.Control
+		}
+		f.returned = true
+		f.result = ev.Result
+		return data.NewNullValue(), nil
+	}
+	if v, ok := ev.Payload.(data.Value); ok && v != nil {
+		return v, nil
+	}
+	return data.NewNullValue(), nil
+}
+
+// suspendFiber 挂起当前 Fiber，把 value 交给恢复方，返回下一次 resume() 传入的值
+func suspendFiber(value data.Value) (data.GetValue, data.Control) {
+	frame, ok := currentFiber()
+	if !ok {
+		return nil, fiberError("Cannot suspend outside of fiber")
+	}
+	co := frame.fiber.co
+	if co.IsDestroying() {
+		return nil, fiberError("Cannot suspend in a force-closed fiber")
+	}
+	if value == nil {
+		value = data.NewNullValue()
+	}
+	sig := co.Suspend(value)
+	switch {
+	case sig.Destroy:
+		return nil, &node.DestroyControl{}
+	case sig.Throw != nil:
+		return nil, sig.Throw
+	case sig.Value != nil:
+		return sig.Value, nil
+	}
+	return data.NewNullValue(), nil
+}
+
+// callFiberCallback 按 call_user_func 的规则调用 Fiber 回调
+func callFiberCallback(ctx data.Context, callback data.Value, args []data.Value) (data.GetValue, data.Control) {
+	return CallUserFunc(ctx, callback, args)
+}
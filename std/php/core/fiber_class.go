@@ -1,13 +1,16 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
 )
 
-// FiberClass 表示 PHP 8.1+ 的 Fiber 类的简化桩
+// FiberClass 表示 PHP 8.1+ 的 Fiber 类；每个实例持有自己的 Fiber 执行状态
 type FiberClass struct {
 	node.Node
+	fiber *Fiber
 }
 
 func NewFiberClass() *FiberClass {
@@ -28,6 +31,15 @@ func (f *FiberClass) GetImplements() []string {
 
 func (f *FiberClass) GetMethods() []data.Method {
 	return []data.Method{
+		&FiberStartMethod{source: f},
+		&FiberResumeMethod{source: f},
+		&FiberThrowMethod{source: f},
+		&FiberGetReturnMethod{source: f},
+		&FiberIsStartedMethod{source: f},
+		&FiberIsSuspendedMethod{source: f},
+		&FiberIsRunningMethod{source: f},
+		&FiberIsTerminatedMethod{source: f},
+		&FiberSuspendMethod{},
 		&FiberGetCurrentMethod{},
 	}
 }
@@ -43,7 +55,7 @@ func (f *FiberClass) GetMethod(name string) (data.Method, bool) {
 }
 
 func (f *FiberClass) GetConstruct() data.Method {
-	return nil
+	return &FiberConstructMethod{source: f}
 }
 
 func (f *FiberClass) GetProperty(name string) (data.Property, bool) {
@@ -55,7 +67,200 @@ func (f *FiberClass) GetPropertyList() []data.Property {
 }
 
 func (f *FiberClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	return data.NewClassValue(f, ctx.CreateBaseContext()), nil
+	return data.NewClassValue(&FiberClass{fiber: &Fiber{}}, ctx.CreateBaseContext()), nil
+}
+
+// fiberObject 取得方法调用所在的 Fiber 对象
+func fiberObject(ctx data.Context) *data.ClassValue {
+	switch c := ctx.(type) {
+	case *data.ClassMethodContext:
+		return c.ClassValue
+	case *data.ClassValue:
+		return c
+	}
+	return nil
+}
+
+// FiberConstructMethod 构造函数 __construct(callable $callback)
+type FiberConstructMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberConstructMethod) GetName() string            { return "__construct" }
+func (m *FiberConstructMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FiberConstructMethod) GetIsStatic() bool          { return false }
+func (m *FiberConstructMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "callback", 0, nil, nil)}
+}
+func (m *FiberConstructMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "callback", 0, data.Mixed{})}
+}
+func (m *FiberConstructMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (m *FiberConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	callback, ok := ctx.GetIndexValue(0)
+	if !ok {
+		return nil, data.NewErrorThrowByName(nil, errors.New("Fiber::__construct() expects exactly 1 argument, 0 given"), "ArgumentCountError")
+	}
+	m.source.fiber.callback = callback
+	return nil, nil
+}
+
+// FiberStartMethod start(mixed ...$args): mixed
+type FiberStartMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberStartMethod) GetName() string            { return "start" }
+func (m *FiberStartMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FiberStartMethod) GetIsStatic() bool          { return false }
+func (m *FiberStartMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameters(nil, "args", 0, nil, nil)}
+}
+func (m *FiberStartMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "args", 0, data.Mixed{})}
+}
+func (m *FiberStartMethod) GetReturnType() data.Types { return data.Mixed{} }
+func (m *FiberStartMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	var args []data.Value
+	if v, ok := ctx.GetIndexValue(0); ok {
+		if arr, ok := v.(*data.ArrayValue); ok {
+			args = arr.ToValueList()
+		}
+	}
+	return m.source.fiber.Start(ctx, fiberObject(ctx), args)
+}
+
+// FiberResumeMethod resume(mixed $value = null): mixed
+type FiberResumeMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberResumeMethod) GetName() string            { return "resume" }
+func (m *FiberResumeMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FiberResumeMethod) GetIsStatic() bool          { return false }
+func (m *FiberResumeMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "value", 0, data.NewNullValue(), nil)}
+}
+func (m *FiberResumeMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "value", 0, data.Mixed{})}
+}
+func (m *FiberResumeMethod) GetReturnType() data.Types { return data.Mixed{} }
+func (m *FiberResumeMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	value, _ := ctx.GetIndexValue(0)
+	return m.source.fiber.Resume(fiberObject(ctx), value)
+}
+
+// FiberThrowMethod throw(Throwable $exception): mixed
+type FiberThrowMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberThrowMethod) GetName() string            { return "throw" }
+func (m *FiberThrowMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FiberThrowMethod) GetIsStatic() bool          { return false }
+func (m *FiberThrowMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "exception", 0, nil, nil)}
+}
+func (m *FiberThrowMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "exception", 0, data.Mixed{})}
+}
+func (m *FiberThrowMethod) GetReturnType() data.Types { return data.Mixed{} }
+func (m *FiberThrowMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	exception, _ := ctx.GetIndexValue(0)
+	return m.source.fiber.Throw(fiberObject(ctx), exception)
+}
+
+// FiberGetReturnMethod getReturn(): mixed
+type FiberGetReturnMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberGetReturnMethod) GetName() string               { return "getReturn" }
+func (m *FiberGetReturnMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FiberGetReturnMethod) GetIsStatic() bool             { return false }
+func (m *FiberGetReturnMethod) GetParams() []data.GetValue    { return nil }
+func (m *FiberGetReturnMethod) GetVariables() []data.Variable { return nil }
+func (m *FiberGetReturnMethod) GetReturnType() data.Types     { return data.Mixed{} }
+func (m *FiberGetReturnMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return m.source.fiber.GetReturn()
+}
+
+// FiberIsStartedMethod isStarted(): bool
+type FiberIsStartedMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberIsStartedMethod) GetName() string               { return "isStarted" }
+func (m *FiberIsStartedMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FiberIsStartedMethod) GetIsStatic() bool             { return false }
+func (m *FiberIsStartedMethod) GetParams() []data.GetValue    { return nil }
+func (m *FiberIsStartedMethod) GetVariables() []data.Variable { return nil }
+func (m *FiberIsStartedMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+func (m *FiberIsStartedMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.fiber.IsStarted()), nil
+}
+
+// FiberIsSuspendedMethod isSuspended(): bool
+type FiberIsSuspendedMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberIsSuspendedMethod) GetName() string               { return "isSuspended" }
+func (m *FiberIsSuspendedMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FiberIsSuspendedMethod) GetIsStatic() bool             { return false }
+func (m *FiberIsSuspendedMethod) GetParams() []data.GetValue    { return nil }
+func (m *FiberIsSuspendedMethod) GetVariables() []data.Variable { return nil }
+func (m *FiberIsSuspendedMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+func (m *FiberIsSuspendedMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.fiber.IsSuspended()), nil
+}
+
+// FiberIsRunningMethod isRunning(): bool
+type FiberIsRunningMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberIsRunningMethod) GetName() string               { return "isRunning" }
+func (m *FiberIsRunningMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FiberIsRunningMethod) GetIsStatic() bool             { return false }
+func (m *FiberIsRunningMethod) GetParams() []data.GetValue    { return nil }
+func (m *FiberIsRunningMethod) GetVariables() []data.Variable { return nil }
+func (m *FiberIsRunningMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+func (m *FiberIsRunningMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.fiber.IsRunning()), nil
+}
+
+// FiberIsTerminatedMethod isTerminated(): bool
+type FiberIsTerminatedMethod struct {
+	source *FiberClass
+}
+
+func (m *FiberIsTerminatedMethod) GetName() string               { return "isTerminated" }
+func (m *FiberIsTerminatedMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *FiberIsTerminatedMethod) GetIsStatic() bool             { return false }
+func (m *FiberIsTerminatedMethod) GetParams() []data.GetValue    { return nil }
+func (m *FiberIsTerminatedMethod) GetVariables() []data.Variable { return nil }
+func (m *FiberIsTerminatedMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+func (m *FiberIsTerminatedMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.fiber.IsTerminated()), nil
+}
+
+// FiberSuspendMethod 静态方法 suspend(mixed $value = null): mixed
+type FiberSuspendMethod struct{}
+
+func (m *FiberSuspendMethod) GetName() string            { return "suspend" }
+func (m *FiberSuspendMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *FiberSuspendMethod) GetIsStatic() bool          { return true }
+func (m *FiberSuspendMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "value", 0, data.NewNullValue(), nil)}
+}
+func (m *FiberSuspendMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "value", 0, data.Mixed{})}
+}
+func (m *FiberSuspendMethod) GetReturnType() data.Types { return data.Mixed{} }
+func (m *FiberSuspendMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	value, _ := ctx.GetIndexValue(0)
+	return suspendFiber(value)
 }
 
 // FiberGetCurrentMethod 静态方法 getCurrent
@@ -68,5 +273,8 @@ func (m *FiberGetCurrentMethod) GetParams() []data.GetValue    { return nil }
 func (m *FiberGetCurrentMethod) GetVariables() []data.Variable { return nil }
 func (m *FiberGetCurrentMethod) GetReturnType() data.Types     { return nil }
 func (m *FiberGetCurrentMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	if frame, ok := currentFiber(); ok && frame.object != nil {
+		return frame.object, nil
+	}
 	return data.NewNullValue(), nil
 }
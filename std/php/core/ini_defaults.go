@@ -23,13 +23,16 @@ var iniDefaults = map[string]string{
 	"file_uploads":             "1",
 	"enable_post_data_reading": "1",
 	"register_argc_argv":       "1",
+	// 分词结果的磁盘缓存，与 opcache.enable_cli 一样默认关闭，见 opcache 包
+	"zy.cache_enable": "0",
+	"zy.cache_dir":    "",
 }
 
 // InitIniDefaults 在运行时启动时应用默认 ini，并合并 PHPT 传入的 ORIGAMI_PHPT_INI。
 func InitIniDefaults() {
 	for key, value := range iniDefaults {
 		if _, ok := IniGet(key); !ok {
-			iniStore.Store(key, value)
+			storeIni(key, value)
 		}
 	}
 	applyIniFromEnv(os.Getenv("ORIGAMI_PHPT_INI"))
@@ -98,13 +101,13 @@ func applyIniFromEnv(raw string) {
 		}
 		key := strings.ToLower(strings.TrimSpace(parts[0]))
 		value := strings.TrimSpace(parts[1])
-		iniStore.Store(key, value)
+		storeIni(key, value)
 	}
 }
 
 // ApplyIniMap 批量设置 ini（PHPT --INI-- 等）。
 func ApplyIniMap(values map[string]string) {
 	for key, value := range values {
-		iniStore.Store(strings.ToLower(key), value)
+		storeIni(strings.ToLower(key), value)
 	}
 }
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/php-any/origami/data"
 )
 
 // iniStore 是全局 PHP ini 配置存储，模拟 php.ini 运行时可修改的配置项。
@@ -13,18 +16,35 @@ func IniSet(key, value string) (string, bool) {
 	key = strings.ToLower(key)
 	if old, loaded := iniStore.Load(key); loaded {
 		if s, ok := old.(string); ok {
-			iniStore.Store(key, value)
+			storeIni(key, value)
 			return s, true
 		}
 	}
 	prev, had := IniGet(key)
-	iniStore.Store(key, value)
+	storeIni(key, value)
 	if had {
 		return prev, true
 	}
 	return "", false
 }
 
+// storeIni 写入配置项；precision 与 serialize_precision 同步到浮点数的字符串转换
+func storeIni(key, value string) {
+	iniStore.Store(key, value)
+	switch key {
+	case "precision", "serialize_precision":
+		p, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return
+		}
+		if key == "precision" {
+			data.SetFloatPrecision(p)
+		} else {
+			data.SetSerializePrecision(p)
+		}
+	}
+}
+
 // IniGet 获取配置项，若不存在返回 ("", false)。
 func IniGet(key string) (string, bool) {
 	key = strings.ToLower(key)
@@ -1,57 +1,16 @@
 package core
 
 import (
-	"strings"
-
 	"github.com/php-any/origami/data"
 )
 
-// outputBufferStack 输出缓冲栈
-type outputBufferStack struct {
-	buffers []strings.Builder
-}
-
-var obStack = &outputBufferStack{buffers: []strings.Builder{{}}}
-
-func (s *outputBufferStack) push() {
-	s.buffers = append(s.buffers, strings.Builder{})
-	s.syncWriter()
-}
-
-func (s *outputBufferStack) pop() string {
-	if len(s.buffers) <= 1 {
-		return ""
-	}
-	last := s.buffers[len(s.buffers)-1].String()
-	s.buffers = s.buffers[:len(s.buffers)-1]
-	s.syncWriter()
-	return last
-}
-
-func (s *outputBufferStack) contents() string {
-	if len(s.buffers) == 0 {
-		return ""
-	}
-	return s.buffers[len(s.buffers)-1].String()
-}
-
-func (s *outputBufferStack) syncWriter() {
-	if len(s.buffers) <= 1 {
-		data.WriteOutput = data.DefaultOutputWriter
-		return
-	}
-	buf := &s.buffers[len(s.buffers)-1]
-	data.WriteOutput = func(str string) {
-		buf.WriteString(str)
-	}
-}
-
 // ObStartFunction 实现 ob_start
 type ObStartFunction struct{}
 
 func NewObStartFunction() data.FuncStmt { return &ObStartFunction{} }
 func (f *ObStartFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
-	obStack.push()
+	// 输出缓冲按执行流隔离（spawn 的协程、HTTP 请求各自独立）
+	data.RoutineOf(ctx).Output().Push()
 	return data.NewBoolValue(true), nil
 }
 func (f *ObStartFunction) GetName() string               { return "ob_start" }
@@ -66,7 +25,7 @@ type ObGetCleanFunction struct{}
 
 func NewObGetCleanFunction() data.FuncStmt { return &ObGetCleanFunction{} }
 func (f *ObGetCleanFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
-	content := obStack.pop()
+	content, _ := data.RoutineOf(ctx).Output().Pop()
 	return data.NewStringValue(content), nil
 }
 func (f *ObGetCleanFunction) GetName() string               { return "ob_get_clean" }
@@ -81,7 +40,7 @@ type ObGetContentsFunction struct{}
 
 func NewObGetContentsFunction() data.FuncStmt { return &ObGetContentsFunction{} }
 func (f *ObGetContentsFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
-	content := obStack.contents()
+	content, _ := data.RoutineOf(ctx).Output().Contents()
 	return data.NewStringValue(content), nil
 }
 func (f *ObGetContentsFunction) GetName() string               { return "ob_get_contents" }
@@ -96,7 +55,7 @@ type ObEndCleanFunction struct{}
 
 func NewObEndCleanFunction() data.FuncStmt { return &ObEndCleanFunction{} }
 func (f *ObEndCleanFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
-	obStack.pop()
+	data.RoutineOf(ctx).Output().Pop()
 	return data.NewBoolValue(true), nil
 }
 func (f *ObEndCleanFunction) GetName() string               { return "ob_end_clean" }
@@ -111,7 +70,7 @@ type ObGetLevelFunction struct{}
 
 func NewObGetLevelFunction() data.FuncStmt { return &ObGetLevelFunction{} }
 func (f *ObGetLevelFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
-	return data.NewIntValue(len(obStack.buffers) - 1), nil
+	return data.NewIntValue(data.RoutineOf(ctx).Output().Level()), nil
 }
 func (f *ObGetLevelFunction) GetName() string               { return "ob_get_level" }
 func (f *ObGetLevelFunction) GetModifier() data.Modifier    { return data.ModifierPublic }
@@ -2,7 +2,6 @@ package core
 
 import (
 	"github.com/php-any/origami/data"
-	"github.com/php-any/origami/runtime"
 )
 
 // RestoreExceptionHandlerFunction 实现 restore_exception_handler 函数
@@ -21,22 +20,14 @@ func NewRestoreExceptionHandlerFunction() data.FuncStmt {
 }
 
 func (f *RestoreExceptionHandlerFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
-	vm := ctx.GetVM()
-
 	// 读取当前 handler
-	current := getCurrentExceptionHandler(vm)
+	current := getCurrentExceptionHandler(ctx)
 	if current == nil {
 		// 没有可恢复的 handler
 		return data.NewBoolValue(false), nil
 	}
 
-	// 根据 VM 类型清空 handler
-	switch v := vm.(type) {
-	case *runtime.VM:
-		v.SetExceptionHandler(nil)
-	case *runtime.TempVM:
-		v.SetExceptionHandler(nil)
-	}
+	data.RoutineOf(ctx).SetExceptionHandler(nil)
 
 	return data.NewBoolValue(true), nil
 }
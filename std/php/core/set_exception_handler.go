@@ -3,7 +3,6 @@ package core
 import (
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
-	"github.com/php-any/origami/runtime"
 )
 
 // SetExceptionHandlerFunction 实现 set_exception_handler 函数
@@ -19,30 +18,23 @@ func (f *SetExceptionHandlerFunction) Call(ctx data.Context) (data.GetValue, dat
 	cb, ok := ctx.GetIndexValue(0)
 	if !ok {
 		// 未传入回调时，直接返回当前回调（与 PHP 行为略有差异，但更安全）
-		if old := getCurrentExceptionHandler(ctx.GetVM()); old != nil {
+		if old := getCurrentExceptionHandler(ctx); old != nil {
 			return old, nil
 		}
 		return data.NewNullValue(), nil
 	}
 
-	vm := ctx.GetVM()
-
-	// 根据 VM 实现类型设置异常处理回调
-	var old data.Value
-	switch v := vm.(type) {
-	case *runtime.VM:
-		old = v.SetExceptionHandler(cb)
-	case *runtime.TempVM:
-		old = v.SetExceptionHandler(cb)
-	default:
-		// 其他 VM 实现（如 LspVM）忽略设置，仅返回 null
+	// 回调注册在当前执行流上：spawn 的协程、HTTP 请求各自独立，创建时继承上级的回调
+	routine := data.RoutineOf(ctx)
+	if routine == nil {
+		// 没有执行流的上下文（如 LSP）忽略设置，仅返回 null
 		return data.NewNullValue(), nil
 	}
 
-	if old == nil {
-		return data.NewNullValue(), nil
+	if old := routine.SetExceptionHandler(cb); old != nil {
+		return old, nil
 	}
-	return old, nil
+	return data.NewNullValue(), nil
 }
 
 func (f *SetExceptionHandlerFunction) GetName() string {
@@ -61,14 +53,7 @@ func (f *SetExceptionHandlerFunction) GetVariables() []data.Variable {
 	}
 }
 
-// getCurrentExceptionHandler 辅助函数，从 VM 中获取当前异常处理回调
-func getCurrentExceptionHandler(vm data.VM) data.Value {
-	switch v := vm.(type) {
-	case *runtime.VM:
-		return v.GetExceptionHandler()
-	case *runtime.TempVM:
-		return v.GetExceptionHandler()
-	default:
-		return nil
-	}
+// getCurrentExceptionHandler 辅助函数，获取当前执行流的异常处理回调
+func getCurrentExceptionHandler(ctx data.Context) data.Value {
+	return data.RoutineOf(ctx).ExceptionHandler()
 }
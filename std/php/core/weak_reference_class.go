@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// WeakReferenceClass 表示 PHP 7.4+ 的 WeakReference 类：持有对象但不阻止其析构。
+// 只能通过 WeakReference::create 创建，同一对象多次 create 返回同一个实例。
+type WeakReferenceClass struct {
+	node.Node
+	ref data.WeakObject
+}
+
+// weakReferenceShape 所有 WeakReference 实例共用的布局缓存
+var weakReferenceShape data.ShapeCache
+
+func NewWeakReferenceClass() *WeakReferenceClass {
+	return &WeakReferenceClass{}
+}
+
+func (w *WeakReferenceClass) GetName() string                               { return "WeakReference" }
+func (w *WeakReferenceClass) GetExtend() *string                            { return nil }
+func (w *WeakReferenceClass) GetImplements() []string                       { return nil }
+func (w *WeakReferenceClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (w *WeakReferenceClass) GetPropertyList() []data.Property              { return nil }
+func (w *WeakReferenceClass) GetConstruct() data.Method                     { return &WeakReferenceConstructMethod{} }
+func (w *WeakReferenceClass) ShapeCache() *data.ShapeCache                  { return &weakReferenceShape }
+
+func (w *WeakReferenceClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(w, ctx.CreateBaseContext()), nil
+}
+
+func (w *WeakReferenceClass) GetMethods() []data.Method {
+	return []data.Method{&WeakReferenceGetMethod{source: w}}
+}
+
+func (w *WeakReferenceClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "get":
+		return &WeakReferenceGetMethod{source: w}, true
+	}
+	return nil, false
+}
+
+func (w *WeakReferenceClass) GetStaticMethod(name string) (data.Method, bool) {
+	switch name {
+	case "create":
+		return &WeakReferenceCreateMethod{}, true
+	}
+	return nil, false
+}
+
+// WeakReferenceConstructMethod `new WeakReference()` 与 PHP 一致地报错
+type WeakReferenceConstructMethod struct{}
+
+func (m *WeakReferenceConstructMethod) GetName() string               { return "__construct" }
+func (m *WeakReferenceConstructMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *WeakReferenceConstructMethod) GetIsStatic() bool             { return false }
+func (m *WeakReferenceConstructMethod) GetParams() []data.GetValue    { return nil }
+func (m *WeakReferenceConstructMethod) GetVariables() []data.Variable { return nil }
+func (m *WeakReferenceConstructMethod) GetReturnType() data.Types     { return nil }
+
+func (m *WeakReferenceConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, data.NewErrorThrowByName(nil, errors.New("Direct instantiation of WeakReference is not allowed, use WeakReference::create instead"), "Error")
+}
+
+// WeakReferenceCreateMethod 实现 WeakReference::create
+type WeakReferenceCreateMethod struct{}
+
+func (m *WeakReferenceCreateMethod) GetName() string            { return "create" }
+func (m *WeakReferenceCreateMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *WeakReferenceCreateMethod) GetIsStatic() bool          { return true }
+func (m *WeakReferenceCreateMethod) GetReturnType() data.Types {
+	return data.NewBaseType("WeakReference")
+}
+
+func (m *WeakReferenceCreateMethod) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "object", 0, nil, data.NewBaseType("object")),
+	}
+}
+
+func (m *WeakReferenceCreateMethod) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "object", 0, data.NewBaseType("object")),
+	}
+}
+
+func (m *WeakReferenceCreateMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, _ := ctx.GetIndexValue(0)
+	var cv *data.ClassValue
+	switch o := v.(type) {
+	case *data.ClassValue:
+		cv = o
+	case *data.ThisValue:
+		cv = o.ClassValue
+	}
+	if cv == nil || cv.ObjectValue == nil {
+		return nil, data.NewErrorThrowByName(nil, errors.New("WeakReference::create(): Argument #1 ($object) must be of type object"), "TypeError")
+	}
+	ref := data.NewWeakObject(cv)
+	return ref.Ref(func() data.Value {
+		return data.NewClassValue(&WeakReferenceClass{ref: ref}, ctx.CreateBaseContext())
+	}), nil
+}
+
+// WeakReferenceGetMethod 实现 WeakReference::get：对象已析构时返回 null
+type WeakReferenceGetMethod struct {
+	source *WeakReferenceClass
+}
+
+func (m *WeakReferenceGetMethod) GetName() string               { return "get" }
+func (m *WeakReferenceGetMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *WeakReferenceGetMethod) GetIsStatic() bool             { return false }
+func (m *WeakReferenceGetMethod) GetParams() []data.GetValue    { return nil }
+func (m *WeakReferenceGetMethod) GetVariables() []data.Variable { return nil }
+func (m *WeakReferenceGetMethod) GetReturnType() data.Types     { return data.NewBaseType("?object") }
+
+func (m *WeakReferenceGetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	if cv := m.source.ref.Get(); cv != nil {
+		return cv, nil
+	}
+	return data.NewNullValue(), nil
+}
@@ -1,14 +1,56 @@
 package core
 
 import (
+	"errors"
+	"sync"
+
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
 )
 
 // WeakMapClass 表示 PHP 8.0+ 的 WeakMap 类
-// WeakMap 允许创建对象的弱引用映射，不会阻止对象被垃圾回收
+// WeakMap 以对象为键，不阻止键对象析构：键对象析构后对应的条目随之消失。
+// 每个实例持有自己的条目（与 Sync\Map 相同，GetValue 创建新的类实例）。
 type WeakMapClass struct {
 	node.Node
+	store *weakMapStore
+}
+
+// weakMapShape 所有 WeakMap 实例共用的布局缓存，避免每个实例在 builtinShapes 中登记一份
+var weakMapShape data.ShapeCache
+
+type weakMapEntry struct {
+	key   data.WeakObject
+	value data.Value
+}
+
+// weakMapStore 按插入顺序保存条目；键对象析构后的条目在下一次访问时清除
+type weakMapStore struct {
+	mu      sync.Mutex
+	entries []weakMapEntry
+}
+
+// live 清除键对象已析构的条目，返回当前条目的快照；调用方持有 mu
+func (s *weakMapStore) live() []weakMapEntry {
+	n := 0
+	for _, e := range s.entries {
+		if e.key.Alive() {
+			s.entries[n] = e
+			n++
+		}
+	}
+	clear(s.entries[n:])
+	s.entries = s.entries[:n]
+	return s.entries
+}
+
+func (s *weakMapStore) find(key data.WeakObject) int {
+	for i, e := range s.live() {
+		if e.key == key {
+			return i
+		}
+	}
+	return -1
 }
 
 func NewWeakMapClass() *WeakMapClass {
@@ -24,16 +66,17 @@ func (w *WeakMapClass) GetExtend() *string {
 }
 
 func (w *WeakMapClass) GetImplements() []string {
-	return []string{"ArrayAccess", "Countable"}
+	return []string{"ArrayAccess", "Countable", "IteratorAggregate"}
 }
 
 func (w *WeakMapClass) GetMethods() []data.Method {
 	return []data.Method{
-		&WeakMapOffsetExistsMethod{},
-		&WeakMapOffsetGetMethod{},
-		&WeakMapOffsetSetMethod{},
-		&WeakMapOffsetUnsetMethod{},
-		&WeakMapCountMethod{},
+		&WeakMapOffsetExistsMethod{source: w},
+		&WeakMapOffsetGetMethod{source: w},
+		&WeakMapOffsetSetMethod{source: w},
+		&WeakMapOffsetUnsetMethod{source: w},
+		&WeakMapCountMethod{source: w},
+		&WeakMapGetIteratorMethod{source: w},
 	}
 }
 
@@ -59,12 +102,46 @@ func (w *WeakMapClass) GetPropertyList() []data.Property {
 	return nil
 }
 
+func (w *WeakMapClass) ShapeCache() *data.ShapeCache {
+	return &weakMapShape
+}
+
 func (w *WeakMapClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
-	return data.NewClassValue(w, ctx.CreateBaseContext()), nil
+	return data.NewClassValue(&WeakMapClass{store: &weakMapStore{}}, ctx.CreateBaseContext()), nil
+}
+
+// weakMapKey 检查键是否为对象
+func weakMapKey(ctx data.Context) (data.WeakObject, data.Control) {
+	v, _ := ctx.GetIndexValue(0)
+	var cv *data.ClassValue
+	switch o := v.(type) {
+	case *data.ClassValue:
+		cv = o
+	case *data.ThisValue:
+		cv = o.ClassValue
+	}
+	if cv == nil || cv.ObjectValue == nil {
+		return data.WeakObject{}, data.NewErrorThrowByName(nil, errors.New("WeakMap key must be an object"), "TypeError")
+	}
+	return data.NewWeakObject(cv), nil
+}
+
+func weakMapKeyParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "object", 0, nil, data.NewBaseType("object")),
+	}
+}
+
+func weakMapKeyVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "object", 0, data.NewBaseType("object")),
+	}
 }
 
 // WeakMapOffsetExistsMethod 实现 offsetExists 方法
-type WeakMapOffsetExistsMethod struct{}
+type WeakMapOffsetExistsMethod struct {
+	source *WeakMapClass
+}
 
 func (m *WeakMapOffsetExistsMethod) GetName() string {
 	return "offsetExists"
@@ -79,7 +156,7 @@ func (m *WeakMapOffsetExistsMethod) GetIsStatic() bool {
 }
 
 func (m *WeakMapOffsetExistsMethod) GetVariables() []data.Variable {
-	return nil
+	return weakMapKeyVariables()
 }
 
 func (m *WeakMapOffsetExistsMethod) GetReturnType() data.Types {
@@ -87,19 +164,29 @@ func (m *WeakMapOffsetExistsMethod) GetReturnType() data.Types {
 }
 
 func (m *WeakMapOffsetExistsMethod) GetParams() []data.GetValue {
-	return []data.GetValue{
-		node.NewParameter(nil, "object", 0, nil, data.NewBaseType("object")),
-	}
+	return weakMapKeyParams()
 }
 
 func (m *WeakMapOffsetExistsMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	// 简单实现，返回 false
-	// 实际需要使用真正的弱引用实现
+	key, acl := weakMapKey(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	s := m.source.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 与 isset 一致：值为 null 时视为不存在
+	if i := s.find(key); i >= 0 {
+		_, isNull := s.entries[i].value.(*data.NullValue)
+		return data.NewBoolValue(!isNull), nil
+	}
 	return data.NewBoolValue(false), nil
 }
 
 // WeakMapOffsetGetMethod 实现 offsetGet 方法
-type WeakMapOffsetGetMethod struct{}
+type WeakMapOffsetGetMethod struct {
+	source *WeakMapClass
+}
 
 func (m *WeakMapOffsetGetMethod) GetName() string {
 	return "offsetGet"
@@ -114,7 +201,7 @@ func (m *WeakMapOffsetGetMethod) GetIsStatic() bool {
 }
 
 func (m *WeakMapOffsetGetMethod) GetVariables() []data.Variable {
-	return nil
+	return weakMapKeyVariables()
 }
 
 func (m *WeakMapOffsetGetMethod) GetReturnType() data.Types {
@@ -122,19 +209,27 @@ func (m *WeakMapOffsetGetMethod) GetReturnType() data.Types {
 }
 
 func (m *WeakMapOffsetGetMethod) GetParams() []data.GetValue {
-	return []data.GetValue{
-		node.NewParameter(nil, "object", 0, nil, data.NewBaseType("object")),
-	}
+	return weakMapKeyParams()
 }
 
 func (m *WeakMapOffsetGetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	// 抛出异常，因为对象不存在
-	panic("InvalidArgumentException: Object not found in WeakMap")
-	return nil, nil
+	key, acl := weakMapKey(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	s := m.source.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.find(key); i >= 0 {
+		return s.entries[i].value, nil
+	}
+	return nil, data.NewErrorThrowByName(nil, errors.New("Object "+key.Get().GetName()+" not contained in WeakMap"), "Error")
 }
 
 // WeakMapOffsetSetMethod 实现 offsetSet 方法
-type WeakMapOffsetSetMethod struct{}
+type WeakMapOffsetSetMethod struct {
+	source *WeakMapClass
+}
 
 func (m *WeakMapOffsetSetMethod) GetName() string {
 	return "offsetSet"
@@ -167,12 +262,29 @@ func (m *WeakMapOffsetSetMethod) GetParams() []data.GetValue {
 }
 
 func (m *WeakMapOffsetSetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	// 简单实现，不存储任何内容
+	key, acl := weakMapKey(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	value, _ := ctx.GetIndexValue(1)
+	if value == nil {
+		value = data.NewNullValue()
+	}
+	s := m.source.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.find(key); i >= 0 {
+		s.entries[i].value = value
+	} else {
+		s.entries = append(s.entries, weakMapEntry{key: key, value: value})
+	}
 	return nil, nil
 }
 
 // WeakMapOffsetUnsetMethod 实现 offsetUnset 方法
-type WeakMapOffsetUnsetMethod struct{}
+type WeakMapOffsetUnsetMethod struct {
+	source *WeakMapClass
+}
 
 func (m *WeakMapOffsetUnsetMethod) GetName() string {
 	return "offsetUnset"
@@ -187,7 +299,7 @@ func (m *WeakMapOffsetUnsetMethod) GetIsStatic() bool {
 }
 
 func (m *WeakMapOffsetUnsetMethod) GetVariables() []data.Variable {
-	return nil
+	return weakMapKeyVariables()
 }
 
 func (m *WeakMapOffsetUnsetMethod) GetReturnType() data.Types {
@@ -195,18 +307,27 @@ func (m *WeakMapOffsetUnsetMethod) GetReturnType() data.Types {
 }
 
 func (m *WeakMapOffsetUnsetMethod) GetParams() []data.GetValue {
-	return []data.GetValue{
-		node.NewParameter(nil, "object", 0, nil, data.NewBaseType("object")),
-	}
+	return weakMapKeyParams()
 }
 
 func (m *WeakMapOffsetUnsetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	// 简单实现，不做任何操作
+	key, acl := weakMapKey(ctx)
+	if acl != nil {
+		return nil, acl
+	}
+	s := m.source.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.find(key); i >= 0 {
+		s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	}
 	return nil, nil
 }
 
 // WeakMapCountMethod 实现 count 方法
-type WeakMapCountMethod struct{}
+type WeakMapCountMethod struct {
+	source *WeakMapClass
+}
 
 func (m *WeakMapCountMethod) GetName() string {
 	return "count"
@@ -233,6 +354,128 @@ func (m *WeakMapCountMethod) GetParams() []data.GetValue {
 }
 
 func (m *WeakMapCountMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
-	// 返回 0，因为没有存储任何内容
-	return data.NewIntValue(0), nil
+	s := m.source.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return data.NewIntValue(len(s.live())), nil
+}
+
+// WeakMapGetIteratorMethod 实现 getIterator 方法：遍历调用时仍存活的条目，键为对象
+type WeakMapGetIteratorMethod struct {
+	source *WeakMapClass
+}
+
+func (m *WeakMapGetIteratorMethod) GetName() string {
+	return "getIterator"
+}
+
+func (m *WeakMapGetIteratorMethod) GetModifier() data.Modifier {
+	return data.ModifierPublic
+}
+
+func (m *WeakMapGetIteratorMethod) GetIsStatic() bool {
+	return false
+}
+
+func (m *WeakMapGetIteratorMethod) GetVariables() []data.Variable {
+	return nil
+}
+
+func (m *WeakMapGetIteratorMethod) GetReturnType() data.Types {
+	return data.NewBaseType("Iterator")
+}
+
+func (m *WeakMapGetIteratorMethod) GetParams() []data.GetValue {
+	return []data.GetValue{}
+}
+
+func (m *WeakMapGetIteratorMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	s := m.source.store
+	s.mu.Lock()
+	entries := append([]weakMapEntry(nil), s.live()...)
+	s.mu.Unlock()
+	return data.NewClassValue(&WeakMapIteratorClass{entries: entries}, ctx.CreateBaseContext()), nil
+}
+
+// WeakMapIteratorClass WeakMap::getIterator 返回的迭代器，遍历创建时的条目快照
+type WeakMapIteratorClass struct {
+	node.Node
+	entries []weakMapEntry
+	pos     int
+}
+
+var weakMapIteratorShape data.ShapeCache
+
+func (c *WeakMapIteratorClass) GetName() string                               { return "WeakMapIterator" }
+func (c *WeakMapIteratorClass) GetExtend() *string                            { return nil }
+func (c *WeakMapIteratorClass) GetImplements() []string                       { return []string{"Iterator"} }
+func (c *WeakMapIteratorClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *WeakMapIteratorClass) GetPropertyList() []data.Property              { return nil }
+func (c *WeakMapIteratorClass) GetConstruct() data.Method                     { return nil }
+func (c *WeakMapIteratorClass) ShapeCache() *data.ShapeCache                  { return &weakMapIteratorShape }
+
+func (c *WeakMapIteratorClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(c, ctx.CreateBaseContext()), nil
+}
+
+func (c *WeakMapIteratorClass) GetMethods() []data.Method {
+	return []data.Method{
+		&WeakMapIteratorMethod{source: c, name: "current"},
+		&WeakMapIteratorMethod{source: c, name: "key"},
+		&WeakMapIteratorMethod{source: c, name: "next"},
+		&WeakMapIteratorMethod{source: c, name: "rewind"},
+		&WeakMapIteratorMethod{source: c, name: "valid"},
+	}
+}
+
+func (c *WeakMapIteratorClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "current", "key", "next", "rewind", "valid":
+		return &WeakMapIteratorMethod{source: c, name: name}, true
+	}
+	return nil, false
+}
+
+// valid 跳过遍历期间键对象已析构的条目
+func (c *WeakMapIteratorClass) valid() bool {
+	for c.pos < len(c.entries) && !c.entries[c.pos].key.Alive() {
+		c.pos++
+	}
+	return c.pos < len(c.entries)
+}
+
+// WeakMapIteratorMethod 实现 Iterator 接口的各个方法
+type WeakMapIteratorMethod struct {
+	source *WeakMapIteratorClass
+	name   string
+}
+
+func (m *WeakMapIteratorMethod) GetName() string               { return m.name }
+func (m *WeakMapIteratorMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *WeakMapIteratorMethod) GetIsStatic() bool             { return false }
+func (m *WeakMapIteratorMethod) GetParams() []data.GetValue    { return nil }
+func (m *WeakMapIteratorMethod) GetVariables() []data.Variable { return nil }
+func (m *WeakMapIteratorMethod) GetReturnType() data.Types     { return nil }
+
+func (m *WeakMapIteratorMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	c := m.source
+	switch m.name {
+	case "rewind":
+		c.pos = 0
+	case "next":
+		c.pos++
+	case "valid":
+		return data.NewBoolValue(c.valid()), nil
+	case "current":
+		if c.valid() {
+			return c.entries[c.pos].value, nil
+		}
+	case "key":
+		if c.valid() {
+			if cv := c.entries[c.pos].key.Get(); cv != nil {
+				return cv, nil
+			}
+		}
+	}
+	return data.NewNullValue(), nil
 }
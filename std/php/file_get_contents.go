@@ -45,8 +45,11 @@ func (f *FileGetContentsFunction) Call(ctx data.Context) (data.GetValue, data.Co
 	if strings.HasPrefix(filePath, "http://") || strings.HasPrefix(filePath, "https://") {
 		contextVal, _ := ctx.GetIndexValue(2)
 		streamCtx := stream.ContextFromResource(contextVal)
-		content, ok := stream.HTTPGetContents(filePath, streamCtx)
+		content, ok := stream.HTTPGetContents(ctx.GoContext(), filePath, streamCtx)
 		if !ok {
+			if goCtx := ctx.GoContext(); goCtx.Err() != nil {
+				return nil, data.NewCancelledThrow(goCtx)
+			}
 			return data.NewBoolValue(false), nil
 		}
 		return data.NewStringValue(content), nil
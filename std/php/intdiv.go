@@ -0,0 +1,48 @@
+package php
+
+import (
+	"errors"
+	"math"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// IntdivFunction 实现 intdiv 函数：整数除法，除数为 0 抛出 DivisionByZeroError，
+// PHP_INT_MIN 除以 -1 抛出 ArithmeticError
+type IntdivFunction struct{}
+
+func NewIntdivFunction() data.FuncStmt { return &IntdivFunction{} }
+
+func (f *IntdivFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
+	a, _ := ctx.GetIndexValue(0)
+	b, _ := ctx.GetIndexValue(1)
+	var x, y int
+	if v, ok := a.(data.AsInt); ok {
+		x, _ = v.AsInt()
+	}
+	if v, ok := b.(data.AsInt); ok {
+		y, _ = v.AsInt()
+	}
+	if y == 0 {
+		return nil, data.NewErrorThrowByName(nil, data.ErrDivisionByZero, "DivisionByZeroError")
+	}
+	if x == math.MinInt && y == -1 {
+		return nil, data.NewErrorThrowByName(nil, errors.New("Division of PHP_INT_MIN by -1 is not an integer"), "ArithmeticError")
+	}
+	return data.NewIntValue(x / y), nil
+}
+
+func (f *IntdivFunction) GetName() string { return "intdiv" }
+func (f *IntdivFunction) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "num1", 0, nil, nil),
+		node.NewParameter(nil, "num2", 1, nil, nil),
+	}
+}
+func (f *IntdivFunction) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "num1", 0, nil),
+		node.NewVariable(nil, "num2", 1, nil),
+	}
+}
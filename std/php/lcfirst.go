@@ -42,7 +42,7 @@ func (f *LcfirstFunction) GetName() string {
 
 func (f *LcfirstFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/opcache"
 	ortruntime "github.com/php-any/origami/runtime"
 	"github.com/php-any/origami/std/exception"
 	"github.com/php-any/origami/std/php/array"
@@ -31,6 +32,7 @@ func Load(vm data.VM) {
 		core.CheckExecutionTimeLimit(file, line)
 	}
 	node.MarkHeaderOutputStarted = core.MarkHeaderOutputStarted
+	opcache.Setting = core.IniGet
 	ortruntime.RunHeaderCallbacksFn = core.RunHeaderCallbacks
 
 	for _, fun := range []data.FuncStmt{
@@ -45,6 +47,7 @@ func Load(vm data.VM) {
 		NewTimezoneNameGetFunction(),
 		NewTimezoneOpenFunction(),
 		NewSleepFunction(),
+		NewUsleepFunction(),
 		NewIsDirFunction(),
 		NewIsFileFunction(),
 		NewScandirFunction(),
@@ -166,6 +169,7 @@ func Load(vm data.VM) {
 		NewCtypeAlnumFunction(),
 		NewCeilFunction(),
 		NewFloorFunction(),
+		NewIntdivFunction(),
 		NewRoundFunction(),
 		NewPowFunction(),
 		NewRandomBytesFunction(),
@@ -301,6 +305,7 @@ func Load(vm data.VM) {
 	vm.AddClass(&core.StdClass{})
 	vm.AddClass(&core.NormalizerClass{})
 	vm.AddClass(&core.WeakMapClass{})
+	vm.AddClass(&core.WeakReferenceClass{})
 	vm.AddClass(&core.FiberClass{})
 
 	// 注册 DOM 类
@@ -54,7 +54,7 @@ func (f *Md5Function) GetName() string {
 
 func (f *Md5Function) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 		node.NewParameter(nil, "raw_output", 1, node.NewNullLiteral(nil), nil),
 	}
 }
@@ -37,7 +37,7 @@ func (f *OrdFunction) GetName() string {
 
 func (f *OrdFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
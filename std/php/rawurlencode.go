@@ -40,7 +40,7 @@ func (f *RawurlencodeFunction) GetName() string {
 
 func (f *RawurlencodeFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
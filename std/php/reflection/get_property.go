@@ -37,7 +37,7 @@ func (m *ReflectionClassGetPropertyMethod) GetVariables() []data.Variable {
 	}
 }
 
-// GetReturnType 返回返回类型，返回混合类型（当前实现返回字符串）
+// GetReturnType 返回返回类型（ReflectionProperty 对象）
 func (m *ReflectionClassGetPropertyMethod) GetReturnType() data.Types {
 	return data.Mixed{}
 }
@@ -45,7 +45,6 @@ func (m *ReflectionClassGetPropertyMethod) GetReturnType() data.Types {
 // Call 执行 getProperty 方法
 // 根据属性名查找并返回对应的属性
 // 如果属性不存在，抛出异常
-// TODO: 当前实现返回属性名字符串，实际应该返回 ReflectionProperty 对象
 func (m *ReflectionClassGetPropertyMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
 	propertyNameValue, _ := ctx.GetIndexValue(0)
 	if propertyNameValue == nil {
@@ -64,6 +63,5 @@ func (m *ReflectionClassGetPropertyMethod) Call(ctx data.Context) (data.GetValue
 		return nil, data.NewErrorThrow(nil, fmt.Errorf("Property %s does not exist", propertyName))
 	}
 
-	// 返回属性名（简化实现，实际应该返回 ReflectionProperty 对象）
-	return data.NewStringValue(prop.GetName()), nil
+	return newReflectionProperty(ctx, classStmt.GetName(), prop.GetName()), nil
 }
@@ -47,7 +47,7 @@ func (m *ReflectionClassNewInstanceArgsMethod) GetReturnType() data.Types {
 // Call 执行 newInstanceArgs 方法
 // 创建被反射类的新实例，并使用数组中的参数传递给构造函数
 // 如果类不存在或参数数量超出限制，抛出异常
-func (m *ReflectionClassNewInstanceArgsMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+func (m *ReflectionClassNewInstanceArgsMethod) Call(ctx data.Context) (_ data.GetValue, acl data.Control) {
 	className, classStmt := getReflectionClassInfo(ctx)
 	if classStmt == nil {
 		return nil, data.NewErrorThrow(nil, fmt.Errorf("Class %s does not exist", className))
@@ -78,6 +78,13 @@ func (m *ReflectionClassNewInstanceArgsMethod) Call(ctx data.Context) (data.GetV
 	}
 
 	if object, ok := object.(*data.ClassValue); ok {
+		// 与 new 相同：构造函数执行前登记，抛出异常时不析构
+		data.TrackObject(object)
+		defer func() {
+			if acl != nil {
+				data.ForgetObject(object)
+			}
+		}()
 		if method := object.Class.GetConstruct(); method != nil {
 			varies := method.GetVariables()
 			fnCtx := object.CreateContext(varies)
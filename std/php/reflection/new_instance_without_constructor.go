@@ -49,5 +49,6 @@ func (m *ReflectionClassNewInstanceWithoutConstructorMethod) Call(ctx data.Conte
 
 	// 创建实例但不调用构造函数
 	object := data.NewClassValue(classStmt, ctx.CreateBaseContext())
+	data.TrackObject(object)
 	return object, nil
 }
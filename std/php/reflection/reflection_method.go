@@ -137,6 +137,10 @@ func getReflectionMethodInfo(ctx data.Context) (string, string, data.Method) {
 						if exists {
 							return className, methodName, method
 						}
+						// 属性钩子（$name::get、$name::set）
+						if hook := propertyHookMethod(stmt, methodName); hook != nil {
+							return className, methodName, hook
+						}
 
 						// 如果是构造函数，检查当前类的构造函数
 						if methodName == token.ConstructName && stmt.GetConstruct() != nil {
@@ -1,6 +1,9 @@
 package reflection
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
 	"github.com/php-any/origami/token"
@@ -28,6 +31,22 @@ func (c *ReflectionPropertyClass) GetMethod(name string) (data.Method, bool) {
 		return &ReflectionPropertyGetValueMethod{}, true
 	case "setValue":
 		return &ReflectionPropertySetValueMethod{}, true
+	case "getName":
+		return &ReflectionPropertyGetNameMethod{}, true
+	case "hasHooks":
+		return &ReflectionPropertyHasHooksMethod{}, true
+	case "getHooks":
+		return &ReflectionPropertyGetHooksMethod{}, true
+	case "hasHook":
+		return &ReflectionPropertyHasHookMethod{}, true
+	case "getHook":
+		return &ReflectionPropertyGetHookMethod{}, true
+	case "isVirtual":
+		return &ReflectionPropertyIsVirtualMethod{}, true
+	case "isPrivateSet":
+		return &ReflectionPropertyIsPrivateSetMethod{}, true
+	case "isProtectedSet":
+		return &ReflectionPropertyIsProtectedSetMethod{}, true
 	}
 	return nil, false
 }
@@ -37,6 +56,14 @@ func (c *ReflectionPropertyClass) GetMethods() []data.Method {
 		&ReflectionPropertySetAccessibleMethod{},
 		&ReflectionPropertyGetValueMethod{},
 		&ReflectionPropertySetValueMethod{},
+		&ReflectionPropertyGetNameMethod{},
+		&ReflectionPropertyHasHooksMethod{},
+		&ReflectionPropertyGetHooksMethod{},
+		&ReflectionPropertyHasHookMethod{},
+		&ReflectionPropertyGetHookMethod{},
+		&ReflectionPropertyIsVirtualMethod{},
+		&ReflectionPropertyIsPrivateSetMethod{},
+		&ReflectionPropertyIsProtectedSetMethod{},
 	}
 }
 func (c *ReflectionPropertyClass) GetConstruct() data.Method {
@@ -62,6 +89,33 @@ func (m *ReflectionPropertyConstructMethod) GetVariables() []data.Variable {
 	}
 }
 func (m *ReflectionPropertyConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	classValue, _ := ctx.GetIndexValue(0)
+	propertyValue, _ := ctx.GetIndexValue(1)
+	if classValue == nil || propertyValue == nil {
+		return nil, data.NewErrorThrow(nil, errors.New("ReflectionProperty::__construct() expects 2 arguments"))
+	}
+	var className string
+	switch v := classValue.(type) {
+	case *data.ClassValue:
+		className = v.Class.GetName()
+	case *data.ThisValue:
+		className = v.Class.GetName()
+	default:
+		className = classValue.AsString()
+	}
+	name := propertyValue.AsString()
+	vm := ctx.GetVM()
+	stmt, acl := vm.GetOrLoadClass(className)
+	if acl != nil {
+		return nil, acl
+	}
+	if _, ok := stmt.GetProperty(name); !ok {
+		return nil, data.NewErrorThrow(nil, fmt.Errorf("Property %s::$%s does not exist", className, name))
+	}
+	if objCtx, ok := ctx.(*data.ClassMethodContext); ok {
+		objCtx.ObjectValue.SetProperty("_className", data.NewStringValue(stmt.GetName()))
+		objCtx.ObjectValue.SetProperty("_propertyName", data.NewStringValue(name))
+	}
 	return nil, nil
 }
 
@@ -0,0 +1,232 @@
+package reflection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// newReflectionProperty 创建 ReflectionProperty 实例
+func newReflectionProperty(ctx data.Context, className, propertyName string) *data.ClassValue {
+	value := data.NewClassValue(&ReflectionPropertyClass{}, ctx.CreateBaseContext())
+	value.ObjectValue.SetProperty("_className", data.NewStringValue(className))
+	value.ObjectValue.SetProperty("_propertyName", data.NewStringValue(propertyName))
+	return value
+}
+
+// getReflectionPropertyInfo 从 ReflectionProperty 实例中取出类名和属性声明
+func getReflectionPropertyInfo(ctx data.Context) (string, *node.ClassProperty) {
+	objCtx, ok := ctx.(*data.ClassMethodContext)
+	if !ok {
+		return "", nil
+	}
+	classNameVal, _ := objCtx.ObjectValue.GetProperty("_className")
+	propertyNameVal, _ := objCtx.ObjectValue.GetProperty("_propertyName")
+	className, ok1 := classNameVal.(*data.StringValue)
+	propertyName, ok2 := propertyNameVal.(*data.StringValue)
+	if !ok1 || !ok2 {
+		return "", nil
+	}
+	stmt, acl := ctx.GetVM().GetOrLoadClass(className.Value)
+	if acl != nil || stmt == nil {
+		return className.Value, nil
+	}
+	prop, _ := stmt.GetProperty(propertyName.Value)
+	cp, _ := prop.(*node.ClassProperty)
+	return className.Value, cp
+}
+
+// propertyHookMethod 按钩子方法名（$name::get、$name::set）查找属性钩子，供 ReflectionMethod 使用
+func propertyHookMethod(stmt data.ClassStmt, methodName string) data.Method {
+	name, kind, ok := strings.Cut(strings.TrimPrefix(methodName, "$"), "::")
+	if !ok {
+		return nil
+	}
+	prop, _ := stmt.GetProperty(name)
+	cp, ok := prop.(*node.ClassProperty)
+	if !ok {
+		return nil
+	}
+	return hookOf(cp, kind)
+}
+
+func hookOf(cp *node.ClassProperty, kind string) data.Method {
+	switch kind {
+	case "get":
+		return cp.GetHook
+	case "set":
+		return cp.SetHook
+	}
+	return nil
+}
+
+// hookTypeName 钩子类型参数：字符串 'get'/'set' 或 PropertyHookType 枚举
+func hookTypeName(v data.Value) string {
+	if cv, ok := v.(*data.ClassValue); ok {
+		if backing, acl := cv.ObjectValue.GetProperty("value"); acl == nil && backing != nil {
+			return strings.ToLower(backing.AsString())
+		}
+	}
+	return strings.ToLower(v.AsString())
+}
+
+// newHookReflectionMethod 为属性钩子创建 ReflectionMethod 实例
+func newHookReflectionMethod(ctx data.Context, className string, hook data.Method) *data.ClassValue {
+	value := data.NewClassValue(&ReflectionMethodClass{}, ctx.CreateBaseContext())
+	value.ObjectValue.SetProperty("_className", data.NewStringValue(className))
+	value.ObjectValue.SetProperty("_methodName", data.NewStringValue(hook.GetName()))
+	return value
+}
+
+// ReflectionPropertyGetNameMethod 实现 ReflectionProperty::getName
+type ReflectionPropertyGetNameMethod struct{}
+
+func (m *ReflectionPropertyGetNameMethod) GetName() string               { return "getName" }
+func (m *ReflectionPropertyGetNameMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *ReflectionPropertyGetNameMethod) GetIsStatic() bool             { return false }
+func (m *ReflectionPropertyGetNameMethod) GetReturnType() data.Types     { return data.String{} }
+func (m *ReflectionPropertyGetNameMethod) GetParams() []data.GetValue    { return nil }
+func (m *ReflectionPropertyGetNameMethod) GetVariables() []data.Variable { return nil }
+func (m *ReflectionPropertyGetNameMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	if objCtx, ok := ctx.(*data.ClassMethodContext); ok {
+		if name, _ := objCtx.ObjectValue.GetProperty("_propertyName"); name != nil {
+			return name, nil
+		}
+	}
+	return data.NewStringValue(""), nil
+}
+
+// ReflectionPropertyHasHooksMethod 实现 ReflectionProperty::hasHooks
+type ReflectionPropertyHasHooksMethod struct{}
+
+func (m *ReflectionPropertyHasHooksMethod) GetName() string               { return "hasHooks" }
+func (m *ReflectionPropertyHasHooksMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *ReflectionPropertyHasHooksMethod) GetIsStatic() bool             { return false }
+func (m *ReflectionPropertyHasHooksMethod) GetReturnType() data.Types     { return data.Bool{} }
+func (m *ReflectionPropertyHasHooksMethod) GetParams() []data.GetValue    { return nil }
+func (m *ReflectionPropertyHasHooksMethod) GetVariables() []data.Variable { return nil }
+func (m *ReflectionPropertyHasHooksMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	_, cp := getReflectionPropertyInfo(ctx)
+	return data.NewBoolValue(cp != nil && cp.HasHooks()), nil
+}
+
+// ReflectionPropertyGetHooksMethod 实现 ReflectionProperty::getHooks，返回 ['get' => ReflectionMethod, 'set' => ReflectionMethod]
+type ReflectionPropertyGetHooksMethod struct{}
+
+func (m *ReflectionPropertyGetHooksMethod) GetName() string               { return "getHooks" }
+func (m *ReflectionPropertyGetHooksMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *ReflectionPropertyGetHooksMethod) GetIsStatic() bool             { return false }
+func (m *ReflectionPropertyGetHooksMethod) GetReturnType() data.Types     { return data.Arrays{} }
+func (m *ReflectionPropertyGetHooksMethod) GetParams() []data.GetValue    { return nil }
+func (m *ReflectionPropertyGetHooksMethod) GetVariables() []data.Variable { return nil }
+func (m *ReflectionPropertyGetHooksMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	className, cp := getReflectionPropertyInfo(ctx)
+	hooks := data.NewObjectValue()
+	if cp != nil {
+		for _, kind := range []string{"get", "set"} {
+			if hook := hookOf(cp, kind); hook != nil {
+				hooks.SetProperty(kind, newHookReflectionMethod(ctx, className, hook))
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// ReflectionPropertyHasHookMethod 实现 ReflectionProperty::hasHook
+type ReflectionPropertyHasHookMethod struct{}
+
+func (m *ReflectionPropertyHasHookMethod) GetName() string            { return "hasHook" }
+func (m *ReflectionPropertyHasHookMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *ReflectionPropertyHasHookMethod) GetIsStatic() bool          { return false }
+func (m *ReflectionPropertyHasHookMethod) GetReturnType() data.Types  { return data.Bool{} }
+func (m *ReflectionPropertyHasHookMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "type", 0, nil, nil)}
+}
+func (m *ReflectionPropertyHasHookMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "type", 0, data.Mixed{})}
+}
+func (m *ReflectionPropertyHasHookMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	_, cp := getReflectionPropertyInfo(ctx)
+	typ, _ := ctx.GetIndexValue(0)
+	if cp == nil || typ == nil {
+		return data.NewBoolValue(false), nil
+	}
+	return data.NewBoolValue(hookOf(cp, hookTypeName(typ)) != nil), nil
+}
+
+// ReflectionPropertyGetHookMethod 实现 ReflectionProperty::getHook，钩子不存在时返回 null
+type ReflectionPropertyGetHookMethod struct{}
+
+func (m *ReflectionPropertyGetHookMethod) GetName() string            { return "getHook" }
+func (m *ReflectionPropertyGetHookMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *ReflectionPropertyGetHookMethod) GetIsStatic() bool          { return false }
+func (m *ReflectionPropertyGetHookMethod) GetReturnType() data.Types  { return data.Mixed{} }
+func (m *ReflectionPropertyGetHookMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "type", 0, nil, nil)}
+}
+func (m *ReflectionPropertyGetHookMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "type", 0, data.Mixed{})}
+}
+func (m *ReflectionPropertyGetHookMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	className, cp := getReflectionPropertyInfo(ctx)
+	typ, _ := ctx.GetIndexValue(0)
+	if cp == nil || typ == nil {
+		return data.NewNullValue(), nil
+	}
+	kind := hookTypeName(typ)
+	if kind != "get" && kind != "set" {
+		return nil, data.NewErrorThrow(nil, fmt.Errorf("ReflectionProperty::getHook(): Argument #1 ($type) must be 'get' or 'set'"))
+	}
+	if hook := hookOf(cp, kind); hook != nil {
+		return newHookReflectionMethod(ctx, className, hook), nil
+	}
+	return data.NewNullValue(), nil
+}
+
+// ReflectionPropertyIsVirtualMethod 实现 ReflectionProperty::isVirtual
+type ReflectionPropertyIsVirtualMethod struct{}
+
+func (m *ReflectionPropertyIsVirtualMethod) GetName() string               { return "isVirtual" }
+func (m *ReflectionPropertyIsVirtualMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *ReflectionPropertyIsVirtualMethod) GetIsStatic() bool             { return false }
+func (m *ReflectionPropertyIsVirtualMethod) GetReturnType() data.Types     { return data.Bool{} }
+func (m *ReflectionPropertyIsVirtualMethod) GetParams() []data.GetValue    { return nil }
+func (m *ReflectionPropertyIsVirtualMethod) GetVariables() []data.Variable { return nil }
+func (m *ReflectionPropertyIsVirtualMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	_, cp := getReflectionPropertyInfo(ctx)
+	return data.NewBoolValue(cp != nil && cp.IsVirtual), nil
+}
+
+// ReflectionPropertyIsPrivateSetMethod 实现 ReflectionProperty::isPrivateSet
+type ReflectionPropertyIsPrivateSetMethod struct{}
+
+func (m *ReflectionPropertyIsPrivateSetMethod) GetName() string { return "isPrivateSet" }
+func (m *ReflectionPropertyIsPrivateSetMethod) GetModifier() data.Modifier {
+	return data.ModifierPublic
+}
+func (m *ReflectionPropertyIsPrivateSetMethod) GetIsStatic() bool             { return false }
+func (m *ReflectionPropertyIsPrivateSetMethod) GetReturnType() data.Types     { return data.Bool{} }
+func (m *ReflectionPropertyIsPrivateSetMethod) GetParams() []data.GetValue    { return nil }
+func (m *ReflectionPropertyIsPrivateSetMethod) GetVariables() []data.Variable { return nil }
+func (m *ReflectionPropertyIsPrivateSetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	_, cp := getReflectionPropertyInfo(ctx)
+	return data.NewBoolValue(cp != nil && cp.WriteModifier == data.ModifierPrivate), nil
+}
+
+// ReflectionPropertyIsProtectedSetMethod 实现 ReflectionProperty::isProtectedSet
+type ReflectionPropertyIsProtectedSetMethod struct{}
+
+func (m *ReflectionPropertyIsProtectedSetMethod) GetName() string { return "isProtectedSet" }
+func (m *ReflectionPropertyIsProtectedSetMethod) GetModifier() data.Modifier {
+	return data.ModifierPublic
+}
+func (m *ReflectionPropertyIsProtectedSetMethod) GetIsStatic() bool             { return false }
+func (m *ReflectionPropertyIsProtectedSetMethod) GetReturnType() data.Types     { return data.Bool{} }
+func (m *ReflectionPropertyIsProtectedSetMethod) GetParams() []data.GetValue    { return nil }
+func (m *ReflectionPropertyIsProtectedSetMethod) GetVariables() []data.Variable { return nil }
+func (m *ReflectionPropertyIsProtectedSetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	_, cp := getReflectionPropertyInfo(ctx)
+	return data.NewBoolValue(cp != nil && cp.WriteModifier == data.ModifierProtected), nil
+}
@@ -46,7 +46,7 @@ func (f *RtrimFunction) GetName() string {
 
 func (f *RtrimFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 		node.NewParameter(nil, "charlist", 1, node.NewNullLiteral(nil), nil),
 	}
 }
@@ -14,6 +14,7 @@ import (
 // 当前为最小实现，对标上面的 unserialize 子集，仅支持：
 // - string  -> s:len:"...";   （len 为字节长度）
 // - int     -> i:number;
+// - float   -> d:number;   （按 serialize_precision）
 // - bool    -> b:0; / b:1;
 // - null    -> N;
 // 其它类型暂不支持，返回 false。
@@ -40,7 +41,7 @@ func makeSerializedString(content string) string {
 
 // phpSerializeValue 将 Origami 内部的 data.Value 按 PHP serialize 语义编码为字符串。
 // 目前支持：
-// - null / bool / int / string
+// - null / bool / int / float / string
 // - 数组：按 PHP 的 a:len:{key;value;...} 语法编码（数值下标使用 i:n;，关联键使用 s:len:"key";）
 // - 对象：ClassValue 按 PHP 的 O:...:...:{...} 格式序列化公共属性
 // 其它复杂类型返回 false，交由上层处理。
@@ -55,6 +56,8 @@ func phpSerializeValue(v data.Value, serializer *jsonser.JsonSerializer) (string
 		return "b:0;", true
 	case *data.IntValue:
 		return fmt.Sprintf("i:%d;", val.Value), true
+	case *data.FloatValue:
+		return "d:" + data.SerializeFloat(val.Value, false, 'E') + ";", true
 	case *data.StringValue:
 		return makeSerializedString(val.Value), true
 	case *data.ArrayValue:
@@ -19,8 +19,7 @@ func (f *SleepFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 		return nil, ctl
 	}
 	i, _ := v.(data.AsInt).AsInt()
-	time.Sleep(time.Duration(i) * time.Second)
-	return nil, nil
+	return nil, sleepContext(ctx, time.Duration(i)*time.Second)
 }
 func (f *SleepFunction) GetName() string {
 	return "sleep"
@@ -37,3 +36,51 @@ func (f *SleepFunction) GetVariables() []data.Variable {
 		node.NewVariable(nil, "seconds", 0, data.Int{}),
 	}
 }
+
+// sleepContext 等待 d，执行流被取消（TaskGroup、Future::cancel）时提前结束并抛出 CancelledError
+func sleepContext(ctx data.Context, d time.Duration) data.Control {
+	goCtx := ctx.GoContext()
+	if goCtx.Done() == nil {
+		time.Sleep(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-goCtx.Done():
+		return data.NewCancelledThrow(goCtx)
+	}
+}
+
+func NewUsleepFunction() data.FuncStmt {
+	return &UsleepFunction{}
+}
+
+// UsleepFunction usleep(int $microseconds): void
+type UsleepFunction struct{}
+
+func (f *UsleepFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, ctl := ctx.GetVariableValue(node.NewVariable(nil, "microseconds", 0, data.Int{}))
+	if ctl != nil {
+		return nil, ctl
+	}
+	i, _ := v.(data.AsInt).AsInt()
+	return nil, sleepContext(ctx, time.Duration(i)*time.Microsecond)
+}
+func (f *UsleepFunction) GetName() string {
+	return "usleep"
+}
+
+func (f *UsleepFunction) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "microseconds", 0, nil, nil),
+	}
+}
+
+func (f *UsleepFunction) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "microseconds", 0, data.Int{}),
+	}
+}
@@ -220,11 +220,10 @@ func aoOffsetSet(arr *data.ArrayValue, offset, value data.Value) {
 	}
 	if sv, ok := offset.(data.AsString); ok {
 		key := sv.AsString()
-		for _, z := range arr.List {
-			if z != nil && z.Name == key {
-				z.Value = value
-				return
-			}
+		arr.Separate()
+		if z, _ := arr.FindSlot(key); z != nil {
+			z.Value = value
+			return
 		}
 		arr.List = append(arr.List, data.NewNamedZVal(key, value))
 	}
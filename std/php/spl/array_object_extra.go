@@ -69,24 +69,28 @@ func aoCompareZValKeys(a, b *data.ZVal) int {
 }
 
 func aoSortByValue(arr *data.ArrayValue) {
+	arr.Separate()
 	sort.SliceStable(arr.List, func(i, j int) bool {
 		return data.Compare(arr.List[i].Value, arr.List[j].Value) < 0
 	})
 }
 
 func aoSortByKey(arr *data.ArrayValue) {
+	arr.Separate()
 	sort.SliceStable(arr.List, func(i, j int) bool {
 		return aoCompareZValKeys(arr.List[i], arr.List[j]) < 0
 	})
 }
 
 func aoSortByValueNatural(arr *data.ArrayValue) {
+	arr.Separate()
 	sort.SliceStable(arr.List, func(i, j int) bool {
 		return strings.Compare(arr.List[i].Value.AsString(), arr.List[j].Value.AsString()) < 0
 	})
 }
 
 func aoSortByValueNaturalCase(arr *data.ArrayValue) {
+	arr.Separate()
 	sort.SliceStable(arr.List, func(i, j int) bool {
 		return strings.Compare(strings.ToLower(arr.List[i].Value.AsString()), strings.ToLower(arr.List[j].Value.AsString())) < 0
 	})
@@ -101,6 +105,7 @@ func aoUserSort(arr *data.ArrayValue, ctx data.Context, callback data.GetValue,
 	case *data.FuncValue:
 		callbackVars = cb.Value.GetVariables()
 	}
+	arr.Separate()
 	sort.SliceStable(arr.List, func(i, j int) bool {
 		fnCtx := ctx.CreateContext(callbackVars)
 		if byKey {
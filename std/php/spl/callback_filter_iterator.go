@@ -67,13 +67,7 @@ func (c *CallbackFilterIteratorClass) GetMethods() []data.Method {
 }
 
 func splInvokeCallable(ctx data.Context, callback data.GetValue, args []data.Value) (data.GetValue, data.Control) {
-	fn := core.NewCallUserFuncFunction()
-	callCtx := ctx.CreateContext(fn.GetVariables())
-	callCtx.SetIndexZVal(0, data.NewZVal(splAsValue(callback)))
-	for i, arg := range args {
-		callCtx.SetIndexZVal(i+1, data.NewZVal(arg))
-	}
-	return fn.Call(callCtx)
+	return core.CallUserFunc(ctx, splAsValue(callback), args)
 }
 
 func splValueToBool(v data.GetValue) bool {
@@ -128,6 +128,7 @@ func splListRemoveAt(arr *data.ArrayValue, index int) {
 	if index < 0 || index >= len(arr.List) {
 		return
 	}
+	arr.Separate()
 	arr.List = append(arr.List[:index], arr.List[index+1:]...)
 }
 
@@ -579,6 +580,7 @@ func (m *SplDLLOffsetSetMethod) Call(ctx data.Context) (data.GetValue, data.Cont
 	if !ok || i < 0 || i >= len(arr.List) {
 		return nil, nil
 	}
+	arr.Separate()
 	arr.List[i].Value = val
 	return nil, nil
 }
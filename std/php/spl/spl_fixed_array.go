@@ -375,7 +375,9 @@ func (m *SplFixedArrayOffsetSetMethod) Call(ctx data.Context) (data.GetValue, da
 	if !ok || i < 0 || i >= sfaGetSize(cv) {
 		return nil, nil
 	}
-	sfaGetStorage(cv).List[i].Value = val
+	storage := sfaGetStorage(cv)
+	storage.Separate()
+	storage.List[i].Value = val
 	return nil, nil
 }
 
@@ -401,6 +403,8 @@ func (m *SplFixedArrayOffsetUnsetMethod) Call(ctx data.Context) (data.GetValue,
 	if !ok || i < 0 || i >= sfaGetSize(cv) {
 		return nil, nil
 	}
-	sfaGetStorage(cv).List[i].Value = data.NewNullValue()
+	storage := sfaGetStorage(cv)
+	storage.Separate()
+	storage.List[i].Value = data.NewNullValue()
 	return nil, nil
 }
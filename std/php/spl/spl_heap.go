@@ -105,6 +105,7 @@ func splHeapBubbleDown(arr *data.ArrayValue, index int, cmp heapCompareFunc) {
 }
 
 func splHeapInsert(arr *data.ArrayValue, value data.Value, cmp heapCompareFunc) {
+	arr.Separate()
 	arr.List = append(arr.List, data.NewZVal(value))
 	splHeapBubbleUp(arr, len(arr.List)-1, cmp)
 }
@@ -113,6 +114,7 @@ func splHeapExtractTop(arr *data.ArrayValue, cmp heapCompareFunc) data.Value {
 	if len(arr.List) == 0 {
 		return data.NewNullValue()
 	}
+	arr.Separate()
 	top := arr.List[0].Value
 	last := len(arr.List) - 1
 	arr.List[0] = arr.List[last]
@@ -20,8 +20,12 @@ func (f *SplObjectHashFunction) Call(ctx data.Context) (data.GetValue, data.Cont
 		return data.NewStringValue(""), nil
 	}
 
-	// Generate a hash based on the object's pointer address
-	hash := fmt.Sprintf("%p", objVal)
+	// 类实例按对象标识生成（与 PHP 一样为 32 位十六进制），其它对象按指针地址
+	instance := objectInstance(objVal)
+	if o, ok := instance.(*data.ObjectValue); ok {
+		return data.NewStringValue(fmt.Sprintf("%032x", o.ID())), nil
+	}
+	hash := fmt.Sprintf("%p", instance)
 	return data.NewStringValue(hash), nil
 }
 
@@ -9,7 +9,7 @@ import (
 )
 
 // SplObjectIdFunction 实现 spl_object_id 函数。
-// 返回对象实例的唯一整数标识，生命周期内保持不变（基于 Go 指针地址）。
+// 返回对象实例的唯一整数标识，生命周期内保持不变（类实例见 ObjectValue.ID，其它对象基于 Go 指针地址）。
 type SplObjectIdFunction struct{}
 
 func NewSplObjectIdFunction() data.FuncStmt {
@@ -27,7 +27,11 @@ func (f *SplObjectIdFunction) Call(ctx data.Context) (data.GetValue, data.Contro
 		return nil, data.NewErrorThrowByName(nil, fmt.Errorf("spl_object_id(): Argument #1 ($object) must be of type object"), "TypeError")
 	}
 
-	rv := reflect.ValueOf(objVal)
+	instance := objectInstance(objVal)
+	if o, ok := instance.(*data.ObjectValue); ok {
+		return data.NewIntValue(o.ID()), nil
+	}
+	rv := reflect.ValueOf(instance)
 	for rv.Kind() == reflect.Interface && !rv.IsNil() {
 		rv = rv.Elem()
 	}
@@ -38,6 +42,21 @@ func (f *SplObjectIdFunction) Call(ctx data.Context) (data.GetValue, data.Contro
 	return nil, data.NewErrorThrowByName(nil, fmt.Errorf("spl_object_id(): Argument #1 ($object) must be of type object"), "TypeError")
 }
 
+// objectInstance 类实例按属性存储所在的 ObjectValue 标识：同一实例的不同包装（$this、WeakReference::get() 等）结果相同
+func objectInstance(v data.GetValue) any {
+	switch o := v.(type) {
+	case *data.ClassValue:
+		if o.ObjectValue != nil {
+			return o.ObjectValue
+		}
+	case *data.ThisValue:
+		if o.ClassValue != nil && o.ObjectValue != nil {
+			return o.ObjectValue
+		}
+	}
+	return v
+}
+
 func (f *SplObjectIdFunction) GetName() string {
 	return "spl_object_id"
 }
@@ -49,8 +49,8 @@ func (f *StrRepeatFunction) GetName() string {
 
 func (f *StrRepeatFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
-		node.NewParameter(nil, "times", 1, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
+		node.NewParameter(nil, "times", 1, nil, data.Int{}),
 	}
 }
 
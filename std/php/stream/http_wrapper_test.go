@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -34,7 +35,7 @@ func TestHTTPGetContentsPost(t *testing.T) {
 		},
 	}, nil)
 
-	body, ok := HTTPGetContents(server.URL, sc)
+	body, ok := HTTPGetContents(context.Background(), server.URL, sc)
 	if !ok {
 		t.Fatal("HTTPGetContents returned false")
 	}
@@ -50,7 +51,7 @@ func TestHTTPGetContentsStatusError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	body, ok := HTTPGetContents(server.URL, nil)
+	body, ok := HTTPGetContents(context.Background(), server.URL, nil)
 	if ok {
 		t.Fatalf("expected failure, got body %q", body)
 	}
@@ -67,7 +68,7 @@ func TestHTTPGetContentsIgnoreErrors(t *testing.T) {
 		"http": {"ignore_errors": "1"},
 	}, nil)
 
-	body, ok := HTTPGetContents(server.URL, sc)
+	body, ok := HTTPGetContents(context.Background(), server.URL, sc)
 	if !ok {
 		t.Fatal("HTTPGetContents returned false")
 	}
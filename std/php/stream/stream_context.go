@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"strconv"
@@ -190,8 +191,8 @@ func parseHeaderBlock(block string) http.Header {
 	return headers
 }
 
-// HTTPGetContents 通过 http wrapper 读取远程 URL 内容。
-func HTTPGetContents(url string, sc *StreamContext) (string, bool) {
+// HTTPGetContents 通过 http wrapper 读取远程 URL 内容，ctx 被取消时请求随之中止。
+func HTTPGetContents(ctx context.Context, url string, sc *StreamContext) (string, bool) {
 	opts := sc.WrapperOptions("http")
 
 	method := "GET"
@@ -207,7 +208,7 @@ func HTTPGetContents(url string, sc *StreamContext) (string, bool) {
 		}
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return "", false
 	}
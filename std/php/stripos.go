@@ -69,8 +69,8 @@ func (f *StriposFunction) GetName() string {
 
 func (f *StriposFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "haystack", 0, nil, nil),
-		node.NewParameter(nil, "needle", 1, nil, nil),
+		node.NewParameter(nil, "haystack", 0, nil, data.String{}),
+		node.NewParameter(nil, "needle", 1, nil, data.String{}),
 		node.NewParameter(nil, "offset", 2, node.NewNullLiteral(nil), nil),
 	}
 }
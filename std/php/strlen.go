@@ -39,7 +39,7 @@ func (f *StrlenFunction) GetName() string {
 
 func (f *StrlenFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
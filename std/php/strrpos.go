@@ -103,8 +103,8 @@ func (f *StrrposFunction) GetName() string {
 
 func (f *StrrposFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "haystack", 0, nil, nil),
-		node.NewParameter(nil, "needle", 1, nil, nil),
+		node.NewParameter(nil, "haystack", 0, nil, data.String{}),
+		node.NewParameter(nil, "needle", 1, nil, data.String{}),
 		node.NewParameter(nil, "offset", 2, node.NewIntLiteral(nil, "0"), nil),
 	}
 }
@@ -179,8 +179,8 @@ func (f *StrriposFunction) GetName() string {
 
 func (f *StrriposFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "haystack", 0, nil, nil),
-		node.NewParameter(nil, "needle", 1, nil, nil),
+		node.NewParameter(nil, "haystack", 0, nil, data.String{}),
+		node.NewParameter(nil, "needle", 1, nil, data.String{}),
 		node.NewParameter(nil, "offset", 2, node.NewIntLiteral(nil, "0"), nil),
 	}
 }
@@ -35,7 +35,7 @@ func (f *StrtolowerFunction) GetName() string {
 
 func (f *StrtolowerFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
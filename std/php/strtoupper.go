@@ -35,7 +35,7 @@ func (f *StrtoupperFunction) GetName() string {
 
 func (f *StrtoupperFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
@@ -90,8 +90,8 @@ func (f *SubstrFunction) GetName() string {
 
 func (f *SubstrFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
-		node.NewParameter(nil, "start", 1, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
+		node.NewParameter(nil, "start", 1, nil, data.Int{}),
 		node.NewParameter(nil, "length", 2, node.NewNullLiteral(nil), nil),
 	}
 }
@@ -42,7 +42,7 @@ func (f *UcfirstFunction) GetName() string {
 
 func (f *UcfirstFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 	}
 }
 
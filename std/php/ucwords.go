@@ -59,7 +59,7 @@ func (f *UcwordsFunction) GetName() string {
 
 func (f *UcwordsFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
-		node.NewParameter(nil, "string", 0, nil, nil),
+		node.NewParameter(nil, "string", 0, nil, data.String{}),
 		node.NewParameter(nil, "delimiters", 1, node.NewNullLiteral(nil), nil),
 	}
 }
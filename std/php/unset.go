@@ -55,6 +55,7 @@ func (f *UnsetFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 					i, err := iv.AsInt()
 					if err == nil && i >= 0 && i < len(arr.List) {
 						// 删除元素（设置为 null）
+						arr.Separate()
 						arr.List[i] = data.NewZVal(data.NewNullValue())
 					}
 				}
@@ -131,7 +131,7 @@ func dumpClassValue(arg *data.ClassValue, indent string, depth int) {
 	className := arg.Class.GetName()
 	n := 0
 	for _, p := range propList {
-		if p.GetIsStatic() {
+		if p.GetIsStatic() || data.IsVirtualProperty(p) {
 			continue
 		}
 		n++
@@ -139,7 +139,7 @@ func dumpClassValue(arg *data.ClassValue, indent string, depth int) {
 	fmt.Printf("%sobject(%s)#%d (%d) {\n", indent, className, varDumpObjectHandle(arg), n)
 	inner := indent + "  "
 	for _, p := range propList {
-		if p.GetIsStatic() {
+		if p.GetIsStatic() || data.IsVirtualProperty(p) {
 			continue
 		}
 		val := props[p.GetName()]
@@ -185,7 +185,7 @@ func varDumpValue(v data.Value, indent string, depth int) {
 	case *data.IntValue:
 		fmt.Printf("%sint(%d)\n", indent, arg.Value)
 	case *data.FloatValue:
-		fmt.Printf("%sfloat(%v)\n", indent, arg.Value)
+		fmt.Printf("%sfloat(%s)\n", indent, data.SerializeFloat(arg.Value, false, 'E'))
 	case *data.BoolValue:
 		if arg.Value {
 			fmt.Printf("%sbool(true)\n", indent)
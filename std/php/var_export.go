@@ -52,10 +52,7 @@ func varExportValue(v data.Value) string {
 		}
 		return "0"
 	case *data.FloatValue:
-		if f, err := val.AsFloat(); err == nil {
-			return fmt.Sprintf("%.6f", f)
-		}
-		return "0.0"
+		return data.SerializeFloat(val.Value, true, 'E')
 	case *data.StringValue:
 		return fmt.Sprintf("'%s'", strings.ReplaceAll(val.Value, "'", "\\'"))
 	case *data.ArrayValue:
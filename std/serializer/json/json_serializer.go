@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/php-any/origami/data"
 	"github.com/php-any/origami/node"
@@ -204,7 +205,11 @@ func (j *JsonSerializer) UnmarshalBool(data []byte, v *data.BoolValue) error {
 
 // Float
 func (j *JsonSerializer) MarshalFloat(v *data.FloatValue) ([]byte, error) {
-	return json.Marshal(v.Value)
+	if math.IsInf(v.Value, 0) || math.IsNaN(v.Value) {
+		return json.Marshal(v.Value)
+	}
+	// 与 PHP 一致按 serialize_precision 输出
+	return []byte(data.SerializeFloat(v.Value, false, 'e')), nil
 }
 
 func (j *JsonSerializer) UnmarshalFloat(data []byte, v *data.FloatValue) error {
@@ -1,6 +1,8 @@
 package signal
 
 import (
+	"os"
+	"reflect"
 	"syscall"
 
 	"github.com/php-any/origami/data"
@@ -62,6 +64,14 @@ func (c *SignalChannelClass) GetConstruct() data.Method {
 
 func (c *SignalChannelClass) AddAnnotations(a *data.ClassValue) {}
 
+// SelectRecv 作为 Channel::select 的接收分支，收到的值为信号编号
+func (c *SignalChannelClass) SelectRecv() (reflect.Value, func(reflect.Value) data.Value) {
+	return reflect.ValueOf(c.channel.channel), func(v reflect.Value) data.Value {
+		sig, _ := v.Interface().(os.Signal)
+		return signalValue(sig)
+	}
+}
+
 type SignalChannelConstructMethod struct {
 	source *SignalChannelClass
 }
@@ -101,10 +111,15 @@ func (m *SignalChannelReceiveMethod) Call(ctx data.Context) (data.GetValue, data
 	if !ok {
 		return data.NewNullValue(), nil
 	}
+	return signalValue(sig), nil
+}
+
+// signalValue 信号转换为信号编号
+func signalValue(sig os.Signal) data.Value {
 	if s, ok := sig.(syscall.Signal); ok {
-		return data.NewIntValue(int(s)), nil
+		return data.NewIntValue(int(s))
 	}
-	return data.NewIntValue(0), nil
+	return data.NewIntValue(0)
 }
 
 func (m *SignalChannelReceiveMethod) GetName() string            { return "receive" }
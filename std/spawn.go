@@ -12,7 +12,8 @@ func NewSpawnFunction() data.FuncStmt {
 	return &SpawnFunction{}
 }
 
-// SpawnFunction 封装 Go 的 go 关键字，异步执行闭包。
+// SpawnFunction 封装 Go 的 go 关键字，异步执行闭包：spawn(Closure $closure, mixed ...$args): Future。
+// 其余参数按位置传给闭包；返回的 Future 在闭包返回或抛出异常时完成，异常在 await 时重新抛出。
 type SpawnFunction struct{}
 
 func (f *SpawnFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
@@ -20,36 +21,33 @@ func (f *SpawnFunction) Call(ctx data.Context) (data.GetValue, data.Control) {
 	if !has {
 		return nil, utils.NewThrow(errors.New("spawn 缺少闭包参数"))
 	}
-	if _, has := ctx.GetIndexValue(1); has {
-		return nil, utils.NewThrow(errors.New("spawn 只接受一个闭包参数"))
-	}
 
-	var call func(data.Context) (data.GetValue, data.Control)
 	switch c := cb.(type) {
 	case *data.BoundFuncValue:
 		if !isClosure(c.Value) {
 			return nil, utils.NewThrow(errors.New("spawn 只接受闭包"))
 		}
-		call = c.Call
 	case *data.FuncValue:
 		if !isClosure(c.Value) {
 			return nil, utils.NewThrow(errors.New("spawn 只接受闭包"))
 		}
-		call = c.Call
 	default:
 		return nil, utils.NewThrow(errors.New("spawn 只接受闭包"))
 	}
 
-	vm := ctx.GetVM()
-	go func() {
-		callCtx := ctx.CreateContext(nil)
-		_, acl := call(callCtx)
-		if acl != nil {
-			vm.ThrowControl(acl)
+	var args []data.Value
+	if v, ok := ctx.GetIndexValue(1); ok {
+		if arr, ok := v.(*data.ArrayValue); ok {
+			args = arr.ToValueList()
 		}
-	}()
+	}
+
+	// 协程使用独立的执行流：调用深度、输出缓冲、static 局部变量互不影响，异常处理回调继承自创建方，
+	// Context 派生自创建方（创建方被取消时一并取消）。未捕获的异常保存在 Future 中，由 await 的调用方处理
+	future := newFuture()
+	runTask(future, ctx.GoContext(), ctx, callClosure(cb, args))
 
-	return nil, nil
+	return newFutureValue(future, ctx), nil
 }
 
 func isClosure(v data.FuncStmt) bool {
@@ -62,11 +60,13 @@ func (f *SpawnFunction) GetName() string { return "spawn" }
 func (f *SpawnFunction) GetParams() []data.GetValue {
 	return []data.GetValue{
 		node.NewParameter(nil, "closure", 0, nil, nil),
+		node.NewParameters(nil, "args", 1, nil, nil),
 	}
 }
 
 func (f *SpawnFunction) GetVariables() []data.Variable {
 	return []data.Variable{
 		node.NewVariable(nil, "closure", 0, data.Mixed{}),
+		node.NewVariable(nil, "args", 1, data.Mixed{}),
 	}
 }
@@ -0,0 +1,235 @@
+package sync
+
+import (
+	"sync/atomic"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// AtomicIntClass 封装 atomic.Int64，可在多个协程间安全计数
+type AtomicIntClass struct {
+	node.Node
+	value *atomic.Int64
+}
+
+func NewAtomicIntClass() data.ClassStmt {
+	return &AtomicIntClass{value: &atomic.Int64{}}
+}
+
+func (c *AtomicIntClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&AtomicIntClass{value: &atomic.Int64{}}, ctx.CreateBaseContext()), nil
+}
+
+func (c *AtomicIntClass) GetName() string                               { return "Sync\\AtomicInt" }
+func (c *AtomicIntClass) GetExtend() *string                            { return nil }
+func (c *AtomicIntClass) GetImplements() []string                       { return nil }
+func (c *AtomicIntClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *AtomicIntClass) GetPropertyList() []data.Property              { return nil }
+func (c *AtomicIntClass) GetConstruct() data.Method                     { return &AtomicIntConstructMethod{source: c} }
+
+func (c *AtomicIntClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "get":
+		return &AtomicIntGetMethod{source: c}, true
+	case "set":
+		return &AtomicIntSetMethod{source: c}, true
+	case "add":
+		return &AtomicIntAddMethod{source: c}, true
+	case "increment":
+		return &AtomicIntIncrementMethod{source: c}, true
+	case "decrement":
+		return &AtomicIntDecrementMethod{source: c}, true
+	case "swap":
+		return &AtomicIntSwapMethod{source: c}, true
+	case "compareAndSwap":
+		return &AtomicIntCompareAndSwapMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *AtomicIntClass) GetMethods() []data.Method {
+	return []data.Method{
+		&AtomicIntGetMethod{source: c},
+		&AtomicIntSetMethod{source: c},
+		&AtomicIntAddMethod{source: c},
+		&AtomicIntIncrementMethod{source: c},
+		&AtomicIntDecrementMethod{source: c},
+		&AtomicIntSwapMethod{source: c},
+		&AtomicIntCompareAndSwapMethod{source: c},
+	}
+}
+
+// AtomicIntConstructMethod __construct(int $value = 0)
+type AtomicIntConstructMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, acl := intArg(ctx, 0, 0)
+	if acl != nil {
+		return nil, acl
+	}
+	m.source.value.Store(int64(v))
+	return nil, nil
+}
+
+func (m *AtomicIntConstructMethod) GetName() string            { return "__construct" }
+func (m *AtomicIntConstructMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *AtomicIntConstructMethod) GetIsStatic() bool          { return false }
+func (m *AtomicIntConstructMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "value", 0, data.NewIntValue(0), data.NewBaseType("int"))}
+}
+func (m *AtomicIntConstructMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "value", 0, nil)}
+}
+func (m *AtomicIntConstructMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// AtomicIntGetMethod get(): int
+type AtomicIntGetMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntGetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewIntValue(int(m.source.value.Load())), nil
+}
+
+func (m *AtomicIntGetMethod) GetName() string               { return "get" }
+func (m *AtomicIntGetMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *AtomicIntGetMethod) GetIsStatic() bool             { return false }
+func (m *AtomicIntGetMethod) GetParams() []data.GetValue    { return nil }
+func (m *AtomicIntGetMethod) GetVariables() []data.Variable { return nil }
+func (m *AtomicIntGetMethod) GetReturnType() data.Types     { return data.NewBaseType("int") }
+
+// AtomicIntSetMethod set(int $value): void
+type AtomicIntSetMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntSetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, acl := intArg(ctx, 0, 0)
+	if acl != nil {
+		return nil, acl
+	}
+	m.source.value.Store(int64(v))
+	return nil, nil
+}
+
+func (m *AtomicIntSetMethod) GetName() string            { return "set" }
+func (m *AtomicIntSetMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *AtomicIntSetMethod) GetIsStatic() bool          { return false }
+func (m *AtomicIntSetMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "value", 0, nil, data.NewBaseType("int"))}
+}
+func (m *AtomicIntSetMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "value", 0, nil)}
+}
+func (m *AtomicIntSetMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// AtomicIntAddMethod add(int $delta): int，返回相加后的值
+type AtomicIntAddMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntAddMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	delta, acl := intArg(ctx, 0, 0)
+	if acl != nil {
+		return nil, acl
+	}
+	return data.NewIntValue(int(m.source.value.Add(int64(delta)))), nil
+}
+
+func (m *AtomicIntAddMethod) GetName() string            { return "add" }
+func (m *AtomicIntAddMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *AtomicIntAddMethod) GetIsStatic() bool          { return false }
+func (m *AtomicIntAddMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "delta", 0, nil, data.NewBaseType("int"))}
+}
+func (m *AtomicIntAddMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "delta", 0, nil)}
+}
+func (m *AtomicIntAddMethod) GetReturnType() data.Types { return data.NewBaseType("int") }
+
+// AtomicIntIncrementMethod increment(): int，返回加一后的值
+type AtomicIntIncrementMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntIncrementMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewIntValue(int(m.source.value.Add(1))), nil
+}
+
+func (m *AtomicIntIncrementMethod) GetName() string               { return "increment" }
+func (m *AtomicIntIncrementMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *AtomicIntIncrementMethod) GetIsStatic() bool             { return false }
+func (m *AtomicIntIncrementMethod) GetParams() []data.GetValue    { return nil }
+func (m *AtomicIntIncrementMethod) GetVariables() []data.Variable { return nil }
+func (m *AtomicIntIncrementMethod) GetReturnType() data.Types     { return data.NewBaseType("int") }
+
+// AtomicIntDecrementMethod decrement(): int，返回减一后的值
+type AtomicIntDecrementMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntDecrementMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewIntValue(int(m.source.value.Add(-1))), nil
+}
+
+func (m *AtomicIntDecrementMethod) GetName() string               { return "decrement" }
+func (m *AtomicIntDecrementMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *AtomicIntDecrementMethod) GetIsStatic() bool             { return false }
+func (m *AtomicIntDecrementMethod) GetParams() []data.GetValue    { return nil }
+func (m *AtomicIntDecrementMethod) GetVariables() []data.Variable { return nil }
+func (m *AtomicIntDecrementMethod) GetReturnType() data.Types     { return data.NewBaseType("int") }
+
+// AtomicIntSwapMethod swap(int $value): int，返回旧值
+type AtomicIntSwapMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntSwapMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, acl := intArg(ctx, 0, 0)
+	if acl != nil {
+		return nil, acl
+	}
+	return data.NewIntValue(int(m.source.value.Swap(int64(v)))), nil
+}
+
+func (m *AtomicIntSwapMethod) GetName() string            { return "swap" }
+func (m *AtomicIntSwapMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *AtomicIntSwapMethod) GetIsStatic() bool          { return false }
+func (m *AtomicIntSwapMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "value", 0, nil, data.NewBaseType("int"))}
+}
+func (m *AtomicIntSwapMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "value", 0, nil)}
+}
+func (m *AtomicIntSwapMethod) GetReturnType() data.Types { return data.NewBaseType("int") }
+
+// AtomicIntCompareAndSwapMethod compareAndSwap(int $old, int $new): bool
+type AtomicIntCompareAndSwapMethod struct {
+	source *AtomicIntClass
+}
+
+func (m *AtomicIntCompareAndSwapMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	old, acl := intArg(ctx, 0, 0)
+	if acl != nil {
+		return nil, acl
+	}
+	v, acl := intArg(ctx, 1, 0)
+	if acl != nil {
+		return nil, acl
+	}
+	return data.NewBoolValue(m.source.value.CompareAndSwap(int64(old), int64(v))), nil
+}
+
+func (m *AtomicIntCompareAndSwapMethod) GetName() string            { return "compareAndSwap" }
+func (m *AtomicIntCompareAndSwapMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *AtomicIntCompareAndSwapMethod) GetIsStatic() bool          { return false }
+func (m *AtomicIntCompareAndSwapMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "old", 0, nil, data.NewBaseType("int")), node.NewParameter(nil, "new", 1, nil, data.NewBaseType("int"))}
+}
+func (m *AtomicIntCompareAndSwapMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "old", 0, nil), node.NewVariable(nil, "new", 1, nil)}
+}
+func (m *AtomicIntCompareAndSwapMethod) GetReturnType() data.Types { return data.NewBaseType("bool") }
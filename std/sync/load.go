@@ -0,0 +1,16 @@
+package sync
+
+import (
+	"github.com/php-any/origami/data"
+)
+
+// Load 注册 Sync\ 命名空间下的并发原语
+func Load(vm data.VM) {
+	vm.AddClass(NewWaitGroupClass())
+	vm.AddClass(NewMutexClass())
+	vm.AddClass(NewRWMutexClass())
+	vm.AddClass(NewOnceClass())
+	vm.AddClass(NewSemaphoreClass())
+	vm.AddClass(NewAtomicIntClass())
+	vm.AddClass(NewMapClass())
+}
@@ -0,0 +1,304 @@
+package sync
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// Map 并发安全的字典，封装 sync.Map；键按字符串比较
+type Map struct {
+	m sync.Map
+}
+
+// entries 按键排序的快照，供 keys/toArray/forEach 使用
+func (m *Map) entries() ([]string, []data.Value) {
+	var keys []string
+	values := make(map[string]data.Value)
+	m.m.Range(func(k, v any) bool {
+		keys = append(keys, k.(string))
+		values[k.(string)] = v.(data.Value)
+		return true
+	})
+	sort.Strings(keys)
+	list := make([]data.Value, len(keys))
+	for i, k := range keys {
+		list[i] = values[k]
+	}
+	return keys, list
+}
+
+func mapKey(ctx data.Context) string {
+	v, _ := ctx.GetIndexValue(0)
+	if v == nil {
+		return ""
+	}
+	return v.AsString()
+}
+
+func mapValue(ctx data.Context, index int) data.Value {
+	if v, ok := ctx.GetIndexValue(index); ok && v != nil {
+		return v
+	}
+	return data.NewNullValue()
+}
+
+type MapClass struct {
+	node.Node
+	store *Map
+}
+
+func NewMapClass() data.ClassStmt {
+	return &MapClass{store: &Map{}}
+}
+
+func (c *MapClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&MapClass{store: &Map{}}, ctx.CreateBaseContext()), nil
+}
+
+func (c *MapClass) GetName() string                               { return "Sync\\Map" }
+func (c *MapClass) GetExtend() *string                            { return nil }
+func (c *MapClass) GetImplements() []string                       { return nil }
+func (c *MapClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *MapClass) GetPropertyList() []data.Property              { return nil }
+func (c *MapClass) GetConstruct() data.Method                     { return nil }
+
+func (c *MapClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "get":
+		return &MapGetMethod{source: c}, true
+	case "set":
+		return &MapSetMethod{source: c}, true
+	case "has":
+		return &MapHasMethod{source: c}, true
+	case "delete":
+		return &MapDeleteMethod{source: c}, true
+	case "getOrSet":
+		return &MapGetOrSetMethod{source: c}, true
+	case "count":
+		return &MapCountMethod{source: c}, true
+	case "keys":
+		return &MapKeysMethod{source: c}, true
+	case "toArray":
+		return &MapToArrayMethod{source: c}, true
+	case "forEach":
+		return &MapForEachMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *MapClass) GetMethods() []data.Method {
+	return []data.Method{
+		&MapGetMethod{source: c},
+		&MapSetMethod{source: c},
+		&MapHasMethod{source: c},
+		&MapDeleteMethod{source: c},
+		&MapGetOrSetMethod{source: c},
+		&MapCountMethod{source: c},
+		&MapKeysMethod{source: c},
+		&MapToArrayMethod{source: c},
+		&MapForEachMethod{source: c},
+	}
+}
+
+// MapGetMethod get(string $key, mixed $default = null): mixed
+type MapGetMethod struct {
+	source *MapClass
+}
+
+func (m *MapGetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	if v, ok := m.source.store.m.Load(mapKey(ctx)); ok {
+		return v.(data.Value), nil
+	}
+	return mapValue(ctx, 1), nil
+}
+
+func (m *MapGetMethod) GetName() string            { return "get" }
+func (m *MapGetMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MapGetMethod) GetIsStatic() bool          { return false }
+func (m *MapGetMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "key", 0, nil, nil), node.NewParameter(nil, "default", 1, data.NewNullValue(), nil)}
+}
+func (m *MapGetMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "key", 0, nil), node.NewVariable(nil, "default", 1, nil)}
+}
+func (m *MapGetMethod) GetReturnType() data.Types { return data.Mixed{} }
+
+// MapSetMethod set(string $key, mixed $value): void
+type MapSetMethod struct {
+	source *MapClass
+}
+
+func (m *MapSetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	m.source.store.m.Store(mapKey(ctx), mapValue(ctx, 1))
+	return nil, nil
+}
+
+func (m *MapSetMethod) GetName() string            { return "set" }
+func (m *MapSetMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MapSetMethod) GetIsStatic() bool          { return false }
+func (m *MapSetMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "key", 0, nil, nil), node.NewParameter(nil, "value", 1, nil, nil)}
+}
+func (m *MapSetMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "key", 0, nil), node.NewVariable(nil, "value", 1, nil)}
+}
+func (m *MapSetMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// MapHasMethod has(string $key): bool
+type MapHasMethod struct {
+	source *MapClass
+}
+
+func (m *MapHasMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	_, ok := m.source.store.m.Load(mapKey(ctx))
+	return data.NewBoolValue(ok), nil
+}
+
+func (m *MapHasMethod) GetName() string            { return "has" }
+func (m *MapHasMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MapHasMethod) GetIsStatic() bool          { return false }
+func (m *MapHasMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "key", 0, nil, nil)}
+}
+func (m *MapHasMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "key", 0, nil)}
+}
+func (m *MapHasMethod) GetReturnType() data.Types { return data.NewBaseType("bool") }
+
+// MapDeleteMethod delete(string $key): void
+type MapDeleteMethod struct {
+	source *MapClass
+}
+
+func (m *MapDeleteMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	m.source.store.m.Delete(mapKey(ctx))
+	return nil, nil
+}
+
+func (m *MapDeleteMethod) GetName() string            { return "delete" }
+func (m *MapDeleteMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MapDeleteMethod) GetIsStatic() bool          { return false }
+func (m *MapDeleteMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "key", 0, nil, nil)}
+}
+func (m *MapDeleteMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "key", 0, nil)}
+}
+func (m *MapDeleteMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// MapGetOrSetMethod getOrSet(string $key, mixed $value): mixed，键不存在时写入 value，返回键最终的值
+type MapGetOrSetMethod struct {
+	source *MapClass
+}
+
+func (m *MapGetOrSetMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	v, _ := m.source.store.m.LoadOrStore(mapKey(ctx), mapValue(ctx, 1))
+	return v.(data.Value), nil
+}
+
+func (m *MapGetOrSetMethod) GetName() string            { return "getOrSet" }
+func (m *MapGetOrSetMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MapGetOrSetMethod) GetIsStatic() bool          { return false }
+func (m *MapGetOrSetMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "key", 0, nil, nil), node.NewParameter(nil, "value", 1, nil, nil)}
+}
+func (m *MapGetOrSetMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "key", 0, nil), node.NewVariable(nil, "value", 1, nil)}
+}
+func (m *MapGetOrSetMethod) GetReturnType() data.Types { return data.Mixed{} }
+
+// MapCountMethod count(): int
+type MapCountMethod struct {
+	source *MapClass
+}
+
+func (m *MapCountMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	keys, _ := m.source.store.entries()
+	return data.NewIntValue(len(keys)), nil
+}
+
+func (m *MapCountMethod) GetName() string               { return "count" }
+func (m *MapCountMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *MapCountMethod) GetIsStatic() bool             { return false }
+func (m *MapCountMethod) GetParams() []data.GetValue    { return nil }
+func (m *MapCountMethod) GetVariables() []data.Variable { return nil }
+func (m *MapCountMethod) GetReturnType() data.Types     { return data.NewBaseType("int") }
+
+// MapKeysMethod keys(): array，按键排序
+type MapKeysMethod struct {
+	source *MapClass
+}
+
+func (m *MapKeysMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	keys, _ := m.source.store.entries()
+	list := make([]data.Value, len(keys))
+	for i, k := range keys {
+		list[i] = data.NewStringValue(k)
+	}
+	return data.NewArrayValue(list), nil
+}
+
+func (m *MapKeysMethod) GetName() string               { return "keys" }
+func (m *MapKeysMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *MapKeysMethod) GetIsStatic() bool             { return false }
+func (m *MapKeysMethod) GetParams() []data.GetValue    { return nil }
+func (m *MapKeysMethod) GetVariables() []data.Variable { return nil }
+func (m *MapKeysMethod) GetReturnType() data.Types     { return data.NewBaseType("array") }
+
+// MapToArrayMethod toArray(): array，按键排序的快照
+type MapToArrayMethod struct {
+	source *MapClass
+}
+
+func (m *MapToArrayMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	keys, values := m.source.store.entries()
+	obj := data.NewObjectValue()
+	for i, k := range keys {
+		obj.SetProperty(k, values[i])
+	}
+	return obj, nil
+}
+
+func (m *MapToArrayMethod) GetName() string               { return "toArray" }
+func (m *MapToArrayMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *MapToArrayMethod) GetIsStatic() bool             { return false }
+func (m *MapToArrayMethod) GetParams() []data.GetValue    { return nil }
+func (m *MapToArrayMethod) GetVariables() []data.Variable { return nil }
+func (m *MapToArrayMethod) GetReturnType() data.Types     { return data.NewBaseType("array") }
+
+// MapForEachMethod forEach(Closure $fn): void，按键排序以 ($value, $key) 调用 fn，fn 返回 false 时停止
+type MapForEachMethod struct {
+	source *MapClass
+}
+
+func (m *MapForEachMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	fn, acl := closureArg(ctx, 0, "Sync\\Map::forEach")
+	if acl != nil {
+		return nil, acl
+	}
+	keys, values := m.source.store.entries()
+	for i, k := range keys {
+		ret, acl := callClosure(ctx, fn, values[i], data.NewStringValue(k))
+		if acl != nil {
+			return nil, acl
+		}
+		if b, ok := ret.(*data.BoolValue); ok && !b.Value {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (m *MapForEachMethod) GetName() string            { return "forEach" }
+func (m *MapForEachMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MapForEachMethod) GetIsStatic() bool          { return false }
+func (m *MapForEachMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "fn", 0, nil, nil)}
+}
+func (m *MapForEachMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "fn", 0, nil)}
+}
+func (m *MapForEachMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
@@ -0,0 +1,158 @@
+package sync
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/utils"
+)
+
+// Mutex 互斥锁。记录加锁状态，解锁未加锁的 Mutex 时抛出异常而不是让 Go 运行时直接退出
+type Mutex struct {
+	mu     sync.Mutex
+	locked atomic.Bool
+}
+
+func (m *Mutex) Lock() {
+	m.mu.Lock()
+	m.locked.Store(true)
+}
+
+func (m *Mutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.locked.Store(true)
+	return true
+}
+
+func (m *Mutex) Unlock() data.Control {
+	if !m.locked.CompareAndSwap(true, false) {
+		return utils.NewThrow(errors.New("Sync\\Mutex::unlock() 解锁未加锁的 Mutex"))
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// WithLock 加锁后调用 fn，fn 返回或抛出异常后解锁
+func (m *Mutex) WithLock(ctx data.Context, fn data.Value) (data.GetValue, data.Control) {
+	m.Lock()
+	defer m.Unlock()
+	return callClosure(ctx, fn)
+}
+
+type MutexClass struct {
+	node.Node
+	mutex *Mutex
+}
+
+func NewMutexClass() data.ClassStmt {
+	return &MutexClass{mutex: &Mutex{}}
+}
+
+func (c *MutexClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&MutexClass{mutex: &Mutex{}}, ctx.CreateBaseContext()), nil
+}
+
+func (c *MutexClass) GetName() string                               { return "Sync\\Mutex" }
+func (c *MutexClass) GetExtend() *string                            { return nil }
+func (c *MutexClass) GetImplements() []string                       { return nil }
+func (c *MutexClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *MutexClass) GetPropertyList() []data.Property              { return nil }
+func (c *MutexClass) GetConstruct() data.Method                     { return nil }
+
+func (c *MutexClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "lock":
+		return &MutexLockMethod{source: c}, true
+	case "tryLock":
+		return &MutexTryLockMethod{source: c}, true
+	case "unlock":
+		return &MutexUnlockMethod{source: c}, true
+	case "withLock":
+		return &MutexWithLockMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *MutexClass) GetMethods() []data.Method {
+	return []data.Method{
+		&MutexLockMethod{source: c},
+		&MutexTryLockMethod{source: c},
+		&MutexUnlockMethod{source: c},
+		&MutexWithLockMethod{source: c},
+	}
+}
+
+type MutexLockMethod struct {
+	source *MutexClass
+}
+
+func (m *MutexLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	m.source.mutex.Lock()
+	return nil, nil
+}
+
+func (m *MutexLockMethod) GetName() string               { return "lock" }
+func (m *MutexLockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *MutexLockMethod) GetIsStatic() bool             { return false }
+func (m *MutexLockMethod) GetParams() []data.GetValue    { return nil }
+func (m *MutexLockMethod) GetVariables() []data.Variable { return nil }
+func (m *MutexLockMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+type MutexTryLockMethod struct {
+	source *MutexClass
+}
+
+func (m *MutexTryLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.mutex.TryLock()), nil
+}
+
+func (m *MutexTryLockMethod) GetName() string               { return "tryLock" }
+func (m *MutexTryLockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *MutexTryLockMethod) GetIsStatic() bool             { return false }
+func (m *MutexTryLockMethod) GetParams() []data.GetValue    { return nil }
+func (m *MutexTryLockMethod) GetVariables() []data.Variable { return nil }
+func (m *MutexTryLockMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+
+type MutexUnlockMethod struct {
+	source *MutexClass
+}
+
+func (m *MutexUnlockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, m.source.mutex.Unlock()
+}
+
+func (m *MutexUnlockMethod) GetName() string               { return "unlock" }
+func (m *MutexUnlockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *MutexUnlockMethod) GetIsStatic() bool             { return false }
+func (m *MutexUnlockMethod) GetParams() []data.GetValue    { return nil }
+func (m *MutexUnlockMethod) GetVariables() []data.Variable { return nil }
+func (m *MutexUnlockMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+// MutexWithLockMethod withLock(Closure $fn): mixed，返回 fn 的返回值
+type MutexWithLockMethod struct {
+	source *MutexClass
+}
+
+func (m *MutexWithLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	fn, acl := closureArg(ctx, 0, "Sync\\Mutex::withLock")
+	if acl != nil {
+		return nil, acl
+	}
+	return m.source.mutex.WithLock(ctx, fn)
+}
+
+func (m *MutexWithLockMethod) GetName() string            { return "withLock" }
+func (m *MutexWithLockMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *MutexWithLockMethod) GetIsStatic() bool          { return false }
+func (m *MutexWithLockMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "fn", 0, nil, nil)}
+}
+func (m *MutexWithLockMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "fn", 0, nil)}
+}
+func (m *MutexWithLockMethod) GetReturnType() data.Types { return data.Mixed{} }
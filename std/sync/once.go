@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// Once 只执行一次的回调，之后的调用返回第一次的结果（或再次抛出第一次的异常），对应 sync.OnceValue
+type Once struct {
+	once   sync.Once
+	done   atomic.Bool
+	result data.GetValue
+	acl    data.Control
+}
+
+func (o *Once) Do(ctx data.Context, fn data.Value) (data.GetValue, data.Control) {
+	o.once.Do(func() {
+		defer o.done.Store(true)
+		o.result, o.acl = callClosure(ctx, fn)
+	})
+	return o.result, o.acl
+}
+
+func (o *Once) IsDone() bool {
+	return o.done.Load()
+}
+
+type OnceClass struct {
+	node.Node
+	once *Once
+}
+
+func NewOnceClass() data.ClassStmt {
+	return &OnceClass{once: &Once{}}
+}
+
+func (c *OnceClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&OnceClass{once: &Once{}}, ctx.CreateBaseContext()), nil
+}
+
+func (c *OnceClass) GetName() string                               { return "Sync\\Once" }
+func (c *OnceClass) GetExtend() *string                            { return nil }
+func (c *OnceClass) GetImplements() []string                       { return nil }
+func (c *OnceClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *OnceClass) GetPropertyList() []data.Property              { return nil }
+func (c *OnceClass) GetConstruct() data.Method                     { return nil }
+
+func (c *OnceClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "do":
+		return &OnceDoMethod{source: c}, true
+	case "isDone":
+		return &OnceIsDoneMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *OnceClass) GetMethods() []data.Method {
+	return []data.Method{
+		&OnceDoMethod{source: c},
+		&OnceIsDoneMethod{source: c},
+	}
+}
+
+// OnceDoMethod do(Closure $fn): mixed
+type OnceDoMethod struct {
+	source *OnceClass
+}
+
+func (m *OnceDoMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	fn, acl := closureArg(ctx, 0, "Sync\\Once::do")
+	if acl != nil {
+		return nil, acl
+	}
+	return m.source.once.Do(ctx, fn)
+}
+
+func (m *OnceDoMethod) GetName() string            { return "do" }
+func (m *OnceDoMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *OnceDoMethod) GetIsStatic() bool          { return false }
+func (m *OnceDoMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "fn", 0, nil, nil)}
+}
+func (m *OnceDoMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "fn", 0, nil)}
+}
+func (m *OnceDoMethod) GetReturnType() data.Types { return data.Mixed{} }
+
+// OnceIsDoneMethod isDone(): bool
+type OnceIsDoneMethod struct {
+	source *OnceClass
+}
+
+func (m *OnceIsDoneMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.once.IsDone()), nil
+}
+
+func (m *OnceIsDoneMethod) GetName() string               { return "isDone" }
+func (m *OnceIsDoneMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *OnceIsDoneMethod) GetIsStatic() bool             { return false }
+func (m *OnceIsDoneMethod) GetParams() []data.GetValue    { return nil }
+func (m *OnceIsDoneMethod) GetVariables() []data.Variable { return nil }
+func (m *OnceIsDoneMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
@@ -0,0 +1,270 @@
+package sync
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/utils"
+)
+
+// RWMutex 读写锁，与 Mutex 一样记录加锁状态，错误的解锁抛出异常
+type RWMutex struct {
+	mu      sync.RWMutex
+	locked  atomic.Bool
+	readers atomic.Int32
+}
+
+func (m *RWMutex) Lock() {
+	m.mu.Lock()
+	m.locked.Store(true)
+}
+
+func (m *RWMutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.locked.Store(true)
+	return true
+}
+
+func (m *RWMutex) Unlock() data.Control {
+	if !m.locked.CompareAndSwap(true, false) {
+		return utils.NewThrow(errors.New("Sync\\RWMutex::unlock() 解锁未加写锁的 RWMutex"))
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *RWMutex) RLock() {
+	m.mu.RLock()
+	m.readers.Add(1)
+}
+
+func (m *RWMutex) TryRLock() bool {
+	if !m.mu.TryRLock() {
+		return false
+	}
+	m.readers.Add(1)
+	return true
+}
+
+func (m *RWMutex) RUnlock() data.Control {
+	if m.readers.Add(-1) < 0 {
+		m.readers.Add(1)
+		return utils.NewThrow(errors.New("Sync\\RWMutex::rUnlock() 解锁未加读锁的 RWMutex"))
+	}
+	m.mu.RUnlock()
+	return nil
+}
+
+// WithLock 加写锁后调用 fn
+func (m *RWMutex) WithLock(ctx data.Context, fn data.Value) (data.GetValue, data.Control) {
+	m.Lock()
+	defer m.Unlock()
+	return callClosure(ctx, fn)
+}
+
+// WithRLock 加读锁后调用 fn
+func (m *RWMutex) WithRLock(ctx data.Context, fn data.Value) (data.GetValue, data.Control) {
+	m.RLock()
+	defer m.RUnlock()
+	return callClosure(ctx, fn)
+}
+
+type RWMutexClass struct {
+	node.Node
+	mutex *RWMutex
+}
+
+func NewRWMutexClass() data.ClassStmt {
+	return &RWMutexClass{mutex: &RWMutex{}}
+}
+
+func (c *RWMutexClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&RWMutexClass{mutex: &RWMutex{}}, ctx.CreateBaseContext()), nil
+}
+
+func (c *RWMutexClass) GetName() string                               { return "Sync\\RWMutex" }
+func (c *RWMutexClass) GetExtend() *string                            { return nil }
+func (c *RWMutexClass) GetImplements() []string                       { return nil }
+func (c *RWMutexClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *RWMutexClass) GetPropertyList() []data.Property              { return nil }
+func (c *RWMutexClass) GetConstruct() data.Method                     { return nil }
+
+func (c *RWMutexClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "lock":
+		return &RWMutexLockMethod{source: c}, true
+	case "tryLock":
+		return &RWMutexTryLockMethod{source: c}, true
+	case "unlock":
+		return &RWMutexUnlockMethod{source: c}, true
+	case "rLock":
+		return &RWMutexRLockMethod{source: c}, true
+	case "tryRLock":
+		return &RWMutexTryRLockMethod{source: c}, true
+	case "rUnlock":
+		return &RWMutexRUnlockMethod{source: c}, true
+	case "withLock":
+		return &RWMutexWithLockMethod{source: c}, true
+	case "withRLock":
+		return &RWMutexWithRLockMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *RWMutexClass) GetMethods() []data.Method {
+	return []data.Method{
+		&RWMutexLockMethod{source: c},
+		&RWMutexTryLockMethod{source: c},
+		&RWMutexUnlockMethod{source: c},
+		&RWMutexRLockMethod{source: c},
+		&RWMutexTryRLockMethod{source: c},
+		&RWMutexRUnlockMethod{source: c},
+		&RWMutexWithLockMethod{source: c},
+		&RWMutexWithRLockMethod{source: c},
+	}
+}
+
+type RWMutexLockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	m.source.mutex.Lock()
+	return nil, nil
+}
+
+func (m *RWMutexLockMethod) GetName() string               { return "lock" }
+func (m *RWMutexLockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *RWMutexLockMethod) GetIsStatic() bool             { return false }
+func (m *RWMutexLockMethod) GetParams() []data.GetValue    { return nil }
+func (m *RWMutexLockMethod) GetVariables() []data.Variable { return nil }
+func (m *RWMutexLockMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+type RWMutexTryLockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexTryLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.mutex.TryLock()), nil
+}
+
+func (m *RWMutexTryLockMethod) GetName() string               { return "tryLock" }
+func (m *RWMutexTryLockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *RWMutexTryLockMethod) GetIsStatic() bool             { return false }
+func (m *RWMutexTryLockMethod) GetParams() []data.GetValue    { return nil }
+func (m *RWMutexTryLockMethod) GetVariables() []data.Variable { return nil }
+func (m *RWMutexTryLockMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+
+type RWMutexUnlockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexUnlockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, m.source.mutex.Unlock()
+}
+
+func (m *RWMutexUnlockMethod) GetName() string               { return "unlock" }
+func (m *RWMutexUnlockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *RWMutexUnlockMethod) GetIsStatic() bool             { return false }
+func (m *RWMutexUnlockMethod) GetParams() []data.GetValue    { return nil }
+func (m *RWMutexUnlockMethod) GetVariables() []data.Variable { return nil }
+func (m *RWMutexUnlockMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+type RWMutexRLockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexRLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	m.source.mutex.RLock()
+	return nil, nil
+}
+
+func (m *RWMutexRLockMethod) GetName() string               { return "rLock" }
+func (m *RWMutexRLockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *RWMutexRLockMethod) GetIsStatic() bool             { return false }
+func (m *RWMutexRLockMethod) GetParams() []data.GetValue    { return nil }
+func (m *RWMutexRLockMethod) GetVariables() []data.Variable { return nil }
+func (m *RWMutexRLockMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+type RWMutexTryRLockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexTryRLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewBoolValue(m.source.mutex.TryRLock()), nil
+}
+
+func (m *RWMutexTryRLockMethod) GetName() string               { return "tryRLock" }
+func (m *RWMutexTryRLockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *RWMutexTryRLockMethod) GetIsStatic() bool             { return false }
+func (m *RWMutexTryRLockMethod) GetParams() []data.GetValue    { return nil }
+func (m *RWMutexTryRLockMethod) GetVariables() []data.Variable { return nil }
+func (m *RWMutexTryRLockMethod) GetReturnType() data.Types     { return data.NewBaseType("bool") }
+
+type RWMutexRUnlockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexRUnlockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, m.source.mutex.RUnlock()
+}
+
+func (m *RWMutexRUnlockMethod) GetName() string               { return "rUnlock" }
+func (m *RWMutexRUnlockMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *RWMutexRUnlockMethod) GetIsStatic() bool             { return false }
+func (m *RWMutexRUnlockMethod) GetParams() []data.GetValue    { return nil }
+func (m *RWMutexRUnlockMethod) GetVariables() []data.Variable { return nil }
+func (m *RWMutexRUnlockMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+// RWMutexWithLockMethod withLock(Closure $fn): mixed，加写锁后调用 fn 并返回其结果
+type RWMutexWithLockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexWithLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	fn, acl := closureArg(ctx, 0, "Sync\\RWMutex::withLock")
+	if acl != nil {
+		return nil, acl
+	}
+	return m.source.mutex.WithLock(ctx, fn)
+}
+
+func (m *RWMutexWithLockMethod) GetName() string            { return "withLock" }
+func (m *RWMutexWithLockMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *RWMutexWithLockMethod) GetIsStatic() bool          { return false }
+func (m *RWMutexWithLockMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "fn", 0, nil, nil)}
+}
+func (m *RWMutexWithLockMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "fn", 0, nil)}
+}
+func (m *RWMutexWithLockMethod) GetReturnType() data.Types { return data.Mixed{} }
+
+// RWMutexWithRLockMethod withRLock(Closure $fn): mixed，加读锁后调用 fn 并返回其结果
+type RWMutexWithRLockMethod struct {
+	source *RWMutexClass
+}
+
+func (m *RWMutexWithRLockMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	fn, acl := closureArg(ctx, 0, "Sync\\RWMutex::withRLock")
+	if acl != nil {
+		return nil, acl
+	}
+	return m.source.mutex.WithRLock(ctx, fn)
+}
+
+func (m *RWMutexWithRLockMethod) GetName() string            { return "withRLock" }
+func (m *RWMutexWithRLockMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *RWMutexWithRLockMethod) GetIsStatic() bool          { return false }
+func (m *RWMutexWithRLockMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "fn", 0, nil, nil)}
+}
+func (m *RWMutexWithRLockMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "fn", 0, nil)}
+}
+func (m *RWMutexWithRLockMethod) GetReturnType() data.Types { return data.Mixed{} }
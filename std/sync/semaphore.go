@@ -0,0 +1,301 @@
+package sync
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+	"github.com/php-any/origami/utils"
+)
+
+// Semaphore 计数信号量：最多 size 个许可，按先来先得唤醒等待者（与 golang.org/x/sync/semaphore 相同的做法）
+type Semaphore struct {
+	size    int
+	mu      sync.Mutex
+	cur     int
+	waiters list.List // *semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	n     int
+	ready chan struct{}
+}
+
+func NewSemaphore(size int) *Semaphore {
+	return &Semaphore{size: size}
+}
+
+func (s *Semaphore) check(n int) data.Control {
+	if n <= 0 || n > s.size {
+		return utils.NewThrow(fmt.Errorf("Sync\\Semaphore 许可数 %d 超出范围 1..%d", n, s.size))
+	}
+	return nil
+}
+
+// Acquire 获取 n 个许可，不足时阻塞
+func (s *Semaphore) Acquire(n int) data.Control {
+	if acl := s.check(n); acl != nil {
+		return acl
+	}
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	w := &semaphoreWaiter{n: n, ready: make(chan struct{})}
+	s.waiters.PushBack(w)
+	s.mu.Unlock()
+	<-w.ready
+	return nil
+}
+
+// TryAcquire 不阻塞地获取 n 个许可
+func (s *Semaphore) TryAcquire(n int) (bool, data.Control) {
+	if acl := s.check(n); acl != nil {
+		return false, acl
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true, nil
+	}
+	return false, nil
+}
+
+// Release 归还 n 个许可并唤醒能满足的等待者
+func (s *Semaphore) Release(n int) data.Control {
+	if acl := s.check(n); acl != nil {
+		return acl
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cur < n {
+		return utils.NewThrow(errors.New("Sync\\Semaphore::release() 归还的许可多于已获取的许可"))
+	}
+	s.cur -= n
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			break
+		}
+		w := front.Value.(*semaphoreWaiter)
+		if s.size-s.cur < w.n {
+			break
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+	return nil
+}
+
+// Available 当前可用的许可数
+func (s *Semaphore) Available() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size - s.cur
+}
+
+// WithPermit 获取一个许可后调用 fn，结束后归还
+func (s *Semaphore) WithPermit(ctx data.Context, fn data.Value) (data.GetValue, data.Control) {
+	if acl := s.Acquire(1); acl != nil {
+		return nil, acl
+	}
+	defer s.Release(1)
+	return callClosure(ctx, fn)
+}
+
+type SemaphoreClass struct {
+	node.Node
+	sem *Semaphore
+}
+
+func NewSemaphoreClass() data.ClassStmt {
+	return &SemaphoreClass{sem: NewSemaphore(1)}
+}
+
+func (c *SemaphoreClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&SemaphoreClass{sem: NewSemaphore(1)}, ctx.CreateBaseContext()), nil
+}
+
+func (c *SemaphoreClass) GetName() string                               { return "Sync\\Semaphore" }
+func (c *SemaphoreClass) GetExtend() *string                            { return nil }
+func (c *SemaphoreClass) GetImplements() []string                       { return nil }
+func (c *SemaphoreClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *SemaphoreClass) GetPropertyList() []data.Property              { return nil }
+func (c *SemaphoreClass) GetConstruct() data.Method                     { return &SemaphoreConstructMethod{source: c} }
+
+func (c *SemaphoreClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "acquire":
+		return &SemaphoreAcquireMethod{source: c}, true
+	case "tryAcquire":
+		return &SemaphoreTryAcquireMethod{source: c}, true
+	case "release":
+		return &SemaphoreReleaseMethod{source: c}, true
+	case "available":
+		return &SemaphoreAvailableMethod{source: c}, true
+	case "withPermit":
+		return &SemaphoreWithPermitMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *SemaphoreClass) GetMethods() []data.Method {
+	return []data.Method{
+		&SemaphoreAcquireMethod{source: c},
+		&SemaphoreTryAcquireMethod{source: c},
+		&SemaphoreReleaseMethod{source: c},
+		&SemaphoreAvailableMethod{source: c},
+		&SemaphoreWithPermitMethod{source: c},
+	}
+}
+
+// SemaphoreConstructMethod __construct(int $permits)，许可总数至少为 1
+type SemaphoreConstructMethod struct {
+	source *SemaphoreClass
+}
+
+func (m *SemaphoreConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	n, acl := intArg(ctx, 0, 1)
+	if acl != nil {
+		return nil, acl
+	}
+	if n < 1 {
+		return nil, utils.NewThrow(errors.New("Sync\\Semaphore 的许可数至少为 1"))
+	}
+	m.source.sem.size = n
+	return nil, nil
+}
+
+func (m *SemaphoreConstructMethod) GetName() string            { return "__construct" }
+func (m *SemaphoreConstructMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *SemaphoreConstructMethod) GetIsStatic() bool          { return false }
+func (m *SemaphoreConstructMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "permits", 0, nil, data.NewBaseType("int"))}
+}
+func (m *SemaphoreConstructMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "permits", 0, nil)}
+}
+func (m *SemaphoreConstructMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// SemaphoreAcquireMethod acquire(int $permits = 1): void
+type SemaphoreAcquireMethod struct {
+	source *SemaphoreClass
+}
+
+func (m *SemaphoreAcquireMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	n, acl := intArg(ctx, 0, 1)
+	if acl != nil {
+		return nil, acl
+	}
+	return nil, m.source.sem.Acquire(n)
+}
+
+func (m *SemaphoreAcquireMethod) GetName() string            { return "acquire" }
+func (m *SemaphoreAcquireMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *SemaphoreAcquireMethod) GetIsStatic() bool          { return false }
+func (m *SemaphoreAcquireMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "permits", 0, data.NewIntValue(1), data.NewBaseType("int"))}
+}
+func (m *SemaphoreAcquireMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "permits", 0, nil)}
+}
+func (m *SemaphoreAcquireMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// SemaphoreTryAcquireMethod tryAcquire(int $permits = 1): bool
+type SemaphoreTryAcquireMethod struct {
+	source *SemaphoreClass
+}
+
+func (m *SemaphoreTryAcquireMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	n, acl := intArg(ctx, 0, 1)
+	if acl != nil {
+		return nil, acl
+	}
+	ok, acl := m.source.sem.TryAcquire(n)
+	if acl != nil {
+		return nil, acl
+	}
+	return data.NewBoolValue(ok), nil
+}
+
+func (m *SemaphoreTryAcquireMethod) GetName() string            { return "tryAcquire" }
+func (m *SemaphoreTryAcquireMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *SemaphoreTryAcquireMethod) GetIsStatic() bool          { return false }
+func (m *SemaphoreTryAcquireMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "permits", 0, data.NewIntValue(1), data.NewBaseType("int"))}
+}
+func (m *SemaphoreTryAcquireMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "permits", 0, nil)}
+}
+func (m *SemaphoreTryAcquireMethod) GetReturnType() data.Types { return data.NewBaseType("bool") }
+
+// SemaphoreReleaseMethod release(int $permits = 1): void
+type SemaphoreReleaseMethod struct {
+	source *SemaphoreClass
+}
+
+func (m *SemaphoreReleaseMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	n, acl := intArg(ctx, 0, 1)
+	if acl != nil {
+		return nil, acl
+	}
+	return nil, m.source.sem.Release(n)
+}
+
+func (m *SemaphoreReleaseMethod) GetName() string            { return "release" }
+func (m *SemaphoreReleaseMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *SemaphoreReleaseMethod) GetIsStatic() bool          { return false }
+func (m *SemaphoreReleaseMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "permits", 0, data.NewIntValue(1), data.NewBaseType("int"))}
+}
+func (m *SemaphoreReleaseMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "permits", 0, nil)}
+}
+func (m *SemaphoreReleaseMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// SemaphoreAvailableMethod available(): int
+type SemaphoreAvailableMethod struct {
+	source *SemaphoreClass
+}
+
+func (m *SemaphoreAvailableMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewIntValue(m.source.sem.Available()), nil
+}
+
+func (m *SemaphoreAvailableMethod) GetName() string               { return "available" }
+func (m *SemaphoreAvailableMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *SemaphoreAvailableMethod) GetIsStatic() bool             { return false }
+func (m *SemaphoreAvailableMethod) GetParams() []data.GetValue    { return nil }
+func (m *SemaphoreAvailableMethod) GetVariables() []data.Variable { return nil }
+func (m *SemaphoreAvailableMethod) GetReturnType() data.Types     { return data.NewBaseType("int") }
+
+// SemaphoreWithPermitMethod withPermit(Closure $fn): mixed，持有一个许可调用 fn 并返回其结果
+type SemaphoreWithPermitMethod struct {
+	source *SemaphoreClass
+}
+
+func (m *SemaphoreWithPermitMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	fn, acl := closureArg(ctx, 0, "Sync\\Semaphore::withPermit")
+	if acl != nil {
+		return nil, acl
+	}
+	return m.source.sem.WithPermit(ctx, fn)
+}
+
+func (m *SemaphoreWithPermitMethod) GetName() string            { return "withPermit" }
+func (m *SemaphoreWithPermitMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *SemaphoreWithPermitMethod) GetIsStatic() bool          { return false }
+func (m *SemaphoreWithPermitMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "fn", 0, nil, nil)}
+}
+func (m *SemaphoreWithPermitMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "fn", 0, nil)}
+}
+func (m *SemaphoreWithPermitMethod) GetReturnType() data.Types { return data.Mixed{} }
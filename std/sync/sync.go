@@ -0,0 +1,65 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/utils"
+)
+
+// callClosure 按位置传参调用闭包（withLock、Once::do 等的回调）
+func callClosure(ctx data.Context, fn data.Value, args ...data.Value) (data.GetValue, data.Control) {
+	callCtx := ctx.CreateContext(make([]data.Variable, len(args)))
+	for i, arg := range args {
+		callCtx.SetIndexZVal(i, data.NewZVal(arg))
+	}
+	switch c := fn.(type) {
+	case *data.BoundFuncValue:
+		return c.Call(callCtx)
+	case *data.FuncValue:
+		return c.Call(callCtx)
+	}
+	return nil, utils.NewThrow(errors.New("回调必须是闭包"))
+}
+
+// closureArg 读取闭包参数
+func closureArg(ctx data.Context, index int, method string) (data.Value, data.Control) {
+	fn, _ := ctx.GetIndexValue(index)
+	switch fn.(type) {
+	case *data.BoundFuncValue, *data.FuncValue:
+		return fn, nil
+	}
+	return nil, utils.NewThrow(fmt.Errorf("%s() 需要一个闭包", method))
+}
+
+// intArg 读取整数参数，未传时返回 def
+func intArg(ctx data.Context, index int, def int) (int, data.Control) {
+	v, ok := ctx.GetIndexValue(index)
+	if !ok {
+		return def, nil
+	}
+	if _, isNull := v.(*data.NullValue); isNull {
+		return def, nil
+	}
+	i, ok := v.(data.AsInt)
+	if !ok {
+		return 0, utils.NewThrow(errors.New("参数必须是 int"))
+	}
+	n, err := i.AsInt()
+	if err != nil {
+		return 0, utils.NewThrow(err)
+	}
+	return n, nil
+}
+
+// protect 把 Go sync 原语的 panic（如 WaitGroup 计数器为负）转为异常
+func protect(fn func()) (acl data.Control) {
+	defer func() {
+		if r := recover(); r != nil {
+			acl = utils.NewThrow(fmt.Errorf("%v", r))
+		}
+	}()
+	fn()
+	return nil
+}
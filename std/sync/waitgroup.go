@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"sync"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+)
+
+// WaitGroupClass 封装 sync.WaitGroup，等待一组协程结束
+type WaitGroupClass struct {
+	node.Node
+	wg *sync.WaitGroup
+}
+
+func NewWaitGroupClass() data.ClassStmt {
+	return &WaitGroupClass{wg: &sync.WaitGroup{}}
+}
+
+func (c *WaitGroupClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&WaitGroupClass{wg: &sync.WaitGroup{}}, ctx.CreateBaseContext()), nil
+}
+
+func (c *WaitGroupClass) GetName() string                               { return "Sync\\WaitGroup" }
+func (c *WaitGroupClass) GetExtend() *string                            { return nil }
+func (c *WaitGroupClass) GetImplements() []string                       { return nil }
+func (c *WaitGroupClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *WaitGroupClass) GetPropertyList() []data.Property              { return nil }
+func (c *WaitGroupClass) GetConstruct() data.Method                     { return nil }
+
+func (c *WaitGroupClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "add":
+		return &WaitGroupAddMethod{source: c}, true
+	case "done":
+		return &WaitGroupDoneMethod{source: c}, true
+	case "wait":
+		return &WaitGroupWaitMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *WaitGroupClass) GetMethods() []data.Method {
+	return []data.Method{
+		&WaitGroupAddMethod{source: c},
+		&WaitGroupDoneMethod{source: c},
+		&WaitGroupWaitMethod{source: c},
+	}
+}
+
+// WaitGroupAddMethod add(int $delta = 1): void，计数器变为负数时抛出异常
+type WaitGroupAddMethod struct {
+	source *WaitGroupClass
+}
+
+func (m *WaitGroupAddMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	delta, acl := intArg(ctx, 0, 1)
+	if acl != nil {
+		return nil, acl
+	}
+	return nil, protect(func() { m.source.wg.Add(delta) })
+}
+
+func (m *WaitGroupAddMethod) GetName() string            { return "add" }
+func (m *WaitGroupAddMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *WaitGroupAddMethod) GetIsStatic() bool          { return false }
+func (m *WaitGroupAddMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "delta", 0, data.NewIntValue(1), data.NewBaseType("int"))}
+}
+func (m *WaitGroupAddMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "delta", 0, nil)}
+}
+func (m *WaitGroupAddMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+
+// WaitGroupDoneMethod done(): void，计数器减一
+type WaitGroupDoneMethod struct {
+	source *WaitGroupClass
+}
+
+func (m *WaitGroupDoneMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return nil, protect(m.source.wg.Done)
+}
+
+func (m *WaitGroupDoneMethod) GetName() string               { return "done" }
+func (m *WaitGroupDoneMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *WaitGroupDoneMethod) GetIsStatic() bool             { return false }
+func (m *WaitGroupDoneMethod) GetParams() []data.GetValue    { return nil }
+func (m *WaitGroupDoneMethod) GetVariables() []data.Variable { return nil }
+func (m *WaitGroupDoneMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
+
+// WaitGroupWaitMethod wait(): void，阻塞到计数器归零
+type WaitGroupWaitMethod struct {
+	source *WaitGroupClass
+}
+
+func (m *WaitGroupWaitMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	m.source.wg.Wait()
+	return nil, nil
+}
+
+func (m *WaitGroupWaitMethod) GetName() string               { return "wait" }
+func (m *WaitGroupWaitMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *WaitGroupWaitMethod) GetIsStatic() bool             { return false }
+func (m *WaitGroupWaitMethod) GetParams() []data.GetValue    { return nil }
+func (m *WaitGroupWaitMethod) GetVariables() []data.Variable { return nil }
+func (m *WaitGroupWaitMethod) GetReturnType() data.Types     { return data.NewBaseType("void") }
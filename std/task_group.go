@@ -0,0 +1,123 @@
+package std
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/php-any/origami/data"
+)
+
+// TaskGroup 一组派生自同一个 Context 的任务（对应 Go 的 errgroup）：
+// wait 等待全部任务结束，第一个失败的任务取消其余任务；limit > 0 时最多同时执行 limit 个任务（工作池）。
+type TaskGroup struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	slots  chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	futures []*Future
+	err     data.Control
+}
+
+func newTaskGroup(parent context.Context, limit int) *TaskGroup {
+	ctx, cancel := context.WithCancelCause(parent)
+	g := &TaskGroup{ctx: ctx, cancel: cancel}
+	if limit > 0 {
+		g.slots = make(chan struct{}, limit)
+	}
+	return g
+}
+
+// Spawn 在组的 Context 中启动任务。达到并发上限时等待空位；
+// 等待期间组被取消时不再启动任务，返回的 Future 以 CancelledError 完成，调用方被取消时抛出 CancelledError
+func (g *TaskGroup) Spawn(ctx data.Context, call func(data.Context) (data.GetValue, data.Control)) (*Future, data.Control) {
+	f := newFuture()
+	if g.slots != nil {
+		select {
+		case g.slots <- struct{}{}:
+		case <-g.ctx.Done():
+			f.settle(nil, data.NewCancelledThrow(g.ctx))
+			g.add(f)
+			return f, nil
+		case <-ctx.GoContext().Done():
+			return nil, data.NewCancelledThrow(ctx.GoContext())
+		}
+	}
+
+	g.wg.Add(1)
+	g.add(f)
+	runTask(f, g.ctx, ctx, func(callCtx data.Context) (data.GetValue, data.Control) {
+		defer g.release()
+		result, acl := safeCall(call, callCtx)
+		// 单独被 Future::cancel 取消的任务不算作组的失败
+		if acl != nil && !f.IsDone() {
+			g.fail(acl)
+		}
+		return result, acl
+	})
+	return f, nil
+}
+
+func (g *TaskGroup) add(f *Future) {
+	g.mu.Lock()
+	g.futures = append(g.futures, f)
+	g.mu.Unlock()
+}
+
+func (g *TaskGroup) release() {
+	if g.slots != nil {
+		<-g.slots
+	}
+	g.wg.Done()
+}
+
+// fail 记录第一个失败并取消组内其余任务
+func (g *TaskGroup) fail(acl data.Control) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return
+	}
+	g.err = acl
+	g.cancel(errors.New("同组任务失败"))
+}
+
+// Cancel 取消组内所有任务，之后 wait 抛出 CancelledError
+func (g *TaskGroup) Cancel(cause error) {
+	if cause == nil {
+		cause = errors.New("任务组已取消")
+	}
+	g.cancel(cause)
+}
+
+// Wait 等待所有任务结束，按启动顺序返回结果；有任务失败时抛出第一个失败的异常。
+// 等待方的 ctx 被取消时抛出 CancelledError
+func (g *TaskGroup) Wait(ctx context.Context) ([]data.Value, data.Control) {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, data.NewCancelledThrow(ctx)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return nil, g.err
+	}
+	results := make([]data.Value, len(g.futures))
+	for i, f := range g.futures {
+		<-f.done
+		results[i] = data.NewNullValue()
+		if f.acl == nil {
+			results[i] = f.result
+		}
+	}
+	return results, nil
+}
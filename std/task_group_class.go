@@ -0,0 +1,225 @@
+package std
+
+import (
+	"context"
+	"errors"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
+	stdcontext "github.com/php-any/origami/std/context"
+)
+
+// TaskGroupClass new TaskGroup(?Context\Context $parent = null, int $limit = 0)。
+// 不传 parent 时派生自创建方的 Context；limit 为同时执行的任务上限，0 表示不限制
+type TaskGroupClass struct {
+	node.Node
+	group *TaskGroup
+}
+
+func NewTaskGroupClass() *TaskGroupClass {
+	return &TaskGroupClass{}
+}
+
+func (c *TaskGroupClass) GetValue(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(&TaskGroupClass{group: newTaskGroup(ctx.GoContext(), 0)}, ctx.CreateBaseContext()), nil
+}
+
+func (c *TaskGroupClass) GetName() string                               { return "TaskGroup" }
+func (c *TaskGroupClass) GetExtend() *string                            { return nil }
+func (c *TaskGroupClass) GetImplements() []string                       { return nil }
+func (c *TaskGroupClass) GetProperty(name string) (data.Property, bool) { return nil, false }
+func (c *TaskGroupClass) GetPropertyList() []data.Property              { return nil }
+func (c *TaskGroupClass) GetConstruct() data.Method                     { return &TaskGroupConstructMethod{source: c} }
+
+func (c *TaskGroupClass) GetMethod(name string) (data.Method, bool) {
+	switch name {
+	case "spawn":
+		return &TaskGroupSpawnMethod{source: c}, true
+	case "wait":
+		return &TaskGroupWaitMethod{source: c}, true
+	case "cancel":
+		return &TaskGroupCancelMethod{source: c}, true
+	case "context":
+		return &TaskGroupContextMethod{source: c}, true
+	}
+	return nil, false
+}
+
+func (c *TaskGroupClass) GetMethods() []data.Method {
+	return []data.Method{
+		&TaskGroupSpawnMethod{source: c},
+		&TaskGroupWaitMethod{source: c},
+		&TaskGroupCancelMethod{source: c},
+		&TaskGroupContextMethod{source: c},
+	}
+}
+
+// TaskGroupConstructMethod __construct(?Context\Context $parent = null, int $limit = 0)
+type TaskGroupConstructMethod struct {
+	source *TaskGroupClass
+}
+
+func (m *TaskGroupConstructMethod) GetName() string            { return "__construct" }
+func (m *TaskGroupConstructMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *TaskGroupConstructMethod) GetIsStatic() bool          { return false }
+func (m *TaskGroupConstructMethod) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "parent", 0, data.NewNullValue(), nil),
+		node.NewParameter(nil, "limit", 1, data.NewIntValue(0), data.NewBaseType("int")),
+	}
+}
+func (m *TaskGroupConstructMethod) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "parent", 0, data.Mixed{}),
+		node.NewVariable(nil, "limit", 1, data.Mixed{}),
+	}
+}
+func (m *TaskGroupConstructMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (m *TaskGroupConstructMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	parent := ctx.GoContext()
+	if v, ok := ctx.GetIndexValue(0); ok {
+		switch p := v.(type) {
+		case *data.NullValue:
+		case *data.ClassValue:
+			src, ok := p.Class.(interface{ GetSource() any })
+			if !ok {
+				return nil, futureError("TypeError", "TaskGroup 的 parent 必须是 Context\\Context")
+			}
+			if c, ok := src.GetSource().(context.Context); ok && c != nil {
+				parent = c
+			}
+		default:
+			return nil, futureError("TypeError", "TaskGroup 的 parent 必须是 Context\\Context")
+		}
+	}
+	limit := 0
+	if v, ok := ctx.GetIndexValue(1); ok {
+		if i, ok := v.(data.AsInt); ok {
+			n, err := i.AsInt()
+			if err != nil {
+				return nil, futureError("TypeError", err.Error())
+			}
+			limit = n
+		}
+	}
+	if limit < 0 {
+		return nil, futureError("ValueError", "TaskGroup 的 limit 不能为负数")
+	}
+	m.source.group = newTaskGroup(parent, limit)
+	return nil, nil
+}
+
+// TaskGroupSpawnMethod spawn(Closure $closure, mixed ...$args): Future
+// 与 spawn() 相同，任务在组的 Context 中执行；达到 limit 时等待空位
+type TaskGroupSpawnMethod struct {
+	source *TaskGroupClass
+}
+
+func (m *TaskGroupSpawnMethod) GetName() string            { return "spawn" }
+func (m *TaskGroupSpawnMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *TaskGroupSpawnMethod) GetIsStatic() bool          { return false }
+func (m *TaskGroupSpawnMethod) GetParams() []data.GetValue {
+	return []data.GetValue{
+		node.NewParameter(nil, "closure", 0, nil, nil),
+		node.NewParameters(nil, "args", 1, nil, nil),
+	}
+}
+func (m *TaskGroupSpawnMethod) GetVariables() []data.Variable {
+	return []data.Variable{
+		node.NewVariable(nil, "closure", 0, data.Mixed{}),
+		node.NewVariable(nil, "args", 1, data.Mixed{}),
+	}
+}
+func (m *TaskGroupSpawnMethod) GetReturnType() data.Types { return data.NewBaseType("Future") }
+func (m *TaskGroupSpawnMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	cb, _ := ctx.GetIndexValue(0)
+	switch c := cb.(type) {
+	case *data.BoundFuncValue:
+		if !isClosure(c.Value) {
+			return nil, futureError("TypeError", "TaskGroup::spawn() 只接受闭包")
+		}
+	case *data.FuncValue:
+		if !isClosure(c.Value) {
+			return nil, futureError("TypeError", "TaskGroup::spawn() 只接受闭包")
+		}
+	default:
+		return nil, futureError("TypeError", "TaskGroup::spawn() 只接受闭包")
+	}
+
+	var args []data.Value
+	if v, ok := ctx.GetIndexValue(1); ok {
+		if arr, ok := v.(*data.ArrayValue); ok {
+			args = arr.ToValueList()
+		}
+	}
+
+	future, acl := m.source.group.Spawn(ctx, callClosure(cb, args))
+	if acl != nil {
+		return nil, acl
+	}
+	return newFutureValue(future, ctx), nil
+}
+
+// TaskGroupWaitMethod wait(): array
+// 等待所有任务结束，按 spawn 顺序返回结果；有任务失败时抛出第一个失败的异常
+type TaskGroupWaitMethod struct {
+	source *TaskGroupClass
+}
+
+func (m *TaskGroupWaitMethod) GetName() string               { return "wait" }
+func (m *TaskGroupWaitMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *TaskGroupWaitMethod) GetIsStatic() bool             { return false }
+func (m *TaskGroupWaitMethod) GetParams() []data.GetValue    { return nil }
+func (m *TaskGroupWaitMethod) GetVariables() []data.Variable { return nil }
+func (m *TaskGroupWaitMethod) GetReturnType() data.Types     { return data.NewBaseType("array") }
+func (m *TaskGroupWaitMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	results, acl := m.source.group.Wait(ctx.GoContext())
+	if acl != nil {
+		return nil, acl
+	}
+	return data.NewArrayValue(results), nil
+}
+
+// TaskGroupCancelMethod cancel(?string $cause = null): void
+// 取消组内所有任务：阻塞中的 channel、sleep、SQL、HTTP 请求抛出 CancelledError
+type TaskGroupCancelMethod struct {
+	source *TaskGroupClass
+}
+
+func (m *TaskGroupCancelMethod) GetName() string            { return "cancel" }
+func (m *TaskGroupCancelMethod) GetModifier() data.Modifier { return data.ModifierPublic }
+func (m *TaskGroupCancelMethod) GetIsStatic() bool          { return false }
+func (m *TaskGroupCancelMethod) GetParams() []data.GetValue {
+	return []data.GetValue{node.NewParameter(nil, "cause", 0, data.NewNullValue(), nil)}
+}
+func (m *TaskGroupCancelMethod) GetVariables() []data.Variable {
+	return []data.Variable{node.NewVariable(nil, "cause", 0, data.Mixed{})}
+}
+func (m *TaskGroupCancelMethod) GetReturnType() data.Types { return data.NewBaseType("void") }
+func (m *TaskGroupCancelMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	var cause error
+	if v, ok := ctx.GetIndexValue(0); ok {
+		if _, isNull := v.(*data.NullValue); !isNull && v.AsString() != "" {
+			cause = errors.New(v.AsString())
+		}
+	}
+	m.source.group.Cancel(cause)
+	return nil, nil
+}
+
+// TaskGroupContextMethod context(): Context\Context，组的 Context，可传给 Channel::select 或嵌套的 TaskGroup
+type TaskGroupContextMethod struct {
+	source *TaskGroupClass
+}
+
+func (m *TaskGroupContextMethod) GetName() string               { return "context" }
+func (m *TaskGroupContextMethod) GetModifier() data.Modifier    { return data.ModifierPublic }
+func (m *TaskGroupContextMethod) GetIsStatic() bool             { return false }
+func (m *TaskGroupContextMethod) GetParams() []data.GetValue    { return nil }
+func (m *TaskGroupContextMethod) GetVariables() []data.Variable { return nil }
+func (m *TaskGroupContextMethod) GetReturnType() data.Types {
+	return data.NewBaseType("Context\\Context")
+}
+func (m *TaskGroupContextMethod) Call(ctx data.Context) (data.GetValue, data.Control) {
+	return data.NewClassValue(stdcontext.NewContextClassFrom(m.source.group.ctx), ctx.CreateBaseContext()), nil
+}
@@ -0,0 +1,114 @@
+<?php
+namespace tests\basic;
+
+// 测试数组的值语义（写时复制）：赋值、传参、返回后修改副本不影响原数组
+
+// 赋值后修改副本
+$a = [1, 2, 3];
+$b = $a;
+$b[0] = 9;
+$b[] = 4;
+if (!(implode(',', $a) === '1,2,3' && implode(',', $b) === '9,2,3,4')) {
+    Log::fatal("[FAIL] 赋值后修改副本 test1");
+} else {
+    Log::info("[PASS] 赋值后修改副本 test1");
+}
+
+// 传参后在函数内修改
+function touchArray($arr) {
+    $arr[1] = 'z';
+    $arr['k'] = 'v';
+    return $arr;
+}
+$c = touchArray($a);
+if (!(implode(',', $a) === '1,2,3' && $c[1] === 'z' && $c['k'] === 'v' && !isset($a['k']))) {
+    Log::fatal("[FAIL] 传参后在函数内修改 test2");
+} else {
+    Log::info("[PASS] 传参后在函数内修改 test2");
+}
+
+// 嵌套数组逐层分离
+$n = [[1, 2], [3]];
+$m = $n;
+$m[0][0] = 'q';
+$m[1][] = 4;
+if (!($n[0][0] === 1 && count($n[1]) === 1 && $m[0][0] === 'q' && count($m[1]) === 2)) {
+    Log::fatal("[FAIL] 嵌套数组逐层分离 test3");
+} else {
+    Log::info("[PASS] 嵌套数组逐层分离 test3");
+}
+
+// unset 与按引用修改的内置函数
+$s1 = [3, 1, 2];
+$s2 = $s1;
+sort($s2);
+unset($s1[0]);
+if (!(implode(',', $s2) === '1,2,3' && count($s1) === 2)) {
+    Log::fatal("[FAIL] unset 与按引用修改的内置函数 test4");
+} else {
+    Log::info("[PASS] unset 与按引用修改的内置函数 test4");
+}
+
+$p1 = [1, 2, 3];
+$p2 = $p1;
+array_pop($p2);
+$p2[] = 'x';
+array_push($p2, 'y');
+if (!(implode(',', $p1) === '1,2,3' && implode(',', $p2) === '1,2,x,y')) {
+    Log::fatal("[FAIL] unset 与按引用修改的内置函数 test5");
+} else {
+    Log::info("[PASS] unset 与按引用修改的内置函数 test5");
+}
+
+// 引用槽位在副本之间继续共享
+$r = [1, 2];
+$ref = &$r[0];
+$copy = $r;
+$ref = 7;
+if (!($r[0] === 7 && $copy[0] === 7 && $copy[1] === 2)) {
+    Log::fatal("[FAIL] 引用槽位在副本之间继续共享 test6");
+} else {
+    Log::info("[PASS] 引用槽位在副本之间继续共享 test6");
+}
+
+// 对象属性中的数组
+class ArrayBag {
+    public $items = [];
+    public function add($x) {
+        $this->items[] = $x;
+    }
+}
+$bag = new ArrayBag();
+$bag->add(1);
+$snapshot = $bag->items;
+$bag->add(2);
+if (!(count($snapshot) === 1 && count($bag->items) === 2)) {
+    Log::fatal("[FAIL] 对象属性中的数组 test7");
+} else {
+    Log::info("[PASS] 对象属性中的数组 test7");
+}
+
+// 大数组的字符串键（哈希索引）保持插入顺序
+$big = [];
+for ($i = 0; $i < 100; $i++) {
+    $big[] = $i;
+}
+for ($i = 0; $i < 100; $i++) {
+    $big['k' . $i] = $i * 2;
+}
+$other = $big;
+$other['k5'] = 'changed';
+unset($other['k6']);
+if (!($big['k5'] === 10 && $big['k99'] === 198 && isset($big['k6']) && !isset($other['k6']) && $other['k5'] === 'changed')) {
+    Log::fatal("[FAIL] 大数组的字符串键（哈希索引）保持插入顺序 test8");
+} else {
+    Log::info("[PASS] 大数组的字符串键（哈希索引）保持插入顺序 test8");
+}
+$keys = array_keys($big);
+if (!($keys[100] === 'k0' && $keys[199] === 'k99')) {
+    Log::fatal("[FAIL] 大数组的字符串键（哈希索引）保持插入顺序 test9");
+} else {
+    Log::info("[PASS] 大数组的字符串键（哈希索引）保持插入顺序 test9");
+}
+
+Log::info("数组写时复制测试完成");
@@ -0,0 +1,26 @@
+<?php
+
+// 外层闭包只通过内层闭包的 use（或箭头函数）使用捕获的变量时，值也要传到内层
+
+$outerVal = 7;
+
+$relay = function() use ($outerVal) {
+    return call_user_func(function() use ($outerVal) { return $outerVal; });
+};
+$arrowRelay = function() use ($outerVal) {
+    return call_user_func(fn() => $outerVal * 2);
+};
+$staticRelay = static function() use ($outerVal) {
+    return call_user_func(static fn() => $outerVal + 1);
+};
+$deep = function() use ($outerVal) {
+    return call_user_func(function() use ($outerVal) {
+        return call_user_func(fn() => $outerVal * 3);
+    });
+};
+
+if ($relay() === 7 && $arrowRelay() === 14 && $staticRelay() === 8 && $deep() === 21) {
+    Log::info("嵌套闭包传递捕获变量测试通过");
+} else {
+    Log::fatal("嵌套闭包传递捕获变量测试失败");
+}
@@ -0,0 +1,65 @@
+<?php
+namespace tests\basic;
+
+require_once __DIR__ . '/types/scalar_lib.php';
+
+use tests\basic\types\Calc;
+
+// 强制转换模式：数字字符串、int/float/string 之间按 PHP 规则转换
+if (types\addInts("5", " 6 ") === 11 && types\takesFloat(3) === 3.0 && types\takesString(12) === "12") {
+    Log::info("强制转换模式参数转换测试通过");
+} else {
+    Log::fatal("强制转换模式参数转换测试失败");
+}
+
+if ((new Calc())->twice("21") === 42 && types\numericReturn() === 12) {
+    Log::info("强制转换模式方法参数与返回值测试通过");
+} else {
+    Log::fatal("强制转换模式方法参数与返回值测试失败");
+}
+
+$message = "";
+try {
+    types\addInts("5abc", 1);
+} catch (\TypeError $e) {
+    $message = $e->getMessage();
+}
+if (str_starts_with($message, 'tests\basic\types\addInts(): Argument #1 ($a) must be of type int, string given')) {
+    Log::info("非数字字符串 TypeError 测试通过");
+} else {
+    Log::fatal("非数字字符串 TypeError 测试失败，实际: {$message}");
+}
+
+// 强制转换模式下 null 同样只能传给可空类型或默认值为 null 的参数
+$message = "";
+try {
+    types\addInts(null, 1);
+} catch (\TypeError $e) {
+    $message = $e->getMessage();
+}
+if (str_starts_with($message, 'tests\basic\types\addInts(): Argument #1 ($a) must be of type int, null given')) {
+    Log::info("强制转换模式 null 参数 TypeError 测试通过");
+} else {
+    Log::fatal("强制转换模式 null 参数 TypeError 测试失败，实际: {$message}");
+}
+
+function coerciveNullable(?int $a, int $b = null)
+{
+    return $a === null && $b === null;
+}
+if (coerciveNullable(null, null) && strlen(null) === 0) {
+    Log::info("强制转换模式可空参数与内置函数 null 参数测试通过");
+} else {
+    Log::fatal("强制转换模式可空参数与内置函数 null 参数测试失败");
+}
+
+// 由标准库回调的函数同样按参数类型转换实参
+$mapped = array_map(fn(int $x) => $x, ["1"]);
+$called = call_user_func(function (int $x) {
+    return $x;
+}, "2");
+if ($mapped === [1] && $called === 2) {
+    Log::info("回调参数强制转换测试通过");
+} else {
+    Log::fatal("回调参数强制转换测试失败");
+}
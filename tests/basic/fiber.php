@@ -0,0 +1,110 @@
+<?php
+namespace tests\basic;
+
+// 测试 Fiber 在任意调用深度挂起，并与调用方交替执行
+function fiberInner($x) {
+    $got = \Fiber::suspend($x * 2);
+    return $got + 1;
+}
+
+$trace = [];
+$fiber = new \Fiber(function ($a, $b) use (&$trace) {
+    $trace[] = "start";
+    $r = fiberInner($a + $b);
+    $trace[] = "resumed-$r";
+    try {
+        \Fiber::suspend("second");
+    } catch (\Exception $e) {
+        $trace[] = "caught-" . $e->getMessage();
+    }
+    return "done";
+});
+
+$first = $fiber->start(1, 2);
+$trace[] = "main-$first";
+if (!$fiber->isSuspended() || $fiber->isRunning()) {
+    Log::fatal("Fiber 挂起状态错误");
+}
+$second = $fiber->resume(10);
+$trace[] = "main-$second";
+$fiber->throw(new \Exception("boom"));
+
+if (implode(",", $trace) == "start,main-6,resumed-11,main-second,caught-boom") {
+    Log::info("Fiber start/resume/suspend/throw 测试通过");
+} else {
+    Log::fatal("Fiber start/resume/suspend/throw 测试失败", $trace);
+}
+
+if ($fiber->isTerminated() && $fiber->getReturn() == "done") {
+    Log::info("Fiber getReturn() 测试通过");
+} else {
+    Log::fatal("Fiber getReturn() 测试失败");
+}
+
+// 测试非法状态转换抛出 FiberError
+$errors = [];
+try {
+    $fiber->start();
+} catch (\FiberError $e) {
+    $errors[] = $e->getMessage();
+}
+try {
+    $fiber->resume();
+} catch (\FiberError $e) {
+    $errors[] = $e->getMessage();
+}
+try {
+    \Fiber::suspend();
+} catch (\FiberError $e) {
+    $errors[] = $e->getMessage();
+}
+if (count($errors) == 3 && $errors[2] == "Cannot suspend outside of fiber") {
+    Log::info("FiberError 测试通过");
+} else {
+    Log::fatal("FiberError 测试失败", $errors);
+}
+
+// 测试 Fiber 内未捕获的异常交给恢复方
+$failing = new \Fiber(function () {
+    \Fiber::suspend();
+    throw new \Exception("inside");
+});
+$failing->start();
+$message = "";
+try {
+    $failing->resume();
+} catch (\Exception $e) {
+    $message = $e->getMessage();
+}
+if ($message == "inside" && $failing->isTerminated()) {
+    Log::info("Fiber 异常传递测试通过");
+} else {
+    Log::fatal("Fiber 异常传递测试失败，实际: {$message}");
+}
+
+// 测试嵌套 Fiber 与 Fiber::getCurrent()
+class FiberOrder
+{
+    public static $items = [];
+}
+
+$outer = new \Fiber(function () {
+    $in = new \Fiber(function () {
+        FiberOrder::$items[] = "in-1";
+        \Fiber::suspend();
+        FiberOrder::$items[] = "in-2";
+    });
+    $in->start();
+    FiberOrder::$items[] = \Fiber::getCurrent() !== null ? "outer-1" : "no-current";
+    \Fiber::suspend();
+    $in->resume();
+    FiberOrder::$items[] = "outer-2";
+});
+$outer->start();
+FiberOrder::$items[] = \Fiber::getCurrent() === null ? "main" : "bad-current";
+$outer->resume();
+if (implode(",", FiberOrder::$items) == "in-1,outer-1,main,in-2,outer-2") {
+    Log::info("嵌套 Fiber 测试通过");
+} else {
+    Log::fatal("嵌套 Fiber 测试失败", FiberOrder::$items);
+}
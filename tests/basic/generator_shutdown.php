@@ -0,0 +1,27 @@
+<?php
+namespace tests\basic;
+
+// 测试脚本结束时销毁仍挂起的生成器：挂起点外层的 finally 在 shutdown 阶段执行
+class GeneratorShutdownHolder {
+    public static $generator = null;
+    public static $closed = false;
+}
+
+function suspendedUntilShutdown() {
+    try {
+        yield 1;
+        yield 2;
+    } finally {
+        GeneratorShutdownHolder::$closed = true;
+        Log::info("脚本结束时销毁挂起的生成器执行 finally 测试通过");
+    }
+}
+
+GeneratorShutdownHolder::$generator = suspendedUntilShutdown();
+GeneratorShutdownHolder::$generator->current();
+
+if (GeneratorShutdownHolder::$closed === false) {
+    Log::info("挂起的生成器在脚本结束前不执行 finally 测试通过");
+} else {
+    Log::fatal("挂起的生成器在脚本结束前不执行 finally 测试失败");
+}
@@ -0,0 +1,64 @@
+<?php
+declare(strict_types=1);
+
+namespace tests\basic;
+
+require_once __DIR__ . '/types/scalar_lib.php';
+
+use tests\basic\types\Calc;
+
+// 严格模式：参数类型按调用方（本文件）检查，只有 int → float 允许
+if (types\addInts(5, 6) === 11 && types\takesFloat(3) === 3.0) {
+    Log::info("strict_types 同类型参数测试通过");
+} else {
+    Log::fatal("strict_types 同类型参数测试失败");
+}
+
+$errors = [];
+try {
+    types\addInts("5", 6);
+} catch (\TypeError $e) {
+    $errors[] = $e->getMessage();
+}
+try {
+    types\takesString(12);
+} catch (\TypeError $e) {
+    $errors[] = $e->getMessage();
+}
+try {
+    (new Calc())->twice("21");
+} catch (\TypeError $e) {
+    $errors[] = $e->getMessage();
+}
+if (count($errors) == 3
+    && str_contains($errors[0], 'Argument #1 ($a) must be of type int, string given')
+    && str_contains($errors[1], 'Argument #1 ($s) must be of type string, int given')
+    && str_starts_with($errors[2], 'tests\basic\types\Calc::twice(): Argument #1 ($x)')) {
+    Log::info("strict_types 参数 TypeError 测试通过");
+} else {
+    Log::fatal("strict_types 参数 TypeError 测试失败", $errors);
+}
+
+// 返回值按函数定义所在文件检查：函数库未声明 strict_types，仍然转换
+if (types\numericReturn() === 12) {
+    Log::info("strict_types 不影响其它文件的返回值测试通过");
+} else {
+    Log::fatal("strict_types 不影响其它文件的返回值测试失败");
+}
+
+function strictReturn(): int
+{
+    return "1";
+}
+
+$message = "";
+try {
+    strictReturn();
+} catch (\TypeError $e) {
+    $message = $e->getMessage();
+}
+if (str_ends_with($message, "strictReturn(): Return value must be of type int, string returned")) {
+    Log::info("strict_types 返回值 TypeError 测试通过");
+} else {
+    Log::fatal("strict_types 返回值 TypeError 测试失败，实际: {$message}");
+}
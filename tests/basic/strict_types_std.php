@@ -0,0 +1,48 @@
+<?php
+declare(strict_types=1);
+
+namespace tests\basic;
+
+require_once __DIR__ . '/types/coercive_lib.php';
+
+// 严格模式同样作用于标准库函数与泛型参数：按调用点所在文件的模式检查
+
+$errors = [];
+try {
+    strlen(5);
+} catch (\TypeError $e) {
+    $errors[] = $e->getMessage();
+}
+try {
+    str_repeat("ab", "3");
+} catch (\TypeError $e) {
+    $errors[] = $e->getMessage();
+}
+try {
+    types\samePair(1, "2");
+} catch (\TypeError $e) {
+    $errors[] = $e->getMessage();
+}
+if (count($errors) == 3
+    && str_starts_with($errors[0], 'strlen(): Argument #1 ($string) must be of type string, int given')
+    && str_starts_with($errors[1], 'str_repeat(): Argument #2 ($times) must be of type int, string given')
+    && str_starts_with($errors[2], 'tests\basic\types\samePair(): Argument #2 ($b) must be of type int, string given')) {
+    Log::info("strict_types 标准库与泛型参数 TypeError 测试通过");
+} else {
+    Log::fatal("strict_types 标准库与泛型参数 TypeError 测试失败", $errors);
+}
+
+// 类型匹配时照常调用；int 可以传给 float 参数
+if (strlen("abc") === 3 && str_repeat("ab", 2) === "abab" && substr("hello", 1, 3) === "ell"
+    && types\samePair(1, 2) === [1, 2]) {
+    Log::info("strict_types 标准库正常调用测试通过");
+} else {
+    Log::fatal("strict_types 标准库正常调用测试失败");
+}
+
+// 未声明 strict_types 的文件中调用仍按强制转换模式
+if (types\lengthOf(12345) === 5) {
+    Log::info("strict_types 不影响其它文件的标准库调用测试通过");
+} else {
+    Log::fatal("strict_types 不影响其它文件的标准库调用测试失败");
+}
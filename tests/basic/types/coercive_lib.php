@@ -0,0 +1,13 @@
+<?php
+namespace tests\basic\types;
+
+// 未声明 strict_types 的文件：在这里调用标准库函数按强制转换模式处理
+function lengthOf($v): int
+{
+    return strlen($v);
+}
+
+function samePair<T>(T $a, T $b): array
+{
+    return [$a, $b];
+}
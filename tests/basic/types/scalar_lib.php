@@ -0,0 +1,31 @@
+<?php
+namespace tests\basic\types;
+
+// 未声明 strict_types 的函数库：返回值按强制转换模式处理
+function addInts(int $a, int $b): int
+{
+    return $a + $b;
+}
+
+function takesFloat(float $f): float
+{
+    return $f;
+}
+
+function takesString(string $s): string
+{
+    return $s;
+}
+
+function numericReturn(): int
+{
+    return "12";
+}
+
+class Calc
+{
+    public function twice(int $x): int
+    {
+        return $x * 2;
+    }
+}
@@ -0,0 +1,189 @@
+<?php
+namespace tests\basic;
+
+// 测试 yield 出现在任意嵌套的控制结构中
+function nestedGenerator() {
+    $i = 0;
+    while ($i < 4) {
+        if ($i % 2 == 0) {
+            switch ($i) {
+                case 0:
+                    yield "zero";
+                    break;
+                default:
+                    yield "even";
+            }
+        } else {
+            foreach ([1, 2] as $j) {
+                yield "odd-$j";
+            }
+        }
+        $i++;
+    }
+}
+
+$values = [];
+foreach (nestedGenerator() as $v) {
+    $values[] = $v;
+}
+if (implode(",", $values) == "zero,odd-1,odd-2,even,odd-1,odd-2") {
+    Log::info("嵌套控制结构中的 yield 测试通过");
+} else {
+    Log::fatal("嵌套控制结构中的 yield 测试失败", $values);
+}
+
+// 测试 send 在任意挂起点传值
+function accumulator() {
+    $total = 0;
+    while (true) {
+        $n = yield $total;
+        if ($n === null) {
+            return $total;
+        }
+        $total += $n;
+    }
+}
+
+$acc = accumulator();
+$acc->current();
+$acc->send(3);
+$current = $acc->send(4);
+if ($current == 7) {
+    Log::info("send() 测试通过");
+} else {
+    Log::fatal("send() 测试失败，期望: 7, 实际: {$current}");
+}
+$acc->next();
+if (!$acc->valid() && $acc->getReturn() == 7) {
+    Log::info("getReturn() 测试通过");
+} else {
+    Log::fatal("getReturn() 测试失败");
+}
+
+// 测试 throw 在挂起点抛出异常，并由生成器内部 catch
+function resilient() {
+    while (true) {
+        try {
+            yield "waiting";
+        } catch (\Exception $e) {
+            yield "caught " . $e->getMessage();
+        }
+    }
+}
+
+$r = resilient();
+$r->current();
+$result = $r->throw(new \Exception("boom"));
+if ($result == "caught boom") {
+    Log::info("throw() 测试通过");
+} else {
+    Log::fatal("throw() 测试失败，实际: {$result}");
+}
+
+// 测试提前结束时 finally 被执行
+$cleaned = false;
+function withFinally(&$flag) {
+    try {
+        for ($i = 0; $i < 10; $i++) {
+            yield $i;
+        }
+    } finally {
+        $flag = true;
+    }
+}
+
+foreach (withFinally($cleaned) as $v) {
+    if ($v == 2) {
+        break;
+    }
+}
+if ($cleaned) {
+    Log::info("提前销毁生成器执行 finally 测试通过");
+} else {
+    Log::fatal("提前销毁生成器执行 finally 测试失败");
+}
+
+// 测试 yield from 的返回值与闭包生成器
+function innerGen() {
+    yield 1;
+    return "inner";
+}
+function outerGen() {
+    $ret = yield from innerGen();
+    yield $ret;
+}
+
+$collected = [];
+foreach (outerGen() as $v) {
+    $collected[] = $v;
+}
+$closure = function () {
+    yield "closure";
+};
+foreach ($closure() as $v) {
+    $collected[] = $v;
+}
+if (implode(",", $collected) == "1,inner,closure") {
+    Log::info("yield from 返回值与闭包生成器测试通过");
+} else {
+    Log::fatal("yield from 返回值与闭包生成器测试失败", $collected);
+}
+
+// 测试在 switch 分支中挂起的生成器被销毁时只执行 finally，不继续执行分支后的语句
+function switchSuspended(array &$trace) {
+    try {
+        switch (1) {
+            case 1:
+                yield 1;
+                $trace[] = "after yield";
+                break;
+        }
+        $trace[] = "after switch";
+    } finally {
+        $trace[] = "finally";
+    }
+}
+
+$switchTrace = [];
+foreach (switchSuspended($switchTrace) as $v) {
+    break;
+}
+if ($switchTrace === ["finally"]) {
+    Log::info("switch 中挂起的生成器销毁测试通过");
+} else {
+    Log::fatal("switch 中挂起的生成器销毁测试失败", $switchTrace);
+}
+
+// 测试生成器被提前释放（unset、覆盖变量、所在函数返回）时执行 finally，仍被引用时不执行
+function releasedGen(array &$trace, $name) {
+    try {
+        yield 1;
+        yield 2;
+    } finally {
+        $trace[] = $name;
+    }
+}
+function releasedInFunction(array &$trace) {
+    $local = releasedGen($trace, "local");
+    $local->current();
+    return 1;
+}
+
+$releaseTrace = [];
+$unsetGen = releasedGen($releaseTrace, "unset");
+$unsetGen->current();
+unset($unsetGen);
+$nullGen = releasedGen($releaseTrace, "null");
+$nullGen->current();
+$nullGen = null;
+releasedInFunction($releaseTrace);
+$heldGen = releasedGen($releaseTrace, "held");
+$heldGen->current();
+$holder = [$heldGen];
+unset($heldGen);
+if ($releaseTrace === ["unset", "null", "local"] && $holder[0]->current() === 1) {
+    Log::info("提前释放生成器执行 finally 测试通过");
+} else {
+    Log::fatal("提前释放生成器执行 finally 测试失败", $releaseTrace);
+}
+$holder = null;
@@ -0,0 +1,199 @@
+<?php
+namespace tests\engine;
+
+// 测试控制流语句在两种执行引擎（--engine=ast / --engine=bytecode）下结果一致
+
+class Counter {
+    public static function sumOdd(int $n): int {
+        $s = 0;
+        for ($i = 0; $i < $n; $i++) {
+            if ($i % 2 == 0) {
+                continue;
+            }
+            $s += $i;
+        }
+        return $s;
+    }
+
+    public function firstOver(array $list, int $limit): int {
+        foreach ($list as $v) {
+            if ($v > $limit) {
+                return $v;
+            }
+        }
+        return -1;
+    }
+}
+
+// continue 跳过本轮剩余语句
+$out = '';
+$i = 0;
+while ($i < 5) {
+    $i++;
+    if ($i == 2) {
+        continue;
+    }
+    $out .= $i;
+}
+if ($out !== '1345') {
+    Log::fatal("[FAIL] continue 跳过本轮剩余语句 test1");
+} else {
+    Log::info("[PASS] continue 跳过本轮剩余语句 test1");
+}
+
+$out = '';
+$k = 0;
+do {
+    $k++;
+    if ($k % 2 == 0) {
+        continue;
+    }
+    $out .= $k;
+} while ($k < 6);
+if ($out !== '135') {
+    Log::fatal("[FAIL] continue 跳过本轮剩余语句 test2");
+} else {
+    Log::info("[PASS] continue 跳过本轮剩余语句 test2");
+}
+
+// break N 跳出多层循环
+$out = '';
+for ($a = 0; $a < 3; $a++) {
+    for ($b = 0; $b < 3; $b++) {
+        if ($b == 1) {
+            break 2;
+        }
+        $out .= $a . '-' . $b . ' ';
+    }
+}
+if ($out !== '0-0 ') {
+    Log::fatal("[FAIL] break N 跳出多层循环 test3");
+} else {
+    Log::info("[PASS] break N 跳出多层循环 test3");
+}
+
+$out = '';
+foreach ([1, 2, 3] as $v) {
+    while (true) {
+        if ($v == 2) {
+            break 2;
+        }
+        break;
+    }
+    $out .= $v;
+}
+if ($out !== '1') {
+    Log::fatal("[FAIL] break N 跳出多层循环 test4");
+} else {
+    Log::info("[PASS] break N 跳出多层循环 test4");
+}
+
+$out = '';
+for ($a = 0; $a < 3; $a++) {
+    switch ($a) {
+        case 1:
+            break 2;
+        default:
+            $out .= $a;
+    }
+}
+if ($out !== '0') {
+    Log::fatal("[FAIL] break N 跳出多层循环 test5");
+} else {
+    Log::info("[PASS] break N 跳出多层循环 test5");
+}
+
+// if / elseif / else 链
+$labels = [];
+foreach ([-1, 0, 1, 2.5, '3'] as $v) {
+    if ($v < 0) {
+        $labels[] = 'neg';
+    } elseif ($v == 0) {
+        $labels[] = 'zero';
+    } elseif ($v >= 1 && $v < 2) {
+        $labels[] = 'one';
+    } else {
+        $labels[] = 'big';
+    }
+}
+if (implode(',', $labels) !== 'neg,zero,one,big,big') {
+    Log::fatal("[FAIL] if / elseif / else 链 test6");
+} else {
+    Log::info("[PASS] if / elseif / else 链 test6");
+}
+
+// 整数与浮点、字符串混合运算
+$f = 0;
+$n = 1;
+while ($n <= 4) {
+    $f = $f + 0.5 * $n;
+    $n = $n + 1;
+}
+if ($f !== 5.0) {
+    Log::fatal("[FAIL] 整数与浮点、字符串混合运算 test7");
+} else {
+    Log::info("[PASS] 整数与浮点、字符串混合运算 test7");
+}
+$s = '';
+for ($i = 0; $i < 3; $i++) {
+    $s = $s . $i;
+}
+if ($s !== '012') {
+    Log::fatal("[FAIL] 整数与浮点、字符串混合运算 test8");
+} else {
+    Log::info("[PASS] 整数与浮点、字符串混合运算 test8");
+}
+
+// 函数、方法与闭包中的循环
+function factorial(int $n): int {
+    $r = 1;
+    while ($n > 1) {
+        $r = $r * $n;
+        $n--;
+    }
+    return $r;
+}
+if (factorial(10) !== 3628800) {
+    Log::fatal("[FAIL] 函数、方法与闭包中的循环 test9");
+} else {
+    Log::info("[PASS] 函数、方法与闭包中的循环 test9");
+}
+if (Counter::sumOdd(10) !== 25) {
+    Log::fatal("[FAIL] 函数、方法与闭包中的循环 test10");
+} else {
+    Log::info("[PASS] 函数、方法与闭包中的循环 test10");
+}
+if ((new Counter())->firstOver([1, 5, 9], 4) !== 5) {
+    Log::fatal("[FAIL] 函数、方法与闭包中的循环 test11");
+} else {
+    Log::info("[PASS] 函数、方法与闭包中的循环 test11");
+}
+$sum = function (array $list): int {
+    $t = 0;
+    for ($i = count($list) - 1; $i >= 0; $i--) {
+        $t += $list[$i];
+    }
+    return $t;
+};
+if ($sum([1, 2, 3, 4]) !== 10) {
+    Log::fatal("[FAIL] 函数、方法与闭包中的循环 test12");
+} else {
+    Log::info("[PASS] 函数、方法与闭包中的循环 test12");
+}
+
+// 循环中的异常仍在原位置报告
+$line = 0;
+try {
+    for ($i = 3; $i >= 0; $i--) {
+        $x = 10 % $i;
+    }
+} catch (\Throwable $e) {
+    $line = $e->getLine();
+}
+if ($line !== 188) {
+    Log::fatal("[FAIL] 循环中的异常仍在原位置报告 test13, 实际行号: ", $line);
+} else {
+    Log::info("[PASS] 循环中的异常仍在原位置报告 test13");
+}
+
+Log::info("控制流测试完成");
@@ -0,0 +1,94 @@
+<?php
+namespace tests\func;
+
+// 测试 Channel::select：多路接收、发送分支、超时、default、after 与 Signal\Channel
+
+// 只有就绪的分支被选中，返回 [键, 值, ok]
+$a = new \Channel(1);
+$b = new \Channel(1);
+$b->send('from b');
+[$key, $value, $ok] = \Channel::select(['a' => $a, 'b' => $b]);
+if (!($key === 'b' && $value === 'from b' && $ok === true)) {
+    Log::fatal("[FAIL] 只有就绪的分支被选中，返回 [键, 值, ok] test1");
+} else {
+    Log::info("[PASS] 只有就绪的分支被选中，返回 [键, 值, ok] test1");
+}
+
+// 列表形式的键为下标；协程中稍后发送的值能被等待到
+spawn(function() use ($a) {
+    $a->send('later');
+});
+[$key, $value] = \Channel::select([$b, $a]);
+if (!($key === 1 && $value === 'later')) {
+    Log::fatal("[FAIL] 列表形式的键为下标；协程中稍后发送的值能被等待到 test2");
+} else {
+    Log::info("[PASS] 列表形式的键为下标；协程中稍后发送的值能被等待到 test2");
+}
+
+// 发送分支 [Channel, 值]
+$out = new \Channel(1);
+[$key, $value, $ok] = \Channel::select(['out' => [$out, 42], 'in' => $a]);
+if (!($key === 'out' && $value === null && $ok === true && $out->receive() === 42)) {
+    Log::fatal("[FAIL] 发送分支 [Channel, 值] test3");
+} else {
+    Log::info("[PASS] 发送分支 [Channel, 值] test3");
+}
+
+// 超时返回 [null, null, false]
+[$key, $value, $ok] = \Channel::select([$a], 0.05);
+if (!($key === null && $ok === false)) {
+    Log::fatal("[FAIL] 超时返回 [null, null, false] test4");
+} else {
+    Log::info("[PASS] 超时返回 [null, null, false] test4");
+}
+
+// 超时为 0 时相当于 default 分支，不阻塞
+if (\Channel::select([$a], 0)[0] !== null) {
+    Log::fatal("[FAIL] 超时为 0 时相当于 default 分支，不阻塞 test5");
+} else {
+    Log::info("[PASS] 超时为 0 时相当于 default 分支，不阻塞 test5");
+}
+
+// after 对应 Go 的 time.After，可与其它分支一起等待
+[$key] = \Channel::select(['work' => $a, 'timeout' => \Channel::after(0.05)]);
+if ($key !== 'timeout') {
+    Log::fatal("[FAIL] after 对应 Go 的 time.After，可与其它分支一起等待 test6");
+} else {
+    Log::info("[PASS] after 对应 Go 的 time.After，可与其它分支一起等待 test6");
+}
+
+// 已关闭的 channel 立即就绪，ok 为 false
+$closed = new \Channel();
+$closed->close();
+[$key, $value, $ok] = \Channel::select(['c' => $closed]);
+if (!($key === 'c' && $value === null && $ok === false)) {
+    Log::fatal("[FAIL] 已关闭的 channel 立即就绪，ok 为 false test7");
+} else {
+    Log::info("[PASS] 已关闭的 channel 立即就绪，ok 为 false test7");
+}
+
+// 向已关闭的 channel 发送抛出异常
+try {
+    \Channel::select([[$closed, 1]]);
+    Log::fatal("[FAIL] 向已关闭的 channel 发送抛出异常: 未抛出预期的异常");
+} catch (\Exception $e) {
+    Log::info("[PASS] 向已关闭的 channel 发送抛出异常 test8");
+}
+
+// Signal\Channel 可作为接收分支
+$sig = new \Signal\Channel();
+[$key] = \Channel::select(['sig' => $sig, 'a' => $a], 0.01);
+if ($key !== null) {
+    Log::fatal("[FAIL] Signal\\Channel 可作为接收分支 test9");
+} else {
+    Log::info("[PASS] Signal\\Channel 可作为接收分支 test9");
+}
+$sig->close();
+[$key, $value, $ok] = \Channel::select(['sig' => $sig, 'a' => $a]);
+if (!($key === 'sig' && $ok === false)) {
+    Log::fatal("[FAIL] Signal\\Channel 可作为接收分支 test10");
+} else {
+    Log::info("[PASS] Signal\\Channel 可作为接收分支 test10");
+}
+
+Log::info("Channel::select 测试完成");
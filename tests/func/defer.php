@@ -0,0 +1,158 @@
+<?php
+namespace tests\func;
+
+// 测试 defer 语句：后进先出、返回/抛出异常时执行、执行时求值、方法、闭包、生成器与 spawn
+
+// 按登记的逆序执行，在 return 求值之后执行，看到变量执行时的值
+function defer_order(array &$trace) {
+    $x = 1;
+    defer $trace[] = "first:$x";
+    defer function() use (&$trace, &$x) {
+        $trace[] = "closure:$x";
+    };
+    $x = 2;
+    $trace[] = "body";
+    return $x;
+}
+$trace = [];
+if (defer_order($trace) !== 2) {
+    Log::fatal("[FAIL] 按登记的逆序执行，在 return 求值之后执行，看到变量执行时的值 test1");
+} else {
+    Log::info("[PASS] 按登记的逆序执行，在 return 求值之后执行，看到变量执行时的值 test1");
+}
+if ($trace !== ['body', 'closure:2', 'first:2']) {
+    Log::fatal("[FAIL] 按登记的逆序执行，在 return 求值之后执行，看到变量执行时的值 test2");
+} else {
+    Log::info("[PASS] 按登记的逆序执行，在 return 求值之后执行，看到变量执行时的值 test2");
+}
+
+// 抛出异常时同样执行
+function defer_throws(array &$trace) {
+    defer $trace[] = "cleanup";
+    throw new \Exception('boom');
+}
+$trace = [];
+try {
+    defer_throws($trace);
+    Log::fatal("[FAIL] 抛出异常时同样执行: 未抛出预期的异常");
+} catch (\Exception $e) {
+    if (!($e->getMessage() === 'boom' && $trace === ['cleanup'])) {
+        Log::fatal("[FAIL] 抛出异常时同样执行 test3");
+    } else {
+        Log::info("[PASS] 抛出异常时同样执行 test3");
+    }
+}
+
+// 延迟执行项抛出的异常替换返回值，其余项仍执行
+function defer_replaces(array &$trace) {
+    defer $trace[] = "still runs";
+    defer throw new \RuntimeException('from defer');
+    return 5;
+}
+$trace = [];
+try {
+    defer_replaces($trace);
+    Log::fatal("[FAIL] 延迟执行项抛出的异常替换返回值，其余项仍执行: 未抛出预期的异常");
+} catch (\RuntimeException $e) {
+    if (!($e->getMessage() === 'from defer' && $trace === ['still runs'])) {
+        Log::fatal("[FAIL] 延迟执行项抛出的异常替换返回值，其余项仍执行 test4");
+    } else {
+        Log::info("[PASS] 延迟执行项抛出的异常替换返回值，其余项仍执行 test4");
+    }
+}
+
+// 方法中可以使用 $this，循环中的多项都登记在函数帧上
+class DeferResource {
+    public $log = [];
+
+    public function run() {
+        for ($i = 0; $i < 2; $i++) {
+            defer $this->log[] = "close $i";
+        }
+        $this->log[] = "open";
+        return count($this->log);
+    }
+}
+$res = new DeferResource();
+if ($res->run() !== 1) {
+    Log::fatal("[FAIL] 方法中可以使用 \$this，循环中的多项都登记在函数帧上 test5");
+} else {
+    Log::info("[PASS] 方法中可以使用 \$this，循环中的多项都登记在函数帧上 test5");
+}
+if ($res->log !== ['open', 'close 2', 'close 2']) {
+    Log::fatal("[FAIL] 方法中可以使用 \$this，循环中的多项都登记在函数帧上 test6");
+} else {
+    Log::info("[PASS] 方法中可以使用 \$this，循环中的多项都登记在函数帧上 test6");
+}
+
+// 闭包有自己的函数帧，不会提前执行外层登记的项
+function defer_nested(array &$trace) {
+    defer $trace[] = "outer";
+    (function() use (&$trace) {
+        defer $trace[] = "inner";
+    })();
+    $trace[] = "after inner";
+}
+$trace = [];
+defer_nested($trace);
+if ($trace !== ['inner', 'after inner', 'outer']) {
+    Log::fatal("[FAIL] 闭包有自己的函数帧，不会提前执行外层登记的项 test7");
+} else {
+    Log::info("[PASS] 闭包有自己的函数帧，不会提前执行外层登记的项 test7");
+}
+
+// 生成器在函数体执行完毕时执行
+function defer_gen(array &$trace) {
+    defer $trace[] = "gen done";
+    yield 1;
+    yield 2;
+}
+$trace = [];
+foreach (defer_gen($trace) as $v) {
+    $trace[] = "y$v";
+}
+if ($trace !== ['y1', 'y2', 'gen done']) {
+    Log::fatal("[FAIL] 生成器在函数体执行完毕时执行 test8");
+} else {
+    Log::info("[PASS] 生成器在函数体执行完毕时执行 test8");
+}
+
+// spawn 的闭包在协程结束时执行
+$ch = new \Channel(1);
+$f = spawn(function() use ($ch) {
+    defer $ch->send('deferred');
+    return 42;
+});
+if ($f->await() !== 42) {
+    Log::fatal("[FAIL] spawn 的闭包在协程结束时执行 test9");
+} else {
+    Log::info("[PASS] spawn 的闭包在协程结束时执行 test9");
+}
+if ($ch->receive() !== 'deferred') {
+    Log::fatal("[FAIL] spawn 的闭包在协程结束时执行 test10");
+} else {
+    Log::info("[PASS] spawn 的闭包在协程结束时执行 test10");
+}
+
+// defer 作为方法名、类常量名时是普通标识符
+class DeferNames {
+    const defer = 'const';
+    public function deferred() { return $this?->defer(); }
+    public function defer() { return 'method'; }
+}
+class DeferStaticNames {
+    public static function defer() { return 'static'; }
+}
+$names = new DeferNames();
+if (!(DeferNames::defer === 'const' && DeferStaticNames::defer() === 'static')) {
+    Log::fatal("[FAIL] defer 作为方法名、类常量名时是普通标识符 test11");
+} else {
+    Log::info("[PASS] defer 作为方法名、类常量名时是普通标识符 test11");
+}
+if (!($names->defer() === 'method' && $names->deferred() === 'method')) {
+    Log::fatal("[FAIL] defer 作为方法名、类常量名时是普通标识符 test12");
+} else {
+    Log::info("[PASS] defer 作为方法名、类常量名时是普通标识符 test12");
+}
+
+Log::info("defer 语句测试完成");
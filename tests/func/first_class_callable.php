@@ -0,0 +1,149 @@
+<?php
+namespace tests\func;
+
+// 测试一等可调用语法 strlen(...)、$obj->method(...)、Foo::bar(...) 与 Closure::fromCallable
+
+class Calc {
+    private $factor;
+    public function __construct($factor) {
+        $this->factor = $factor;
+    }
+    public function mul($x) {
+        return $x * $this->factor;
+    }
+    private function secret() {
+        return 'secret';
+    }
+    public function secretRef() {
+        return $this->secret(...);
+    }
+    public static function make() {
+        return static::class;
+    }
+    public function selfRef() {
+        return self::make(...);
+    }
+    public function staticRef() {
+        return static::make(...);
+    }
+}
+
+class SubCalc extends Calc {
+    public function parentRef() {
+        return parent::make(...);
+    }
+}
+
+function twice($x) {
+    return $x * 2;
+}
+
+// 内置函数与用户函数的闭包
+$len = strlen(...);
+if (!($len instanceof \Closure && $len('abcd') === 4)) {
+    Log::fatal("[FAIL] 内置函数与用户函数的闭包 test1");
+} else {
+    Log::info("[PASS] 内置函数与用户函数的闭包 test1");
+}
+if (array_map(strtoupper(...), ['a', 'b']) !== ['A', 'B']) {
+    Log::fatal("[FAIL] 内置函数与用户函数的闭包 test2");
+} else {
+    Log::info("[PASS] 内置函数与用户函数的闭包 test2");
+}
+
+$double = twice(...);
+if (array_map($double, [1, 2, 3]) !== [2, 4, 6]) {
+    Log::fatal("[FAIL] 内置函数与用户函数的闭包 test3");
+} else {
+    Log::info("[PASS] 内置函数与用户函数的闭包 test3");
+}
+
+// 方法闭包保留 $this
+$calc = new Calc(3);
+$mul = $calc->mul(...);
+if ($mul(2) !== 6) {
+    Log::fatal("[FAIL] 方法闭包保留 \$this test4");
+} else {
+    Log::info("[PASS] 方法闭包保留 \$this test4");
+}
+if ($calc->secretRef()() !== 'secret') {
+    Log::fatal("[FAIL] 方法闭包保留 \$this test5");
+} else {
+    Log::info("[PASS] 方法闭包保留 \$this test5");
+}
+
+try {
+    $calc->secret(...);
+    Log::fatal("[FAIL] 方法闭包保留 \$this: 未抛出预期的异常");
+} catch (\Error $e) {
+    if ($e->getMessage() !== 'Call to private method tests\func\Calc::secret() from global scope') {
+        Log::fatal("[FAIL] 方法闭包保留 \$this test6");
+    } else {
+        Log::info("[PASS] 方法闭包保留 \$this test6");
+    }
+}
+
+// 静态方法闭包保留后期静态绑定
+$make = SubCalc::make(...);
+if ($make() !== 'tests\func\SubCalc') {
+    Log::fatal("[FAIL] 静态方法闭包保留后期静态绑定 test7");
+} else {
+    Log::info("[PASS] 静态方法闭包保留后期静态绑定 test7");
+}
+$sub = new SubCalc(1);
+if ($sub->selfRef()() !== 'tests\func\SubCalc') {
+    Log::fatal("[FAIL] 静态方法闭包保留后期静态绑定 test8");
+} else {
+    Log::info("[PASS] 静态方法闭包保留后期静态绑定 test8");
+}
+if ($sub->staticRef()() !== 'tests\func\SubCalc') {
+    Log::fatal("[FAIL] 静态方法闭包保留后期静态绑定 test9");
+} else {
+    Log::info("[PASS] 静态方法闭包保留后期静态绑定 test9");
+}
+if ($sub->parentRef()() !== 'tests\func\SubCalc') {
+    Log::fatal("[FAIL] 静态方法闭包保留后期静态绑定 test10");
+} else {
+    Log::info("[PASS] 静态方法闭包保留后期静态绑定 test10");
+}
+
+// 作为 usort 回调
+$sorter = new class {
+    public function cmp($a, $b) {
+        return $a <=> $b;
+    }
+};
+$list = [3, 1, 2];
+usort($list, $sorter->cmp(...));
+if ($list !== [1, 2, 3]) {
+    Log::fatal("[FAIL] 作为 usort 回调 test11");
+} else {
+    Log::info("[PASS] 作为 usort 回调 test11");
+}
+
+// Closure::fromCallable
+$upper = \Closure::fromCallable('strtoupper');
+if ($upper('x') !== 'X') {
+    Log::fatal("[FAIL] Closure::fromCallable test12");
+} else {
+    Log::info("[PASS] Closure::fromCallable test12");
+}
+if (\Closure::fromCallable([$calc, 'mul'])(5) !== 15) {
+    Log::fatal("[FAIL] Closure::fromCallable test13");
+} else {
+    Log::info("[PASS] Closure::fromCallable test13");
+}
+if (\Closure::fromCallable('tests\func\SubCalc::make')() !== 'tests\func\SubCalc') {
+    Log::fatal("[FAIL] Closure::fromCallable test14");
+} else {
+    Log::info("[PASS] Closure::fromCallable test14");
+}
+
+try {
+    \Closure::fromCallable('no_such_function');
+    Log::fatal("[FAIL] Closure::fromCallable: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    Log::info("[PASS] Closure::fromCallable test15");
+}
+
+Log::info("一等可调用语法测试完成");
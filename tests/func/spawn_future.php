@@ -0,0 +1,169 @@
+<?php
+namespace tests\func;
+
+// 测试 spawn 返回的 Future：结果、参数、异常传递、超时、取消、then 与 all/any/race
+
+// await 返回闭包的返回值，spawn 的其余参数按位置传给闭包
+$f = spawn(function($a, $b) {
+    return $a + $b;
+}, 2, 3);
+if ($f->await() !== 5) {
+    Log::fatal("[FAIL] await 返回闭包的返回值，spawn 的其余参数按位置传给闭包 test1");
+} else {
+    Log::info("[PASS] await 返回闭包的返回值，spawn 的其余参数按位置传给闭包 test1");
+}
+if ($f->isDone() !== true) {
+    Log::fatal("[FAIL] await 返回闭包的返回值，spawn 的其余参数按位置传给闭包 test2");
+} else {
+    Log::info("[PASS] await 返回闭包的返回值，spawn 的其余参数按位置传给闭包 test2");
+}
+
+// 协程内未捕获的异常在 await 处重新抛出
+$f = spawn(function() {
+    throw new \Exception('boom');
+});
+try {
+    $f->await();
+    Log::fatal("[FAIL] 协程内未捕获的异常在 await 处重新抛出: 未抛出预期的异常");
+} catch (\Exception $e) {
+    if ($e->getMessage() !== 'boom') {
+        Log::fatal("[FAIL] 协程内未捕获的异常在 await 处重新抛出 test3");
+    } else {
+        Log::info("[PASS] 协程内未捕获的异常在 await 处重新抛出 test3");
+    }
+}
+
+// await 超时抛出 TimeoutError，Future 仍未完成
+$gate = new \Channel(1);
+$slow = spawn(function() use ($gate) {
+    return $gate->receive();
+});
+try {
+    $slow->await(0.05);
+    Log::fatal("[FAIL] await 超时抛出 TimeoutError，Future 仍未完成: 未抛出预期的异常");
+} catch (\TimeoutError $e) {
+    if ($slow->isDone() !== false) {
+        Log::fatal("[FAIL] await 超时抛出 TimeoutError，Future 仍未完成 test4");
+    } else {
+        Log::info("[PASS] await 超时抛出 TimeoutError，Future 仍未完成 test4");
+    }
+}
+$gate->send('late');
+if ($slow->await(1) !== 'late') {
+    Log::fatal("[FAIL] await 超时抛出 TimeoutError，Future 仍未完成 test5");
+} else {
+    Log::info("[PASS] await 超时抛出 TimeoutError，Future 仍未完成 test5");
+}
+
+// cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃
+$gate = new \Channel(1);
+$task = spawn(function() use ($gate) {
+    return $gate->receive();
+});
+if ($task->cancel() !== true) {
+    Log::fatal("[FAIL] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃 test6");
+} else {
+    Log::info("[PASS] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃 test6");
+}
+if ($task->cancel() !== false) {
+    Log::fatal("[FAIL] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃 test7");
+} else {
+    Log::info("[PASS] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃 test7");
+}
+$gate->send('ignored');
+try {
+    $task->await();
+    Log::fatal("[FAIL] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃: 未抛出预期的异常");
+} catch (\CancelledError $e) {
+    if (!$task->isDone()) {
+        Log::fatal("[FAIL] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃 test8");
+    } else {
+        Log::info("[PASS] cancel 之后 await 抛出 CancelledError，任务稍后的结果被丢弃 test8");
+    }
+}
+
+// then 以结果调用回调，失败时跳过回调直接传递异常
+if (spawn(function() { return 20; })
+    ->then(function($v) { return $v + 1; })
+    ->then(function($v) { return $v * 2; })
+    ->await() !== 42) {
+    Log::fatal("[FAIL] then 以结果调用回调，失败时跳过回调直接传递异常 test9");
+} else {
+    Log::info("[PASS] then 以结果调用回调，失败时跳过回调直接传递异常 test9");
+}
+$called = false;
+try {
+    spawn(function() { throw new \Exception('first'); })
+        ->then(function($v) use (&$called) { $called = true; })
+        ->await();
+    Log::fatal("[FAIL] then 以结果调用回调，失败时跳过回调直接传递异常: 未抛出预期的异常");
+} catch (\Exception $e) {
+    if (!($e->getMessage() === 'first' && $called === false)) {
+        Log::fatal("[FAIL] then 以结果调用回调，失败时跳过回调直接传递异常 test10");
+    } else {
+        Log::info("[PASS] then 以结果调用回调，失败时跳过回调直接传递异常 test10");
+    }
+}
+
+// all 按原来的键返回全部结果，任一失败时抛出
+$all = \Future::all([
+    spawn(function() { return 'a'; }),
+    spawn(function() { return 'b'; }),
+]);
+if ($all !== ['a', 'b']) {
+    Log::fatal("[FAIL] all 按原来的键返回全部结果，任一失败时抛出 test11");
+} else {
+    Log::info("[PASS] all 按原来的键返回全部结果，任一失败时抛出 test11");
+}
+$named = \Future::all([
+    'x' => spawn(function() { return 1; }),
+    'y' => spawn(function() { return 2; }),
+]);
+if (!($named['x'] === 1 && $named['y'] === 2)) {
+    Log::fatal("[FAIL] all 按原来的键返回全部结果，任一失败时抛出 test12");
+} else {
+    Log::info("[PASS] all 按原来的键返回全部结果，任一失败时抛出 test12");
+}
+try {
+    \Future::all([
+        spawn(function() { return 1; }),
+        spawn(function() { throw new \Exception('all failed'); }),
+    ]);
+    Log::fatal("[FAIL] all 按原来的键返回全部结果，任一失败时抛出: 未抛出预期的异常");
+} catch (\Exception $e) {
+    if ($e->getMessage() !== 'all failed') {
+        Log::fatal("[FAIL] all 按原来的键返回全部结果，任一失败时抛出 test13");
+    } else {
+        Log::info("[PASS] all 按原来的键返回全部结果，任一失败时抛出 test13");
+    }
+}
+
+// any 返回第一个成功的结果，race 返回第一个完成的结果
+$gate = new \Channel(1);
+if (\Future::any([
+    spawn(function() { throw new \Exception('no'); }),
+    spawn(function() { return 'yes'; }),
+]) !== 'yes') {
+    Log::fatal("[FAIL] any 返回第一个成功的结果，race 返回第一个完成的结果 test14");
+} else {
+    Log::info("[PASS] any 返回第一个成功的结果，race 返回第一个完成的结果 test14");
+}
+if (\Future::race([
+    spawn(function() use ($gate) { return $gate->receive(); }),
+    spawn(function() { return 'fast'; }),
+]) !== 'fast') {
+    Log::fatal("[FAIL] any 返回第一个成功的结果，race 返回第一个完成的结果 test15");
+} else {
+    Log::info("[PASS] any 返回第一个成功的结果，race 返回第一个完成的结果 test15");
+}
+$gate->send(1);
+
+// Future 只能由 spawn 创建
+try {
+    new \Future();
+    Log::fatal("[FAIL] Future 只能由 spawn 创建: 未抛出预期的异常");
+} catch (\FutureError $e) {
+    Log::info("[PASS] Future 只能由 spawn 创建 test16");
+}
+
+Log::info("spawn Future 测试完成");
@@ -0,0 +1,97 @@
+<?php
+namespace tests\func;
+
+// 测试 spawn 协程的执行状态隔离：调用深度、输出缓冲、static 局部变量、异常处理回调
+
+// 调用深度按协程计数：主流程与三个协程同时停在第 300 层，合计超过 500 层也不会触发递归限制
+class RoutineDeep {
+    public function __construct(public $ready, public $go) {}
+
+    public function down(int $n, bool $main): int {
+        if ($n === 0) {
+            if ($main) {
+                $this->ready->receive();
+                $this->ready->receive();
+                $this->ready->receive();
+                $this->go->send(1);
+                $this->go->send(1);
+                $this->go->send(1);
+            } else {
+                $this->ready->send(1);
+                $this->go->receive();
+            }
+            return 0;
+        }
+        return 1 + $this->down($n - 1, $main);
+    }
+}
+
+$deep = new RoutineDeep(new \Channel(3), new \Channel(3));
+$depths = new \Channel(3);
+for ($k = 0; $k < 3; $k += 1) {
+    spawn(function() use ($deep, $depths) {
+        $depths->send($deep->down(300, false));
+    });
+}
+if ($deep->down(300, true) !== 300) {
+    Log::fatal("[FAIL] 调用深度按协程计数：主流程与三个协程同时停在第 300 层，合计超过 500 层也不会触发递归限制 test1");
+} else {
+    Log::info("[PASS] 调用深度按协程计数：主流程与三个协程同时停在第 300 层，合计超过 500 层也不会触发递归限制 test1");
+}
+if ($depths->receive() + $depths->receive() + $depths->receive() !== 900) {
+    Log::fatal("[FAIL] 调用深度按协程计数：主流程与三个协程同时停在第 300 层，合计超过 500 层也不会触发递归限制 test2");
+} else {
+    Log::info("[PASS] 调用深度按协程计数：主流程与三个协程同时停在第 300 层，合计超过 500 层也不会触发递归限制 test2");
+}
+
+// 输出缓冲按协程隔离：协程内的 ob_start 不会截获主流程的输出，反之亦然
+$out = new \Channel(1);
+ob_start();
+echo "main";
+spawn(function() use ($out) {
+    $level = ob_get_level();
+    ob_start();
+    echo "child";
+    $out->send($level . ':' . ob_get_clean());
+});
+$child = $out->receive();
+$main = ob_get_clean();
+if (!($child === '0:child' && $main === 'main' && ob_get_level() === 0)) {
+    Log::fatal("[FAIL] 输出缓冲按协程隔离：协程内的 ob_start 不会截获主流程的输出，反之亦然 test3");
+} else {
+    Log::info("[PASS] 输出缓冲按协程隔离：协程内的 ob_start 不会截获主流程的输出，反之亦然 test3");
+}
+
+// static 局部变量按协程隔离
+function routineCounter(): int {
+    static $n = 0;
+    $n = $n + 1;
+    return $n;
+}
+routineCounter();
+routineCounter();
+$counts = new \Channel(1);
+spawn(function() use ($counts) {
+    routineCounter();
+    $counts->send(routineCounter());
+});
+if (!($counts->receive() === 2 && routineCounter() === 3)) {
+    Log::fatal("[FAIL] static 局部变量按协程隔离 test4");
+} else {
+    Log::info("[PASS] static 局部变量按协程隔离 test4");
+}
+
+// 协程继承创建时的异常处理回调；协程内替换回调不影响主流程
+$handler = function($e) {};
+set_exception_handler($handler);
+$inherited = spawn(function() {
+    return set_exception_handler(function($e) {}) !== null;
+})->await();
+if (!($inherited && set_exception_handler(null) === $handler)) {
+    Log::fatal("[FAIL] 协程继承创建时的异常处理回调；协程内替换回调不影响主流程 test5");
+} else {
+    Log::info("[PASS] 协程继承创建时的异常处理回调；协程内替换回调不影响主流程 test5");
+}
+restore_exception_handler();
+
+Log::info("spawn 协程执行状态隔离测试完成");
@@ -0,0 +1,191 @@
+<?php
+namespace tests\func;
+
+// 测试 Sync\ 并发原语：WaitGroup、Mutex、RWMutex、Once、Semaphore、AtomicInt、Map
+
+// WaitGroup 等待全部协程结束；AtomicInt 在协程间安全计数
+$wg = new \Sync\WaitGroup();
+$counter = new \Sync\AtomicInt();
+for ($k = 0; $k < 8; $k += 1) {
+    $wg->add();
+    spawn(function() use ($wg, $counter) {
+        for ($j = 0; $j < 100; $j += 1) {
+            $counter->increment();
+        }
+        $wg->done();
+    });
+}
+$wg->wait();
+if ($counter->get() !== 800) {
+    Log::fatal("[FAIL] WaitGroup 等待全部协程结束；AtomicInt 在协程间安全计数 test1");
+} else {
+    Log::info("[PASS] WaitGroup 等待全部协程结束；AtomicInt 在协程间安全计数 test1");
+}
+
+// 计数器变为负数抛出异常
+try {
+    $wg->done();
+    Log::fatal("[FAIL] 计数器变为负数抛出异常: 未抛出预期的异常");
+} catch (\Exception $e) {
+    Log::info("[PASS] 计数器变为负数抛出异常 test2");
+}
+
+// Mutex::withLock 保护普通数组的读改写，返回闭包的结果
+$mu = new \Sync\Mutex();
+$box = new \Sync\Map();
+$box->set('n', 0);
+$wg = new \Sync\WaitGroup();
+$wg->add(4);
+for ($k = 0; $k < 4; $k += 1) {
+    spawn(function() use ($wg, $mu, $box) {
+        for ($j = 0; $j < 50; $j += 1) {
+            $mu->withLock(function() use ($box) {
+                $box->set('n', $box->get('n') + 1);
+            });
+        }
+        $wg->done();
+    });
+}
+$wg->wait();
+if ($box->get('n') !== 200) {
+    Log::fatal("[FAIL] Mutex::withLock 保护普通数组的读改写，返回闭包的结果 test3");
+} else {
+    Log::info("[PASS] Mutex::withLock 保护普通数组的读改写，返回闭包的结果 test3");
+}
+if ($mu->withLock(function() { return 'locked'; }) !== 'locked') {
+    Log::fatal("[FAIL] Mutex::withLock 保护普通数组的读改写，返回闭包的结果 test4");
+} else {
+    Log::info("[PASS] Mutex::withLock 保护普通数组的读改写，返回闭包的结果 test4");
+}
+
+// 闭包抛出异常后锁被释放
+try {
+    $mu->withLock(function() { throw new \Exception('inside'); });
+} catch (\Exception $e) {
+}
+if ($mu->tryLock() !== true) {
+    Log::fatal("[FAIL] 闭包抛出异常后锁被释放 test5");
+} else {
+    Log::info("[PASS] 闭包抛出异常后锁被释放 test5");
+}
+$mu->unlock();
+
+// 解锁未加锁的 Mutex 抛出异常
+try {
+    $mu->unlock();
+    Log::fatal("[FAIL] 解锁未加锁的 Mutex 抛出异常: 未抛出预期的异常");
+} catch (\Exception $e) {
+    Log::info("[PASS] 解锁未加锁的 Mutex 抛出异常 test6");
+}
+
+// RWMutex：读锁可共享，写锁互斥
+$rw = new \Sync\RWMutex();
+$rw->rLock();
+if (!($rw->tryRLock() === true && $rw->tryLock() === false)) {
+    Log::fatal("[FAIL] RWMutex：读锁可共享，写锁互斥 test7");
+} else {
+    Log::info("[PASS] RWMutex：读锁可共享，写锁互斥 test7");
+}
+$rw->rUnlock();
+$rw->rUnlock();
+if ($rw->withLock(function() { return 1; }) !== 1) {
+    Log::fatal("[FAIL] RWMutex：读锁可共享，写锁互斥 test8");
+} else {
+    Log::info("[PASS] RWMutex：读锁可共享，写锁互斥 test8");
+}
+if ($rw->withRLock(function() { return 2; }) !== 2) {
+    Log::fatal("[FAIL] RWMutex：读锁可共享，写锁互斥 test9");
+} else {
+    Log::info("[PASS] RWMutex：读锁可共享，写锁互斥 test9");
+}
+
+// Once 只执行一次，之后返回第一次的结果
+$once = new \Sync\Once();
+$calls = new \Sync\AtomicInt(0);
+$first = $once->do(function() use ($calls) { $calls->increment(); return 'first'; });
+$second = $once->do(function() use ($calls) { $calls->increment(); return 'second'; });
+if (!($first === 'first' && $second === 'first' && $calls->get() === 1 && $once->isDone())) {
+    Log::fatal("[FAIL] Once 只执行一次，之后返回第一次的结果 test10");
+} else {
+    Log::info("[PASS] Once 只执行一次，之后返回第一次的结果 test10");
+}
+
+// Semaphore 限制并发数
+$sem = new \Sync\Semaphore(2);
+$active = new \Sync\AtomicInt();
+$peak = new \Sync\AtomicInt();
+$wg = new \Sync\WaitGroup();
+$wg->add(6);
+for ($k = 0; $k < 6; $k += 1) {
+    spawn(function() use ($sem, $active, $peak, $wg) {
+        $sem->withPermit(function() use ($active, $peak) {
+            $now = $active->increment();
+            $seen = $peak->get();
+            while ($now > $seen && !$peak->compareAndSwap($seen, $now)) {
+                $seen = $peak->get();
+            }
+            sleep(0.01);
+            $active->decrement();
+        });
+        $wg->done();
+    });
+}
+$wg->wait();
+if (!($peak->get() <= 2 && $sem->available() === 2)) {
+    Log::fatal("[FAIL] Semaphore 限制并发数 test11");
+} else {
+    Log::info("[PASS] Semaphore 限制并发数 test11");
+}
+if (!($sem->tryAcquire(2) === true && $sem->tryAcquire() === false)) {
+    Log::fatal("[FAIL] Semaphore 限制并发数 test12");
+} else {
+    Log::info("[PASS] Semaphore 限制并发数 test12");
+}
+$sem->release(2);
+try {
+    $sem->release();
+    Log::fatal("[FAIL] Semaphore 限制并发数: 未抛出预期的异常");
+} catch (\Exception $e) {
+    Log::info("[PASS] Semaphore 限制并发数 test13");
+}
+
+// AtomicInt 的其它操作
+$a = new \Sync\AtomicInt(5);
+if (!($a->add(3) === 8 && $a->swap(1) === 8 && $a->decrement() === 0)) {
+    Log::fatal("[FAIL] AtomicInt 的其它操作 test14");
+} else {
+    Log::info("[PASS] AtomicInt 的其它操作 test14");
+}
+
+// Sync\Map
+$m = new \Sync\Map();
+$m->set('b', 2);
+$m->set('a', 1);
+if (!($m->getOrSet('a', 9) === 1 && $m->getOrSet('c', 3) === 3)) {
+    Log::fatal("[FAIL] Sync\\Map test15");
+} else {
+    Log::info("[PASS] Sync\\Map test15");
+}
+if (!($m->has('c') && $m->get('x', 'none') === 'none' && $m->count() === 3)) {
+    Log::fatal("[FAIL] Sync\\Map test16");
+} else {
+    Log::info("[PASS] Sync\\Map test16");
+}
+$m->delete('c');
+if (!($m->keys() === ['a', 'b'] && $m->toArray() === ['a' => 1, 'b' => 2])) {
+    Log::fatal("[FAIL] Sync\\Map test17");
+} else {
+    Log::info("[PASS] Sync\\Map test17");
+}
+$seen = [];
+$m->forEach(function($value, $key) use (&$seen) {
+    $seen[] = $key . '=' . $value;
+    return false;
+});
+if ($seen !== ['a=1']) {
+    Log::fatal("[FAIL] Sync\\Map test18");
+} else {
+    Log::info("[PASS] Sync\\Map test18");
+}
+
+Log::info("Sync 并发原语测试完成");
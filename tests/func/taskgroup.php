@@ -0,0 +1,160 @@
+<?php
+namespace tests\func;
+
+// 测试 TaskGroup：结果顺序、第一个失败取消其余任务、并发上限、取消传递到阻塞操作、context 函数
+
+// wait 按 spawn 顺序返回结果，spawn 的其余参数按位置传给闭包
+$group = new \TaskGroup();
+foreach ([30, 10, 20] as $ms) {
+    $group->spawn(function($ms) {
+        usleep($ms * 1000);
+        return $ms;
+    }, $ms);
+}
+if ($group->wait() !== [30, 10, 20]) {
+    Log::fatal("[FAIL] wait 按 spawn 顺序返回结果，spawn 的其余参数按位置传给闭包 test1");
+} else {
+    Log::info("[PASS] wait 按 spawn 顺序返回结果，spawn 的其余参数按位置传给闭包 test1");
+}
+
+// 第一个失败的异常由 wait 抛出，阻塞在 receive 的兄弟任务被取消
+$group = new \TaskGroup();
+$never = new \Channel();
+$sibling = $group->spawn(function() use ($never) {
+    return $never->receive();
+});
+$group->spawn(function() {
+    usleep(10000);
+    throw new \Exception('first');
+});
+try {
+    $group->wait();
+    Log::fatal("[FAIL] 第一个失败的异常由 wait 抛出，阻塞在 receive 的兄弟任务被取消: 未抛出预期的异常");
+} catch (\Exception $e) {
+    if ($e->getMessage() !== 'first') {
+        Log::fatal("[FAIL] 第一个失败的异常由 wait 抛出，阻塞在 receive 的兄弟任务被取消 test2");
+    } else {
+        Log::info("[PASS] 第一个失败的异常由 wait 抛出，阻塞在 receive 的兄弟任务被取消 test2");
+    }
+}
+try {
+    $sibling->await();
+    Log::fatal("[FAIL] 第一个失败的异常由 wait 抛出，阻塞在 receive 的兄弟任务被取消: 未抛出预期的异常");
+} catch (\CancelledError $e) {
+    Log::info("[PASS] 第一个失败的异常由 wait 抛出，阻塞在 receive 的兄弟任务被取消 test3");
+}
+
+// sleep 与 Channel::select 同样响应取消
+$group = new \TaskGroup();
+$slept = $group->spawn(function() {
+    sleep(30);
+    return 'woke';
+});
+$selected = $group->spawn(function() use ($never) {
+    return \Channel::select([$never]);
+});
+usleep(10000);
+$group->cancel('stop');
+foreach ([$slept, $selected] as $f) {
+    try {
+        $f->await(1);
+        Log::fatal("[FAIL] sleep 与 Channel::select 同样响应取消: 未抛出预期的异常");
+    } catch (\CancelledError $e) {
+        if ($e->getMessage() !== 'stop') {
+            Log::fatal("[FAIL] sleep 与 Channel::select 同样响应取消 test4");
+        } else {
+            Log::info("[PASS] sleep 与 Channel::select 同样响应取消 test4");
+        }
+    }
+}
+try {
+    $group->wait();
+    Log::fatal("[FAIL] sleep 与 Channel::select 同样响应取消: 未抛出预期的异常");
+} catch (\CancelledError $e) {
+    Log::info("[PASS] sleep 与 Channel::select 同样响应取消 test5");
+}
+
+// limit 限制同时执行的任务数
+$group = new \TaskGroup(null, 2);
+$lock = new \Sync\Mutex();
+$running = new \Sync\AtomicInt();
+$peak = new \Sync\AtomicInt();
+for ($i = 0; $i < 6; $i += 1) {
+    $group->spawn(function() use ($running, $peak, $lock) {
+        $now = $running->increment();
+        $lock->withLock(function() use ($peak, $now) {
+            if ($now > $peak->get()) {
+                $peak->set($now);
+            }
+        });
+        usleep(20000);
+        $running->decrement();
+        return $now;
+    });
+}
+if (count($group->wait()) !== 6) {
+    Log::fatal("[FAIL] limit 限制同时执行的任务数 test6");
+} else {
+    Log::info("[PASS] limit 限制同时执行的任务数 test6");
+}
+if ($peak->get() !== 2) {
+    Log::fatal("[FAIL] limit 限制同时执行的任务数 test7");
+} else {
+    Log::info("[PASS] limit 限制同时执行的任务数 test7");
+}
+
+// 组的 Context 可作为 Channel::select 的分支，被取消时就绪（与 Go 的 <-ctx.Done() 相同，ok 为 false）
+$group = new \TaskGroup();
+$group->cancel();
+[$key, $value, $ok] = \Channel::select(['done' => $group->context()], 1);
+if (!($key === 'done' && $ok === false)) {
+    Log::fatal("[FAIL] 组的 Context 可作为 Channel::select 的分支，被取消时就绪（与 Go 的 <-ctx.Done() 相同，ok 为 false） test8");
+} else {
+    Log::info("[PASS] 组的 Context 可作为 Channel::select 的分支，被取消时就绪（与 Go 的 <-ctx.Done() 相同，ok 为 false） test8");
+}
+
+// parent Context 被取消时组内任务一并取消
+[$ctx, $cancel] = \context\withCancelCause(\context\background());
+$group = new \TaskGroup($ctx);
+$task = $group->spawn(function() use ($never) {
+    return $never->receive();
+});
+$cancel('parent gone');
+try {
+    $task->await(1);
+    Log::fatal("[FAIL] parent Context 被取消时组内任务一并取消: 未抛出预期的异常");
+} catch (\CancelledError $e) {
+    if ($e->getMessage() !== 'parent gone') {
+        Log::fatal("[FAIL] parent Context 被取消时组内任务一并取消 test9");
+    } else {
+        Log::info("[PASS] parent Context 被取消时组内任务一并取消 test9");
+    }
+}
+
+// 单独 cancel 一个任务不影响组内其它任务
+$group = new \TaskGroup();
+$lone = $group->spawn(function() use ($never) {
+    return $never->receive();
+});
+$group->spawn(function() {
+    return 'ok';
+});
+$lone->cancel();
+if ($group->wait() !== [null, 'ok']) {
+    Log::fatal("[FAIL] 单独 cancel 一个任务不影响组内其它任务 test10");
+} else {
+    Log::info("[PASS] 单独 cancel 一个任务不影响组内其它任务 test10");
+}
+
+// 任务中 context\current() 是任务自己的 Context
+$f = spawn(function() use ($never) {
+    return \Channel::select([\context\current(), $never]);
+});
+$f->cancel();
+if (!$f->isDone()) {
+    Log::fatal("[FAIL] 任务中 context\\current() 是任务自己的 Context test11");
+} else {
+    Log::info("[PASS] 任务中 context\\current() 是任务自己的 Context test11");
+}
+
+Log::info("TaskGroup 测试完成");
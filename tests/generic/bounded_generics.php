@@ -0,0 +1,123 @@
+<?php
+namespace tests\generic;
+
+// 测试泛型约束、泛型函数以及按类型实参检查参数与返回值
+
+interface Entity {
+    public function id(): int;
+}
+
+class GenUser implements Entity {
+    public function __construct(public int $uid) {}
+    public function id(): int { return $this->uid; }
+}
+
+class GenOrder implements Entity {
+    public function __construct(public int $no) {}
+    public function id(): int { return $this->no; }
+}
+
+class GenPlain {}
+
+class Repo<T extends Entity> {
+    public array $items = [];
+    public ?T $last = null;
+
+    public function add(T $item): static {
+        $this->items[] = $item;
+        $this->last = $item;
+        return $this;
+    }
+
+    public function first(): T {
+        return $this->items[0];
+    }
+
+    public function ids(): array<int> {
+        return array_map(fn($e) => $e->id(), $this->items);
+    }
+}
+
+$users = new Repo<GenUser>();
+$orders = new Repo<GenOrder>();
+$users->add(new GenUser(1))->add(new GenUser(2));
+$orders->add(new GenOrder(7));
+
+$errors = [];
+try { $users->add(new GenOrder(3)); } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+try { $orders->last = new GenUser(4); } catch (\TypeError $e) { $errors[] = 'last'; }
+try { new Repo<GenPlain>(); } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+
+if ($users->first()->id() == 1 && $orders->first()->id() == 7
+    && implode(',', $users->ids()) == '1,2' && count($errors) == 3
+    && $errors[0] == 'tests\generic\Repo::add(): Argument #1 ($item) must be of type tests\generic\GenUser, tests\generic\GenOrder given'
+    && $errors[1] == 'last'
+    && $errors[2] == 'tests\generic\Repo<tests\generic\GenPlain>: type argument tests\generic\GenPlain for T must be a subtype of tests\generic\Entity') {
+    Log::info("泛型类约束测试通过");
+} else {
+    Log::fatal("泛型类约束测试失败", $errors);
+}
+
+function map<T, R>(array<T> $xs, callable $f): array<R> {
+    $out = [];
+    foreach ($xs as $x) {
+        $out[] = $f($x);
+    }
+    return $out;
+}
+
+function pair<T>(T $a, T $b): array<T> {
+    return [$a, $b];
+}
+
+function firstOf<T extends Entity>(array<T> $xs): T {
+    return $xs[0];
+}
+
+function broken<T>(T $a): T {
+    return 'broken';
+}
+
+$errors = [];
+$doubled = map([1, 2, 3], fn($x) => $x * 2);
+$names = pair('a', 'b');
+try { pair(1, new GenPlain()); } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+try { map([1, 'x'], fn($x) => $x); } catch (\TypeError $e) { $errors[] = 'array'; }
+try { firstOf([new GenPlain()]); } catch (\TypeError $e) { $errors[] = 'bound'; }
+try { broken(1); } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+
+if (implode(',', $doubled) == '2,4,6' && implode(',', $names) == 'a,b'
+    && firstOf([new GenOrder(9)])->id() == 9 && count($errors) == 4
+    && $errors[0] == 'tests\generic\pair(): Argument #2 ($b) must be of type int, tests\generic\GenPlain given'
+    && $errors[1] == 'array' && $errors[2] == 'bound'
+    && $errors[3] == 'tests\generic\broken(): Return value must be of type int, string returned') {
+    Log::info("泛型函数测试通过");
+} else {
+    Log::fatal("泛型函数测试失败", $errors);
+}
+
+class Box<T> {
+    public function __construct(public T $value) {}
+
+    public function map<U>(callable $f) {
+        return new Box($f($this->value));
+    }
+
+    public function get(): T {
+        return $this->value;
+    }
+}
+
+$ints = new Box<int>(5);
+$strs = new Box<string>('x');
+$errors = [];
+try { $ints->value = 'abc'; } catch (\TypeError $e) { $errors[] = 'int'; }
+try { new Box<int>('abc'); } catch (\TypeError $e) { $errors[] = 'ctor'; }
+$strs->value = 'y';
+
+if ($ints->get() === 5 && $strs->get() === 'y' && $ints->map(fn($v) => $v + 1)->get() == 6
+    && count($errors) == 2) {
+    Log::info("泛型实例独立类型测试通过");
+} else {
+    Log::fatal("泛型实例独立类型测试失败", $errors);
+}
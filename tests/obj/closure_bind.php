@@ -0,0 +1,91 @@
+<?php
+namespace tests\obj;
+
+// 测试 Closure::bind、bindTo、call：换绑 $this 与作用域、静态闭包不能绑定对象
+
+class BindCounter {
+    private $count = 0;
+    public function getter() {
+        return function () {
+            return $this->count;
+        };
+    }
+}
+
+$peek = function () {
+    return $this->count;
+};
+
+$c1 = new BindCounter();
+$c2 = new BindCounter();
+
+// Closure::bind 换绑 $this 与作用域后可以读取私有属性
+$bound = \Closure::bind($peek, $c1, BindCounter::class);
+if ($bound() !== 0) {
+    Log::fatal("[FAIL] Closure::bind 换绑 \$this 与作用域后可以读取私有属性 test1");
+} else {
+    Log::info("[PASS] Closure::bind 换绑 \$this 与作用域后可以读取私有属性 test1");
+}
+
+// call 临时绑定对象并立即调用
+$inc = function ($by) {
+    $this->count += $by;
+    return $this->count;
+};
+if ($inc->call($c2, 5) !== 5) {
+    Log::fatal("[FAIL] call 临时绑定对象并立即调用 test2");
+} else {
+    Log::info("[PASS] call 临时绑定对象并立即调用 test2");
+}
+if ($inc->call($c2, 2) !== 7) {
+    Log::fatal("[FAIL] call 临时绑定对象并立即调用 test3");
+} else {
+    Log::info("[PASS] call 临时绑定对象并立即调用 test3");
+}
+
+// bindTo 以对象作为作用域
+$toC2 = $peek->bindTo($c2, $c2);
+if ($toC2() !== 7) {
+    Log::fatal("[FAIL] bindTo 以对象作为作用域 test4");
+} else {
+    Log::info("[PASS] bindTo 以对象作为作用域 test4");
+}
+
+// 类中定义的闭包换绑到另一个对象
+$g = $c1->getter();
+if ($g() !== 0) {
+    Log::fatal("[FAIL] 类中定义的闭包换绑到另一个对象 test5");
+} else {
+    Log::info("[PASS] 类中定义的闭包换绑到另一个对象 test5");
+}
+if ($g->bindTo($c2)() !== 7) {
+    Log::fatal("[FAIL] 类中定义的闭包换绑到另一个对象 test6");
+} else {
+    Log::info("[PASS] 类中定义的闭包换绑到另一个对象 test6");
+}
+
+// 静态闭包不能绑定对象
+$static = static function () {
+    return 1;
+};
+if ($static->bindTo($c1) !== null) {
+    Log::fatal("[FAIL] 静态闭包不能绑定对象 test7");
+} else {
+    Log::info("[PASS] 静态闭包不能绑定对象 test7");
+}
+if ($static->bindTo(null)() !== 1) {
+    Log::fatal("[FAIL] 静态闭包不能绑定对象 test8");
+} else {
+    Log::info("[PASS] 静态闭包不能绑定对象 test8");
+}
+
+// 解绑 $this 后不能再访问
+$unbound = $peek->bindTo(null, BindCounter::class);
+try {
+    $unbound();
+    Log::fatal("[FAIL] 解绑 \$this 后不能再访问: 未抛出预期的异常");
+} catch (\Error $e) {
+    Log::info("[PASS] 解绑 \$this 后不能再访问 test9");
+}
+
+Log::info("闭包绑定测试完成");
@@ -0,0 +1,170 @@
+<?php
+namespace tests\obj;
+
+// 测试析构函数的执行时机：unset、覆盖变量、离开函数作用域、赋值给其它变量、数组持有、构造函数抛出异常与闭包捕获
+
+$log = [];
+
+class DestructProbe {
+    public $name;
+    public function __construct($name) {
+        $this->name = $name;
+    }
+    public function __destruct() {
+        global $log;
+        $log[] = $this->name;
+    }
+}
+
+class DestructThrows {
+    public function __construct() {
+        throw new \Exception('ctor');
+    }
+    public function __destruct() {
+        global $log;
+        $log[] = 'throws';
+    }
+}
+
+class DestructIdentity {
+    public function __destruct() {
+        global $log;
+        $log[] = spl_object_id($this);
+        $log[] = spl_object_hash($this);
+    }
+}
+
+// unset 后立即析构
+$a = new DestructProbe('a');
+unset($a);
+if ($log !== ['a']) {
+    Log::fatal("[FAIL] unset 后立即析构 test1");
+} else {
+    Log::info("[PASS] unset 后立即析构 test1");
+}
+
+// 覆盖变量时旧对象立即析构
+$log = [];
+$b = new DestructProbe('b1');
+$b = new DestructProbe('b2');
+if ($log !== ['b1']) {
+    Log::fatal("[FAIL] 覆盖变量时旧对象立即析构 test2");
+} else {
+    Log::info("[PASS] 覆盖变量时旧对象立即析构 test2");
+}
+$b = null;
+if ($log !== ['b1', 'b2']) {
+    Log::fatal("[FAIL] 覆盖变量时旧对象立即析构 test3");
+} else {
+    Log::info("[PASS] 覆盖变量时旧对象立即析构 test3");
+}
+
+// 离开函数作用域时析构局部对象
+function destructScope() {
+    global $log;
+    $x = new DestructProbe('local');
+    $log[] = 'body';
+}
+$log = [];
+destructScope();
+if ($log !== ['body', 'local']) {
+    Log::fatal("[FAIL] 离开函数作用域时析构局部对象 test4");
+} else {
+    Log::info("[PASS] 离开函数作用域时析构局部对象 test4");
+}
+
+// 还有其它变量持有时不析构
+$log = [];
+$c = new DestructProbe('c');
+$d = $c;
+unset($c);
+if ($log !== []) {
+    Log::fatal("[FAIL] 还有其它变量持有时不析构 test5");
+} else {
+    Log::info("[PASS] 还有其它变量持有时不析构 test5");
+}
+unset($d);
+if ($log !== ['c']) {
+    Log::fatal("[FAIL] 还有其它变量持有时不析构 test6");
+} else {
+    Log::info("[PASS] 还有其它变量持有时不析构 test6");
+}
+
+// 数组持有的对象随数组释放
+$log = [];
+$list = [new DestructProbe('e')];
+if ($log !== []) {
+    Log::fatal("[FAIL] 数组持有的对象随数组释放 test7");
+} else {
+    Log::info("[PASS] 数组持有的对象随数组释放 test7");
+}
+$list = [];
+if ($log !== ['e']) {
+    Log::fatal("[FAIL] 数组持有的对象随数组释放 test8");
+} else {
+    Log::info("[PASS] 数组持有的对象随数组释放 test8");
+}
+
+// 构造函数抛出异常时不执行析构函数
+$log = [];
+try {
+    $t = new DestructThrows();
+} catch (\Exception $ex) {
+}
+if ($log !== []) {
+    Log::fatal("[FAIL] 构造函数抛出异常时不执行析构函数 test9");
+} else {
+    Log::info("[PASS] 构造函数抛出异常时不执行析构函数 test9");
+}
+
+// 闭包按值捕获的对象在闭包释放后析构
+$log = [];
+$f = new DestructProbe('f');
+$fn = function () use ($f) {
+    return $f->name;
+};
+unset($f);
+if (!($log === [] && $fn() === 'f')) {
+    Log::fatal("[FAIL] 闭包按值捕获的对象在闭包释放后析构 test10");
+} else {
+    Log::info("[PASS] 闭包按值捕获的对象在闭包释放后析构 test10");
+}
+unset($fn);
+if ($log !== ['f']) {
+    Log::fatal("[FAIL] 闭包按值捕获的对象在闭包释放后析构 test11");
+} else {
+    Log::info("[PASS] 闭包按值捕获的对象在闭包释放后析构 test11");
+}
+
+// 返回 $this 的链式调用不提前析构
+$log = [];
+$g = new DestructProbe('g');
+$h = (function ($o) { return $o; })($g);
+unset($g);
+if (!($log === [] && $h->name === 'g')) {
+    Log::fatal("[FAIL] 返回 \$this 的链式调用不提前析构 test12");
+} else {
+    Log::info("[PASS] 返回 \$this 的链式调用不提前析构 test12");
+}
+unset($h);
+if ($log !== ['g']) {
+    Log::fatal("[FAIL] 返回 \$this 的链式调用不提前析构 test13");
+} else {
+    Log::info("[PASS] 返回 \$this 的链式调用不提前析构 test13");
+}
+
+// 经 GC 确认不可达后析构的对象保持原来的 spl_object_id 与 spl_object_hash
+$log = [];
+$i = new DestructIdentity();
+$identity = [spl_object_id($i), spl_object_hash($i)];
+$held = [$i];
+unset($i, $held);
+$step = 1;
+$step = 2;
+if ($log !== $identity) {
+    Log::fatal("[FAIL] 经 GC 确认不可达后析构的对象保持原来的 spl_object_id 与 spl_object_hash test14");
+} else {
+    Log::info("[PASS] 经 GC 确认不可达后析构的对象保持原来的 spl_object_id 与 spl_object_hash test14");
+}
+
+Log::info("析构函数测试完成");
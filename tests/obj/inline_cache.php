@@ -0,0 +1,126 @@
+<?php
+namespace tests\obj;
+
+// 测试调用点内联缓存：同一调用点遇到多个类（含超过缓存容量）时仍按对象的类分派，访问控制按调用者判断
+
+class IcShape {
+    public $sides = 0;
+    protected $label = 'shape';
+
+    public function name() {
+        return $this->label;
+    }
+
+    public static function kind() {
+        return 'shape';
+    }
+
+    public function peek($o) {
+        return $o->label;
+    }
+}
+
+class IcTriangle extends IcShape {
+    public $sides = 3;
+    protected $label = 'triangle';
+}
+
+class IcSquare extends IcShape {
+    public $sides = 4;
+
+    public function name() {
+        return 'square';
+    }
+
+    public static function kind() {
+        return 'polygon';
+    }
+}
+
+class IcPentagon extends IcShape { public $sides = 5; }
+class IcHexagon extends IcShape { public $sides = 6; }
+class IcCircle extends IcShape {
+    public function name() {
+        return 'circle';
+    }
+}
+
+// 同一调用点遍历 6 个类（超过 4 路缓存）
+$shapes = [new IcShape(), new IcTriangle(), new IcSquare(), new IcPentagon(), new IcHexagon(), new IcCircle()];
+$names = [];
+$sides = [];
+for ($round = 0; $round < 3; $round++) {
+    foreach ($shapes as $s) {
+        if ($round === 0) {
+            $names[] = $s->name();
+            $sides[] = $s->sides;
+        }
+        $s->name();
+    }
+}
+if (implode(',', $names) !== 'shape,triangle,square,shape,shape,circle') {
+    Log::fatal("[FAIL] 同一调用点遍历 6 个类（超过 4 路缓存） test1");
+} else {
+    Log::info("[PASS] 同一调用点遍历 6 个类（超过 4 路缓存） test1");
+}
+if (implode(',', $sides) !== '0,3,4,5,6,0') {
+    Log::fatal("[FAIL] 同一调用点遍历 6 个类（超过 4 路缓存） test2");
+} else {
+    Log::info("[PASS] 同一调用点遍历 6 个类（超过 4 路缓存） test2");
+}
+
+// 静态方法：继承与覆盖
+function icKind($i) {
+    if ($i === 0) {
+        return IcTriangle::kind();
+    }
+    return IcSquare::kind();
+}
+if (!(icKind(0) === 'shape' && icKind(1) === 'polygon' && icKind(0) === 'shape')) {
+    Log::fatal("[FAIL] 静态方法：继承与覆盖 test3");
+} else {
+    Log::info("[PASS] 静态方法：继承与覆盖 test3");
+}
+
+// 访问控制不随缓存共享：类内部可以读 protected 属性，外部不行
+class IcReader {
+    public function read($o) {
+        return $o->label;
+    }
+}
+$t = new IcTriangle();
+if ((new IcShape())->peek($t) !== 'triangle') {
+    Log::fatal("[FAIL] 访问控制不随缓存共享：类内部可以读 protected 属性，外部不行 test4");
+} else {
+    Log::info("[PASS] 访问控制不随缓存共享：类内部可以读 protected 属性，外部不行 test4");
+}
+$denied = false;
+try {
+    (new IcReader())->read($t);
+} catch (\Throwable $e) {
+    $denied = true;
+}
+if (!$denied) {
+    Log::fatal("[FAIL] 访问控制不随缓存共享：类内部可以读 protected 属性，外部不行 test5");
+} else {
+    Log::info("[PASS] 访问控制不随缓存共享：类内部可以读 protected 属性，外部不行 test5");
+}
+
+// 动态属性与声明属性在同一调用点
+#[\AllowDynamicProperties]
+class IcBag {
+    public $x = 1;
+}
+function icGetX($o) {
+    return $o->x;
+}
+$bag = new IcBag();
+$plain = new \stdClass();
+$plain->x = 2;
+if (!(icGetX($bag) === 1 && icGetX($plain) === 2 && icGetX($bag) === 1)) {
+    Log::fatal("[FAIL] 动态属性与声明属性在同一调用点 test6");
+} else {
+    Log::info("[PASS] 动态属性与声明属性在同一调用点 test6");
+}
+
+Log::info("调用点内联缓存测试完成");
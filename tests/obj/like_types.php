@@ -0,0 +1,191 @@
+<?php
+namespace tests\obj;
+
+// 测试 like 结构化类型：参数与属性类型只要求具有接口/类的全部方法且参数个数兼容，缺少时 TypeError 列出缺少的方法
+
+interface LikeWriter {
+    public function write($data);
+    public function flush();
+}
+
+// 没有声明 implements，多出的可选参数兼容
+class LikeFileSink {
+    public $buf = [];
+    public function write($data, $mode = 'a') {
+        $this->buf[] = $data;
+    }
+    public function flush() {
+        return count($this->buf);
+    }
+}
+
+// 方法可以继承自父类
+class LikeBaseSink {
+    public function flush() {
+        return 'base';
+    }
+}
+class LikeChildSink extends LikeBaseSink {
+    public function write($data) {
+    }
+}
+
+// 缺少 flush，write 的必填参数多于接口
+class LikeHalfSink {
+    public function write($data, $mode) {
+    }
+}
+
+function like_save(like LikeWriter $w, $data) {
+    $w->write($data);
+    return $w->flush();
+}
+
+// 没有声明 implements 的类与继承来的方法满足 like 参数类型
+if (like_save(new LikeFileSink(), 'x') !== 1) {
+    Log::fatal("[FAIL] 没有声明 implements 的类与继承来的方法满足 like 参数类型 test1");
+} else {
+    Log::info("[PASS] 没有声明 implements 的类与继承来的方法满足 like 参数类型 test1");
+}
+if (like_save(new LikeChildSink(), 'x') !== 'base') {
+    Log::fatal("[FAIL] 没有声明 implements 的类与继承来的方法满足 like 参数类型 test2");
+} else {
+    Log::info("[PASS] 没有声明 implements 的类与继承来的方法满足 like 参数类型 test2");
+}
+
+// 缺少方法或参数不兼容时 TypeError 列出缺少的方法
+try {
+    like_save(new LikeHalfSink(), 'x');
+    Log::fatal("[FAIL] 缺少方法或参数不兼容时 TypeError 列出缺少的方法: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    $msg = $e->getMessage();
+    if (strpos($msg, 'must be of type like tests\obj\LikeWriter') === false) {
+        Log::fatal("[FAIL] 缺少方法或参数不兼容时 TypeError 列出缺少的方法 test3");
+    } else {
+        Log::info("[PASS] 缺少方法或参数不兼容时 TypeError 列出缺少的方法 test3");
+    }
+    if (strpos($msg, 'missing methods: write() with incompatible parameters, flush()') === false) {
+        Log::fatal("[FAIL] 缺少方法或参数不兼容时 TypeError 列出缺少的方法 test4");
+    } else {
+        Log::info("[PASS] 缺少方法或参数不兼容时 TypeError 列出缺少的方法 test4");
+    }
+}
+
+// 非对象参数抛出 TypeError
+try {
+    like_save('not an object', 'x');
+    Log::fatal("[FAIL] 非对象参数抛出 TypeError: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    if (strpos($e->getMessage(), 'string given') === false) {
+        Log::fatal("[FAIL] 非对象参数抛出 TypeError test5");
+    } else {
+        Log::info("[PASS] 非对象参数抛出 TypeError test5");
+    }
+}
+
+// 同一个类再次检查使用缓存的结果
+if (like_save(new LikeFileSink(), 'y') !== 1) {
+    Log::fatal("[FAIL] 同一个类再次检查使用缓存的结果 test6");
+} else {
+    Log::info("[PASS] 同一个类再次检查使用缓存的结果 test6");
+}
+
+// 属性类型、构造函数提升与可变参数
+class LikeCounter {
+    public function count() {
+        return 3;
+    }
+}
+class LikeHolder {
+    public like \Countable $items;
+
+    public function __construct(public like LikeWriter $out) {
+    }
+
+    public function all(like LikeWriter ...$writers) {
+        return count($writers);
+    }
+}
+$holder = new LikeHolder(new LikeFileSink());
+$holder->items = new LikeCounter();
+if ($holder->items->count() !== 3) {
+    Log::fatal("[FAIL] 属性类型、构造函数提升与可变参数 test7");
+} else {
+    Log::info("[PASS] 属性类型、构造函数提升与可变参数 test7");
+}
+try {
+    $holder->items = new LikeFileSink();
+    Log::fatal("[FAIL] 属性类型、构造函数提升与可变参数: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    if (strpos($e->getMessage(), 'missing methods: count()') === false) {
+        Log::fatal("[FAIL] 属性类型、构造函数提升与可变参数 test8");
+    } else {
+        Log::info("[PASS] 属性类型、构造函数提升与可变参数 test8");
+    }
+}
+try {
+    new LikeHolder(new LikeCounter());
+    Log::fatal("[FAIL] 属性类型、构造函数提升与可变参数: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    Log::info("[PASS] 属性类型、构造函数提升与可变参数 test9");
+}
+if ($holder->all(new LikeFileSink(), new LikeChildSink()) !== 2) {
+    Log::fatal("[FAIL] 属性类型、构造函数提升与可变参数 test10");
+} else {
+    Log::info("[PASS] 属性类型、构造函数提升与可变参数 test10");
+}
+
+// 可空的 ?like 参数与 like 返回类型
+function likeOptional(?like LikeWriter $w = null) {
+    return $w === null ? 'none' : $w->flush();
+}
+function likeReturn($w): like LikeWriter {
+    return $w;
+}
+function likeMaybe($w): ?like LikeWriter {
+    return $w;
+}
+if (likeOptional() !== 'none') {
+    Log::fatal("[FAIL] 可空的 ?like 参数与 like 返回类型 test11");
+} else {
+    Log::info("[PASS] 可空的 ?like 参数与 like 返回类型 test11");
+}
+if (likeOptional(new LikeChildSink()) !== 'base') {
+    Log::fatal("[FAIL] 可空的 ?like 参数与 like 返回类型 test12");
+} else {
+    Log::info("[PASS] 可空的 ?like 参数与 like 返回类型 test12");
+}
+if (!(likeReturn(new LikeChildSink()) instanceof LikeChildSink)) {
+    Log::fatal("[FAIL] 可空的 ?like 参数与 like 返回类型 test13");
+} else {
+    Log::info("[PASS] 可空的 ?like 参数与 like 返回类型 test13");
+}
+if (likeMaybe(null) !== null) {
+    Log::fatal("[FAIL] 可空的 ?like 参数与 like 返回类型 test14");
+} else {
+    Log::info("[PASS] 可空的 ?like 参数与 like 返回类型 test14");
+}
+try {
+    likeReturn(new LikeHalfSink());
+    Log::fatal("[FAIL] 可空的 ?like 参数与 like 返回类型: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    if (strpos($e->getMessage(), 'Return value must be of type like') === false) {
+        Log::fatal("[FAIL] 可空的 ?like 参数与 like 返回类型 test15");
+    } else {
+        Log::info("[PASS] 可空的 ?like 参数与 like 返回类型 test15");
+    }
+}
+
+// like 表达式与类型声明使用同样的检查
+if ((new LikeChildSink() like LikeWriter) !== true) {
+    Log::fatal("[FAIL] like 表达式与类型声明使用同样的检查 test16");
+} else {
+    Log::info("[PASS] like 表达式与类型声明使用同样的检查 test16");
+}
+if ((new LikeHalfSink() like LikeWriter) !== false) {
+    Log::fatal("[FAIL] like 表达式与类型声明使用同样的检查 test17");
+} else {
+    Log::info("[PASS] like 表达式与类型声明使用同样的检查 test17");
+}
+
+Log::info("like 结构化类型测试完成");
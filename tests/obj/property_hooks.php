@@ -0,0 +1,108 @@
+<?php
+namespace tests\obj;
+
+// 测试属性钩子（get/set）、虚拟属性与非对称可见性 private(set)/protected(set)
+class HookUser {
+    public string $fullName {
+        get => $this->first . ' ' . $this->last;
+        set(string $value) {
+            [$this->first, $this->last] = explode(' ', $value, 2);
+        }
+    }
+
+    public string $email {
+        set => strtolower($value);
+    }
+
+    public int $age = 0 {
+        set {
+            if ($value < 0) {
+                throw new \InvalidArgumentException("age 不能为负数");
+            }
+            $this->age = $value;
+        }
+    }
+
+    public function __construct(public string $first, public string $last) {}
+}
+
+$u = new HookUser('John', 'Doe');
+$ok = $u->fullName == 'John Doe';
+$u->fullName = 'Jane Smith';
+$ok = $ok && $u->first == 'Jane' && $u->last == 'Smith';
+$u->email = 'Jane@Example.COM';
+$ok = $ok && $u->email == 'jane@example.com';
+$u->age = 30;
+$u->age += 2;
+$ok = $ok && $u->age == 32;
+$errors = [];
+try { $u->age = -1; } catch (\InvalidArgumentException $e) { $errors[] = $e->getMessage(); }
+try { $u->fullName = ['x']; } catch (\TypeError $e) { $errors[] = 'TypeError'; }
+try { unset($u->email); } catch (\Error $e) { $errors[] = $e->getMessage(); }
+
+if ($ok && $u->age == 32 && count($errors) == 3 && $errors[0] == 'age 不能为负数'
+    && $errors[2] == 'Cannot unset hooked property tests\obj\HookUser::$email') {
+    Log::info("属性钩子 get/set 测试通过");
+} else {
+    Log::fatal("属性钩子 get/set 测试失败", $errors);
+}
+
+class HookRect {
+    public int $area {
+        get => $this->w * $this->h;
+    }
+
+    public function __construct(public int $w, public int $h) {}
+}
+
+$r = new HookRect(3, 4);
+$errors = [];
+try { $r->area = 1; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+$ref = new \ReflectionProperty(HookRect::class, 'area');
+
+if ($r->area == 12 && count($errors) == 1 && $errors[0] == 'Property tests\obj\HookRect::$area is read-only'
+    && $ref->isVirtual() && $ref->hasHook('get') && !$ref->hasHook('set')
+    && implode(',', array_keys($ref->getHooks())) == 'get') {
+    Log::info("虚拟属性测试通过");
+} else {
+    Log::fatal("虚拟属性测试失败", $errors);
+}
+
+class HookAccount {
+    public private(set) int $id = 0;
+
+    public function __construct(protected(set) string $owner) {}
+
+    public function renew() {
+        $this->id++;
+    }
+}
+
+class HookSavings extends HookAccount {
+    public function transfer(string $owner) {
+        $this->owner = $owner;
+    }
+
+    public function reset() {
+        $this->id = 0;
+    }
+}
+
+$a = new HookSavings('amy');
+$a->renew();
+$a->transfer('bob');
+$errors = [];
+try { $a->id = 5; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $a->owner = 'eve'; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $a->reset(); } catch (\Error $e) { $errors[] = $e->getMessage(); }
+$ref = new \ReflectionProperty(HookAccount::class, 'id');
+
+if ($a->id == 1 && $a->owner == 'bob' && count($errors) == 3
+    && $errors[0] == 'Cannot modify private(set) property tests\obj\HookAccount::$id from global scope'
+    && $errors[1] == 'Cannot modify protected(set) property tests\obj\HookAccount::$owner from global scope'
+    && $errors[2] == 'Cannot modify private(set) property tests\obj\HookAccount::$id from scope tests\obj\HookSavings'
+    && $ref->isPrivateSet() && !$ref->hasHooks()) {
+    Log::info("非对称可见性测试通过");
+} else {
+    Log::fatal("非对称可见性测试失败", $errors);
+}
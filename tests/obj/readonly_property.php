@@ -0,0 +1,98 @@
+<?php
+namespace tests\obj;
+
+// 测试 readonly 属性：只能在声明类作用域内初始化一次，之后的所有写入路径都会报错
+class ReadonlyPoint {
+    public function __construct(public readonly int $x, public readonly int $y) {}
+}
+
+function readonlyBump(&$v) {
+    $v++;
+}
+
+$p = new ReadonlyPoint(1, 2);
+$errors = [];
+try { $p->x = 5; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $p->x++; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $p->x += 2; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { unset($p->x); } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $r = &$p->x; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { readonlyBump($p->y); } catch (\Error $e) { $errors[] = $e->getMessage(); }
+
+if (count($errors) == 6 && $errors[0] == 'Cannot modify readonly property tests\obj\ReadonlyPoint::$x'
+    && $errors[3] == 'Cannot unset readonly property tests\obj\ReadonlyPoint::$x'
+    && $p->x == 1 && $p->y == 2) {
+    Log::info("readonly 属性写入路径测试通过");
+} else {
+    Log::fatal("readonly 属性写入路径测试失败", $errors);
+}
+
+class ReadonlyLazy {
+    public readonly array $items;
+    public readonly string $name;
+
+    public function init() {
+        $this->items = [1, 2];
+    }
+
+    public function push() {
+        $this->items[] = 3;
+    }
+}
+
+$lazy = new ReadonlyLazy();
+$errors = [];
+try { $lazy->name = "outside"; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+$lazy->init();
+try { $lazy->init(); } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $lazy->push(); } catch (\Error $e) { $errors[] = $e->getMessage(); }
+
+if (count($errors) == 3 && $errors[0] == 'Cannot initialize readonly property tests\obj\ReadonlyLazy::$name from global scope'
+    && $errors[2] == 'Cannot modify readonly property tests\obj\ReadonlyLazy::$items'
+    && count($lazy->items) == 2) {
+    Log::info("readonly 属性初始化作用域测试通过");
+} else {
+    Log::fatal("readonly 属性初始化作用域测试失败", $errors);
+}
+
+class ReadonlyBase {
+    public readonly int $id;
+
+    public function __construct(int $id) {
+        $this->id = $id;
+    }
+}
+
+class ReadonlyChild extends ReadonlyBase {
+    public function __construct() {
+        parent::__construct(9);
+    }
+}
+
+$child = new ReadonlyChild();
+if ($child->id == 9) {
+    Log::info("readonly 属性在父类构造函数中初始化测试通过");
+} else {
+    Log::fatal("readonly 属性在父类构造函数中初始化测试失败");
+}
+
+readonly class ReadonlyMoney {
+    public function __construct(public int $amount, public string $currency) {}
+
+    public function __clone() {
+        $this->amount = $this->amount * 2;
+    }
+}
+
+$money = new ReadonlyMoney(5, "EUR");
+$errors = [];
+try { $money->amount = 6; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+try { $money->extra = 1; } catch (\Error $e) { $errors[] = $e->getMessage(); }
+$doubled = clone $money;
+
+if (count($errors) == 2 && $errors[1] == 'Cannot create dynamic property tests\obj\ReadonlyMoney::$extra'
+    && $money->amount == 5 && $doubled->amount == 10) {
+    Log::info("readonly class 与 __clone 重新初始化测试通过");
+} else {
+    Log::fatal("readonly class 与 __clone 重新初始化测试失败", $errors);
+}
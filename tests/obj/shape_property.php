@@ -0,0 +1,101 @@
+<?php
+namespace tests\obj;
+
+// 测试类实例的属性布局：声明属性、继承属性、动态属性、unset 后重新赋值、遍历顺序与 clone
+
+function shapeKeys($obj) {
+    $keys = [];
+    foreach ($obj as $name => $value) {
+        $keys[] = $name;
+    }
+    return $keys;
+}
+
+class ShapeBase {
+    public $a = 1;
+    public $b = 2;
+    public function getB() {
+        return $this->b;
+    }
+}
+
+#[\AllowDynamicProperties]
+class ShapeChild extends ShapeBase {
+    public $c = 3;
+    public $d = null;
+}
+
+// 声明属性与继承属性的初始值
+$o = new ShapeChild();
+if (!($o->a === 1 && $o->getB() === 2 && $o->c === 3 && $o->d === null)) {
+    Log::fatal("[FAIL] 声明属性与继承属性的初始值 test1");
+} else {
+    Log::info("[PASS] 声明属性与继承属性的初始值 test1");
+}
+
+// 声明属性的遍历顺序：本类在前，父类在后
+if (implode(',', shapeKeys($o)) !== 'c,d,a,b') {
+    Log::fatal("[FAIL] 声明属性的遍历顺序：本类在前，父类在后 test2");
+} else {
+    Log::info("[PASS] 声明属性的遍历顺序：本类在前，父类在后 test2");
+}
+
+// 动态属性追加在声明属性之后，不同实例互不影响
+$o->e = 'x';
+$o->f = 'y';
+$p = new ShapeChild();
+$p->f = 'only-f';
+if (!($o->e === 'x' && $o->f === 'y' && !isset($p->e) && $p->f === 'only-f')) {
+    Log::fatal("[FAIL] 动态属性追加在声明属性之后，不同实例互不影响 test3");
+} else {
+    Log::info("[PASS] 动态属性追加在声明属性之后，不同实例互不影响 test3");
+}
+if (implode(',', shapeKeys($o)) !== 'c,d,a,b,e,f') {
+    Log::fatal("[FAIL] 动态属性追加在声明属性之后，不同实例互不影响 test4");
+} else {
+    Log::info("[PASS] 动态属性追加在声明属性之后，不同实例互不影响 test4");
+}
+
+// unset 后 isset 为 false，重新赋值后恢复
+unset($o->c);
+if (isset($o->c)) {
+    Log::fatal("[FAIL] unset 后 isset 为 false，重新赋值后恢复 test5");
+} else {
+    Log::info("[PASS] unset 后 isset 为 false，重新赋值后恢复 test5");
+}
+$o->c = 30;
+if (!($o->c === 30 && $p->c === 3)) {
+    Log::fatal("[FAIL] unset 后 isset 为 false，重新赋值后恢复 test6");
+} else {
+    Log::info("[PASS] unset 后 isset 为 false，重新赋值后恢复 test6");
+}
+
+// 大量动态属性（超出布局限制后改用字典存储）保持插入顺序
+$many = new ShapeChild();
+for ($i = 0; $i < 200; $i += 1) {
+    $name = 'p' . $i;
+    $many->$name = $i;
+}
+$keys = shapeKeys($many);
+if (!(count($keys) === 204 && $many->p0 === 0 && $many->p199 === 199 && $many->a === 1)) {
+    Log::fatal("[FAIL] 大量动态属性（超出布局限制后改用字典存储）保持插入顺序 test7");
+} else {
+    Log::info("[PASS] 大量动态属性（超出布局限制后改用字典存储）保持插入顺序 test7");
+}
+if (!($keys[4] === 'p0' && $keys[203] === 'p199')) {
+    Log::fatal("[FAIL] 大量动态属性（超出布局限制后改用字典存储）保持插入顺序 test8");
+} else {
+    Log::info("[PASS] 大量动态属性（超出布局限制后改用字典存储）保持插入顺序 test8");
+}
+
+// clone 得到独立的属性存储
+$q = clone $o;
+$q->a = 'changed';
+$q->e = 'cloned';
+if (!($o->a === 1 && $o->e === 'x' && $q->a === 'changed' && $q->e === 'cloned' && $q->f === 'y')) {
+    Log::fatal("[FAIL] clone 得到独立的属性存储 test9");
+} else {
+    Log::info("[PASS] clone 得到独立的属性存储 test9");
+}
+
+Log::info("属性布局测试完成");
@@ -0,0 +1,55 @@
+<?php
+namespace tests\obj;
+
+// 测试带类型的属性：写入时按 PHP 规则转换标量，无法转换时抛出 TypeError
+class TypedHolder {
+    public int $count = 0;
+    public float $ratio = 0.0;
+    public ?string $label = null;
+    public static int $instances = 0;
+}
+
+$h = new TypedHolder();
+$h->count = "42";
+$h->ratio = 3;
+$h->label = null;
+TypedHolder::$instances = "7";
+
+if ($h->count === 42 && $h->ratio === 3.0 && TypedHolder::$instances === 7) {
+    Log::info("属性类型转换测试通过");
+} else {
+    Log::fatal("属性类型转换测试失败");
+}
+
+$errors = [];
+try { $h->count = "abc"; } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+try { $h->count = null; } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+try { TypedHolder::$instances = [1]; } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+$name = "ratio";
+try { $h->$name = "x"; } catch (\TypeError $e) { $errors[] = $e->getMessage(); }
+
+if (count($errors) == 4
+    && $errors[0] == 'Cannot assign string to property tests\obj\TypedHolder::$count of type int'
+    && $errors[1] == 'Cannot assign null to property tests\obj\TypedHolder::$count of type int'
+    && $errors[2] == 'Cannot assign array to property tests\obj\TypedHolder::$instances of type int'
+    && $h->count === 42) {
+    Log::info("属性类型 TypeError 测试通过");
+} else {
+    Log::fatal("属性类型 TypeError 测试失败", $errors);
+}
+
+// 按引用绑定带类型的属性：引用与属性共享存储，通过引用写入同样按属性类型检查
+$ref = &$h->label;
+$ref = "zz";
+$cnt = &$h->count;
+$cnt = "5";
+$cnt += 2;
+$refErr = "";
+try { $cnt = "abc"; } catch (\TypeError $e) { $refErr = $e->getMessage(); }
+
+if ($h->label === "zz" && $h->count === 7
+    && $refErr == 'Cannot assign string to reference held by property tests\obj\TypedHolder::$count of type int') {
+    Log::info("属性引用类型检查测试通过");
+} else {
+    Log::fatal("属性引用类型检查测试失败", $refErr);
+}
@@ -0,0 +1,86 @@
+<?php
+namespace tests\obj;
+
+// 测试 WeakReference 与 WeakMap：不阻止对象析构，对象析构后引用失效、条目消失
+
+class WeakTarget {
+    public $id;
+    public function __construct($id) {
+        $this->id = $id;
+    }
+}
+
+// WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null
+$o = new WeakTarget(1);
+$ref = \WeakReference::create($o);
+if ($ref->get() !== $o) {
+    Log::fatal("[FAIL] WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null test1");
+} else {
+    Log::info("[PASS] WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null test1");
+}
+if (\WeakReference::create($o) !== $ref) {
+    Log::fatal("[FAIL] WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null test2");
+} else {
+    Log::info("[PASS] WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null test2");
+}
+unset($o);
+if ($ref->get() !== null) {
+    Log::fatal("[FAIL] WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null test3");
+} else {
+    Log::info("[PASS] WeakReference::create 对同一对象返回同一个实例，对象析构后 get() 返回 null test3");
+}
+
+// WeakReference 不能直接实例化
+try {
+    new \WeakReference();
+    Log::fatal("[FAIL] WeakReference 不能直接实例化: 未抛出预期的异常");
+} catch (\Error $e) {
+    Log::info("[PASS] WeakReference 不能直接实例化 test4");
+}
+
+// WeakMap 以对象为键，按插入顺序遍历
+$map = new \WeakMap();
+$k1 = new WeakTarget(1);
+$k2 = new WeakTarget(2);
+$map[$k1] = 'one';
+$map[$k2] = 'two';
+if (!(count($map) === 2 && $map[$k1] === 'one' && isset($map[$k2]))) {
+    Log::fatal("[FAIL] WeakMap 以对象为键，按插入顺序遍历 test5");
+} else {
+    Log::info("[PASS] WeakMap 以对象为键，按插入顺序遍历 test5");
+}
+
+$seen = [];
+foreach ($map as $key => $value) {
+    $seen[] = $key->id . ':' . $value;
+}
+if ($seen !== ['1:one', '2:two']) {
+    Log::fatal("[FAIL] WeakMap 以对象为键，按插入顺序遍历 test6");
+} else {
+    Log::info("[PASS] WeakMap 以对象为键，按插入顺序遍历 test6");
+}
+
+// 键对象析构后条目消失
+unset($k1);
+if (count($map) !== 1) {
+    Log::fatal("[FAIL] 键对象析构后条目消失 test7");
+} else {
+    Log::info("[PASS] 键对象析构后条目消失 test7");
+}
+
+// unset 删除条目，读取不存在的键抛出 Error
+unset($map[$k2]);
+if (!(count($map) === 0 && !isset($map[$k2]))) {
+    Log::fatal("[FAIL] unset 删除条目，读取不存在的键抛出 Error test8");
+} else {
+    Log::info("[PASS] unset 删除条目，读取不存在的键抛出 Error test8");
+}
+
+try {
+    $map[$k2];
+    Log::fatal("[FAIL] unset 删除条目，读取不存在的键抛出 Error: 未抛出预期的异常");
+} catch (\Error $e) {
+    Log::info("[PASS] unset 删除条目，读取不存在的键抛出 Error test9");
+}
+
+Log::info("弱引用测试完成");
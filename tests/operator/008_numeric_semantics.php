@@ -0,0 +1,136 @@
+<?php
+namespace tests\operator;
+
+// 测试 PHP 8 的数值语义：整数溢出为 float、数字字符串前后空白、非数字字符串的 TypeError、
+// 整数除法结果、除零异常与字符串自增
+
+// 整数溢出为 float
+if (!is_float(PHP_INT_MAX + 1)) {
+    Log::fatal("[FAIL] 整数溢出为 float test1");
+} else {
+    Log::info("[PASS] 整数溢出为 float test1");
+}
+if (!is_float(PHP_INT_MIN - 1)) {
+    Log::fatal("[FAIL] 整数溢出为 float test2");
+} else {
+    Log::info("[PASS] 整数溢出为 float test2");
+}
+if (!is_float(PHP_INT_MAX * 2)) {
+    Log::fatal("[FAIL] 整数溢出为 float test3");
+} else {
+    Log::info("[PASS] 整数溢出为 float test3");
+}
+if (!is_float(-PHP_INT_MIN)) {
+    Log::fatal("[FAIL] 整数溢出为 float test4");
+} else {
+    Log::info("[PASS] 整数溢出为 float test4");
+}
+$i = PHP_INT_MAX;
+$i++;
+if (!is_float($i)) {
+    Log::fatal("[FAIL] 整数溢出为 float test5");
+} else {
+    Log::info("[PASS] 整数溢出为 float test5");
+}
+$j = 1;
+$j += PHP_INT_MAX;
+if (!is_float($j)) {
+    Log::fatal("[FAIL] 整数溢出为 float test6");
+} else {
+    Log::info("[PASS] 整数溢出为 float test6");
+}
+if (!(2 ** 62 === 4611686018427387904 && is_float(2 ** 64))) {
+    Log::fatal("[FAIL] 整数溢出为 float test7");
+} else {
+    Log::info("[PASS] 整数溢出为 float test7");
+}
+
+// 数字字符串允许前后空白
+if (!(" 12" + 1 === 13 && "12 " + 1 === 13 && "1.5" * 2 === 3.0)) {
+    Log::fatal("[FAIL] 数字字符串允许前后空白 test8");
+} else {
+    Log::info("[PASS] 数字字符串允许前后空白 test8");
+}
+if ("10" - "3" !== 7) {
+    Log::fatal("[FAIL] 数字字符串允许前后空白 test9");
+} else {
+    Log::info("[PASS] 数字字符串允许前后空白 test9");
+}
+
+// 非数字字符串参与算术运算抛出 TypeError
+try {
+    $x = "abc" - 1;
+    Log::fatal("[FAIL] 非数字字符串参与算术运算抛出 TypeError: 未抛出预期的异常");
+} catch (\TypeError $e) {
+    if ($e->getMessage() !== "Unsupported operand types: string - int") {
+        Log::fatal("[FAIL] 非数字字符串参与算术运算抛出 TypeError test10");
+    } else {
+        Log::info("[PASS] 非数字字符串参与算术运算抛出 TypeError test10");
+    }
+}
+
+// 整数相除能整除时为 int，否则为 float
+if (!(6 / 2 === 3 && 7 / 2 === 3.5)) {
+    Log::fatal("[FAIL] 整数相除能整除时为 int，否则为 float test11");
+} else {
+    Log::info("[PASS] 整数相除能整除时为 int，否则为 float test11");
+}
+if (PHP_INT_MIN % -1 !== 0) {
+    Log::fatal("[FAIL] 整数相除能整除时为 int，否则为 float test12");
+} else {
+    Log::info("[PASS] 整数相除能整除时为 int，否则为 float test12");
+}
+
+// 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获
+try {
+    $x = 1 / 0;
+    Log::fatal("[FAIL] 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获: 未抛出预期的异常");
+} catch (\ArithmeticError $e) {
+    if (!($e instanceof \DivisionByZeroError && $e->getMessage() === "Division by zero")) {
+        Log::fatal("[FAIL] 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获 test13");
+    } else {
+        Log::info("[PASS] 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获 test13");
+    }
+}
+try {
+    $x = 1 % 0;
+    Log::fatal("[FAIL] 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获: 未抛出预期的异常");
+} catch (\DivisionByZeroError $e) {
+    if ($e->getMessage() !== "Modulo by zero") {
+        Log::fatal("[FAIL] 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获 test14");
+    } else {
+        Log::info("[PASS] 除零抛出 DivisionByZeroError，可以用 ArithmeticError 捕获 test14");
+    }
+}
+
+// 宽松比较
+if (!("abc" != 0 && "1e3" == "1000" && null == 0 && "1 " == 1)) {
+    Log::fatal("[FAIL] 宽松比较 test15");
+} else {
+    Log::info("[PASS] 宽松比较 test15");
+}
+if ((7 <=> "7.0") !== 0) {
+    Log::fatal("[FAIL] 宽松比较 test16");
+} else {
+    Log::info("[PASS] 宽松比较 test16");
+}
+
+// 字符串自增
+$s = "a";
+$s++;
+$t = "Zz";
+$t++;
+if (!($s === "b" && $t === "AAa")) {
+    Log::fatal("[FAIL] 字符串自增 test17");
+} else {
+    Log::info("[PASS] 字符串自增 test17");
+}
+
+// 浮点数转字符串
+if (!(((string)0.1) === "0.1" && ((string)1e25) === "1.0E+25" && ((string)(0.1 + 0.2)) === "0.3")) {
+    Log::fatal("[FAIL] 浮点数转字符串 test18");
+} else {
+    Log::info("[PASS] 浮点数转字符串 test18");
+}
+
+Log::info("数值语义测试完成");
@@ -0,0 +1,115 @@
+<?php
+namespace tests\operator;
+
+// 测试管道运算符 |> 与占位符 ? 部分应用
+
+function exclaim($s) {
+    return $s . '!';
+}
+
+class Wrapper {
+    public function wrap($pre, $s, $post) {
+        return $pre . $s . $post;
+    }
+    public static function mul($a, $b) {
+        return $a * $b;
+    }
+}
+
+// 左结合：从左到右依次调用
+if (("  hello " |> trim(...) |> strtoupper(...) |> exclaim(...)) !== 'HELLO!') {
+    Log::fatal("[FAIL] 左结合：从左到右依次调用 test1");
+} else {
+    Log::info("[PASS] 左结合：从左到右依次调用 test1");
+}
+
+// 可换行书写，右侧可以是箭头函数或函数名字符串
+$len = 'abc'
+    |> exclaim(...)
+    |> (fn($s) => $s . $s)
+    |> 'strlen';
+if ($len !== 8) {
+    Log::fatal("[FAIL] 可换行书写，右侧可以是箭头函数或函数名字符串 test2");
+} else {
+    Log::info("[PASS] 可换行书写，右侧可以是箭头函数或函数名字符串 test2");
+}
+
+// 优先级：低于字符串连接与算术，高于比较
+if ((1 + 2 |> (fn($x) => $x * 10)) !== 30) {
+    Log::fatal("[FAIL] 优先级：低于字符串连接与算术，高于比较 test3");
+} else {
+    Log::info("[PASS] 优先级：低于字符串连接与算术，高于比较 test3");
+}
+if (!(5 |> (fn($x) => $x + 1) == 6)) {
+    Log::fatal("[FAIL] 优先级：低于字符串连接与算术，高于比较 test4");
+} else {
+    Log::info("[PASS] 优先级：低于字符串连接与算术，高于比较 test4");
+}
+if (("a" . "b" |> strtoupper(...)) !== 'AB') {
+    Log::fatal("[FAIL] 优先级：低于字符串连接与算术，高于比较 test5");
+} else {
+    Log::info("[PASS] 优先级：低于字符串连接与算术，高于比较 test5");
+}
+if (!("a" . "b" |> strtoupper(...) === 'AB')) {
+    Log::fatal("[FAIL] 优先级：低于字符串连接与算术，高于比较 test6");
+} else {
+    Log::info("[PASS] 优先级：低于字符串连接与算术，高于比较 test6");
+}
+
+// 部分应用：非占位实参在创建时求值
+$from = 'a';
+$replace = str_replace($from, 'o', ?);
+$from = 'n';
+if ($replace('banana') !== 'bonono') {
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test7");
+} else {
+    Log::info("[PASS] 部分应用：非占位实参在创建时求值 test7");
+}
+if (('banana' |> str_replace('a', 'i', ?)) !== 'binini') {
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test8");
+} else {
+    Log::info("[PASS] 部分应用：非占位实参在创建时求值 test8");
+}
+
+$w = new Wrapper();
+$brackets = $w->wrap('[', ?, ']');
+if ($brackets('x') !== '[x]') {
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test9");
+} else {
+    Log::info("[PASS] 部分应用：非占位实参在创建时求值 test9");
+}
+if (Wrapper::mul(?, 3)(7) !== 21) {
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test10");
+} else {
+    Log::info("[PASS] 部分应用：非占位实参在创建时求值 test10");
+}
+if (sprintf('%s-%s', ?, ?)('a', 'b') !== 'a-b') {
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test11");
+} else {
+    Log::info("[PASS] 部分应用：非占位实参在创建时求值 test11");
+}
+if (array_map(exclaim(...), ['a', 'b']) !== ['a!', 'b!']) {
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test12");
+} else {
+    Log::info("[PASS] 部分应用：非占位实参在创建时求值 test12");
+}
+
+try {
+    5 |> 3;
+    Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值: 未抛出预期的异常");
+} catch (\Error $e) {
+    if ($e->getMessage() !== 'Value of type int is not callable') {
+        Log::fatal("[FAIL] 部分应用：非占位实参在创建时求值 test13");
+    } else {
+        Log::info("[PASS] 部分应用：非占位实参在创建时求值 test13");
+    }
+}
+
+// 三元运算符中的 ? 不受影响
+if ((true ? 'y' : 'n') !== 'y') {
+    Log::fatal("[FAIL] 三元运算符中的 ? 不受影响 test14");
+} else {
+    Log::info("[PASS] 三元运算符中的 ? 不受影响 test14");
+}
+
+Log::info("管道运算符与部分应用测试完成");
@@ -0,0 +1,357 @@
+<?php
+namespace tests\operator;
+
+// 测试运算符重载：__add/__sub/__mul/__div/__mod/__pow/__concat/__equals/__compare/__neg
+
+class OverloadMoney {
+    public function __construct(public int $cents, public string $currency = 'EUR') {}
+
+    public function __add($other, $reversed) {
+        return new OverloadMoney($this->cents + self::centsOf($other), $this->currency);
+    }
+
+    public function __sub($other, $reversed) {
+        $diff = $this->cents - self::centsOf($other);
+        return new OverloadMoney($reversed ? -$diff : $diff, $this->currency);
+    }
+
+    public function __mul($factor, $reversed) {
+        return new OverloadMoney($this->cents * $factor, $this->currency);
+    }
+
+    public function __div($divisor, $reversed) {
+        if ($divisor == 0) {
+            throw new \InvalidArgumentException('money divided by zero');
+        }
+        return new OverloadMoney(intdiv($this->cents, $divisor), $this->currency);
+    }
+
+    public function __mod($divisor, $reversed) {
+        return new OverloadMoney($this->cents % $divisor, $this->currency);
+    }
+
+    public function __neg() {
+        return new OverloadMoney(-$this->cents, $this->currency);
+    }
+
+    public function __concat($other, $reversed) {
+        $text = $this->cents . ' ' . $this->currency;
+        return $reversed ? $other . $text : $text . $other;
+    }
+
+    public function __compare($other) {
+        return $this->cents <=> self::centsOf($other);
+    }
+
+    private static function centsOf($v) {
+        return $v instanceof OverloadMoney ? $v->cents : (int) $v;
+    }
+}
+
+class OverloadVector {
+    public function __construct(public float $x, public float $y) {}
+
+    public function __add($o, $reversed) {
+        return new OverloadVector($this->x + $o->x, $this->y + $o->y);
+    }
+
+    public function __pow($n, $reversed) {
+        return ($this->x ** $n) + ($this->y ** $n);
+    }
+
+    public function __equals($o) {
+        return $o instanceof OverloadVector && $this->x == $o->x && $this->y == $o->y;
+    }
+}
+
+// 没有定义魔术方法的类保持原有行为
+class OverloadPlain {
+    public $a = 1;
+}
+
+// 子类继承父类的运算符方法
+class OverloadEuro extends OverloadMoney {}
+
+// 魔术方法的可见性与默认参数与普通方法调用一致
+class OverloadGuarded {
+    public function __construct(public int $v) {}
+    private function __add($other, $reversed) {
+        return $this->v + $other;
+    }
+    protected function __mul($other, $reversed) {
+        return $this->v * $other;
+    }
+    public function __sub($other, $reversed = false, $extra = 7) {
+        return $extra;
+    }
+    public function plus($n) {
+        return $this + $n;
+    }
+}
+
+class OverloadGuardedChild extends OverloadGuarded {
+    public function times($n) {
+        return $this * $n;
+    }
+}
+
+$a = new OverloadMoney(150);
+$b = new OverloadMoney(275);
+
+// 算术运算
+if (($a + $b)->cents !== 425) {
+    Log::fatal("[FAIL] 算术运算 test1");
+} else {
+    Log::info("[PASS] 算术运算 test1");
+}
+if (($b - $a)->cents !== 125) {
+    Log::fatal("[FAIL] 算术运算 test2");
+} else {
+    Log::info("[PASS] 算术运算 test2");
+}
+if (($a * 3)->cents !== 450) {
+    Log::fatal("[FAIL] 算术运算 test3");
+} else {
+    Log::info("[PASS] 算术运算 test3");
+}
+if (($b / 2)->cents !== 137) {
+    Log::fatal("[FAIL] 算术运算 test4");
+} else {
+    Log::info("[PASS] 算术运算 test4");
+}
+if (($b % 100)->cents !== 75) {
+    Log::fatal("[FAIL] 算术运算 test5");
+} else {
+    Log::info("[PASS] 算术运算 test5");
+}
+if ((-$a)->cents !== -150) {
+    Log::fatal("[FAIL] 算术运算 test6");
+} else {
+    Log::info("[PASS] 算术运算 test6");
+}
+
+// 对象在右侧：第二个参数为 true
+if ((100 + $a)->cents !== 250) {
+    Log::fatal("[FAIL] 对象在右侧：第二个参数为 true test7");
+} else {
+    Log::info("[PASS] 对象在右侧：第二个参数为 true test7");
+}
+if ((500 - $a)->cents !== 350) {
+    Log::fatal("[FAIL] 对象在右侧：第二个参数为 true test8");
+} else {
+    Log::info("[PASS] 对象在右侧：第二个参数为 true test8");
+}
+
+// 字符串连接优先于 __toString
+if (($a . '!') !== '150 EUR!') {
+    Log::fatal("[FAIL] 字符串连接优先于 __toString test9");
+} else {
+    Log::info("[PASS] 字符串连接优先于 __toString test9");
+}
+if (('total: ' . $b) !== 'total: 275 EUR') {
+    Log::fatal("[FAIL] 字符串连接优先于 __toString test10");
+} else {
+    Log::info("[PASS] 字符串连接优先于 __toString test10");
+}
+
+// 比较运算使用 __compare，对象在右侧时取反
+if (!($a < $b)) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test11");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test11");
+}
+if (!($b > $a)) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test12");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test12");
+}
+if (!($a <= new OverloadMoney(150))) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test13");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test13");
+}
+if ($a >= $b) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test14");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test14");
+}
+if (($a <=> $b) !== -1) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test15");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test15");
+}
+if (($b <=> $a) !== 1) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test16");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test16");
+}
+if ((200 <=> $a) !== 1) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test17");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test17");
+}
+if (!(100 < $a)) {
+    Log::fatal("[FAIL] 比较运算使用 __compare，对象在右侧时取反 test18");
+} else {
+    Log::info("[PASS] 比较运算使用 __compare，对象在右侧时取反 test18");
+}
+
+// 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份
+if (!($a == new OverloadMoney(150))) {
+    Log::fatal("[FAIL] 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份 test19");
+} else {
+    Log::info("[PASS] 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份 test19");
+}
+if (!($a != $b)) {
+    Log::fatal("[FAIL] 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份 test20");
+} else {
+    Log::info("[PASS] 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份 test20");
+}
+if ($a === new OverloadMoney(150)) {
+    Log::fatal("[FAIL] 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份 test21");
+} else {
+    Log::info("[PASS] 没有 __equals 时 == 使用 __compare 的结果，=== 仍比较身份 test21");
+}
+
+// 复合赋值
+$total = new OverloadMoney(0);
+foreach ([$a, $b, new OverloadMoney(25)] as $m) {
+    $total += $m;
+}
+if ($total->cents !== 450) {
+    Log::fatal("[FAIL] 复合赋值 test22");
+} else {
+    Log::info("[PASS] 复合赋值 test22");
+}
+$total -= 50;
+$total *= 2;
+$total /= 4;
+if ($total->cents !== 200) {
+    Log::fatal("[FAIL] 复合赋值 test23");
+} else {
+    Log::info("[PASS] 复合赋值 test23");
+}
+$label = 'sum: ';
+$label .= $total;
+if ($label !== 'sum: 200 EUR') {
+    Log::fatal("[FAIL] 复合赋值 test24");
+} else {
+    Log::info("[PASS] 复合赋值 test24");
+}
+
+// __equals、__pow 与继承
+$v = new OverloadVector(1, 2);
+$w = $v + new OverloadVector(2, 2);
+if (!($w->x == 3 && $w->y == 4)) {
+    Log::fatal("[FAIL] __equals、__pow 与继承 test25");
+} else {
+    Log::info("[PASS] __equals、__pow 与继承 test25");
+}
+if (!(($w ** 2) == 25)) {
+    Log::fatal("[FAIL] __equals、__pow 与继承 test26");
+} else {
+    Log::info("[PASS] __equals、__pow 与继承 test26");
+}
+if (!($w == new OverloadVector(3, 4))) {
+    Log::fatal("[FAIL] __equals、__pow 与继承 test27");
+} else {
+    Log::info("[PASS] __equals、__pow 与继承 test27");
+}
+if (!($w != $v)) {
+    Log::fatal("[FAIL] __equals、__pow 与继承 test28");
+} else {
+    Log::info("[PASS] __equals、__pow 与继承 test28");
+}
+$e = new OverloadEuro(10);
+if (!(($e + 5)->cents === 15 && $e < $a)) {
+    Log::fatal("[FAIL] __equals、__pow 与继承 test29");
+} else {
+    Log::info("[PASS] __equals、__pow 与继承 test29");
+}
+
+// 魔术方法抛出的异常正常传播
+try {
+    $a / 0;
+    Log::fatal("[FAIL] 魔术方法抛出的异常正常传播: 未抛出预期的异常");
+} catch (\InvalidArgumentException $ex) {
+    if ($ex->getMessage() !== 'money divided by zero') {
+        Log::fatal("[FAIL] 魔术方法抛出的异常正常传播 test30");
+    } else {
+        Log::info("[PASS] 魔术方法抛出的异常正常传播 test30");
+    }
+}
+
+// 排序使用 __compare
+$wallet = [new OverloadMoney(300), $a, new OverloadMoney(50), $b];
+sort($wallet);
+if (array_map(fn($m) => $m->cents, $wallet) !== [50, 150, 275, 300]) {
+    Log::fatal("[FAIL] 排序使用 __compare test31");
+} else {
+    Log::info("[PASS] 排序使用 __compare test31");
+}
+rsort($wallet);
+if (array_map(fn($m) => $m->cents, $wallet) !== [300, 275, 150, 50]) {
+    Log::fatal("[FAIL] 排序使用 __compare test32");
+} else {
+    Log::info("[PASS] 排序使用 __compare test32");
+}
+usort($wallet, fn($x, $y) => $x <=> $y);
+if ($wallet[0]->cents !== 50) {
+    Log::fatal("[FAIL] 排序使用 __compare test33");
+} else {
+    Log::info("[PASS] 排序使用 __compare test33");
+}
+
+// private、protected 魔术方法只能在类内部使用，未传入的参数取默认值
+$g = new OverloadGuarded(2);
+if ($g->plus(3) !== 5) {
+    Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test34");
+} else {
+    Log::info("[PASS] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test34");
+}
+if ((new OverloadGuardedChild(2))->times(4) !== 8) {
+    Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test35");
+} else {
+    Log::info("[PASS] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test35");
+}
+if (($g - 1) !== 7) {
+    Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test36");
+} else {
+    Log::info("[PASS] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test36");
+}
+try {
+    $g + 1;
+    Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值: 未抛出预期的异常");
+} catch (\Error $ex) {
+    if ($ex->getMessage() !== 'Call to private method tests\\operator\\OverloadGuarded::__add() from global scope') {
+        Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test37");
+    } else {
+        Log::info("[PASS] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test37");
+    }
+}
+try {
+    $g * 2;
+    Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值: 未抛出预期的异常");
+} catch (\Error $ex) {
+    if (!str_contains($ex->getMessage(), 'Call to protected method')) {
+        Log::fatal("[FAIL] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test38");
+    } else {
+        Log::info("[PASS] private、protected 魔术方法只能在类内部使用，未传入的参数取默认值 test38");
+    }
+}
+
+// 未定义魔术方法时回退到原有行为
+$p = new OverloadPlain();
+$q = new OverloadPlain();
+if (($p + ['k']) !== [1, 'k']) {
+    Log::fatal("[FAIL] 未定义魔术方法时回退到原有行为 test39");
+} else {
+    Log::info("[PASS] 未定义魔术方法时回退到原有行为 test39");
+}
+if (!($p !== $q && $p === $p)) {
+    Log::fatal("[FAIL] 未定义魔术方法时回退到原有行为 test40");
+} else {
+    Log::info("[PASS] 未定义魔术方法时回退到原有行为 test40");
+}
+
+Log::info("运算符重载测试完成");
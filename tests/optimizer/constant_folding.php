@@ -0,0 +1,146 @@
+<?php
+namespace tests\optimizer;
+
+// 测试语法树优化：常量折叠、常量内联、删除不可达分支后结果与未优化时一致
+
+const HOUR = 60 * 60;
+const GREETING = 'hello' . ', ' . 'world';
+define('OPT_LIMIT', 2 * 5);
+
+class OptLimits {
+    const MAX = 3 * 4;
+    const NAME = 'opt' . '-' . 'limits';
+    public static int $counter = 1;
+
+    public function total(): int {
+        return self::MAX + OptLimits::MAX;
+    }
+
+    public function label(): string {
+        return self::NAME . ':' . self::$counter;
+    }
+}
+
+// 算术表达式折叠
+if (2 * 60 * 60 !== 7200) {
+    Log::fatal("[FAIL] 算术表达式折叠 test1");
+} else {
+    Log::info("[PASS] 算术表达式折叠 test1");
+}
+
+// 字符串连接折叠
+if (!('a' . 'b' . 'c' === 'abc' && 'n' . 15 === 'n15')) {
+    Log::fatal("[FAIL] 字符串连接折叠 test2");
+} else {
+    Log::info("[PASS] 字符串连接折叠 test2");
+}
+
+// 除法、取模、幂与取负折叠
+if (!((7 / 2) === 3.5 && 7 % 3 === 1 && 2 ** 10 === 1024 && -(3) == -3)) {
+    Log::fatal("[FAIL] 除法、取模、幂与取负折叠 test3");
+} else {
+    Log::info("[PASS] 除法、取模、幂与取负折叠 test3");
+}
+
+// 比较运算折叠
+if (!((1 < 2) === true && (1 <=> 2) === -1 && ('1' == 1) === true && ('1' === 1) === false)) {
+    Log::fatal("[FAIL] 比较运算折叠 test4");
+} else {
+    Log::info("[PASS] 比较运算折叠 test4");
+}
+
+// 逻辑运算折叠
+if (!((true && false) === false && (false || true) === true && !false === true)) {
+    Log::fatal("[FAIL] 逻辑运算折叠 test5");
+} else {
+    Log::info("[PASS] 逻辑运算折叠 test5");
+}
+
+// 位运算折叠
+if (!((6 & 3) === 2 && (6 | 3) === 7 && (6 ^ 3) === 5 && (1 << 4) === 16 && (256 >> 4) === 16)) {
+    Log::fatal("[FAIL] 位运算折叠 test6");
+} else {
+    Log::info("[PASS] 位运算折叠 test6");
+}
+
+// 三元运算折叠
+if (!((1 > 2 ? 'x' : 'y') === 'y' && (0 ? 'x' : 'y') === 'y' && ('0' ? 'x' : 'y') === 'x')) {
+    Log::fatal("[FAIL] 三元运算折叠 test7");
+} else {
+    Log::info("[PASS] 三元运算折叠 test7");
+}
+
+// 全局常量内联
+if (!(HOUR === 3600 && GREETING === 'hello, world')) {
+    Log::fatal("[FAIL] 全局常量内联 test8");
+} else {
+    Log::info("[PASS] 全局常量内联 test8");
+}
+
+// 类常量内联
+if (!((new OptLimits())->total() === 24 && OptLimits::MAX === 12)) {
+    Log::fatal("[FAIL] 类常量内联 test9");
+} else {
+    Log::info("[PASS] 类常量内联 test9");
+}
+
+// 类常量与静态属性混合的表达式
+OptLimits::$counter = 5;
+if ((new OptLimits())->label() !== 'opt-limits:5') {
+    Log::fatal("[FAIL] 类常量与静态属性混合的表达式 test10");
+} else {
+    Log::info("[PASS] 类常量与静态属性混合的表达式 test10");
+}
+
+// 折叠出的字面量不能在循环中被自增修改
+$runs = [];
+for ($j = 0; $j < 2; $j++) {
+    for ($i = 2 * 0; $i < 1 + 2; $i++) {
+    }
+    $runs[] = $i;
+}
+if (implode(',', $runs) !== '3,3') {
+    Log::fatal("[FAIL] 折叠出的字面量不能在循环中被自增修改 test11");
+} else {
+    Log::info("[PASS] 折叠出的字面量不能在循环中被自增修改 test11");
+}
+
+// 条件为常量的分支
+$branch = '';
+if (false) {
+    $branch .= 'dead';
+} elseif (1 > 2) {
+    $branch .= 'no';
+} elseif ($branch === '') {
+    $branch .= 'dynamic';
+} else {
+    $branch .= 'else';
+}
+if (true) {
+    $branch .= '-then';
+} else {
+    $branch .= '-dead';
+}
+if (0) {
+    $branch .= '-zero';
+}
+if ($branch !== 'dynamic-then') {
+    Log::fatal("[FAIL] 条件为常量的分支 test12");
+} else {
+    Log::info("[PASS] 条件为常量的分支 test12");
+}
+
+// 会出错的常量表达式不折叠，仍在运行时原位置报错
+$line = 0;
+try {
+    $bad = 10 % 0;
+} catch (\Throwable $e) {
+    $line = $e->getLine();
+}
+if ($line !== 136) {
+    Log::fatal("[FAIL] 会出错的常量表达式不折叠，仍在运行时原位置报错 test13");
+} else {
+    Log::info("[PASS] 会出错的常量表达式不折叠，仍在运行时原位置报错 test13");
+}
+
+Log::info("语法树优化测试完成");
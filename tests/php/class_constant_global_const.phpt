@@ -0,0 +1,30 @@
+--TEST--
+class constant initializers referencing global constants
+--FILE--
+<?php
+const FACTOR = 2;
+define('OFFSET', 4);
+
+class Scaled {
+    const BASE = FACTOR * 3;
+    const SHIFTED = self::BASE + OFFSET;
+    const LATE = LATE_LIMIT - 1;
+    const MISSING = UNDEFINED_LIMIT + 1;
+}
+
+const LATE_LIMIT = 10;
+
+var_dump(Scaled::BASE);
+var_dump(Scaled::SHIFTED);
+var_dump(Scaled::LATE);
+try {
+    var_dump(Scaled::MISSING);
+} catch (Error $e) {
+    echo get_class($e), ": ", $e->getMessage(), "\n";
+}
+?>
+--EXPECT--
+int(6)
+int(10)
+int(9)
+Error: Undefined constant "UNDEFINED_LIMIT"
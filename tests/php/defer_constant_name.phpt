@@ -0,0 +1,23 @@
+--TEST--
+defer is an ordinary name for a declared constant
+--FILE--
+<?php
+const defer = 7;
+
+var_dump(defer);
+var_dump(defer + 1);
+echo defer, "\n";
+
+function withCleanup() {
+    defer $f = null;
+    defer function () { echo "cleanup\n"; };
+    echo "body\n";
+}
+withCleanup();
+?>
+--EXPECT--
+int(7)
+int(8)
+7
+body
+cleanup
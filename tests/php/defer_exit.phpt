@@ -0,0 +1,24 @@
+--TEST--
+defer runs pending work on exit and at script end
+--FILE--
+<?php
+defer echo "top-level\n";
+
+function leave() {
+    defer echo "leave\n";
+    echo "exiting\n";
+    exit(0);
+}
+
+function outer() {
+    defer echo "outer\n";
+    leave();
+}
+
+outer();
+echo "unreachable\n";
+--EXPECT--
+exiting
+leave
+outer
+top-level
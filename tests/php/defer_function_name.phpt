@@ -0,0 +1,22 @@
+--TEST--
+defer is an ordinary name for a declared function
+--FILE--
+<?php
+function defer(?callable $callback = null) {
+    return $callback ? $callback() : "no callback";
+}
+
+var_dump(defer(fn() => "called"));
+var_dump(defer());
+
+function withCleanup() {
+    defer function () { echo "cleanup\n"; };
+    echo "body\n";
+}
+withCleanup();
+?>
+--EXPECT--
+string(6) "called"
+string(11) "no callback"
+body
+cleanup
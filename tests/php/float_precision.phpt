@@ -0,0 +1,50 @@
+--TEST--
+precision and serialize_precision control float to string conversion
+--FILE--
+<?php
+$f = 1/3;
+echo $f, "\n";
+var_dump($f);
+var_dump(0.1 + 0.2);
+echo 1e15, " ", 1e14, " ", 0.0001, " ", 0.00001, "\n";
+var_export(3.0);
+echo "\n";
+echo json_encode([1.0, 0.1, 1e25]), "\n";
+echo serialize(0.5), "\n";
+var_dump(7 / 2, 6 / 2, -0.0);
+ini_set('precision', 5);
+echo $f, "\n";
+ini_set('serialize_precision', 3);
+var_dump($f);
+try {
+    echo 1 / 0;
+} catch (\DivisionByZeroError $e) {
+    echo get_class($e), ": ", $e->getMessage(), "\n";
+}
+try {
+    echo 1 % 0;
+} catch (\ArithmeticError $e) {
+    echo get_class($e), ": ", $e->getMessage(), "\n";
+}
+try {
+    echo 1 << -1;
+} catch (\ArithmeticError $e) {
+    echo get_class($e), ": ", $e->getMessage(), "\n";
+}
+?>
+--EXPECT--
+0.33333333333333
+float(0.3333333333333333)
+float(0.30000000000000004)
+1.0E+15 1.0E+14 0.0001 1.0E-5
+3.0
+[1,0.1,1.0e+25]
+d:0.5;
+float(3.5)
+int(3)
+float(-0)
+0.33333
+float(0.333)
+DivisionByZeroError: Division by zero
+DivisionByZeroError: Modulo by zero
+ArithmeticError: Bit shift by negative number
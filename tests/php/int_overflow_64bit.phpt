@@ -0,0 +1,48 @@
+--TEST--
+testing integer overflow (64bit)
+--INI--
+serialize_precision=17
+--FILE--
+<?php
+
+$doubles = array(
+        PHP_INT_MAX,
+        PHP_INT_MAX + 1,
+        PHP_INT_MAX + 1000,
+        PHP_INT_MAX * 2 + 4,
+        -PHP_INT_MAX -1,
+        -PHP_INT_MAX -2,
+        -PHP_INT_MAX -1000,
+        -PHP_INT_MAX * 2 + 4,
+        );
+
+foreach ($doubles as $d) {
+        $l = (int)$d;
+        var_dump($l);
+}
+
+$i = PHP_INT_MAX;
+$i++;
+var_dump($i);
+$j = PHP_INT_MIN;
+$j--;
+var_dump($j);
+var_dump(PHP_INT_MAX * PHP_INT_MAX, -PHP_INT_MIN, PHP_INT_MIN - 1);
+
+echo "Done\n";
+?>
+--EXPECT--
+int(9223372036854775807)
+int(-9223372036854775808)
+int(-9223372036854775808)
+int(0)
+int(-9223372036854775808)
+int(-9223372036854775808)
+int(-9223372036854775808)
+int(0)
+float(9.2233720368547758E+18)
+float(-9.2233720368547758E+18)
+float(8.5070591730234616E+37)
+float(9.2233720368547758E+18)
+float(-9.2233720368547758E+18)
+Done
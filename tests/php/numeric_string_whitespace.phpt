@@ -0,0 +1,53 @@
+--TEST--
+Numeric strings: leading and trailing whitespace, leading-numeric warnings and non-numeric TypeError
+--FILE--
+<?php
+var_dump("123" + 1);
+var_dump(" 123" + 1);
+var_dump("123 " + 1);
+var_dump("\t\n 1.5 \r" * 2);
+var_dump("1e3" == "1000");
+var_dump("1 " == 1);
+var_dump("abc" == 0);
+var_dump(null == 0);
+var_dump("5 apples" * 2);
+try {
+    var_dump("abc" * 2);
+} catch (\TypeError $e) {
+    echo $e->getMessage(), "\n";
+}
+try {
+    var_dump([] - 1);
+} catch (\TypeError $e) {
+    echo $e->getMessage(), "\n";
+}
+$s = "Az";
+$s++;
+var_dump($s);
+$s = "zz";
+$s++;
+var_dump($s);
+$s = "9";
+$s++;
+var_dump($s);
+var_dump((int)"12abc", (float)" 1.5e1 ");
+?>
+--EXPECTF--
+int(124)
+int(124)
+int(124)
+float(3)
+bool(true)
+bool(true)
+bool(false)
+bool(true)
+
+Warning: A non-numeric value encountered in %s on line %d
+int(10)
+Unsupported operand types: string * int
+Unsupported operand types: array - int
+string(2) "Ba"
+string(3) "aaa"
+int(10)
+int(12)
+float(15)
@@ -71,6 +71,7 @@ var TokenDefinitions = []TokenDefinition{
 	{Type: VAR, Literal: "var", WordType: KEYWORD},
 	{Type: ECHO, Literal: "echo", WordType: KEYWORD},
 	{Type: GOTO, Literal: "goto", WordType: KEYWORD},
+	{Type: DEFER, Literal: "defer", WordType: KEYWORD},
 	{Type: THROW, Literal: "throw", WordType: KEYWORD},
 	{Type: TRY, Literal: "try", WordType: KEYWORD},
 	{Type: CATCH, Literal: "catch", WordType: KEYWORD},
@@ -160,6 +161,7 @@ var TokenDefinitions = []TokenDefinition{
 	{Type: NULLSAFE_CALL, Literal: "??->", WordType: OPERATOR},
 	{Type: NULL_COALESCE_ASSIGN, Literal: "??=", WordType: OPERATOR}, // 必须在 ?? 之前，因为 ??= 更长
 	{Type: NULL_COALESCE, Literal: "??", WordType: OPERATOR},
+	{Type: PIPE, Literal: "|>", WordType: OPERATOR},
 	{Type: POWER, Literal: "**", WordType: OPERATOR},
 	{Type: POWER_EQ, Literal: "**=", WordType: OPERATOR},
 	{Type: ADD_EQ, Literal: "+=", WordType: OPERATOR},
@@ -44,6 +44,7 @@ const (
 	VAR                                 // var 变量定义
 	ECHO                                // echo 输出语句
 	GOTO                                // goto 语句
+	DEFER                               // defer 延迟执行
 	THROW                               // throw 抛出异常
 	TRY                                 // try 异常处理
 	CATCH                               // catch 捕获异常
@@ -150,6 +151,7 @@ const (
 	NULLSAFE_CALL        // ??-> 空安全对象运算符 (PHP 8.0+)
 	NULL_COALESCE        // ?? 空合并运算符 (PHP 7+)
 	NULL_COALESCE_ASSIGN // ??= 空合并赋值运算符 (PHP 7.4+)
+	PIPE                 // |> 管道运算符 (PHP 8.5+)
 	POWER                // ** 幂运算符 (PHP 5.6+)
 	POWER_EQ             // **= 幂赋值运算符 (PHP 5.6+)
 	ADD_EQ               // += 加法赋值运算符
@@ -27,6 +27,9 @@ func extractClassNamesFromType(typ data.Types) []string {
 	case data.Class:
 		logrus.Debugf("找到 Class 类型: %s", t.Name)
 		classNames = append(classNames, t.Name)
+	case data.LikeType:
+		// like Writer 参数按接口 Writer 补全
+		classNames = append(classNames, t.Name)
 	case data.NullableType:
 		// 可空类型，递归获取基础类型
 		logrus.Debugf("NullableType，递归处理基础类型")
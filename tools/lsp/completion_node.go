@@ -223,6 +223,9 @@ func extractClassNamesFromTypeForCompletion(typ data.Types) []string {
 		}
 	case data.Class:
 		classNames = append(classNames, t.Name)
+	case data.LikeType:
+		// like Writer 参数按接口 Writer 补全
+		classNames = append(classNames, t.Name)
 	case data.NullableType:
 		// 可空类型，递归获取基础类型
 		names := extractClassNamesFromTypeForCompletion(t.BaseType)
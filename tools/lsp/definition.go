@@ -602,12 +602,17 @@ func getTypes(v data.GetValue) data.Types {
 				}
 			}
 		}
+	case *node.PipeExpression:
+		return callableReturnType(c.Right)
 	case *node.VariableExpression:
 		switch cc := c.Type.(type) {
 		case *data.LspTypes:
 			for _, types := range cc.Types {
 				return types
 			}
+		case data.LikeType:
+			// like Writer 参数按 Writer 推断
+			return data.Class{Name: cc.Name}
 		default:
 			return cc
 		}
@@ -615,6 +620,34 @@ func getTypes(v data.GetValue) data.Types {
 	return nil
 }
 
+// callableReturnType 推断可调用值（管道右侧）被调用后的返回类型
+func callableReturnType(v data.GetValue) data.Types {
+	switch c := v.(type) {
+	case *node.PartialApplication:
+		return callableReturnType(c.Callable)
+	case *node.FunctionCallable:
+		fn := c.Fun
+		if fn == nil && globalLspVM != nil {
+			fn, _ = globalLspVM.GetFunc(c.Name)
+		}
+		if ret, ok := fn.(data.GetReturnType); ok {
+			return ret.GetReturnType()
+		}
+	case *node.MethodCallable:
+		if expr, ok := getTypes(c.Object).(data.Class); ok && globalLspVM != nil {
+			if class, exists := globalLspVM.GetClass(expr.Name); exists {
+				if method, ok := class.GetMethod(c.Method); ok {
+					return method.GetReturnType()
+				}
+			}
+		}
+	case data.GetReturnType:
+		// 闭包、箭头函数
+		return c.GetReturnType()
+	}
+	return nil
+}
+
 func setTypes(v data.Variable, t data.Types) {
 	switch c := v.(type) {
 	case *node.VariableExpression:
@@ -660,6 +693,8 @@ func typeKey(t data.Types) string {
 	switch v := t.(type) {
 	case data.Class:
 		return "class:" + v.Name
+	case data.LikeType:
+		return "like:" + v.Name
 	case data.NullableType:
 		return "nullable:" + typeKey(v.BaseType)
 	case data.MultipleReturnType:
@@ -761,6 +796,8 @@ func getClassNameFromType(typ data.Types) string {
 		return ""
 	case data.Class:
 		return t.Name
+	case data.LikeType:
+		return t.Name
 	case data.NullableType:
 		// 如果是可空类型，递归获取基础类型的类名
 		return getClassNameFromType(t.BaseType)
@@ -64,20 +64,11 @@ func validateDocumentWithAST(uri, content string) []defines.Diagnostic {
 		ast, acl = parser.ParseString(content, "memory_content")
 	}
 
-	// 如果解析失败，返回解析错误
+	// 如果解析失败，返回全部解析错误（解析器会从错误中恢复并继续解析）
 	if acl != nil {
-		// 使用 acl 自带的 from 位置定位
-		startLine, startChar, endLine, endChar := uint32(0), uint32(0), uint32(0), uint32(0)
-		if gf, ok := acl.(node.GetFrom); ok && gf.GetFrom() != nil {
-			sl, sc, el, ec := gf.GetFrom().ToLSPPosition()
-			startLine, startChar, endLine, endChar = uint32(sl), uint32(sc), uint32(el), uint32(ec)
+		for _, e := range syntaxErrors(acl) {
+			diagnostics = append(diagnostics, syntaxDiagnostic(e))
 		}
-		diagnostics = append(diagnostics, defines.Diagnostic{
-			Range:    defines.Range{Start: defines.Position{Line: startLine, Character: startChar}, End: defines.Position{Line: endLine, Character: endChar}},
-			Severity: &[]defines.DiagnosticSeverity{defines.DiagnosticSeverityError}[0],
-			Message:  fmt.Sprintf("解析错误: %v", acl.AsString()),
-			Source:   &[]string{"origami-lsp"}[0],
-		})
 		return diagnostics
 	}
 
@@ -90,6 +81,32 @@ func validateDocumentWithAST(uri, content string) []defines.Diagnostic {
 	return diagnostics
 }
 
+// syntaxErrors 展开一次解析收集到的全部语法错误
+func syntaxErrors(acl data.Control) []data.Control {
+	errs := []data.Control{acl}
+	if tv, ok := acl.(*data.ThrowValue); ok {
+		for _, e := range tv.SyntaxErrors {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// syntaxDiagnostic 把解析错误转换为诊断，使用错误自带的 from 位置定位
+func syntaxDiagnostic(acl data.Control) defines.Diagnostic {
+	startLine, startChar, endLine, endChar := uint32(0), uint32(0), uint32(0), uint32(0)
+	if gf, ok := acl.(node.GetFrom); ok && gf.GetFrom() != nil {
+		sl, sc, el, ec := gf.GetFrom().ToLSPPosition()
+		startLine, startChar, endLine, endChar = uint32(sl), uint32(sc), uint32(el), uint32(ec)
+	}
+	return defines.Diagnostic{
+		Range:    defines.Range{Start: defines.Position{Line: startLine, Character: startChar}, End: defines.Position{Line: endLine, Character: endChar}},
+		Severity: &[]defines.DiagnosticSeverity{defines.DiagnosticSeverityError}[0],
+		Message:  fmt.Sprintf("解析错误: %v", acl.AsString()),
+		Source:   &[]string{"origami-lsp"}[0],
+	}
+}
+
 // 验证AST的语义
 func validateASTSemantics(ast *node.Program) []defines.Diagnostic {
 	var diagnostics []defines.Diagnostic
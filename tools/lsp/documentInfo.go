@@ -48,6 +48,14 @@ func inferTypeFromExpression(expr data.GetValue) data.Types {
 	case *node.CallExpression:
 		// 函数调用的返回类型需要更复杂的分析，暂时返回 mixed
 		return data.NewBaseType("mixed")
+	case *node.PipeExpression:
+		// 管道表达式的类型为最后一个可调用值的返回类型
+		if ret := getTypes(e); ret != nil {
+			return ret
+		}
+		return data.NewBaseType("mixed")
+	case *node.FunctionCallable, *node.MethodCallable, *node.ValueCallable, *node.PartialApplication, *node.LambdaExpression:
+		return data.NewBaseType("Closure")
 	case data.Variable:
 		// 如果是变量，返回其已知类型
 		return e.GetType()
@@ -652,6 +660,9 @@ func (d *DocumentInfo) foreachNode(ctx *LspContext, stmt data.GetValue, parent d
 	case *node.ThrowStatement:
 		// throw语句：遍历抛出的值
 		d.foreachNode(ctx, n.Value, parent, check)
+	case *node.DeferStatement:
+		// defer语句：遍历延迟执行的表达式或闭包
+		d.foreachNode(ctx, n.Work, parent, check)
 
 	case *node.EchoStatement:
 		// echo语句：遍历所有表达式
@@ -853,6 +864,24 @@ func (d *DocumentInfo) foreachNode(ctx *LspContext, stmt data.GetValue, parent d
 		d.foreachNode(ctx, n.Left, parent, check)
 		d.foreachNode(ctx, n.Right, parent, check)
 
+	case *node.PipeExpression:
+		// 管道表达式：遍历传入的值和可调用值
+		d.foreachNode(ctx, n.Left, parent, check)
+		d.foreachNode(ctx, n.Right, parent, check)
+
+	case *node.PartialApplication:
+		// 部分应用：遍历可调用值和实参
+		d.foreachNode(ctx, n.Callable, parent, check)
+		for _, arg := range n.Args {
+			d.foreachNode(ctx, arg, parent, check)
+		}
+
+	case *node.MethodCallable:
+		d.foreachNode(ctx, n.Object, parent, check)
+
+	case *node.ValueCallable:
+		d.foreachNode(ctx, n.Target, parent, check)
+
 	case *node.PostfixIncr:
 		// 后缀递增：遍历操作数
 		d.foreachNode(ctx, n.Left, parent, check)
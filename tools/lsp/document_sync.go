@@ -128,7 +128,7 @@ func handleTextDocumentDidChange(ctx context.Context, conn *jsonrpc2.Conn, req *
 		filePath = "memory_content" // 非文件 URI 使用虚拟路径
 	}
 	ast, acl = p.ParseString(content, filePath)
-	if acl != nil {
+	if acl != nil && ast == nil {
 		logrus.Warnf("重新解析 AST 失败 %s：%v", uri, acl)
 		// 解析失败时，只更新内容和版本，保留原有的 AST 和解析器
 		if existingDoc, exists := documents[uri]; exists {
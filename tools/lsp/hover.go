@@ -4,6 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/php-any/origami/data"
+	"github.com/php-any/origami/node"
 
 	"github.com/php-any/origami/tools/lsp/defines"
 	"github.com/sirupsen/logrus"
@@ -29,8 +34,11 @@ func handleTextDocumentHover(ctx context.Context, req *jsonrpc2.Request) (interf
 		return nil, nil
 	}
 
-	// 获取悬停信息
-	hoverInfo := getHoverInfo(doc.Content, position)
+	// 获取悬停信息：优先显示带钩子或非对称可见性的属性声明
+	hoverInfo := getPropertyHoverInfo(doc, position)
+	if hoverInfo == "" {
+		hoverInfo = getHoverInfo(doc.Content, position)
+	}
 	if hoverInfo == "" {
 		return nil, nil
 	}
@@ -74,3 +82,78 @@ func getHoverInfo(content string, position defines.Position) string {
 		return ""
 	}
 }
+
+// getPropertyHoverInfo 光标处的单词是某个类的属性，且该属性声明了钩子或 private(set)/protected(set) 时，
+// 返回属性声明与钩子列表
+func getPropertyHoverInfo(doc *DocumentInfo, position defines.Position) string {
+	word := getWordAtPosition(doc.Content, position)
+	if word == "" || globalLspVM == nil {
+		return ""
+	}
+	classes := globalLspVM.GetAllClasses()
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var infos []string
+	for _, name := range names {
+		prop, ok := classes[name].GetProperty(word)
+		if !ok {
+			continue
+		}
+		cp, ok := prop.(*node.ClassProperty)
+		if !ok || (!cp.HasHooks() && cp.WriteModifier == data.ModifierPublic) {
+			continue
+		}
+		infos = append(infos, formatPropertyHover(name, cp))
+	}
+	return strings.Join(infos, "\n\n---\n\n")
+}
+
+func modifierText(m data.Modifier) string {
+	switch m {
+	case data.ModifierPrivate:
+		return "private"
+	case data.ModifierProtected:
+		return "protected"
+	}
+	return "public"
+}
+
+// formatPropertyHover 生成属性悬停信息，例如：
+// public private(set) string $name { get; set(string $v); }
+func formatPropertyHover(className string, cp *node.ClassProperty) string {
+	var b strings.Builder
+	b.WriteString("```php\n")
+	b.WriteString(modifierText(cp.Modifier))
+	if cp.WriteModifier != data.ModifierPublic {
+		b.WriteString(" " + modifierText(cp.WriteModifier) + "(set)")
+	}
+	if cp.Type != nil {
+		b.WriteString(" " + cp.Type.String())
+	}
+	b.WriteString(" $" + cp.Name)
+	if cp.HasHooks() {
+		b.WriteString(" {")
+		if cp.GetHook != nil {
+			b.WriteString(" get;")
+		}
+		if cp.SetHook != nil {
+			b.WriteString(" set")
+			for _, param := range cp.SetHook.GetParams() {
+				if p, ok := param.(*node.Parameter); ok && p.Type != nil {
+					b.WriteString("(" + p.Type.String() + " $" + p.Name + ")")
+				}
+			}
+			b.WriteString(";")
+		}
+		b.WriteString(" }")
+	}
+	b.WriteString("\n```\n")
+	b.WriteString("**" + className + "::$" + cp.Name + "**")
+	if cp.IsVirtual {
+		b.WriteString(" (virtual)")
+	}
+	return b.String()
+}
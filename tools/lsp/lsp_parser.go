@@ -72,12 +72,8 @@ func (p *LspParser) ParseFile(filePath string) (*node.Program, data.Control) {
 	}
 
 	// 使用真正的解析器解析文件
-	program, acl := p.parser.Clone().ParseFile(filePath)
-	if acl != nil {
-		return nil, acl
-	}
-
-	return program, nil
+	// 有语法错误时 program 是从错误中恢复后得到的部分 AST，仍然可以用于补全
+	return p.parser.Clone().ParseFile(filePath)
 }
 
 // ParseString 从字符串解析程序 - 用于处理编辑器中的最新内容
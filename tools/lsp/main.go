@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/php-any/origami/opcache"
 	"github.com/sirupsen/logrus"
 	"github.com/sourcegraph/jsonrpc2"
 )
@@ -62,6 +63,8 @@ var (
 	logFile    = flag.String("log-file", "lsp.log", "Log file path (default lsp.log; empty not allowed)")
 	consoleLog = flag.Bool("console-log", true, "Enable console logging in stdio mode (default: true)")
 	scanDir    = flag.String("scan-dir", "", "Directory to scan for .zy files (optional)")
+	cacheOn    = flag.Bool("cache", true, "Share the on-disk token cache with zy (zy.cache_enable)")
+	cacheDir   = flag.String("cache-dir", "", "Token cache directory (zy.cache_dir, default: user cache dir)")
 
 	// 全局 LSP 连接，用于发送通知
 	globalConn *jsonrpc2.Conn
@@ -82,6 +85,20 @@ type CancelParams struct {
 func main() {
 	flag.Parse()
 
+	// 与 zy 共用分词缓存
+	opcache.Setting = func(key string) (string, bool) {
+		switch key {
+		case opcache.IniEnable:
+			if *cacheOn {
+				return "1", true
+			}
+			return "0", true
+		case opcache.IniDir:
+			return *cacheDir, true
+		}
+		return "", false
+	}
+
 	// 初始化全局日志器
 	var output io.Writer
 	var effectiveLevel int = *logLevel
@@ -165,6 +165,10 @@ func (p *LSPSymbolProvider) GetClassMembers(className string) []defines.Completi
 
 		class, exists := p.vm.GetClass(currentClassName)
 		if !exists {
+			// 接口类型（如 like Writer 参数）补全接口及其父接口声明的方法
+			if currentClassName == className {
+				p.addInterfaceMembers(className, itemsMap, map[string]bool{})
+			}
 			logrus.Debugf("未找到类定义: %s", currentClassName)
 			break
 		}
@@ -266,6 +270,42 @@ func (p *LSPSymbolProvider) GetClassMembers(className string) []defines.Completi
 	return items
 }
 
+// addInterfaceMembers 添加接口及其父接口声明的方法
+func (p *LSPSymbolProvider) addInterfaceMembers(name string, itemsMap map[string]defines.CompletionItem, visited map[string]bool) {
+	if visited[name] {
+		return
+	}
+	visited[name] = true
+	iface, ok := p.vm.GetInterface(name)
+	if !ok {
+		return
+	}
+	for _, method := range iface.GetMethods() {
+		label := method.GetName()
+		if _, exists := itemsMap[label]; exists {
+			continue
+		}
+		paramDisplay, paramSnippet := formatMethodParams(method)
+		returnType := "mixed"
+		if ret := method.GetReturnType(); ret != nil {
+			returnType = ret.String()
+		}
+		detail := fmt.Sprintf("public method %s%s: %s in %s", label, paramDisplay, returnType, iface.GetName())
+		insertText := label + paramSnippet
+		insertTextFormat := defines.InsertTextFormatSnippet
+		itemsMap[label] = defines.CompletionItem{
+			Label:            label,
+			Kind:             &[]defines.CompletionItemKind{defines.CompletionItemKindMethod}[0],
+			Detail:           &detail,
+			InsertText:       &insertText,
+			InsertTextFormat: &insertTextFormat,
+		}
+	}
+	for _, parent := range iface.GetExtends() {
+		p.addInterfaceMembers(parent, itemsMap, visited)
+	}
+}
+
 // GetStaticClassMembers 获取类的静态成员
 func (p *LSPSymbolProvider) GetStaticClassMembers(className string) []defines.CompletionItem {
 	if p.vm == nil {
@@ -28,6 +28,7 @@ func init() {
 }
 
 func main() {
+	os.Args = append(os.Args[:1], cmd.ParseRunFlags(os.Args[1:])...)
 	if len(os.Args) > 1 && cmd.IsDirectScriptArg(os.Args[1]) {
 		if err := cmd.RunScriptFile(os.Args[1]); err != nil {
 			os.Exit(1)